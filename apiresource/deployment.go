@@ -33,7 +33,7 @@ import (
 	core "k8s.io/kubernetes/pkg/apis/core"
 )
 
-//TODO: Add support for replicaset, cronjob and statefulset
+//TODO: Add support for replicaset
 
 const (
 	// podKind defines Pod Kind
@@ -46,6 +46,10 @@ const (
 	replicationControllerKind string = "ReplicationController"
 	// daemonSetKind defines DaemonSet Kind
 	daemonSetKind string = "DaemonSet"
+	// statefulSetKind defines StatefulSet Kind
+	statefulSetKind string = "StatefulSet"
+	// cronJobKind defines CronJob Kind
+	cronJobKind string = "CronJob"
 )
 
 // Deployment handles all objects like a Deployment
@@ -54,7 +58,7 @@ type Deployment struct {
 
 // getSupportedKinds returns kinds supported by the deployment
 func (d *Deployment) getSupportedKinds() []string {
-	return []string{podKind, jobKind, common.DeploymentKind, deploymentConfigKind, replicationControllerKind}
+	return []string{podKind, jobKind, common.DeploymentKind, deploymentConfigKind, replicationControllerKind, statefulSetKind, cronJobKind}
 }
 
 // createNewResources converts ir to runtime object
@@ -62,11 +66,19 @@ func (d *Deployment) createNewResources(ir irtypes.EnhancedIR, supportedKinds []
 	objs := []runtime.Object{}
 	for _, service := range ir.Services {
 		var obj runtime.Object
+		if service.Schedule != "" && !common.IsStringPresent(supportedKinds, cronJobKind) {
+			logrus.Warnf("CronJob not supported by target cluster. Creating the scheduled service %s as a job without its schedule %q.", service.Name, service.Schedule)
+		}
+		if isStatefulSet(service) && !common.IsStringPresent(supportedKinds, statefulSetKind) {
+			logrus.Warnf("StatefulSet not supported by target cluster. Creating the stateful service %s as a stateless workload.", service.Name)
+		}
 		if service.Daemon {
 			if !common.IsStringPresent(supportedKinds, daemonSetKind) {
 				logrus.Errorf("Creating Daemonset even though not supported by target cluster.")
 			}
 			obj = d.createDaemonSet(service, targetCluster.Spec)
+		} else if service.Schedule != "" && common.IsStringPresent(supportedKinds, cronJobKind) {
+			obj = d.createCronJob(service, targetCluster.Spec)
 		} else if service.RestartPolicy == core.RestartPolicyNever || service.RestartPolicy == core.RestartPolicyOnFailure {
 			if common.IsStringPresent(supportedKinds, jobKind) {
 				obj = d.createJob(service, targetCluster.Spec)
@@ -76,6 +88,8 @@ func (d *Deployment) createNewResources(ir irtypes.EnhancedIR, supportedKinds []
 			pod := d.createPod(service, targetCluster.Spec)
 			pod.Spec.RestartPolicy = core.RestartPolicyOnFailure
 			obj = pod
+		} else if isStatefulSet(service) && common.IsStringPresent(supportedKinds, statefulSetKind) {
+			obj = d.createStatefulSet(service, ir, targetCluster.Spec)
 		} else if common.IsStringPresent(supportedKinds, deploymentConfigKind) {
 			obj = d.createDeploymentConfig(service, targetCluster.Spec)
 		} else if common.IsStringPresent(supportedKinds, common.DeploymentKind) {
@@ -101,6 +115,35 @@ func (d *Deployment) convertToClusterSupportedKinds(obj runtime.Object, supporte
 	if d1, ok := lobj.(*apps.DaemonSet); ok {
 		return []runtime.Object{d1}, true
 	}
+	if d1, ok := lobj.(*apps.StatefulSet); ok {
+		if common.IsStringPresent(supportedKinds, statefulSetKind) {
+			return []runtime.Object{d1}, true
+		}
+		logrus.Warnf("StatefulSet not supported by target cluster. Converting %s to a stateless workload.", d1.Name)
+		podSpec := d.statefulSetToPodSpec(*d1)
+		if common.IsStringPresent(supportedKinds, deploymentConfigKind) {
+			return []runtime.Object{d.toDeploymentConfig(d1.ObjectMeta, podSpec, d1.Spec.Replicas, targetCluster.Spec)}, true
+		} else if common.IsStringPresent(supportedKinds, common.DeploymentKind) {
+			return []runtime.Object{d.toDeployment(d1.ObjectMeta, podSpec, d1.Spec.Replicas, targetCluster.Spec)}, true
+		} else if common.IsStringPresent(supportedKinds, replicationControllerKind) {
+			return []runtime.Object{d.toReplicationController(d1.ObjectMeta, podSpec, d1.Spec.Replicas, targetCluster.Spec)}, true
+		} else if common.IsStringPresent(supportedKinds, podKind) {
+			return []runtime.Object{d.toPod(d1.ObjectMeta, podSpec, core.RestartPolicyAlways, targetCluster.Spec)}, true
+		}
+		return []runtime.Object{obj}, true
+	}
+	if d1, ok := lobj.(*batch.CronJob); ok {
+		if common.IsStringPresent(supportedKinds, cronJobKind) {
+			return []runtime.Object{d1}, true
+		}
+		logrus.Warnf("CronJob not supported by target cluster. Converting %s to a job without its schedule %q.", d1.Name, d1.Spec.Schedule)
+		if common.IsStringPresent(supportedKinds, jobKind) {
+			return []runtime.Object{d.toJob(d1.ObjectMeta, d1.Spec.JobTemplate.Spec.Template.Spec, targetCluster.Spec)}, true
+		} else if common.IsStringPresent(supportedKinds, podKind) {
+			return []runtime.Object{d.toPod(d1.ObjectMeta, d1.Spec.JobTemplate.Spec.Template.Spec, core.RestartPolicyOnFailure, targetCluster.Spec)}, true
+		}
+		return []runtime.Object{obj}, true
+	}
 	if d1, ok := lobj.(*core.Pod); ok && (d1.Spec.RestartPolicy == core.RestartPolicyOnFailure || d1.Spec.RestartPolicy == core.RestartPolicyNever) {
 		if common.IsStringPresent(supportedKinds, jobKind) {
 			return []runtime.Object{d.podToJob(*d1, targetCluster.Spec)}, true
@@ -242,6 +285,40 @@ func (d *Deployment) createDaemonSet(service irtypes.Service, cluster collecttyp
 	return &pod
 }
 
+// createStatefulSet creates a StatefulSet, whose persistent volume claims are created from volume claim templates
+func (d *Deployment) createStatefulSet(service irtypes.Service, ir irtypes.EnhancedIR, cluster collecttypes.ClusterMetadataSpec) *apps.StatefulSet {
+	podSpec := service.PodSpec
+	podSpec = d.convertVolumesKindsByPolicy(podSpec, cluster)
+	podSpec.RestartPolicy = core.RestartPolicyAlways
+	podSpec, claimTemplates := d.toVolumeClaimTemplates(podSpec, ir.Storages, getVolumeClaimTemplateNames(ir, cluster))
+	meta := metav1.ObjectMeta{
+		Name:        service.Name,
+		Labels:      getPodLabels(service.Name, service.Networks),
+		Annotations: getAnnotations(service),
+	}
+	statefulSet := apps.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       statefulSetKind,
+			APIVersion: apps.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta,
+		Spec: apps.StatefulSetSpec{
+			Replicas: int32(service.Replicas),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: getServiceLabels(service.Name),
+			},
+			Template: core.PodTemplateSpec{
+				ObjectMeta: meta,
+				Spec:       podSpec,
+			},
+			VolumeClaimTemplates: claimTemplates,
+			ServiceName:          getHeadlessServiceName(service.Name),
+		},
+	}
+	logrus.Debugf("Created StatefulSet for %s", service.Name)
+	return &statefulSet
+}
+
 func (d *Deployment) createJob(service irtypes.Service, cluster collecttypes.ClusterMetadataSpec) *batch.Job {
 	podspec := service.PodSpec
 	podspec = d.convertVolumesKindsByPolicy(podspec, cluster)
@@ -267,6 +344,41 @@ func (d *Deployment) createJob(service irtypes.Service, cluster collecttypes.Clu
 	return &pod
 }
 
+// createCronJob creates a CronJob, which runs the service on its schedule
+func (d *Deployment) createCronJob(service irtypes.Service, cluster collecttypes.ClusterMetadataSpec) *batch.CronJob {
+	podspec := service.PodSpec
+	podspec = d.convertVolumesKindsByPolicy(podspec, cluster)
+	if podspec.RestartPolicy != core.RestartPolicyNever {
+		podspec.RestartPolicy = core.RestartPolicyOnFailure
+	}
+	meta := metav1.ObjectMeta{
+		Name:        service.Name,
+		Labels:      getPodLabels(service.Name, service.Networks),
+		Annotations: getAnnotations(service),
+	}
+	cronJob := batch.CronJob{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       cronJobKind,
+			APIVersion: batch.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta,
+		Spec: batch.CronJobSpec{
+			Schedule: service.Schedule,
+			JobTemplate: batch.JobTemplateSpec{
+				ObjectMeta: meta,
+				Spec: batch.JobSpec{
+					Template: core.PodTemplateSpec{
+						ObjectMeta: meta,
+						Spec:       podspec,
+					},
+				},
+			},
+		},
+	}
+	logrus.Debugf("Created CronJob for %s", service.Name)
+	return &cronJob
+}
+
 // Conversions section
 
 func (d *Deployment) toDeploymentConfig(meta metav1.ObjectMeta, podspec core.PodSpec, replicas int32, cluster collecttypes.ClusterMetadataSpec) *okdappsv1.DeploymentConfig {
@@ -352,18 +464,23 @@ func (d *Deployment) toReplicationController(meta metav1.ObjectMeta, podspec cor
 }
 
 func (d *Deployment) podToJob(obj core.Pod, cluster collecttypes.ClusterMetadataSpec) *batch.Job {
-	podspec := obj.Spec
+	return d.toJob(obj.ObjectMeta, obj.Spec, cluster)
+}
+
+func (d *Deployment) toJob(meta metav1.ObjectMeta, podspec core.PodSpec, cluster collecttypes.ClusterMetadataSpec) *batch.Job {
 	podspec = d.convertVolumesKindsByPolicy(podspec, cluster)
-	podspec.RestartPolicy = core.RestartPolicyOnFailure
+	if podspec.RestartPolicy != core.RestartPolicyNever {
+		podspec.RestartPolicy = core.RestartPolicyOnFailure
+	}
 	pod := batch.Job{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       jobKind,
 			APIVersion: batch.SchemeGroupVersion.String(),
 		},
-		ObjectMeta: obj.ObjectMeta,
+		ObjectMeta: meta,
 		Spec: batch.JobSpec{
 			Template: core.PodTemplateSpec{
-				ObjectMeta: obj.ObjectMeta,
+				ObjectMeta: meta,
 				Spec:       podspec,
 			},
 		},
@@ -371,6 +488,72 @@ func (d *Deployment) podToJob(obj core.Pod, cluster collecttypes.ClusterMetadata
 	return &pod
 }
 
+// toVolumeClaimTemplates moves the given persistent volume claims of the pod to volume claim templates.
+// The volume mounts are renamed to the claim names, since a volume claim template is mounted by its name.
+func (d *Deployment) toVolumeClaimTemplates(podspec core.PodSpec, storages []irtypes.Storage, claimNames []string) (core.PodSpec, []core.PersistentVolumeClaim) {
+	pvcStorages := map[string]irtypes.Storage{}
+	for _, st := range storages {
+		if st.StorageType == irtypes.PVCKind && common.IsStringPresent(claimNames, st.Name) {
+			pvcStorages[st.Name] = st
+		}
+	}
+	claimTemplates := []core.PersistentVolumeClaim{}
+	volumeNames := map[string]string{}
+	volumes := []core.Volume{}
+	for _, v := range podspec.Volumes {
+		if v.PersistentVolumeClaim == nil {
+			volumes = append(volumes, v)
+			continue
+		}
+		st, ok := pvcStorages[v.PersistentVolumeClaim.ClaimName]
+		if !ok {
+			volumes = append(volumes, v)
+			continue
+		}
+		volumeNames[v.Name] = st.Name
+		claimTemplate := core.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        st.Name,
+				Annotations: st.Annotations,
+			},
+			Spec: *st.PersistentVolumeClaimSpec.DeepCopy(),
+		}
+		if len(claimTemplate.Spec.AccessModes) == 0 {
+			claimTemplate.Spec.AccessModes = []core.PersistentVolumeAccessMode{core.ReadWriteOnce}
+		}
+		if _, ok := claimTemplate.Spec.Resources.Requests[core.ResourceStorage]; !ok {
+			if claimTemplate.Spec.Resources.Requests == nil {
+				claimTemplate.Spec.Resources.Requests = core.ResourceList{}
+			}
+			claimTemplate.Spec.Resources.Requests[core.ResourceStorage] = common.DefaultPVCSize
+		}
+		claimTemplates = append(claimTemplates, claimTemplate)
+	}
+	if len(claimTemplates) == 0 {
+		return podspec, nil
+	}
+	podspec.Volumes = volumes
+	podspec.Containers = renameVolumeMounts(podspec.Containers, volumeNames)
+	podspec.InitContainers = renameVolumeMounts(podspec.InitContainers, volumeNames)
+	return podspec, claimTemplates
+}
+
+// statefulSetToPodSpec returns the pod spec of the stateful set, with the volume claim templates as persistent volume claims
+func (d *Deployment) statefulSetToPodSpec(statefulSet apps.StatefulSet) core.PodSpec {
+	podspec := *statefulSet.Spec.Template.Spec.DeepCopy()
+	for _, claimTemplate := range statefulSet.Spec.VolumeClaimTemplates {
+		podspec.Volumes = append(podspec.Volumes, core.Volume{
+			Name: claimTemplate.Name,
+			VolumeSource: core.VolumeSource{
+				PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{
+					ClaimName: claimTemplate.Name,
+				},
+			},
+		})
+	}
+	return podspec
+}
+
 func (d *Deployment) toPod(meta metav1.ObjectMeta, podspec core.PodSpec, restartPolicy core.RestartPolicy, cluster collecttypes.ClusterMetadataSpec) *core.Pod {
 	podspec = d.convertVolumesKindsByPolicy(podspec, cluster)
 	podspec.RestartPolicy = restartPolicy
@@ -416,6 +599,39 @@ func (d *Deployment) convertVolumesKindsByPolicy(podspec core.PodSpec, cluster c
 	return podspec
 }
 
+func renameVolumeMounts(containers []core.Container, volumeNames map[string]string) []core.Container {
+	if containers == nil {
+		return nil
+	}
+	nContainers := make([]core.Container, len(containers))
+	for i, container := range containers {
+		if container.VolumeMounts == nil {
+			nContainers[i] = container
+			continue
+		}
+		volMounts := make([]core.VolumeMount, len(container.VolumeMounts))
+		for j, vm := range container.VolumeMounts {
+			if name, ok := volumeNames[vm.Name]; ok {
+				vm.Name = name
+			}
+			volMounts[j] = vm
+		}
+		container.VolumeMounts = volMounts
+		nContainers[i] = container
+	}
+	return nContainers
+}
+
+// isStatefulSet checks if the service gets converted to a StatefulSet
+func isStatefulSet(service irtypes.Service) bool {
+	return service.StatefulSet && !service.Daemon && service.RestartPolicy != core.RestartPolicyNever && service.RestartPolicy != core.RestartPolicyOnFailure
+}
+
+// getHeadlessServiceName returns the name of the headless service which governs the StatefulSet of the service
+func getHeadlessServiceName(name string) string {
+	return name + "-headless"
+}
+
 func getMatchingVolume(vm core.VolumeMount, vList []core.Volume) core.Volume {
 	for _, v := range vList {
 		if strings.Compare(v.Name, vm.Name) == 0 {
@@ -0,0 +1,183 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"k8s.io/apimachinery/pkg/runtime"
+	apps "k8s.io/kubernetes/pkg/apis/apps"
+	batch "k8s.io/kubernetes/pkg/apis/batch"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func getStatefulIR() irtypes.EnhancedIR {
+	ir := irtypes.NewIR()
+	svc := irtypes.NewServiceWithName("db")
+	svc.StatefulSet = true
+	svc.Replicas = 1
+	svc.Containers = []core.Container{{Name: "db", Image: "postgres", VolumeMounts: []core.VolumeMount{{Name: "data", MountPath: "/var/lib/postgresql/data"}}}}
+	svc.Volumes = []core.Volume{{Name: "data", VolumeSource: core.VolumeSource{PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{ClaimName: "dbdata"}}}}
+	ir.Services[svc.Name] = svc
+	ir.Storages = append(ir.Storages, irtypes.Storage{Name: "dbdata", StorageType: irtypes.PVCKind})
+	return irtypes.NewEnhancedIRFromIR(ir)
+}
+
+func getClusterWithKinds(kinds ...string) collecttypes.ClusterMetadata {
+	cluster := collecttypes.NewClusterMetadata("test")
+	cluster.Spec.APIKindVersionMap = map[string][]string{}
+	for _, kind := range kinds {
+		cluster.Spec.APIKindVersionMap[kind] = []string{"v1"}
+	}
+	return cluster
+}
+
+func TestStatefulSet(t *testing.T) {
+	t.Run("stateful service on a cluster supporting StatefulSet", func(t *testing.T) {
+		ir := getStatefulIR()
+		cluster := getClusterWithKinds(statefulSetKind, common.DeploymentKind, string(irtypes.PVCKind), common.ServiceKind)
+		objs := (&APIResource{IAPIResource: new(Deployment)}).ConvertIRToObjects(ir, cluster)
+		if len(objs) != 1 {
+			t.Fatalf("Expected 1 object. Actual: %+v", objs)
+		}
+		statefulSet, ok := objs[0].(*apps.StatefulSet)
+		if !ok {
+			t.Fatalf("Expected a StatefulSet. Actual: %T", objs[0])
+		}
+		if statefulSet.Spec.ServiceName != "db-headless" {
+			t.Fatalf("Expected the service name db-headless. Actual: %s", statefulSet.Spec.ServiceName)
+		}
+		if len(statefulSet.Spec.VolumeClaimTemplates) != 1 || statefulSet.Spec.VolumeClaimTemplates[0].Name != "dbdata" {
+			t.Fatalf("Expected a volume claim template for dbdata. Actual: %+v", statefulSet.Spec.VolumeClaimTemplates)
+		}
+		if len(statefulSet.Spec.Template.Spec.Volumes) != 0 {
+			t.Fatalf("Expected the persistent volume claim to be removed from the volumes. Actual: %+v", statefulSet.Spec.Template.Spec.Volumes)
+		}
+		if mountName := statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts[0].Name; mountName != "dbdata" {
+			t.Fatalf("Expected the volume mount to refer to the claim template dbdata. Actual: %s", mountName)
+		}
+		if ir.Services["db"].Containers[0].VolumeMounts[0].Name != "data" {
+			t.Fatal("The IR should not have been modified")
+		}
+
+		storageObjs := (&APIResource{IAPIResource: new(Storage)}).ConvertIRToObjects(ir, cluster)
+		if len(storageObjs) != 0 {
+			t.Fatalf("Expected no persistent volume claims since they are created by the StatefulSet. Actual: %+v", storageObjs)
+		}
+
+		serviceObjs := (&APIResource{IAPIResource: new(Service)}).ConvertIRToObjects(ir, cluster)
+		headless := false
+		for _, obj := range serviceObjs {
+			if svc, ok := obj.(*core.Service); ok && svc.Name == "db-headless" && svc.Spec.ClusterIP == "None" {
+				headless = true
+			}
+		}
+		if !headless {
+			t.Fatalf("Expected a headless service db-headless. Actual: %+v", serviceObjs)
+		}
+	})
+
+	t.Run("stateful service on a cluster without StatefulSet", func(t *testing.T) {
+		ir := getStatefulIR()
+		cluster := getClusterWithKinds(common.DeploymentKind, string(irtypes.PVCKind), common.ServiceKind)
+		objs := (&APIResource{IAPIResource: new(Deployment)}).ConvertIRToObjects(ir, cluster)
+		if len(objs) != 1 {
+			t.Fatalf("Expected 1 object. Actual: %+v", objs)
+		}
+		if _, ok := objs[0].(*apps.Deployment); !ok {
+			t.Fatalf("Expected a Deployment. Actual: %T", objs[0])
+		}
+		storageObjs := (&APIResource{IAPIResource: new(Storage)}).ConvertIRToObjects(ir, cluster)
+		if len(storageObjs) != 1 {
+			t.Fatalf("Expected the persistent volume claim dbdata. Actual: %+v", storageObjs)
+		}
+	})
+
+	t.Run("StatefulSet converted to a Deployment", func(t *testing.T) {
+		ir := getStatefulIR()
+		statefulSet := new(Deployment).createStatefulSet(ir.Services["db"], ir, getClusterWithKinds(statefulSetKind, string(irtypes.PVCKind)).Spec)
+		cluster := getClusterWithKinds(common.DeploymentKind, string(irtypes.PVCKind))
+		objs, ok := new(Deployment).convertToClusterSupportedKinds(statefulSet, []string{common.DeploymentKind}, []runtime.Object{statefulSet}, ir, cluster)
+		if !ok || len(objs) != 1 {
+			t.Fatalf("Expected the StatefulSet to be converted. Actual: %+v", objs)
+		}
+		deployment, ok := objs[0].(*apps.Deployment)
+		if !ok {
+			t.Fatalf("Expected a Deployment. Actual: %T", objs[0])
+		}
+		volumes := deployment.Spec.Template.Spec.Volumes
+		if len(volumes) != 1 || volumes[0].PersistentVolumeClaim == nil || volumes[0].PersistentVolumeClaim.ClaimName != "dbdata" {
+			t.Fatalf("Expected the volume claim template to be converted to a persistent volume claim. Actual: %+v", volumes)
+		}
+	})
+}
+
+func TestCronJob(t *testing.T) {
+	getScheduledIR := func() irtypes.EnhancedIR {
+		ir := irtypes.NewIR()
+		svc := irtypes.NewServiceWithName("report")
+		svc.Schedule = "0 1 * * *"
+		svc.RestartPolicy = core.RestartPolicyOnFailure
+		svc.Containers = []core.Container{{Name: "report", Image: "report:latest"}}
+		ir.Services[svc.Name] = svc
+		return irtypes.NewEnhancedIRFromIR(ir)
+	}
+
+	t.Run("scheduled service on a cluster supporting CronJob", func(t *testing.T) {
+		cluster := getClusterWithKinds(cronJobKind, jobKind, podKind)
+		objs := (&APIResource{IAPIResource: new(Deployment)}).ConvertIRToObjects(getScheduledIR(), cluster)
+		if len(objs) != 1 {
+			t.Fatalf("Expected 1 object. Actual: %+v", objs)
+		}
+		cronJob, ok := objs[0].(*batch.CronJob)
+		if !ok {
+			t.Fatalf("Expected a CronJob. Actual: %T", objs[0])
+		}
+		if cronJob.Spec.Schedule != "0 1 * * *" {
+			t.Fatalf("Expected the schedule 0 1 * * *. Actual: %s", cronJob.Spec.Schedule)
+		}
+		if cronJob.Spec.JobTemplate.Spec.Template.Spec.RestartPolicy != core.RestartPolicyOnFailure {
+			t.Fatalf("Expected the restart policy OnFailure. Actual: %s", cronJob.Spec.JobTemplate.Spec.Template.Spec.RestartPolicy)
+		}
+	})
+
+	t.Run("scheduled service on a cluster without CronJob", func(t *testing.T) {
+		cluster := getClusterWithKinds(jobKind, podKind)
+		objs := (&APIResource{IAPIResource: new(Deployment)}).ConvertIRToObjects(getScheduledIR(), cluster)
+		if len(objs) != 1 {
+			t.Fatalf("Expected 1 object. Actual: %+v", objs)
+		}
+		if _, ok := objs[0].(*batch.Job); !ok {
+			t.Fatalf("Expected a Job. Actual: %T", objs[0])
+		}
+	})
+
+	t.Run("CronJob converted to a Job", func(t *testing.T) {
+		ir := getScheduledIR()
+		cronJob := new(Deployment).createCronJob(ir.Services["report"], collecttypes.ClusterMetadataSpec{})
+		objs, ok := new(Deployment).convertToClusterSupportedKinds(cronJob, []string{jobKind}, []runtime.Object{cronJob}, ir, getClusterWithKinds(jobKind))
+		if !ok || len(objs) != 1 {
+			t.Fatalf("Expected the CronJob to be converted. Actual: %+v", objs)
+		}
+		if _, ok := objs[0].(*batch.Job); !ok {
+			t.Fatalf("Expected a Job. Actual: %T", objs[0])
+		}
+	})
+}
@@ -17,19 +17,39 @@
 package apiresource
 
 import (
+	"sort"
+
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
 	"github.com/konveyor/move2kube/types"
 	collecttypes "github.com/konveyor/move2kube/types/collection"
 	irtypes "github.com/konveyor/move2kube/types/ir"
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	core "k8s.io/kubernetes/pkg/apis/core"
 	networking "k8s.io/kubernetes/pkg/apis/networking"
 )
 
 const (
 	networkPolicyKind = "NetworkPolicy"
 	networkSelector   = types.GroupName + "/network"
+	// networksNetworkPolicy isolates only the networks found in the source
+	networksNetworkPolicy = "networks"
+	// ingressNetworkPolicy additionally denies the ingress to the services except from the services calling them
+	ingressNetworkPolicy = "ingress"
+	// strictNetworkPolicy additionally denies the egress from the services except to the services they call and to dns
+	strictNetworkPolicy      = "strict"
+	defaultDenyNetworkPolicy = "default-deny"
+	allowDNSNetworkPolicy    = "allow-dns"
+	allowNetworkPolicySuffix = "-allow"
+	anyIPBlock               = "0.0.0.0/0"
+)
+
+var (
+	// dnsPorts are the ports of the cluster dns. The openshift dns pods listen on 5353.
+	dnsPorts = []int{53, 5353}
 )
 
 // NetworkPolicy handles NetworkPolicy objects
@@ -61,9 +81,151 @@ func (d *NetworkPolicy) createNewResources(ir irtypes.EnhancedIR, supportedKinds
 			objs = append(objs, obj)
 		}
 	}
+	return append(objs, d.createDependencyNetworkPolicies(ir)...)
+}
+
+// createDependencyNetworkPolicies creates the network policies allowing only the calls between the services which depend on each other
+func (d *NetworkPolicy) createDependencyNetworkPolicies(ir irtypes.EnhancedIR) []runtime.Object {
+	objs := []runtime.Object{}
+	callers := map[string][]string{}
+	for sn, s := range ir.Services {
+		for _, dependency := range s.Dependencies {
+			if _, ok := ir.Services[dependency]; ok {
+				callers[dependency] = append(callers[dependency], sn)
+			}
+		}
+	}
+	// Without the calls between the services, denying the traffic would break the application
+	if len(callers) == 0 {
+		return objs
+	}
+	message := "How strict should the network policies of the services be?"
+	hints := []string{
+		"networks : only the networks found in the source are isolated",
+		"ingress : the services accept connections only from the services calling them, and from anywhere if exposed",
+		"strict : the services can additionally connect only to the services they call and to dns. The services calling external services are allowed to connect outside the cluster",
+	}
+	strictness := qaengine.FetchSelectAnswer(common.ConfigNetworkPolicyKey, message, hints, networksNetworkPolicy, []string{networksNetworkPolicy, ingressNetworkPolicy, strictNetworkPolicy})
+	if strictness == networksNetworkPolicy {
+		return objs
+	}
+	strict := strictness == strictNetworkPolicy
+	policyTypes := []networking.PolicyType{networking.PolicyTypeIngress}
+	if strict {
+		policyTypes = append(policyTypes, networking.PolicyTypeEgress)
+	}
+	objs = append(objs, d.newNetworkPolicy(defaultDenyNetworkPolicy, getAllServicesSelector(), policyTypes))
+	if strict {
+		np := d.newNetworkPolicy(allowDNSNetworkPolicy, getAllServicesSelector(), []networking.PolicyType{networking.PolicyTypeEgress})
+		ports := []networking.NetworkPolicyPort{}
+		for _, port := range dnsPorts {
+			for _, protocol := range []core.Protocol{core.ProtocolUDP, core.ProtocolTCP} {
+				ports = append(ports, newNetworkPolicyPort(protocol, intstr.FromInt(port)))
+			}
+		}
+		np.Spec.Egress = []networking.NetworkPolicyEgressRule{{Ports: ports}}
+		objs = append(objs, np)
+	}
+	serviceNames := []string{}
+	for sn := range ir.Services {
+		serviceNames = append(serviceNames, sn)
+	}
+	sort.Strings(serviceNames)
+	for _, sn := range serviceNames {
+		s := ir.Services[sn]
+		np := d.newNetworkPolicy(sn+allowNetworkPolicySuffix, metav1.LabelSelector{MatchLabels: getServiceLabels(sn)}, nil)
+		ports := d.getPodPorts(s)
+		if len(callers[sn]) != 0 {
+			sort.Strings(callers[sn])
+			rule := networking.NetworkPolicyIngressRule{Ports: ports}
+			for _, caller := range callers[sn] {
+				rule.From = append(rule.From, networking.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: getServiceLabels(caller)}})
+			}
+			np.Spec.Ingress = append(np.Spec.Ingress, rule)
+		}
+		if s.HasValidAnnotation(common.ExposeSelector) || s.OnlyIngress {
+			np.Spec.Ingress = append(np.Spec.Ingress, networking.NetworkPolicyIngressRule{Ports: ports})
+		}
+		if len(np.Spec.Ingress) != 0 {
+			np.Spec.PolicyTypes = append(np.Spec.PolicyTypes, networking.PolicyTypeIngress)
+		}
+		if strict && len(s.Dependencies) != 0 {
+			rule := networking.NetworkPolicyEgressRule{}
+			external := false
+			for _, dependency := range s.Dependencies {
+				if _, ok := ir.Services[dependency]; !ok {
+					external = true
+					continue
+				}
+				rule.To = append(rule.To, networking.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: getServiceLabels(dependency)}})
+			}
+			if external {
+				logrus.Warnf("The service %s calls services which are not part of the application. Allowing it to connect anywhere outside the cluster", sn)
+				rule.To = append(rule.To, networking.NetworkPolicyPeer{IPBlock: &networking.IPBlock{CIDR: anyIPBlock}})
+			}
+			np.Spec.Egress = []networking.NetworkPolicyEgressRule{rule}
+			np.Spec.PolicyTypes = append(np.Spec.PolicyTypes, networking.PolicyTypeEgress)
+		}
+		if len(np.Spec.PolicyTypes) != 0 {
+			objs = append(objs, np)
+		}
+	}
 	return objs
 }
 
+// getPodPorts returns the pod ports the service forwards to. It returns nil if all the ports should be allowed.
+func (d *NetworkPolicy) getPodPorts(service irtypes.Service) []networking.NetworkPolicyPort {
+	ports := []networking.NetworkPolicyPort{}
+	for _, pf := range service.ServiceToPodPortForwardings {
+		port := intstr.FromInt(int(pf.PodPort.Number))
+		if pf.PodPort.Number == 0 {
+			if pf.PodPort.Name == "" {
+				return nil
+			}
+			port = intstr.FromString(pf.PodPort.Name)
+		}
+		protocol := core.ProtocolTCP
+		for _, c := range service.Containers {
+			for _, cp := range c.Ports {
+				matches := (pf.PodPort.Number != 0 && cp.ContainerPort == pf.PodPort.Number) || (pf.PodPort.Name != "" && cp.Name == pf.PodPort.Name)
+				if matches && cp.Protocol != "" {
+					protocol = cp.Protocol
+				}
+			}
+		}
+		ports = append(ports, newNetworkPolicyPort(protocol, port))
+	}
+	if len(ports) == 0 {
+		return nil
+	}
+	return ports
+}
+
+func (d *NetworkPolicy) newNetworkPolicy(name string, podSelector metav1.LabelSelector, policyTypes []networking.PolicyType) *networking.NetworkPolicy {
+	return &networking.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       networkPolicyKind,
+			APIVersion: networking.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: networking.NetworkPolicySpec{
+			PodSelector: podSelector,
+			PolicyTypes: policyTypes,
+		},
+	}
+}
+
+func newNetworkPolicyPort(protocol core.Protocol, port intstr.IntOrString) networking.NetworkPolicyPort {
+	return networking.NetworkPolicyPort{Protocol: &protocol, Port: &port}
+}
+
+// getAllServicesSelector selects the pods of all the services
+func getAllServicesSelector() metav1.LabelSelector {
+	return metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: selector, Operator: metav1.LabelSelectorOpExists}}}
+}
+
 // convertToClusterSupportedKinds converts kinds to cluster supported kinds
 func (d *NetworkPolicy) convertToClusterSupportedKinds(obj runtime.Object, supportedKinds []string, otherobjs []runtime.Object, _ irtypes.EnhancedIR, targetCluster collecttypes.ClusterMetadata) ([]runtime.Object, bool) {
 	if common.IsStringPresent(d.getSupportedKinds(), obj.GetObjectKind().GroupVersionKind().Kind) {
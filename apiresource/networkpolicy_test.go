@@ -21,6 +21,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
 	"github.com/konveyor/move2kube/types/collection"
 	irtypes "github.com/konveyor/move2kube/types/ir"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -237,6 +238,88 @@ func TestConvertToClusterSupportedKinds(t *testing.T) {
 	})
 }
 
+// getDependencyIR returns an IR in which web calls api, which calls db and an external payments service
+func getDependencyIR() irtypes.EnhancedIR {
+	ir := irtypes.NewIR()
+	for _, name := range []string{"web", "api", "db"} {
+		s := irtypes.NewServiceWithName(name)
+		s.ServiceToPodPortForwardings = []irtypes.ServiceToPodPortForwarding{
+			{ServicePort: networking.ServiceBackendPort{Number: 80}, PodPort: networking.ServiceBackendPort{Number: 8080}},
+		}
+		ir.Services[name] = s
+	}
+	web := ir.Services["web"]
+	web.Annotations = map[string]string{common.ExposeSelector: common.AnnotationLabelValue}
+	web.Dependencies = []string{"api"}
+	ir.Services["web"] = web
+	api := ir.Services["api"]
+	api.Dependencies = []string{"db", "payments.example.com"}
+	ir.Services["api"] = api
+	return irtypes.NewEnhancedIRFromIR(ir)
+}
+
+func getDependencyNetworkPolicies() map[string]*networking.NetworkPolicy {
+	policies := map[string]*networking.NetworkPolicy{}
+	for _, obj := range new(NetworkPolicy).createDependencyNetworkPolicies(getDependencyIR()) {
+		np := obj.(*networking.NetworkPolicy)
+		policies[np.Name] = np
+	}
+	return policies
+}
+
+func TestDependencyNetworkPolicies(t *testing.T) {
+	qaengine.StartEngine(true, 0, true)
+
+	t.Run("only the networks are isolated by default", func(t *testing.T) {
+		if policies := getDependencyNetworkPolicies(); len(policies) != 0 {
+			t.Fatalf("Expected no dependency network policies by default. Actual: %+v", policies)
+		}
+	})
+	t.Run("ingress", func(t *testing.T) {
+		qaengine.SetupConfigFile("", []string{common.ConfigNetworkPolicyKey + `="` + ingressNetworkPolicy + `"`}, nil, nil)
+		policies := getDependencyNetworkPolicies()
+		if len(policies) != 4 {
+			t.Fatalf("Expected a default deny policy and a policy for each service. Actual: %+v", policies)
+		}
+		defaultDeny := policies[defaultDenyNetworkPolicy]
+		if defaultDeny == nil || len(defaultDeny.Spec.Ingress) != 0 || len(defaultDeny.Spec.PolicyTypes) != 1 || defaultDeny.Spec.PolicyTypes[0] != networking.PolicyTypeIngress {
+			t.Fatalf("Expected the ingress to be denied by default. Actual: %+v", defaultDeny)
+		}
+		apiPolicy := policies["api"+allowNetworkPolicySuffix]
+		if apiPolicy == nil || len(apiPolicy.Spec.Ingress) != 1 || apiPolicy.Spec.Ingress[0].From[0].PodSelector.MatchLabels[selector] != "web" {
+			t.Fatalf("Expected api to accept connections from web. Actual: %+v", apiPolicy)
+		}
+		if apiPolicy.Spec.Ingress[0].Ports[0].Port.IntValue() != 8080 {
+			t.Fatalf("Expected api to accept connections on the port 8080. Actual: %+v", apiPolicy.Spec.Ingress[0].Ports)
+		}
+		if len(apiPolicy.Spec.Egress) != 0 {
+			t.Fatalf("Expected the egress not to be restricted. Actual: %+v", apiPolicy.Spec.Egress)
+		}
+		webPolicy := policies["web"+allowNetworkPolicySuffix]
+		if webPolicy == nil || len(webPolicy.Spec.Ingress) != 1 || len(webPolicy.Spec.Ingress[0].From) != 0 {
+			t.Fatalf("Expected the exposed service web to accept connections from anywhere. Actual: %+v", webPolicy)
+		}
+	})
+	t.Run("strict allows the egress to the dependencies and the external hosts", func(t *testing.T) {
+		qaengine.SetupConfigFile("", []string{common.ConfigNetworkPolicyKey + `="` + strictNetworkPolicy + `"`}, nil, nil)
+		policies := getDependencyNetworkPolicies()
+		if policies[allowDNSNetworkPolicy] == nil {
+			t.Fatalf("Expected the dns to be allowed. Actual: %+v", policies)
+		}
+		apiPolicy := policies["api"+allowNetworkPolicySuffix]
+		if apiPolicy == nil || len(apiPolicy.Spec.Egress) != 1 {
+			t.Fatalf("Expected an egress rule for api. Actual: %+v", apiPolicy)
+		}
+		to := apiPolicy.Spec.Egress[0].To
+		if len(to) != 2 || to[0].PodSelector == nil || to[0].PodSelector.MatchLabels[selector] != "db" {
+			t.Fatalf("Expected api to be allowed to connect to db. Actual: %+v", to)
+		}
+		if to[1].IPBlock == nil || to[1].IPBlock.CIDR != anyIPBlock {
+			t.Fatalf("Expected api to be allowed to connect outside the cluster for payments.example.com. Actual: %+v", to)
+		}
+	})
+}
+
 func helperCreateNetworkPolicy(name string) *networking.NetworkPolicy {
 	return &networking.NetworkPolicy{
 		TypeMeta: metav1.TypeMeta{
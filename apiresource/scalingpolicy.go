@@ -0,0 +1,202 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/k8sschema"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	okdappsv1 "github.com/openshift/api/apps/v1"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/kubernetes/pkg/apis/autoscaling"
+	core "k8s.io/kubernetes/pkg/apis/core"
+	"k8s.io/kubernetes/pkg/apis/policy"
+)
+
+const (
+	// horizontalPodAutoscalerKind defines HorizontalPodAutoscaler Kind
+	horizontalPodAutoscalerKind = "HorizontalPodAutoscaler"
+	// podDisruptionBudgetKind defines PodDisruptionBudget Kind
+	podDisruptionBudgetKind = "PodDisruptionBudget"
+)
+
+// ScalingPolicy handles the HorizontalPodAutoscaler and PodDisruptionBudget objects
+type ScalingPolicy struct {
+}
+
+// getSupportedKinds returns kinds supported by the scaling policy
+func (s *ScalingPolicy) getSupportedKinds() []string {
+	return []string{horizontalPodAutoscalerKind, podDisruptionBudgetKind}
+}
+
+// createNewResources converts ir to runtime objects
+func (s *ScalingPolicy) createNewResources(ir irtypes.EnhancedIR, supportedKinds []string, targetCluster collecttypes.ClusterMetadata) []runtime.Object {
+	objs := []runtime.Object{}
+	for _, service := range ir.Services {
+		if service.Autoscaling.MaxReplicas != 0 {
+			if common.IsStringPresent(supportedKinds, horizontalPodAutoscalerKind) {
+				objs = append(objs, s.createHorizontalPodAutoscaler(service, targetCluster.Spec))
+			} else {
+				logrus.Warnf("HorizontalPodAutoscaler not supported by target cluster. Not autoscaling the service %s.", service.Name)
+			}
+		}
+		if service.MaxUnavailable != 0 {
+			if common.IsStringPresent(supportedKinds, podDisruptionBudgetKind) {
+				objs = append(objs, s.createPodDisruptionBudget(service))
+			} else {
+				logrus.Warnf("PodDisruptionBudget not supported by target cluster. Not creating a disruption budget for the service %s.", service.Name)
+			}
+		}
+	}
+	return objs
+}
+
+// convertToClusterSupportedKinds converts objects to kind supported by the cluster
+func (s *ScalingPolicy) convertToClusterSupportedKinds(obj runtime.Object, supportedKinds []string, otherobjs []runtime.Object, ir irtypes.EnhancedIR, targetCluster collecttypes.ClusterMetadata) ([]runtime.Object, bool) {
+	if obj.GetObjectKind().GroupVersionKind().Kind == horizontalPodAutoscalerKind {
+		return []runtime.Object{s.toPreferredHorizontalPodAutoscalerVersion(obj, targetCluster.Spec)}, true
+	}
+	if common.IsStringPresent(s.getSupportedKinds(), obj.GetObjectKind().GroupVersionKind().Kind) {
+		return []runtime.Object{obj}, true
+	}
+	return nil, false
+}
+
+func (s *ScalingPolicy) createHorizontalPodAutoscaler(service irtypes.Service, cluster collecttypes.ClusterMetadataSpec) *autoscaling.HorizontalPodAutoscaler {
+	minReplicas := int32(service.Autoscaling.MinReplicas)
+	metrics := []autoscaling.MetricSpec{}
+	resourceTargets := []struct {
+		name       core.ResourceName
+		percentage int
+	}{
+		{name: core.ResourceCPU, percentage: service.Autoscaling.TargetCPUUtilizationPercentage},
+		{name: core.ResourceMemory, percentage: service.Autoscaling.TargetMemoryUtilizationPercentage},
+	}
+	for _, resourceTarget := range resourceTargets {
+		if resourceTarget.percentage == 0 {
+			continue
+		}
+		utilization := int32(resourceTarget.percentage)
+		metrics = append(metrics, autoscaling.MetricSpec{
+			Type: autoscaling.ResourceMetricSourceType,
+			Resource: &autoscaling.ResourceMetricSource{
+				Name: resourceTarget.name,
+				Target: autoscaling.MetricTarget{
+					Type:               autoscaling.UtilizationMetricType,
+					AverageUtilization: &utilization,
+				},
+			},
+		})
+	}
+	hpa := &autoscaling.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       horizontalPodAutoscalerKind,
+			APIVersion: autoscaling.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   service.Name,
+			Labels: getServiceLabels(service.Name),
+		},
+		Spec: autoscaling.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: s.getScaleTargetRef(service, cluster),
+			MinReplicas:    &minReplicas,
+			MaxReplicas:    int32(service.Autoscaling.MaxReplicas),
+			Metrics:        metrics,
+		},
+	}
+	logrus.Debugf("Created HorizontalPodAutoscaler for %s", service.Name)
+	return hpa
+}
+
+func (s *ScalingPolicy) createPodDisruptionBudget(service irtypes.Service) *policy.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt(service.MaxUnavailable)
+	pdb := &policy.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       podDisruptionBudgetKind,
+			APIVersion: policy.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   service.Name,
+			Labels: getServiceLabels(service.Name),
+		},
+		Spec: policy.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: getServiceLabels(service.Name),
+			},
+		},
+	}
+	logrus.Debugf("Created PodDisruptionBudget for %s", service.Name)
+	return pdb
+}
+
+// getScaleTargetRef returns the reference to the workload which Deployment creates for the service
+func (s *ScalingPolicy) getScaleTargetRef(service irtypes.Service, cluster collecttypes.ClusterMetadataSpec) autoscaling.CrossVersionObjectReference {
+	ref := autoscaling.CrossVersionObjectReference{Name: service.Name, Kind: common.DeploymentKind, APIVersion: appsv1.SchemeGroupVersion.String()}
+	if isStatefulSet(service) && cluster.GetSupportedVersions(statefulSetKind) != nil {
+		ref.Kind = statefulSetKind
+	} else if cluster.GetSupportedVersions(deploymentConfigKind) != nil {
+		ref.Kind = deploymentConfigKind
+		ref.APIVersion = okdappsv1.SchemeGroupVersion.String()
+	} else if cluster.GetSupportedVersions(common.DeploymentKind) == nil && cluster.GetSupportedVersions(replicationControllerKind) != nil {
+		ref.Kind = replicationControllerKind
+		ref.APIVersion = corev1.SchemeGroupVersion.String()
+	}
+	return ref
+}
+
+// toPreferredHorizontalPodAutoscalerVersion converts the HorizontalPodAutoscaler to the newest version supported by the cluster,
+// since only autoscaling/v2beta2 supports scaling on memory utilization
+func (s *ScalingPolicy) toPreferredHorizontalPodAutoscalerVersion(obj runtime.Object, cluster collecttypes.ClusterMetadataSpec) runtime.Object {
+	versions := cluster.GetSupportedVersions(horizontalPodAutoscalerKind)
+	for _, gv := range []schema.GroupVersion{autoscalingv2beta2.SchemeGroupVersion, autoscalingv1.SchemeGroupVersion} {
+		if !common.IsStringPresent(versions, gv.String()) {
+			continue
+		}
+		newobj, err := k8sschema.ConvertToVersion(obj, gv)
+		if err != nil {
+			logrus.Debugf("Unable to convert the HorizontalPodAutoscaler to %s : %s", gv, err)
+			continue
+		}
+		if hpa, ok := obj.(*autoscaling.HorizontalPodAutoscaler); ok && gv == autoscalingv1.SchemeGroupVersion {
+			for _, metric := range hpa.Spec.Metrics {
+				if metric.Resource != nil && metric.Resource.Name == core.ResourceMemory {
+					logrus.Warnf("The target cluster does not support %s. The memory utilization target of the HorizontalPodAutoscaler %s is dropped.", autoscalingv2beta2.SchemeGroupVersion, hpa.Name)
+					break
+				}
+			}
+		}
+		return newobj
+	}
+	return obj
+}
+
+// isClusterSupportedHorizontalPodAutoscaler returns true for the HorizontalPodAutoscalers which are already in a version supported by the cluster,
+// so that the version chosen in convertToClusterSupportedKinds is not replaced by the version the cluster lists first
+func isClusterSupportedHorizontalPodAutoscaler(obj runtime.Object, cluster collecttypes.ClusterMetadataSpec) bool {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	return gvk.Kind == horizontalPodAutoscalerKind && common.IsStringPresent(cluster.GetSupportedVersions(horizontalPodAutoscalerKind), gvk.GroupVersion().String())
+}
@@ -0,0 +1,143 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/kubernetes/pkg/apis/policy"
+)
+
+func getScaledIR() irtypes.EnhancedIR {
+	ir := irtypes.NewIR()
+	svc := irtypes.NewServiceWithName("web")
+	svc.Replicas = 2
+	svc.Autoscaling = irtypes.Autoscaling{MinReplicas: 2, MaxReplicas: 5, TargetCPUUtilizationPercentage: 70, TargetMemoryUtilizationPercentage: 60}
+	svc.MaxUnavailable = 1
+	ir.Services[svc.Name] = svc
+	ir.Services["worker"] = irtypes.NewServiceWithName("worker")
+	return irtypes.NewEnhancedIRFromIR(ir)
+}
+
+func TestScalingPolicy(t *testing.T) {
+	t.Run("autoscaling v2beta2 and pod disruption budget", func(t *testing.T) {
+		cluster := getClusterWithKinds(common.DeploymentKind, podDisruptionBudgetKind)
+		cluster.Spec.APIKindVersionMap[horizontalPodAutoscalerKind] = []string{"autoscaling/v1", "autoscaling/v2beta2"}
+		objs := (&APIResource{IAPIResource: new(ScalingPolicy)}).ConvertIRToObjects(getScaledIR(), cluster)
+		if len(objs) != 2 {
+			t.Fatalf("Expected 2 objects. Actual: %+v", objs)
+		}
+		var hpa *autoscalingv2beta2.HorizontalPodAutoscaler
+		var pdb *policy.PodDisruptionBudget
+		for _, obj := range objs {
+			switch o := obj.(type) {
+			case *autoscalingv2beta2.HorizontalPodAutoscaler:
+				hpa = o
+			case *policy.PodDisruptionBudget:
+				pdb = o
+			}
+		}
+		if hpa == nil || pdb == nil {
+			t.Fatalf("Expected a HorizontalPodAutoscaler and a PodDisruptionBudget. Actual: %+v", objs)
+		}
+		if hpa.APIVersion != "autoscaling/v2beta2" {
+			t.Fatalf("Expected the api version autoscaling/v2beta2. Actual: %s", hpa.APIVersion)
+		}
+		if *hpa.Spec.MinReplicas != 2 || hpa.Spec.MaxReplicas != 5 || len(hpa.Spec.Metrics) != 2 {
+			t.Fatalf("Expected 2 to 5 replicas scaled on cpu and memory. Actual: %+v", hpa.Spec)
+		}
+		if hpa.Spec.ScaleTargetRef.Kind != common.DeploymentKind || hpa.Spec.ScaleTargetRef.APIVersion != "apps/v1" {
+			t.Fatalf("Expected the HorizontalPodAutoscaler to scale the Deployment. Actual: %+v", hpa.Spec.ScaleTargetRef)
+		}
+		if pdb.Spec.MaxUnavailable.IntValue() != 1 {
+			t.Fatalf("Expected 1 max unavailable pod. Actual: %+v", pdb.Spec.MaxUnavailable)
+		}
+	})
+
+	t.Run("autoscaling v1 on a cluster without v2beta2", func(t *testing.T) {
+		cluster := getClusterWithKinds(deploymentConfigKind)
+		cluster.Spec.APIKindVersionMap[horizontalPodAutoscalerKind] = []string{"autoscaling/v1"}
+		objs := (&APIResource{IAPIResource: new(ScalingPolicy)}).ConvertIRToObjects(getScaledIR(), cluster)
+		if len(objs) != 1 {
+			t.Fatalf("Expected 1 object. Actual: %+v", objs)
+		}
+		hpa, ok := objs[0].(*autoscalingv1.HorizontalPodAutoscaler)
+		if !ok {
+			t.Fatalf("Expected an autoscaling/v1 HorizontalPodAutoscaler. Actual: %T", objs[0])
+		}
+		if hpa.Spec.TargetCPUUtilizationPercentage == nil || *hpa.Spec.TargetCPUUtilizationPercentage != 70 {
+			t.Fatalf("Expected the target cpu utilization 70. Actual: %+v", hpa.Spec.TargetCPUUtilizationPercentage)
+		}
+		if hpa.Spec.ScaleTargetRef.Kind != deploymentConfigKind {
+			t.Fatalf("Expected the HorizontalPodAutoscaler to scale the DeploymentConfig. Actual: %+v", hpa.Spec.ScaleTargetRef)
+		}
+	})
+
+	t.Run("autoscaling v1 on a cluster with autoscaling v2 but without v2beta2", func(t *testing.T) {
+		cluster := getClusterWithKinds(common.DeploymentKind)
+		cluster.Spec.APIKindVersionMap[horizontalPodAutoscalerKind] = []string{"autoscaling/v1", "autoscaling/v2"}
+		hook := logrustest.NewGlobal()
+		defer hook.Reset()
+		objs := (&APIResource{IAPIResource: new(ScalingPolicy)}).ConvertIRToObjects(getScaledIR(), cluster)
+		if len(objs) != 1 {
+			t.Fatalf("Expected 1 object. Actual: %+v", objs)
+		}
+		hpa, ok := objs[0].(*autoscalingv1.HorizontalPodAutoscaler)
+		if !ok {
+			t.Fatalf("Expected an autoscaling/v1 HorizontalPodAutoscaler. Actual: %T", objs[0])
+		}
+		if hpa.Spec.TargetCPUUtilizationPercentage == nil || *hpa.Spec.TargetCPUUtilizationPercentage != 70 {
+			t.Fatalf("Expected the target cpu utilization 70. Actual: %+v", hpa.Spec.TargetCPUUtilizationPercentage)
+		}
+		warned := false
+		for _, entry := range hook.AllEntries() {
+			if entry.Level == logrus.WarnLevel && strings.Contains(entry.Message, "memory utilization target") {
+				warned = true
+			}
+		}
+		if !warned {
+			t.Fatalf("Expected a warning about the dropped memory utilization target. Actual: %+v", hook.AllEntries())
+		}
+	})
+}
+
+func TestConvertVersionKeepsHorizontalPodAutoscalerVersion(t *testing.T) {
+	cluster := getClusterWithKinds(common.DeploymentKind)
+	cluster.Spec.APIKindVersionMap[horizontalPodAutoscalerKind] = []string{"autoscaling/v1", "autoscaling/v2beta2"}
+	objs := (&APIResource{IAPIResource: new(ScalingPolicy)}).ConvertIRToObjects(getScaledIR(), cluster)
+	convertedObjs, err := convertVersion(objs, cluster.Spec)
+	if err != nil {
+		t.Fatalf("Unable to convert the objects : %s", err)
+	}
+	if len(convertedObjs) != 1 {
+		t.Fatalf("Expected 1 object. Actual: %+v", convertedObjs)
+	}
+	hpa, ok := convertedObjs[0].(*autoscalingv2beta2.HorizontalPodAutoscaler)
+	if !ok {
+		t.Fatalf("Expected the autoscaling/v2beta2 HorizontalPodAutoscaler to be kept, although the cluster lists autoscaling/v1 first. Actual: %T", convertedObjs[0])
+	}
+	if len(hpa.Spec.Metrics) != 2 {
+		t.Fatalf("Expected the cpu and memory metrics to be kept. Actual: %+v", hpa.Spec.Metrics)
+	}
+}
@@ -0,0 +1,292 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/k8sschema/externalsecrets"
+	"github.com/konveyor/move2kube/k8sschema/sealedsecrets"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// secretsOutputSecret writes the secrets as plain Secrets
+	secretsOutputSecret = "Secret"
+	// secretsOutputSealedSecret writes the secrets as SealedSecrets encrypted using the public certificate of the sealed secrets controller
+	secretsOutputSealedSecret = "SealedSecret"
+	// secretsOutputExternalSecret writes the secrets as ExternalSecrets referring to the values in a secret store
+	secretsOutputExternalSecret = "ExternalSecret"
+	// secretsOutputSOPS writes the secrets as Secrets encrypted using SOPS
+	secretsOutputSOPS = "SOPS"
+	sopsCommand       = "sops"
+	// sopsEncryptedRegex limits the encryption to the values of the secrets, so that the files can still be reviewed and applied by name
+	sopsEncryptedRegex = "^(data|stringData)$"
+)
+
+var externalSecretsRefreshInterval = metav1.Duration{Duration: time.Hour}
+
+// getSecretsOutput returns the kind of the objects the secrets are written as
+func getSecretsOutput() string {
+	hints := []string{
+		"Secret : the values are written in plain text, so the yamls should not be committed to a repository",
+		"SealedSecret : the values are encrypted using the public certificate of the Bitnami sealed secrets controller in the cluster",
+		"ExternalSecret : the values are fetched from a secret store by the External Secrets Operator and are not written",
+		"SOPS : the values are encrypted using the sops CLI",
+	}
+	return qaengine.FetchSelectAnswer(common.ConfigSecretsOutputKey, "How should the secrets be written?", hints, secretsOutputSecret, []string{secretsOutputSecret, secretsOutputSealedSecret, secretsOutputExternalSecret, secretsOutputSOPS})
+}
+
+// createSecretObject creates the object the secret is written as.
+// When the values cannot be encrypted, a Secret without the values is created, so that no plain text is written.
+func (s *Storage) createSecretObject(st irtypes.Storage, secretsOutput string) runtime.Object {
+	switch secretsOutput {
+	case secretsOutputSealedSecret:
+		certPath := qaengine.FetchStringAnswer(common.ConfigSealedSecretsCertKey, "Provide the path of the public certificate of the sealed secrets controller", []string{"The certificate can be fetched using `kubeseal --fetch-cert`"}, "")
+		publicKey, err := getSealingPublicKey(certPath)
+		if err != nil {
+			logrus.Errorf("Unable to seal the secret %s. Writing it without the values : %s", st.Name, err)
+			return s.createSecret(clearSecretContent(st))
+		}
+		sealedSecret, err := s.createSealedSecret(st, publicKey)
+		if err != nil {
+			logrus.Errorf("Unable to seal the secret %s. Writing it without the values : %s", st.Name, err)
+			return s.createSecret(clearSecretContent(st))
+		}
+		return sealedSecret
+	case secretsOutputExternalSecret:
+		storeName := qaengine.FetchStringAnswer(common.ConfigExternalSecretsStoreNameKey, "Provide the name of the secret store of the external secrets", []string{"The values of each secret are fetched from the key with the name of the secret"}, common.DefaultProjectName)
+		storeKind := qaengine.FetchSelectAnswer(common.ConfigExternalSecretsStoreKindKey, "Select the kind of the secret store of the external secrets", nil, externalsecrets.SecretStoreKind, []string{externalsecrets.SecretStoreKind, externalsecrets.ClusterSecretStoreKind})
+		logrus.Infof("The values of the secret %s have to be stored in the key %s of the secret store %s", st.Name, common.MakeFileNameCompliant(st.Name), storeName)
+		return s.createExternalSecret(st, externalsecrets.SecretStoreRef{Name: storeName, Kind: storeKind})
+	}
+	return s.createSecret(st)
+}
+
+func (s *Storage) createSealedSecret(st irtypes.Storage, publicKey *rsa.PublicKey) (*sealedsecrets.SealedSecret, error) {
+	secret := s.createSecret(st)
+	encryptedData := map[string]string{}
+	for k, v := range secret.Data {
+		encrypted, err := sealSecretValue(publicKey, v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt the value of the key %s : %s", k, err)
+		}
+		encryptedData[k] = base64.StdEncoding.EncodeToString(encrypted)
+	}
+	// The namespace is not known yet, so the secret is sealed for the whole cluster
+	annotations := map[string]string{sealedsecrets.ClusterWideAnnotation: "true"}
+	templateAnnotations := map[string]string{sealedsecrets.ClusterWideAnnotation: "true"}
+	for k, v := range secret.Annotations {
+		templateAnnotations[k] = v
+	}
+	return &sealedsecrets.SealedSecret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       sealedsecrets.SealedSecretKind,
+			APIVersion: sealedsecrets.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        secret.Name,
+			Annotations: annotations,
+		},
+		Spec: sealedsecrets.SealedSecretSpec{
+			Template: sealedsecrets.SecretTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Name: secret.Name, Annotations: templateAnnotations},
+				Type:       string(secret.Type),
+			},
+			EncryptedData: encryptedData,
+		},
+	}, nil
+}
+
+func (s *Storage) createExternalSecret(st irtypes.Storage, storeRef externalsecrets.SecretStoreRef) *externalsecrets.ExternalSecret {
+	secret := s.createSecret(st)
+	keys := []string{}
+	for k := range secret.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	data := []externalsecrets.ExternalSecretData{}
+	for _, k := range keys {
+		data = append(data, externalsecrets.ExternalSecretData{
+			SecretKey: k,
+			RemoteRef: externalsecrets.ExternalSecretDataRemoteRef{Key: secret.Name, Property: k},
+		})
+	}
+	refreshInterval := externalSecretsRefreshInterval
+	return &externalsecrets.ExternalSecret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       externalsecrets.ExternalSecretKind,
+			APIVersion: externalsecrets.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        secret.Name,
+			Annotations: secret.Annotations,
+		},
+		Spec: externalsecrets.ExternalSecretSpec{
+			SecretStoreRef:  storeRef,
+			Target:          externalsecrets.ExternalSecretTarget{Name: secret.Name, Template: &externalsecrets.ExternalSecretTemplate{Type: string(secret.Type)}},
+			RefreshInterval: &refreshInterval,
+			Data:            data,
+		},
+	}
+}
+
+// getSealingPublicKey reads the public key from the PEM encoded certificate of the sealed secrets controller
+func getSealingPublicKey(certPath string) (*rsa.PublicKey, error) {
+	if certPath == "" {
+		return nil, fmt.Errorf("the path of the certificate is empty")
+	}
+	certBytes, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the certificate at path %s : %s", certPath, err)
+	}
+	block, _ := pem.Decode(certBytes)
+	if block == nil {
+		return nil, fmt.Errorf("the file at path %s is not PEM encoded", certPath)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the certificate at path %s : %s", certPath, err)
+	}
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("the certificate at path %s does not have an RSA public key", certPath)
+	}
+	return publicKey, nil
+}
+
+// sealSecretValue encrypts the value the same way as kubeseal does for cluster wide secrets.
+// A random session key encrypts the value using AES-GCM and the session key is encrypted using RSA-OAEP.
+// The output is the length of the encrypted session key, the encrypted session key and the encrypted value.
+func sealSecretValue(publicKey *rsa.PublicKey, value []byte) ([]byte, error) {
+	sessionKey := make([]byte, 32)
+	if _, err := rand.Read(sessionKey); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// The label is empty for cluster wide secrets
+	encryptedSessionKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, sessionKey, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	encrypted := make([]byte, 2)
+	binary.BigEndian.PutUint16(encrypted, uint16(len(encryptedSessionKey)))
+	encrypted = append(encrypted, encryptedSessionKey...)
+	// The session key is used only once, so the nonce can be zero
+	zeroNonce := make([]byte, aead.NonceSize())
+	return aead.Seal(encrypted, zeroNonce, value, nil), nil
+}
+
+// encryptSecretFiles encrypts the values in the written secrets using SOPS, when the secrets are to be encrypted using SOPS.
+// When sops fails, the secret is written again without the values, so that no plain text is written.
+func encryptSecretFiles(outputPath string, objs []runtime.Object, filesWritten []string) {
+	secrets := []runtime.Object{}
+	for _, obj := range objs {
+		if obj.GetObjectKind().GroupVersionKind().Kind == string(irtypes.SecretKind) {
+			secrets = append(secrets, obj)
+		}
+	}
+	if len(secrets) == 0 || getSecretsOutput() != secretsOutputSOPS {
+		return
+	}
+	recipients := qaengine.FetchStringAnswer(common.ConfigSOPSAgeRecipientsKey, "Provide the age recipients the secrets should be encrypted for", []string{"Use commas to separate multiple recipients", "Leave empty to use the creation rules in the .sops.yaml file"}, "")
+	args := []string{"--encrypt", "--in-place", "--encrypted-regex", sopsEncryptedRegex}
+	if recipients = strings.TrimSpace(recipients); recipients != "" {
+		args = append(args, "--age", recipients)
+	}
+	_, lookErr := exec.LookPath(sopsCommand)
+	for _, secret := range secrets {
+		yamlPath := filepath.Join(outputPath, getFilename(secret))
+		if !common.IsStringPresent(filesWritten, yamlPath) {
+			continue
+		}
+		if lookErr != nil {
+			logrus.Errorf("Unable to encrypt the secret at path %s using %s. Writing it without the values : %s", yamlPath, sopsCommand, lookErr)
+		} else if output, err := exec.Command(sopsCommand, append(args, yamlPath)...).CombinedOutput(); err != nil {
+			logrus.Errorf("Unable to encrypt the secret at path %s using %s. Writing it without the values : %s\n%s", yamlPath, sopsCommand, err, output)
+		} else {
+			continue
+		}
+		if err := writeClearedSecret(yamlPath, secret); err != nil {
+			logrus.Errorf("Failed to write the secret without the values to the file at path %s . Error: %q", yamlPath, err)
+		}
+	}
+}
+
+// writeClearedSecret writes the secret with its values emptied
+func writeClearedSecret(yamlPath string, secret runtime.Object) error {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(secret)
+	if err != nil {
+		return err
+	}
+	obj := &unstructured.Unstructured{Object: u}
+	for _, field := range []string{"data", "stringData"} {
+		values, ok, err := unstructured.NestedMap(u, field)
+		if err != nil || !ok {
+			continue
+		}
+		for k := range values {
+			values[k] = ""
+		}
+		if err := unstructured.SetNestedMap(u, values, field); err != nil {
+			return err
+		}
+	}
+	yamlBytes, err := common.MarshalObjToYaml(obj)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(yamlPath, yamlBytes, common.DefaultFilePermission)
+}
+
+// clearSecretContent returns the secret with its values emptied
+func clearSecretContent(st irtypes.Storage) irtypes.Storage {
+	content := map[string][]byte{}
+	for k := range st.Content {
+		content[k] = []byte{}
+	}
+	st.Content = content
+	return st
+}
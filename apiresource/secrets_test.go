@@ -0,0 +1,217 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/k8sschema/externalsecrets"
+	"github.com/konveyor/move2kube/k8sschema/sealedsecrets"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestSecretsOutput(t *testing.T) {
+	qaengine.StartEngine(true, 0, true)
+	st := irtypes.Storage{Name: "db-credentials", StorageType: irtypes.SecretKind, Content: map[string][]byte{"password": []byte("s3cr3t"), "username": []byte("admin")}}
+
+	t.Run("sealed secret can be unsealed using the private key", func(t *testing.T) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("Failed to generate the key. Error: %q", err)
+		}
+		template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "sealed-secret"}, NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour)}
+		certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+		if err != nil {
+			t.Fatalf("Failed to create the certificate. Error: %q", err)
+		}
+		certPath := filepath.Join(t.TempDir(), "cert.pem")
+		if err := ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), 0644); err != nil {
+			t.Fatalf("Failed to write the certificate. Error: %q", err)
+		}
+		publicKey, err := getSealingPublicKey(certPath)
+		if err != nil {
+			t.Fatalf("Failed to read the public key from the certificate. Error: %q", err)
+		}
+		sealedSecret, err := new(Storage).createSealedSecret(st, publicKey)
+		if err != nil {
+			t.Fatalf("Failed to seal the secret. Error: %q", err)
+		}
+		if sealedSecret.Annotations[sealedsecrets.ClusterWideAnnotation] != "true" || sealedSecret.Spec.Template.Type != string(core.SecretTypeOpaque) {
+			t.Fatalf("Expected a cluster wide sealed secret of type opaque. Actual: %+v", sealedSecret)
+		}
+		for k, v := range st.Content {
+			encrypted, err := base64.StdEncoding.DecodeString(sealedSecret.Spec.EncryptedData[k])
+			if err != nil {
+				t.Fatalf("Failed to decode the value of %s. Error: %q", k, err)
+			}
+			keyLength := int(binary.BigEndian.Uint16(encrypted))
+			sessionKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, privateKey, encrypted[2:2+keyLength], []byte{})
+			if err != nil {
+				t.Fatalf("Failed to decrypt the session key of %s. Error: %q", k, err)
+			}
+			block, err := aes.NewCipher(sessionKey)
+			if err != nil {
+				t.Fatalf("Failed to create the cipher. Error: %q", err)
+			}
+			aead, err := cipher.NewGCM(block)
+			if err != nil {
+				t.Fatalf("Failed to create the cipher. Error: %q", err)
+			}
+			value, err := aead.Open(nil, make([]byte, aead.NonceSize()), encrypted[2+keyLength:], nil)
+			if err != nil || string(value) != string(v) {
+				t.Fatalf("Expected the value of %s to be %s. Actual: %s Error: %v", k, v, value, err)
+			}
+		}
+	})
+
+	t.Run("secret without the values when the certificate is missing", func(t *testing.T) {
+		obj := new(Storage).createSecretObject(st, secretsOutputSealedSecret)
+		secret, ok := obj.(*core.Secret)
+		if !ok {
+			t.Fatalf("Expected a secret. Actual: %+v", obj)
+		}
+		if len(secret.Data) != 2 || len(secret.Data["password"]) != 0 {
+			t.Fatalf("Expected the keys of the secret without the values. Actual: %+v", secret.Data)
+		}
+		if string(st.Content["password"]) != "s3cr3t" {
+			t.Fatalf("Expected the values in the IR to be unchanged. Actual: %+v", st.Content)
+		}
+	})
+
+	t.Run("external secret refers to the store", func(t *testing.T) {
+		obj := new(Storage).createSecretObject(st, secretsOutputExternalSecret)
+		externalSecret, ok := obj.(*externalsecrets.ExternalSecret)
+		if !ok {
+			t.Fatalf("Expected an external secret. Actual: %+v", obj)
+		}
+		if externalSecret.Spec.SecretStoreRef.Kind != externalsecrets.SecretStoreKind || externalSecret.Spec.Target.Name != "db-credentials" {
+			t.Fatalf("Expected the external secret to refer to a secret store. Actual: %+v", externalSecret.Spec)
+		}
+		if len(externalSecret.Spec.Data) != 2 || externalSecret.Spec.Data[0].SecretKey != "password" || externalSecret.Spec.Data[0].RemoteRef != (externalsecrets.ExternalSecretDataRemoteRef{Key: "db-credentials", Property: "password"}) {
+			t.Fatalf("Expected the keys of the secret to refer to the properties in the store. Actual: %+v", externalSecret.Spec.Data)
+		}
+	})
+}
+
+func TestEncryptSecretFiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The fake sops command needs a unix shell")
+	}
+	qaengine.StartEngine(true, 0, true)
+	qaengine.SetupConfigFile("", []string{common.ConfigSecretsOutputKey + `="` + secretsOutputSOPS + `"`, common.ConfigSOPSAgeRecipientsKey + `="age1recipient"`}, nil, nil)
+	defer qaengine.SetupConfigFile("", []string{common.ConfigSecretsOutputKey + `="` + secretsOutputSecret + `"`}, nil, nil)
+
+	writeSecret := func(t *testing.T) (string, []k8sruntime.Object) {
+		secret := &core.Secret{
+			TypeMeta:   metav1.TypeMeta{Kind: string(irtypes.SecretKind), APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "db-credentials"},
+			Data:       map[string][]byte{"password": []byte("s3cr3t")},
+		}
+		outputPath := t.TempDir()
+		yamlBytes, err := common.MarshalObjToYaml(secret)
+		if err != nil {
+			t.Fatalf("Failed to marshal the secret. Error: %q", err)
+		}
+		yamlPath := filepath.Join(outputPath, getFilename(secret))
+		if err := ioutil.WriteFile(yamlPath, yamlBytes, 0644); err != nil {
+			t.Fatalf("Failed to write the secret. Error: %q", err)
+		}
+		return yamlPath, []k8sruntime.Object{secret}
+	}
+	plainTextValues := []string{"s3cr3t", base64.StdEncoding.EncodeToString([]byte("s3cr3t"))}
+
+	t.Run("secret is encrypted in place using sops", func(t *testing.T) {
+		binDir := t.TempDir()
+		argsPath := filepath.Join(binDir, "args")
+		fakeSOPS := "#!/bin/sh\necho \"$@\" > " + argsPath + "\nfor last; do :; done\necho 'data: {password: ENC[AES256_GCM,data:xyz]}' > \"$last\"\n"
+		if err := ioutil.WriteFile(filepath.Join(binDir, sopsCommand), []byte(fakeSOPS), 0755); err != nil {
+			t.Fatalf("Failed to write the fake sops command. Error: %q", err)
+		}
+		t.Setenv("PATH", binDir)
+		yamlPath, objs := writeSecret(t)
+		encryptSecretFiles(filepath.Dir(yamlPath), objs, []string{yamlPath})
+		args, err := ioutil.ReadFile(argsPath)
+		if err != nil {
+			t.Fatalf("Expected sops to be run. Error: %q", err)
+		}
+		for _, arg := range []string{"--encrypt --in-place", "--encrypted-regex " + sopsEncryptedRegex, "--age age1recipient", yamlPath} {
+			if !strings.Contains(string(args), arg) {
+				t.Fatalf("Expected the sops arguments to contain %s. Actual: %s", arg, args)
+			}
+		}
+		assertNoPlainTextValues(t, yamlPath, plainTextValues)
+	})
+
+	t.Run("secret is written without the values when sops is missing", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		yamlPath, objs := writeSecret(t)
+		encryptSecretFiles(filepath.Dir(yamlPath), objs, []string{yamlPath})
+		assertNoPlainTextValues(t, yamlPath, plainTextValues)
+		secret := struct {
+			Data map[string]string `yaml:"data"`
+		}{}
+		if err := common.ReadYaml(yamlPath, &secret); err != nil {
+			t.Fatalf("Failed to read the secret. Error: %q", err)
+		}
+		if value, ok := secret.Data["password"]; !ok || value != "" {
+			t.Fatalf("Expected the keys of the secret to be kept without the values. Actual: %+v", secret.Data)
+		}
+	})
+
+	t.Run("secret which was not written is not touched", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		yamlPath, objs := writeSecret(t)
+		encryptSecretFiles(filepath.Dir(yamlPath), objs, nil)
+		yamlBytes, err := ioutil.ReadFile(yamlPath)
+		if err != nil || !strings.Contains(string(yamlBytes), plainTextValues[1]) {
+			t.Fatalf("Expected the secret to be unchanged. Actual: %s Error: %v", yamlBytes, err)
+		}
+	})
+}
+
+func assertNoPlainTextValues(t *testing.T, yamlPath string, values []string) {
+	yamlBytes, err := ioutil.ReadFile(yamlPath)
+	if err != nil {
+		t.Fatalf("Failed to read the secret. Error: %q", err)
+	}
+	for _, value := range values {
+		if strings.Contains(string(yamlBytes), value) {
+			t.Fatalf("Expected the secret to not contain the value %s. Actual: %s", value, yamlBytes)
+		}
+	}
+}
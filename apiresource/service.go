@@ -18,10 +18,12 @@ package apiresource
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/k8sschema"
+	"github.com/konveyor/move2kube/k8sschema/gatewayapi"
 	"github.com/konveyor/move2kube/qaengine"
 	collecttypes "github.com/konveyor/move2kube/types/collection"
 	irtypes "github.com/konveyor/move2kube/types/ir"
@@ -30,6 +32,7 @@ import (
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	core "k8s.io/kubernetes/pkg/apis/core"
 	networking "k8s.io/kubernetes/pkg/apis/networking"
@@ -37,6 +40,20 @@ import (
 
 const (
 	routeKind = "Route"
+	// defaultGatewayClassName is the gateway class used when the gateway controller of the cluster is not known
+	defaultGatewayClassName = "default"
+	httpListenerName        = "http"
+	httpsListenerName       = "https"
+	// certManagerIssuerAnnotation and certManagerClusterIssuerAnnotation make cert-manager issue the certificates of ingresses and gateways
+	certManagerIssuerAnnotation        = "cert-manager.io/issuer"
+	certManagerClusterIssuerAnnotation = "cert-manager.io/cluster-issuer"
+	// certManagerRouteIssuerNameAnnotation and certManagerRouteIssuerKindAnnotation make the cert-manager OpenShift routes add-on issue the certificates of routes
+	certManagerRouteIssuerNameAnnotation = "cert-manager.io/issuer-name"
+	certManagerRouteIssuerKindAnnotation = "cert-manager.io/issuer-kind"
+	// The following annotations are understood by the NGINX ingress controller
+	nginxSSLRedirectAnnotation     = "nginx.ingress.kubernetes.io/ssl-redirect"
+	nginxBackendProtocolAnnotation = "nginx.ingress.kubernetes.io/backend-protocol"
+	nginxSSLPassthroughAnnotation  = "nginx.ingress.kubernetes.io/ssl-passthrough"
 )
 
 // Service handles all objects related to a service
@@ -45,13 +62,14 @@ type Service struct {
 
 // getSupportedKinds returns supported kinds
 func (d *Service) getSupportedKinds() []string {
-	return []string{common.ServiceKind, common.IngressKind, routeKind}
+	return []string{common.ServiceKind, common.IngressKind, routeKind, gatewayapi.GatewayKind, gatewayapi.HTTPRouteKind, gatewayapi.GRPCRouteKind}
 }
 
 // createNewResources converts IR to runtime objects
 func (d *Service) createNewResources(ir irtypes.EnhancedIR, supportedKinds []string, targetCluster collecttypes.ClusterMetadata) []runtime.Object {
 	objs := []runtime.Object{}
 	ingressEnabled := false
+	gatewayEnabled := false
 	for _, service := range ir.Services {
 		exposeobjectcreated := false
 		if service.HasValidAnnotation(common.ExposeSelector) || service.OnlyIngress {
@@ -63,6 +81,10 @@ func (d *Service) createNewResources(ir irtypes.EnhancedIR, supportedKinds []str
 					objs = append(objs, routeObj)
 				}
 				exposeobjectcreated = true
+			} else if common.IsStringPresent(supportedKinds, gatewayapi.HTTPRouteKind) {
+				// Clusters with the Gateway API may not have an ingress controller, so the gateway is preferred over ingress
+				exposeobjectcreated = true
+				gatewayEnabled = true
 			} else if common.IsStringPresent(supportedKinds, common.IngressKind) {
 				//Create Ingress
 				// obj := d.createIngress(service)
@@ -79,16 +101,23 @@ func (d *Service) createNewResources(ir irtypes.EnhancedIR, supportedKinds []str
 		}
 		obj := d.createService(service)
 		objs = append(objs, obj)
+		if isStatefulSet(service) && targetCluster.Spec.GetSupportedVersions(statefulSetKind) != nil {
+			objs = append(objs, d.createHeadlessService(service))
+		}
 	}
 
 	// Create one ingress for all services
 	if ingressEnabled {
-		obj := d.createIngress(ir, targetCluster.Spec)
-		if obj != nil {
+		for _, obj := range d.createIngresses(ir, targetCluster.Spec) {
 			objs = append(objs, obj)
 		}
 	}
 
+	// Create one gateway for all services
+	if gatewayEnabled {
+		objs = append(objs, d.createGatewayResources(ir, targetCluster.Spec)...)
+	}
+
 	return objs
 }
 
@@ -105,6 +134,29 @@ func (d *Service) convertToClusterSupportedKinds(obj runtime.Object, supportedKi
 		if _, ok := lobj.(*core.Service); ok {
 			return []runtime.Object{obj}, true
 		}
+		if ingress, ok := d.gatewayRouteToIngress(obj, otherobjs); ok {
+			if ingress == nil {
+				return []runtime.Object{}, true
+			}
+			return d.ingressToRoute(*ingress), true
+		}
+		if _, ok := obj.(*gatewayapi.Gateway); ok {
+			return []runtime.Object{}, true
+		}
+	} else if common.IsStringPresent(supportedKinds, gatewayapi.HTTPRouteKind) {
+		if route, ok := obj.(*okdroutev1.Route); ok {
+			ingress := d.routeToIngress(*route, ir, targetCluster.Spec)[0].(*networking.Ingress)
+			return d.ingressToHTTPRoutes(*ingress, otherobjs, targetCluster.Spec), true
+		}
+		if ingress, ok := lobj.(*networking.Ingress); ok {
+			return d.ingressToHTTPRoutes(*ingress, otherobjs, targetCluster.Spec), true
+		}
+		if grpcRoute, ok := obj.(*gatewayapi.GRPCRoute); ok && !common.IsStringPresent(supportedKinds, gatewayapi.GRPCRouteKind) {
+			return []runtime.Object{d.grpcRouteToHTTPRoute(*grpcRoute, targetCluster.Spec)}, true
+		}
+		if _, ok := lobj.(*core.Service); ok {
+			return []runtime.Object{obj}, true
+		}
 	} else if common.IsStringPresent(supportedKinds, common.IngressKind) {
 		if route, ok := obj.(*okdroutev1.Route); ok {
 			return d.routeToIngress(*route, ir, targetCluster.Spec), true
@@ -115,6 +167,15 @@ func (d *Service) convertToClusterSupportedKinds(obj runtime.Object, supportedKi
 		if _, ok := lobj.(*core.Service); ok {
 			return []runtime.Object{obj}, true
 		}
+		if ingress, ok := d.gatewayRouteToIngress(obj, otherobjs); ok {
+			if ingress == nil {
+				return []runtime.Object{}, true
+			}
+			return []runtime.Object{ingress}, true
+		}
+		if _, ok := obj.(*gatewayapi.Gateway); ok {
+			return []runtime.Object{}, true
+		}
 	} else {
 		if route, ok := obj.(*okdroutev1.Route); ok {
 			return d.routeToService(*route), true
@@ -122,6 +183,15 @@ func (d *Service) convertToClusterSupportedKinds(obj runtime.Object, supportedKi
 		if ingress, ok := lobj.(*networking.Ingress); ok {
 			return d.ingressToService(*ingress), true
 		}
+		if ingress, ok := d.gatewayRouteToIngress(obj, otherobjs); ok {
+			if ingress == nil {
+				return []runtime.Object{}, true
+			}
+			return d.ingressToService(*ingress), true
+		}
+		if _, ok := obj.(*gatewayapi.Gateway); ok {
+			return []runtime.Object{}, true
+		}
 		if _, ok := lobj.(*core.Service); ok {
 			//TODO: Check if the destination cluster supports loadbalancer or nodeport and change between them.
 			return []runtime.Object{obj}, true
@@ -164,6 +234,11 @@ func (d *Service) ingressToRoute(ingress networking.Ingress) []runtime.Object {
 				},
 				Status: okdroutev1.RouteStatus{Ingress: ingressArray},
 			}
+			for _, tls := range ingress.Spec.TLS {
+				if common.IsStringPresent(tls.Hosts, ingressspec.Host) {
+					route.Spec.TLS = &okdroutev1.TLSConfig{Termination: okdroutev1.TLSTerminationEdge, InsecureEdgeTerminationPolicy: okdroutev1.InsecureEdgeTerminationPolicyRedirect}
+				}
+			}
 			objs = append(objs, route)
 		}
 	}
@@ -208,6 +283,9 @@ func (d *Service) routeToIngress(route okdroutev1.Route, ir irtypes.EnhancedIR,
 			},
 		},
 	}
+	if route.Spec.TLS != nil && route.Spec.TLS.Termination != okdroutev1.TLSTerminationPassthrough {
+		ingress.Spec.TLS = []networking.IngressTLS{{Hosts: []string{route.Spec.Host}}}
+	}
 
 	return []runtime.Object{&ingress}
 }
@@ -321,12 +399,47 @@ func (d *Service) createRoute(irName string, service irtypes.Service, port core.
 			Ingress: ingressArray,
 		},
 	}
+	if tls, ok := ir.TLS[hostprefix]; ok {
+		d.setRouteTLS(route, tls)
+	}
 	return route
 }
 
-// createIngress creates a single ingress for all services
+// setRouteTLS sets the TLS termination of the route
+func (d *Service) setRouteTLS(route *okdroutev1.Route, tls irtypes.TLSConfig) {
+	termination := okdroutev1.TLSTerminationEdge
+	switch tls.Termination {
+	case irtypes.ReencryptTLSTermination:
+		termination = okdroutev1.TLSTerminationReencrypt
+	case irtypes.PassthroughTLSTermination:
+		termination = okdroutev1.TLSTerminationPassthrough
+		if route.Spec.Path != "" && route.Spec.Path != "/" {
+			logrus.Warnf("Routes with passthrough termination can not have a path. Removing the path %s from the route %s", route.Spec.Path, route.Name)
+		}
+		route.Spec.Path = ""
+	}
+	insecureEdgeTerminationPolicy := okdroutev1.InsecureEdgeTerminationPolicyNone
+	if tls.RedirectHTTP {
+		insecureEdgeTerminationPolicy = okdroutev1.InsecureEdgeTerminationPolicyRedirect
+	}
+	route.Spec.TLS = &okdroutev1.TLSConfig{Termination: termination, InsecureEdgeTerminationPolicy: insecureEdgeTerminationPolicy}
+	if termination == okdroutev1.TLSTerminationPassthrough {
+		return
+	}
+	if tls.Issuer != "" {
+		issuerKind := tls.IssuerKind
+		if issuerKind == "" {
+			issuerKind = irtypes.ClusterIssuerKind
+		}
+		route.Annotations = common.MergeStringMaps(route.Annotations, map[string]string{certManagerRouteIssuerNameAnnotation: tls.Issuer, certManagerRouteIssuerKindAnnotation: issuerKind})
+	} else if tls.SecretName != "" {
+		logrus.Warnf("Routes can not refer to secrets. The route %s uses the default certificate of the router unless the certificate in the secret %s is added to it.", route.Name, tls.SecretName)
+	}
+}
+
+// createIngresses creates one ingress for all the hosts. Hosts which need different TLS annotations get their own ingress.
 //TODO: Only supports fan-out. Virtual named hosting is not supported yet.
-func (d *Service) createIngress(ir irtypes.EnhancedIR, targetClusterSpec collecttypes.ClusterMetadataSpec) *networking.Ingress {
+func (d *Service) createIngresses(ir irtypes.EnhancedIR, targetClusterSpec collecttypes.ClusterMetadataSpec) []*networking.Ingress {
 	pathType := networking.PathTypePrefix
 
 	hostHTTPIngressPaths := map[string][]networking.HTTPIngressPath{} //[hostprefix]
@@ -361,54 +474,83 @@ func (d *Service) createIngress(ir irtypes.EnhancedIR, targetClusterSpec collect
 		return nil
 	}
 
-	// Configure the rule with the above fan-out paths
-	rules := []networking.IngressRule{}
 	host := targetClusterSpec.Host
-	secretName := ""
 	if host == "" {
 		host = commonqa.IngressHost(d.getHostName(ir.Name))
 	}
-	defaultSecretName := ""
-	secretName = qaengine.FetchStringAnswer(common.ConfigIngressTLSKey, "Provide the TLS secret for ingress", []string{"Leave empty to use http"}, defaultSecretName)
-	for hostprefix, httpIngressPaths := range hostHTTPIngressPaths {
-		ph := host
-		if hostprefix != "" {
-			ph = hostprefix + "." + ph
+	hostPrefixes := []string{}
+	for hostprefix := range hostHTTPIngressPaths {
+		hostPrefixes = append(hostPrefixes, hostprefix)
+	}
+	sort.Strings(hostPrefixes)
+	ingresses := []*networking.Ingress{}
+	ingressesByAnnotations := map[string]*networking.Ingress{}
+	for _, hostprefix := range hostPrefixes {
+		ph := d.getPrefixedHost(host, hostprefix)
+		tls, hasTLS := ir.TLS[hostprefix]
+		annotations := map[string]string{}
+		if hasTLS {
+			annotations = d.getIngressTLSAnnotations(tls)
 		}
-		rules = append(rules, networking.IngressRule{
+		annotationsID := fmt.Sprintf("%v", annotations)
+		ingress, ok := ingressesByAnnotations[annotationsID]
+		if !ok {
+			ingressName := ir.Name
+			if len(ingresses) != 0 {
+				ingressName = d.getGatewayRouteName(ir.Name, hostprefix)
+			}
+			ingress = &networking.Ingress{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       common.IngressKind,
+					APIVersion: networking.SchemeGroupVersion.String(),
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   ingressName,
+					Labels: getServiceLabels(ingressName),
+				},
+			}
+			if len(annotations) != 0 {
+				ingress.Annotations = annotations
+			}
+			ingressesByAnnotations[annotationsID] = ingress
+			ingresses = append(ingresses, ingress)
+		}
+		ingress.Spec.Rules = append(ingress.Spec.Rules, networking.IngressRule{
 			Host: ph,
 			IngressRuleValue: networking.IngressRuleValue{
 				HTTP: &networking.HTTPIngressRuleValue{
-					Paths: httpIngressPaths,
+					Paths: hostHTTPIngressPaths[hostprefix],
 				},
 			},
 		})
+		if hasTLS && tls.Termination != irtypes.PassthroughTLSTermination && tls.SecretName != "" {
+			ingress.Spec.TLS = append(ingress.Spec.TLS, networking.IngressTLS{Hosts: []string{ph}, SecretName: tls.SecretName})
+		}
 	}
+	return ingresses
+}
 
-	tls := []networking.IngressTLS{}
-	if secretName != "" {
-		tls = []networking.IngressTLS{{Hosts: []string{host},
-			SecretName: secretName,
-		}}
+// getIngressTLSAnnotations returns the annotations which configure the TLS of an ingress host
+func (d *Service) getIngressTLSAnnotations(tls irtypes.TLSConfig) map[string]string {
+	annotations := map[string]string{}
+	if tls.Issuer != "" {
+		if tls.IssuerKind == irtypes.IssuerKind {
+			annotations[certManagerIssuerAnnotation] = tls.Issuer
+		} else {
+			annotations[certManagerClusterIssuerAnnotation] = tls.Issuer
+		}
 	}
-
-	ingressName := ir.Name
-	ingress := networking.Ingress{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       common.IngressKind,
-			APIVersion: networking.SchemeGroupVersion.String(),
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:   ingressName,
-			Labels: getServiceLabels(ingressName),
-		},
-		Spec: networking.IngressSpec{
-			Rules: rules,
-			TLS:   tls,
-		},
+	switch tls.Termination {
+	case irtypes.ReencryptTLSTermination:
+		annotations[nginxBackendProtocolAnnotation] = "HTTPS"
+	case irtypes.PassthroughTLSTermination:
+		annotations[nginxSSLPassthroughAnnotation] = "true"
 	}
-
-	return &ingress
+	// The NGINX ingress controller redirects the hosts with TLS to https by default
+	if !tls.RedirectHTTP {
+		annotations[nginxSSLRedirectAnnotation] = "false"
+	}
+	return annotations
 }
 
 // createService creates a service
@@ -436,6 +578,42 @@ func (d *Service) createService(service irtypes.Service) *core.Service {
 	return svc
 }
 
+// createHeadlessService creates the headless service which gives the pods of a StatefulSet their network identity
+func (d *Service) createHeadlessService(service irtypes.Service) *core.Service {
+	ports := []core.ServicePort{}
+	for _, forwarding := range service.ServiceToPodPortForwardings {
+		servicePortName := forwarding.ServicePort.Name
+		if servicePortName == "" {
+			servicePortName = fmt.Sprintf("port-%d", forwarding.ServicePort.Number)
+		}
+		targetPort := intstr.IntOrString{Type: intstr.String, StrVal: forwarding.PodPort.Name}
+		if forwarding.PodPort.Name == "" {
+			targetPort.Type = intstr.Int
+			targetPort.IntVal = forwarding.PodPort.Number
+		}
+		ports = append(ports, core.ServicePort{
+			Name:       servicePortName,
+			Port:       forwarding.ServicePort.Number,
+			TargetPort: targetPort,
+		})
+	}
+	return &core.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       common.ServiceKind,
+			APIVersion: core.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   getHeadlessServiceName(service.Name),
+			Labels: getServiceLabels(service.Name),
+		},
+		Spec: core.ServiceSpec{
+			ClusterIP: "None",
+			Selector:  getServiceLabels(service.Name),
+			Ports:     ports,
+		},
+	}
+}
+
 // GetServicePorts configure the container service ports.
 func (d *Service) getExposeInfo(service irtypes.Service) (servicePorts []core.ServicePort, hostPrefixes []string, relPaths []string, serviceType core.ServiceType) {
 	servicePorts = []core.ServicePort{}
@@ -457,7 +635,7 @@ func (d *Service) getExposeInfo(service irtypes.Service) (servicePorts []core.Se
 			Port:       forwarding.ServicePort.Number,
 			TargetPort: targetPort,
 		}
-		hostPrefix, relPath, st := d.parseServiceRelPath(forwarding.ServiceRelPath)
+		hostPrefix, relPath, st := forwarding.ParseServiceRelPath()
 		switch st {
 		case core.ServiceTypeLoadBalancer:
 			serviceType = st
@@ -475,39 +653,572 @@ func (d *Service) getExposeInfo(service irtypes.Service) (servicePorts []core.Se
 	return servicePorts, hostPrefixes, relPaths, serviceType
 }
 
-func (d *Service) parseServiceRelPath(path string) (hostPrefix, relPath string, serviceType core.ServiceType) {
-	serviceType = core.ServiceTypeClusterIP
-	relPath = path
-	hostPrefix = ""
-	const nodeportSuffix = ":N"
-	const loadBalancerSuffix = ":L"
-	const noneSuffix = ":-"
-	if strings.HasSuffix(relPath, nodeportSuffix) {
-		serviceType = core.ServiceTypeNodePort
-		relPath = strings.TrimSuffix(relPath, nodeportSuffix)
+func (d *Service) getHostName(irName string) string {
+	return irName + ".com"
+}
+
+// createGatewayResources creates a gateway and the routes which attach the exposed services to it
+func (d *Service) createGatewayResources(ir irtypes.EnhancedIR, targetClusterSpec collecttypes.ClusterMetadataSpec) []runtime.Object {
+	objs := []runtime.Object{}
+	exposedHostPrefixes := []string{}
+	for _, service := range ir.Services {
+		_, hostPrefixes, relPaths, _ := d.getExposeInfo(service)
+		for i, relPath := range relPaths {
+			if relPath != "" {
+				exposedHostPrefixes = common.MergeStringSlices(exposedHostPrefixes, hostPrefixes[i])
+			}
+		}
 	}
-	if strings.HasSuffix(relPath, loadBalancerSuffix) {
-		serviceType = core.ServiceTypeLoadBalancer
-		relPath = strings.TrimSuffix(relPath, loadBalancerSuffix)
+	if len(exposedHostPrefixes) == 0 {
+		return objs
 	}
-	if strings.HasSuffix(relPath, noneSuffix) {
-		serviceType = ""
-		relPath = strings.TrimSuffix(relPath, noneSuffix)
+	sort.Strings(exposedHostPrefixes)
+	host := targetClusterSpec.Host
+	if host == "" {
+		host = commonqa.IngressHost(d.getHostName(ir.Name))
 	}
-	if relPath != "" && !strings.HasPrefix(relPath, `/`) {
-		parts := []string{relPath}
-		if strings.Contains(relPath, `/`) {
-			parts = strings.SplitN(relPath, `/`, 2)
+	gatewayRef, isExistingGateway := d.getGatewayParentRef(ir.Name)
+	httpsListeners := []gatewayapi.Listener{}
+	redirectHostnames := []string{}
+	parentRefs := map[string]gatewayapi.ParentReference{} //[hostprefix]
+	issuerAnnotations := map[string]string{}
+	for _, hostPrefix := range exposedHostPrefixes {
+		parentRefs[hostPrefix] = gatewayRef
+		tls, ok := ir.TLS[hostPrefix]
+		if !ok {
+			continue
 		}
-		relPath = `/`
-		if len(parts) > 1 {
-			relPath += parts[1]
+		hostname := d.getPrefixedHost(host, hostPrefix)
+		if isExistingGateway {
+			logrus.Infof("The TLS of the host %s has to be configured in the gateway %s", hostname, gatewayRef.Name)
+			continue
+		}
+		switch tls.Termination {
+		case irtypes.PassthroughTLSTermination:
+			logrus.Warnf("Passthrough TLS termination is not supported for gateways. The host %s is served over http.", hostname)
+			continue
+		case irtypes.ReencryptTLSTermination:
+			logrus.Warnf("The gateway connects to the services of the host %s over http. A BackendTLSPolicy is required to re-encrypt the connections.", hostname)
+		}
+		listenerName := d.getGatewayRouteName(httpsListenerName, hostPrefix)
+		httpsListeners = append(httpsListeners, gatewayapi.Listener{
+			Name:     listenerName,
+			Hostname: &hostname,
+			Port:     443,
+			Protocol: gatewayapi.HTTPSProtocolType,
+			TLS:      &gatewayapi.GatewayTLSConfig{CertificateRefs: []gatewayapi.SecretObjectReference{{Name: tls.SecretName}}},
+		})
+		if tls.RedirectHTTP {
+			// The routes of the host attach only to its https listener, since its http listener redirects
+			redirectHostnames = append(redirectHostnames, hostname)
+			parentRef := gatewayRef
+			parentRef.SectionName = &listenerName
+			parentRefs[hostPrefix] = parentRef
+		}
+		if tls.Issuer != "" {
+			issuerAnnotation := certManagerClusterIssuerAnnotation
+			if tls.IssuerKind == irtypes.IssuerKind {
+				issuerAnnotation = certManagerIssuerAnnotation
+			}
+			if issuer, ok := issuerAnnotations[issuerAnnotation]; ok && issuer != tls.Issuer {
+				logrus.Warnf("A gateway can have only one issuer. Using the issuer %s instead of %s for the host %s", issuer, tls.Issuer, hostname)
+				continue
+			}
+			issuerAnnotations[issuerAnnotation] = tls.Issuer
 		}
-		hostPrefix = parts[0]
 	}
-	return hostPrefix, relPath, serviceType
+	for _, service := range ir.Services {
+		backendServiceName := service.BackendServiceName
+		if service.BackendServiceName == "" {
+			backendServiceName = service.Name
+		}
+		httpRouteRules := map[string][]gatewayapi.HTTPRouteRule{} //[hostprefix]
+		grpcRouteRules := map[string][]gatewayapi.GRPCRouteRule{} //[hostprefix]
+		servicePorts, hostPrefixes, relPaths, _ := d.getExposeInfo(service)
+		for i, servicePort := range servicePorts {
+			if relPaths[i] == "" {
+				continue
+			}
+			port := servicePort.Port
+			backendRef := gatewayapi.BackendRef{BackendObjectReference: gatewayapi.BackendObjectReference{Name: backendServiceName, Port: &port}}
+			if d.isGRPCPort(servicePort) {
+				grpcRouteRules[hostPrefixes[i]] = append(grpcRouteRules[hostPrefixes[i]], gatewayapi.GRPCRouteRule{BackendRefs: []gatewayapi.GRPCBackendRef{{BackendRef: backendRef}}})
+				continue
+			}
+			pathType := gatewayapi.PathMatchPathPrefix
+			path := relPaths[i]
+			httpRouteRules[hostPrefixes[i]] = append(httpRouteRules[hostPrefixes[i]], gatewayapi.HTTPRouteRule{
+				Matches:     []gatewayapi.HTTPRouteMatch{{Path: &gatewayapi.HTTPPathMatch{Type: &pathType, Value: &path}}},
+				BackendRefs: []gatewayapi.HTTPBackendRef{{BackendRef: backendRef}},
+			})
+		}
+		for hostPrefix, rules := range httpRouteRules {
+			objs = append(objs, &gatewayapi.HTTPRoute{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       gatewayapi.HTTPRouteKind,
+					APIVersion: d.getGatewayAPIVersion(targetClusterSpec, gatewayapi.HTTPRouteKind),
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   d.getGatewayRouteName(service.Name, hostPrefix),
+					Labels: getServiceLabels(service.Name),
+				},
+				Spec: gatewayapi.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi.CommonRouteSpec{ParentRefs: []gatewayapi.ParentReference{parentRefs[hostPrefix]}},
+					Hostnames:       []string{d.getPrefixedHost(host, hostPrefix)},
+					Rules:           rules,
+				},
+			})
+		}
+		for hostPrefix, rules := range grpcRouteRules {
+			objs = append(objs, &gatewayapi.GRPCRoute{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       gatewayapi.GRPCRouteKind,
+					APIVersion: d.getGatewayAPIVersion(targetClusterSpec, gatewayapi.GRPCRouteKind),
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   d.getGatewayRouteName(service.Name, hostPrefix),
+					Labels: getServiceLabels(service.Name),
+				},
+				Spec: gatewayapi.GRPCRouteSpec{
+					CommonRouteSpec: gatewayapi.CommonRouteSpec{ParentRefs: []gatewayapi.ParentReference{parentRefs[hostPrefix]}},
+					Hostnames:       []string{d.getPrefixedHost(host, hostPrefix)},
+					Rules:           rules,
+				},
+			})
+		}
+	}
+	if isExistingGateway {
+		return objs
+	}
+	gateway := d.createGateway(gatewayRef.Name, httpsListeners, targetClusterSpec)
+	if len(issuerAnnotations) != 0 {
+		gateway.Annotations = issuerAnnotations
+	}
+	objs = append(objs, gateway)
+	if len(redirectHostnames) != 0 {
+		objs = append(objs, d.createHTTPSRedirectRoute(gatewayRef.Name, redirectHostnames, targetClusterSpec))
+	}
+	return objs
 }
 
-func (d *Service) getHostName(irName string) string {
-	return irName + ".com"
+// createHTTPSRedirectRoute creates a HTTPRoute which redirects the http requests to the hosts to https
+func (d *Service) createHTTPSRedirectRoute(gatewayName string, hostnames []string, targetClusterSpec collecttypes.ClusterMetadataSpec) *gatewayapi.HTTPRoute {
+	sectionName := httpListenerName
+	scheme := "https"
+	statusCode := 301
+	name := gatewayName + "-https-redirect"
+	return &gatewayapi.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       gatewayapi.HTTPRouteKind,
+			APIVersion: d.getGatewayAPIVersion(targetClusterSpec, gatewayapi.HTTPRouteKind),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: getServiceLabels(name),
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapi.CommonRouteSpec{ParentRefs: []gatewayapi.ParentReference{{Name: gatewayName, SectionName: &sectionName}}},
+			Hostnames:       hostnames,
+			Rules: []gatewayapi.HTTPRouteRule{{
+				Filters: []gatewayapi.HTTPRouteFilter{{
+					Type:            gatewayapi.HTTPRouteFilterRequestRedirect,
+					RequestRedirect: &gatewayapi.HTTPRequestRedirectFilter{Scheme: &scheme, StatusCode: &statusCode},
+				}},
+			}},
+		},
+	}
+}
+
+// createGateway creates a gateway with a http listener and the https listeners
+func (d *Service) createGateway(name string, httpsListeners []gatewayapi.Listener, targetClusterSpec collecttypes.ClusterMetadataSpec) *gatewayapi.Gateway {
+	gatewayClassName := qaengine.FetchStringAnswer(common.ConfigGatewayClassKey, "Provide the gateway class of the gateway", []string{"The gateway class is provided by the gateway controller installed in the cluster"}, defaultGatewayClassName)
+	listeners := append([]gatewayapi.Listener{{Name: httpListenerName, Port: 80, Protocol: gatewayapi.HTTPProtocolType}}, httpsListeners...)
+	return &gatewayapi.Gateway{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       gatewayapi.GatewayKind,
+			APIVersion: d.getGatewayAPIVersion(targetClusterSpec, gatewayapi.GatewayKind),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: getServiceLabels(name),
+		},
+		Spec: gatewayapi.GatewaySpec{
+			GatewayClassName: gatewayClassName,
+			Listeners:        listeners,
+		},
+	}
+}
+
+// getHTTPSListeners returns a https listener for each TLS secret of an ingress
+func (d *Service) getHTTPSListeners(tls []networking.IngressTLS) []gatewayapi.Listener {
+	listeners := []gatewayapi.Listener{}
+	for _, t := range tls {
+		if t.SecretName == "" {
+			continue
+		}
+		listener := gatewayapi.Listener{
+			Name:     httpsListenerName,
+			Port:     443,
+			Protocol: gatewayapi.HTTPSProtocolType,
+			TLS:      &gatewayapi.GatewayTLSConfig{CertificateRefs: []gatewayapi.SecretObjectReference{{Name: t.SecretName}}},
+		}
+		if len(listeners) > 0 {
+			listener.Name = fmt.Sprintf("%s-%d", httpsListenerName, len(listeners))
+		}
+		// A listener can have only one hostname, so a certificate for multiple hosts is used for all the hosts
+		if len(t.Hosts) == 1 {
+			hostname := t.Hosts[0]
+			listener.Hostname = &hostname
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
+// getGatewayParentRef returns the reference to the gateway the routes attach to and whether the gateway already exists in the cluster
+func (d *Service) getGatewayParentRef(defaultGatewayName string) (gatewayapi.ParentReference, bool) {
+	gatewayName := qaengine.FetchStringAnswer(common.ConfigGatewayNameKey, "Provide the name of an existing gateway to attach the routes to", []string{"Leave empty to create a new gateway", "Use <namespace>/<name> for a gateway in another namespace"}, "")
+	gatewayName = strings.TrimSpace(gatewayName)
+	if gatewayName == "" {
+		return gatewayapi.ParentReference{Name: defaultGatewayName}, false
+	}
+	parentRef := gatewayapi.ParentReference{Name: gatewayName}
+	if parts := strings.SplitN(gatewayName, "/", 2); len(parts) == 2 {
+		parentRef.Namespace = &parts[0]
+		parentRef.Name = parts[1]
+	}
+	return parentRef, true
+}
+
+// getGatewayAPIVersion returns the version of the Gateway API supported by the cluster for the kind
+func (d *Service) getGatewayAPIVersion(targetClusterSpec collecttypes.ClusterMetadataSpec, kind string) string {
+	for _, version := range targetClusterSpec.GetSupportedVersions(kind) {
+		gv, err := schema.ParseGroupVersion(version)
+		if err != nil {
+			logrus.Debugf("Unable to parse group version %s : %s", version, err)
+			continue
+		}
+		if gv.Group == gatewayapi.GroupName {
+			return version
+		}
+	}
+	return gatewayapi.SchemeGroupVersion.String()
+}
+
+func (d *Service) getGatewayRouteName(serviceName, hostPrefix string) string {
+	if hostPrefix == "" {
+		return serviceName
+	}
+	return common.MakeStringDNSLabelNameCompliant(serviceName + "-" + hostPrefix)
+}
+
+func (d *Service) getPrefixedHost(host, hostPrefix string) string {
+	if hostPrefix == "" {
+		return host
+	}
+	return hostPrefix + "." + host
+}
+
+// isGRPCPort checks if the port serves gRPC, based on the port naming conventions
+func (d *Service) isGRPCPort(servicePort core.ServicePort) bool {
+	for _, name := range []string{servicePort.Name, servicePort.TargetPort.StrVal} {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "grpc") || strings.HasPrefix(name, "h2c") {
+			return true
+		}
+	}
+	return servicePort.AppProtocol != nil && (strings.EqualFold(*servicePort.AppProtocol, "grpc") || *servicePort.AppProtocol == "kubernetes.io/h2c")
+}
+
+// ingressToHTTPRoutes converts the ingress to a HTTPRoute for each rule, attached to a gateway
+func (d *Service) ingressToHTTPRoutes(ingress networking.Ingress, otherobjs []runtime.Object, targetClusterSpec collecttypes.ClusterMetadataSpec) []runtime.Object {
+	objs := []runtime.Object{}
+	parentRef, isExistingGateway := d.getGatewayParentRef(ingress.Name)
+	for i, rule := range ingress.Spec.Rules {
+		httpRoute := d.newHTTPRoute(ingress.ObjectMeta, parentRef, targetClusterSpec)
+		if len(ingress.Spec.Rules) > 1 {
+			httpRoute.Name = fmt.Sprintf("%s-%d", ingress.Name, i)
+		}
+		if rule.Host != "" {
+			httpRoute.Spec.Hostnames = []string{rule.Host}
+		}
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			backendRef, ok := d.ingressBackendToBackendRef(path.Backend, otherobjs)
+			if !ok {
+				continue
+			}
+			pathType := gatewayapi.PathMatchPathPrefix
+			if path.PathType != nil && *path.PathType == networking.PathTypeExact {
+				pathType = gatewayapi.PathMatchExact
+			}
+			pathValue := path.Path
+			if pathValue == "" {
+				pathValue = "/"
+			}
+			httpRoute.Spec.Rules = append(httpRoute.Spec.Rules, gatewayapi.HTTPRouteRule{
+				Matches:     []gatewayapi.HTTPRouteMatch{{Path: &gatewayapi.HTTPPathMatch{Type: &pathType, Value: &pathValue}}},
+				BackendRefs: []gatewayapi.HTTPBackendRef{{BackendRef: backendRef}},
+			})
+		}
+		if len(httpRoute.Spec.Rules) != 0 {
+			objs = append(objs, httpRoute)
+		}
+	}
+	if ingress.Spec.DefaultBackend != nil {
+		if backendRef, ok := d.ingressBackendToBackendRef(*ingress.Spec.DefaultBackend, otherobjs); ok {
+			httpRoute := d.newHTTPRoute(ingress.ObjectMeta, parentRef, targetClusterSpec)
+			if len(objs) != 0 {
+				httpRoute.Name = ingress.Name + "-default"
+			}
+			httpRoute.Spec.Rules = []gatewayapi.HTTPRouteRule{{BackendRefs: []gatewayapi.HTTPBackendRef{{BackendRef: backendRef}}}}
+			objs = append(objs, httpRoute)
+		}
+	}
+	if len(objs) != 0 && !isExistingGateway {
+		objs = append(objs, d.createGateway(parentRef.Name, d.getHTTPSListeners(ingress.Spec.TLS), targetClusterSpec))
+	}
+	return objs
+}
+
+func (d *Service) newHTTPRoute(objectMeta metav1.ObjectMeta, parentRef gatewayapi.ParentReference, targetClusterSpec collecttypes.ClusterMetadataSpec) *gatewayapi.HTTPRoute {
+	return &gatewayapi.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       gatewayapi.HTTPRouteKind,
+			APIVersion: d.getGatewayAPIVersion(targetClusterSpec, gatewayapi.HTTPRouteKind),
+		},
+		ObjectMeta: *objectMeta.DeepCopy(),
+		Spec: gatewayapi.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapi.CommonRouteSpec{ParentRefs: []gatewayapi.ParentReference{parentRef}},
+		},
+	}
+}
+
+// ingressBackendToBackendRef converts the ingress backend to a backend reference. Routes refer to the service ports by number.
+func (d *Service) ingressBackendToBackendRef(backend networking.IngressBackend, otherobjs []runtime.Object) (gatewayapi.BackendRef, bool) {
+	if backend.Service == nil {
+		logrus.Warnf("Only service backends can be converted to the Gateway API. Ignoring the backend %+v", backend)
+		return gatewayapi.BackendRef{}, false
+	}
+	port := backend.Service.Port.Number
+	if backend.Service.Port.Name != "" {
+		servicePort, ok := d.getServicePort(otherobjs, backend.Service.Name, func(p core.ServicePort) bool { return p.Name == backend.Service.Port.Name })
+		if !ok {
+			logrus.Warnf("Unable to find the port %s of the service %s. Ignoring the backend.", backend.Service.Port.Name, backend.Service.Name)
+			return gatewayapi.BackendRef{}, false
+		}
+		port = servicePort.Port
+	}
+	return gatewayapi.BackendRef{BackendObjectReference: gatewayapi.BackendObjectReference{Name: backend.Service.Name, Port: &port}}, true
+}
+
+// getServicePort finds the port of the service among the other objects
+func (d *Service) getServicePort(otherobjs []runtime.Object, serviceName string, matches func(core.ServicePort) bool) (core.ServicePort, bool) {
+	for _, obj := range otherobjs {
+		lobj, _ := k8sschema.ConvertToLiasonScheme(obj)
+		service, ok := lobj.(*core.Service)
+		if !ok || service.Name != serviceName {
+			continue
+		}
+		for _, port := range service.Spec.Ports {
+			if matches(port) {
+				return port, true
+			}
+		}
+	}
+	return core.ServicePort{}, false
+}
+
+// gatewayRouteToIngress converts HTTPRoutes and GRPCRoutes to ingress. It returns false for other objects, and a nil ingress for routes without backends.
+func (d *Service) gatewayRouteToIngress(obj runtime.Object, otherobjs []runtime.Object) (*networking.Ingress, bool) {
+	objectMeta := metav1.ObjectMeta{}
+	parentRefs := []gatewayapi.ParentReference{}
+	hostnames := []string{}
+	paths := []networking.HTTPIngressPath{}
+	switch route := obj.(type) {
+	case *gatewayapi.HTTPRoute:
+		objectMeta, parentRefs, hostnames = route.ObjectMeta, route.Spec.ParentRefs, route.Spec.Hostnames
+		for _, rule := range route.Spec.Rules {
+			backendRefs := []gatewayapi.BackendRef{}
+			for _, backendRef := range rule.BackendRefs {
+				backendRefs = append(backendRefs, backendRef.BackendRef)
+			}
+			backend, ok := d.backendRefsToIngressBackend(route.Name, backendRefs, otherobjs)
+			if !ok {
+				continue
+			}
+			matches := rule.Matches
+			if len(matches) == 0 {
+				matches = []gatewayapi.HTTPRouteMatch{{}}
+			}
+			for _, match := range matches {
+				pathType := networking.PathTypePrefix
+				path := "/"
+				if match.Path != nil {
+					if match.Path.Value != nil {
+						path = *match.Path.Value
+					}
+					if match.Path.Type != nil {
+						switch *match.Path.Type {
+						case gatewayapi.PathMatchExact:
+							pathType = networking.PathTypeExact
+						case gatewayapi.PathMatchRegularExpression:
+							pathType = networking.PathTypeImplementationSpecific
+						}
+					}
+				}
+				paths = append(paths, networking.HTTPIngressPath{Path: path, PathType: &pathType, Backend: backend})
+			}
+		}
+	case *gatewayapi.GRPCRoute:
+		logrus.Warnf("The GRPCRoute %s is converted to an ingress. The ingress controller needs to be configured to use gRPC to connect to the backends.", route.Name)
+		objectMeta, parentRefs, hostnames = route.ObjectMeta, route.Spec.ParentRefs, route.Spec.Hostnames
+		for _, rule := range route.Spec.Rules {
+			backendRefs := []gatewayapi.BackendRef{}
+			for _, backendRef := range rule.BackendRefs {
+				backendRefs = append(backendRefs, backendRef.BackendRef)
+			}
+			backend, ok := d.backendRefsToIngressBackend(route.Name, backendRefs, otherobjs)
+			if !ok {
+				continue
+			}
+			matches := rule.Matches
+			if len(matches) == 0 {
+				matches = []gatewayapi.GRPCRouteMatch{{}}
+			}
+			for _, match := range matches {
+				path, isExact := d.grpcMethodToPath(match.Method)
+				pathType := networking.PathTypePrefix
+				if isExact {
+					pathType = networking.PathTypeExact
+				}
+				paths = append(paths, networking.HTTPIngressPath{Path: path, PathType: &pathType, Backend: backend})
+			}
+		}
+	default:
+		return nil, false
+	}
+	if len(paths) == 0 {
+		return nil, true
+	}
+	if len(hostnames) == 0 {
+		hostnames = []string{""}
+	}
+	rules := []networking.IngressRule{}
+	for _, hostname := range hostnames {
+		rules = append(rules, networking.IngressRule{
+			Host:             hostname,
+			IngressRuleValue: networking.IngressRuleValue{HTTP: &networking.HTTPIngressRuleValue{Paths: paths}},
+		})
+	}
+	return &networking.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       common.IngressKind,
+			APIVersion: networking.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: objectMeta,
+		Spec: networking.IngressSpec{
+			Rules: rules,
+			TLS:   d.getGatewayTLS(parentRefs, hostnames, otherobjs),
+		},
+	}, true
+}
+
+// backendRefsToIngressBackend converts the backends of a route rule to an ingress backend, which can have only one service
+func (d *Service) backendRefsToIngressBackend(routeName string, backendRefs []gatewayapi.BackendRef, otherobjs []runtime.Object) (networking.IngressBackend, bool) {
+	if len(backendRefs) == 0 {
+		return networking.IngressBackend{}, false
+	}
+	if len(backendRefs) > 1 {
+		logrus.Warnf("A rule of the route %s splits the traffic between %d backends. Only the backend %s is used in the ingress.", routeName, len(backendRefs), backendRefs[0].Name)
+	}
+	backendRef := backendRefs[0]
+	if (backendRef.Kind != nil && *backendRef.Kind != common.ServiceKind) || backendRef.Port == nil {
+		logrus.Warnf("Only service backends with a port can be converted to ingress. Ignoring the backend %s of the route %s", backendRef.Name, routeName)
+		return networking.IngressBackend{}, false
+	}
+	port := networking.ServiceBackendPort{Number: *backendRef.Port}
+	// Named ports are preferred, since routes need them
+	if servicePort, ok := d.getServicePort(otherobjs, backendRef.Name, func(p core.ServicePort) bool { return p.Port == *backendRef.Port }); ok && servicePort.Name != "" {
+		port = networking.ServiceBackendPort{Name: servicePort.Name}
+	}
+	return networking.IngressBackend{Service: &networking.IngressServiceBackend{Name: backendRef.Name, Port: port}}, true
+}
+
+// getGatewayTLS returns the TLS secrets of the https listeners of the gateways the route is attached to
+func (d *Service) getGatewayTLS(parentRefs []gatewayapi.ParentReference, hostnames []string, otherobjs []runtime.Object) []networking.IngressTLS {
+	tls := []networking.IngressTLS{}
+	for _, obj := range otherobjs {
+		gateway, ok := obj.(*gatewayapi.Gateway)
+		if !ok {
+			continue
+		}
+		for _, parentRef := range parentRefs {
+			if parentRef.Name != gateway.Name || (parentRef.Kind != nil && *parentRef.Kind != gatewayapi.GatewayKind) {
+				continue
+			}
+			for _, listener := range gateway.Spec.Listeners {
+				if listener.TLS == nil {
+					continue
+				}
+				hosts := []string{}
+				for _, hostname := range hostnames {
+					if hostname != "" {
+						hosts = append(hosts, hostname)
+					}
+				}
+				if listener.Hostname != nil {
+					hosts = []string{*listener.Hostname}
+				}
+				for _, certificateRef := range listener.TLS.CertificateRefs {
+					tls = append(tls, networking.IngressTLS{Hosts: hosts, SecretName: certificateRef.Name})
+				}
+			}
+		}
+	}
+	return tls
+}
+
+// grpcRouteToHTTPRoute converts the GRPCRoute to a HTTPRoute for clusters which support only HTTPRoute
+func (d *Service) grpcRouteToHTTPRoute(grpcRoute gatewayapi.GRPCRoute, targetClusterSpec collecttypes.ClusterMetadataSpec) *gatewayapi.HTTPRoute {
+	httpRoute := &gatewayapi.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       gatewayapi.HTTPRouteKind,
+			APIVersion: d.getGatewayAPIVersion(targetClusterSpec, gatewayapi.HTTPRouteKind),
+		},
+		ObjectMeta: grpcRoute.ObjectMeta,
+		Spec: gatewayapi.HTTPRouteSpec{
+			CommonRouteSpec: grpcRoute.Spec.CommonRouteSpec,
+			Hostnames:       grpcRoute.Spec.Hostnames,
+		},
+	}
+	for _, rule := range grpcRoute.Spec.Rules {
+		httpRule := gatewayapi.HTTPRouteRule{}
+		for _, match := range rule.Matches {
+			path, isExact := d.grpcMethodToPath(match.Method)
+			pathType := gatewayapi.PathMatchPathPrefix
+			if isExact {
+				pathType = gatewayapi.PathMatchExact
+			}
+			httpRule.Matches = append(httpRule.Matches, gatewayapi.HTTPRouteMatch{Path: &gatewayapi.HTTPPathMatch{Type: &pathType, Value: &path}})
+		}
+		for _, backendRef := range rule.BackendRefs {
+			httpRule.BackendRefs = append(httpRule.BackendRefs, gatewayapi.HTTPBackendRef(backendRef))
+		}
+		httpRoute.Spec.Rules = append(httpRoute.Spec.Rules, httpRule)
+	}
+	return httpRoute
+}
+
+// grpcMethodToPath returns the HTTP/2 path of the gRPC method match, which is /<service>/<method>
+func (d *Service) grpcMethodToPath(method *gatewayapi.GRPCMethodMatch) (path string, isExact bool) {
+	if method == nil || method.Service == nil || *method.Service == "" {
+		return "/", false
+	}
+	if method.Method == nil || *method.Method == "" {
+		return "/" + *method.Service, false
+	}
+	return "/" + *method.Service + "/" + *method.Method, true
 }
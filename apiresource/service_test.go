@@ -0,0 +1,272 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/k8sschema/gatewayapi"
+	"github.com/konveyor/move2kube/qaengine"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	okdroutev1 "github.com/openshift/api/route/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	networking "k8s.io/kubernetes/pkg/apis/networking"
+)
+
+func getExposedIR() irtypes.EnhancedIR {
+	ir := irtypes.NewIR()
+	ir.Name = "shop"
+	web := irtypes.NewServiceWithName("web")
+	web.Annotations = map[string]string{common.ExposeSelector: common.AnnotationLabelValue}
+	web.ServiceToPodPortForwardings = []irtypes.ServiceToPodPortForwarding{
+		{ServicePort: networking.ServiceBackendPort{Name: "http", Number: 80}, PodPort: networking.ServiceBackendPort{Number: 8080}, ServiceRelPath: "/web"},
+	}
+	ir.Services[web.Name] = web
+	orders := irtypes.NewServiceWithName("orders")
+	orders.Annotations = map[string]string{common.ExposeSelector: common.AnnotationLabelValue}
+	orders.ServiceToPodPortForwardings = []irtypes.ServiceToPodPortForwarding{
+		{ServicePort: networking.ServiceBackendPort{Name: "grpc", Number: 9090}, PodPort: networking.ServiceBackendPort{Number: 9090}, ServiceRelPath: "/"},
+	}
+	ir.Services[orders.Name] = orders
+	return irtypes.NewEnhancedIRFromIR(ir)
+}
+
+func getGatewayCluster(kinds ...string) collecttypes.ClusterMetadata {
+	cluster := getClusterWithKinds(common.ServiceKind, common.IngressKind)
+	for _, kind := range kinds {
+		cluster.Spec.APIKindVersionMap[kind] = []string{gatewayapi.SchemeGroupVersion.String()}
+	}
+	return cluster
+}
+
+func TestGatewayAPI(t *testing.T) {
+	qaengine.StartEngine(true, 0, true)
+
+	t.Run("gateway and routes on a cluster with the Gateway API", func(t *testing.T) {
+		cluster := getGatewayCluster(gatewayapi.GatewayKind, gatewayapi.HTTPRouteKind, gatewayapi.GRPCRouteKind)
+		objs := (&APIResource{IAPIResource: new(Service)}).ConvertIRToObjects(getExposedIR(), cluster)
+		var gateway *gatewayapi.Gateway
+		var httpRoute *gatewayapi.HTTPRoute
+		var grpcRoute *gatewayapi.GRPCRoute
+		for _, obj := range objs {
+			switch o := obj.(type) {
+			case *gatewayapi.Gateway:
+				gateway = o
+			case *gatewayapi.HTTPRoute:
+				httpRoute = o
+			case *gatewayapi.GRPCRoute:
+				grpcRoute = o
+			case *networking.Ingress, *networkingv1.Ingress:
+				t.Fatalf("Expected no ingress. Actual: %+v", o)
+			}
+		}
+		if gateway == nil || httpRoute == nil || grpcRoute == nil {
+			t.Fatalf("Expected a Gateway, a HTTPRoute and a GRPCRoute. Actual: %+v", objs)
+		}
+		if gateway.Name != "shop" || gateway.Spec.GatewayClassName != defaultGatewayClassName || len(gateway.Spec.Listeners) != 1 {
+			t.Fatalf("Expected the gateway shop with a http listener. Actual: %+v", gateway)
+		}
+		if len(httpRoute.Spec.ParentRefs) != 1 || httpRoute.Spec.ParentRefs[0].Name != "shop" {
+			t.Fatalf("Expected the HTTPRoute to be attached to the gateway shop. Actual: %+v", httpRoute.Spec.ParentRefs)
+		}
+		rule := httpRoute.Spec.Rules[0]
+		if *rule.Matches[0].Path.Value != "/web" || rule.BackendRefs[0].Name != "web" || *rule.BackendRefs[0].Port != 80 {
+			t.Fatalf("Expected /web to be routed to the port 80 of web. Actual: %+v", rule)
+		}
+		if grpcRoute.Name != "orders" || *grpcRoute.Spec.Rules[0].BackendRefs[0].Port != 9090 {
+			t.Fatalf("Expected the GRPCRoute to route to the port 9090 of orders. Actual: %+v", grpcRoute)
+		}
+	})
+
+	t.Run("grpc routes are converted on a cluster without GRPCRoute", func(t *testing.T) {
+		cluster := getGatewayCluster(gatewayapi.GatewayKind, gatewayapi.HTTPRouteKind)
+		objs := (&APIResource{IAPIResource: new(Service)}).ConvertIRToObjects(getExposedIR(), cluster)
+		httpRoutes := 0
+		for _, obj := range objs {
+			if _, ok := obj.(*gatewayapi.GRPCRoute); ok {
+				t.Fatalf("Expected no GRPCRoute. Actual: %+v", obj)
+			}
+			if _, ok := obj.(*gatewayapi.HTTPRoute); ok {
+				httpRoutes++
+			}
+		}
+		if httpRoutes != 2 {
+			t.Fatalf("Expected 2 HTTPRoutes. Actual: %+v", objs)
+		}
+	})
+
+	t.Run("ingress on a cluster without the Gateway API", func(t *testing.T) {
+		cluster := getClusterWithKinds(common.ServiceKind, common.IngressKind)
+		routes := (&APIResource{IAPIResource: new(Service)}).ConvertIRToObjects(getExposedIR(), getGatewayCluster(gatewayapi.GatewayKind, gatewayapi.HTTPRouteKind, gatewayapi.GRPCRouteKind))
+		ingresses := []*networking.Ingress{}
+		for _, route := range routes {
+			objs, ok := new(Service).convertToClusterSupportedKinds(route, []string{common.ServiceKind, common.IngressKind}, routes, getExposedIR(), cluster)
+			if !ok {
+				t.Fatalf("Failed to convert %+v", route)
+			}
+			for _, obj := range objs {
+				if _, ok := obj.(*gatewayapi.Gateway); ok {
+					t.Fatalf("Expected the gateway to be removed. Actual: %+v", obj)
+				}
+				if ingress, ok := obj.(*networking.Ingress); ok {
+					ingresses = append(ingresses, ingress)
+				}
+			}
+		}
+		if len(ingresses) != 2 {
+			t.Fatalf("Expected 2 ingresses. Actual: %+v", ingresses)
+		}
+		for _, ingress := range ingresses {
+			if ingress.Name != "web" {
+				continue
+			}
+			path := ingress.Spec.Rules[0].HTTP.Paths[0]
+			if path.Path != "/web" || path.Backend.Service.Port.Name != "http" {
+				t.Fatalf("Expected /web to be routed to the port http of web. Actual: %+v", path)
+			}
+		}
+	})
+
+	t.Run("ingress is converted to HTTPRoute", func(t *testing.T) {
+		pathType := networking.PathTypeExact
+		ingress := &networking.Ingress{
+			Spec: networking.IngressSpec{
+				TLS: []networking.IngressTLS{{Hosts: []string{"shop.com"}, SecretName: "shop-tls"}},
+				Rules: []networking.IngressRule{{
+					Host: "shop.com",
+					IngressRuleValue: networking.IngressRuleValue{HTTP: &networking.HTTPIngressRuleValue{Paths: []networking.HTTPIngressPath{
+						{Path: "/cart", PathType: &pathType, Backend: networking.IngressBackend{Service: &networking.IngressServiceBackend{Name: "cart", Port: networking.ServiceBackendPort{Number: 8080}}}},
+					}}},
+				}},
+			},
+		}
+		ingress.Name = "shop"
+		ingress.Kind = common.IngressKind
+		ingress.APIVersion = networking.SchemeGroupVersion.String()
+		cluster := getGatewayCluster(gatewayapi.GatewayKind, gatewayapi.HTTPRouteKind)
+		objs, ok := new(Service).convertToClusterSupportedKinds(ingress, []string{common.ServiceKind, gatewayapi.GatewayKind, gatewayapi.HTTPRouteKind}, []runtime.Object{}, irtypes.NewEnhancedIRFromIR(irtypes.NewIR()), cluster)
+		if !ok || len(objs) != 2 {
+			t.Fatalf("Expected a HTTPRoute and a Gateway. Actual: %+v", objs)
+		}
+		httpRoute, ok := objs[0].(*gatewayapi.HTTPRoute)
+		if !ok {
+			t.Fatalf("Expected a HTTPRoute. Actual: %T", objs[0])
+		}
+		if httpRoute.Spec.Hostnames[0] != "shop.com" || *httpRoute.Spec.Rules[0].Matches[0].Path.Type != gatewayapi.PathMatchExact {
+			t.Fatalf("Expected an exact match on shop.com. Actual: %+v", httpRoute.Spec)
+		}
+		gateway, ok := objs[1].(*gatewayapi.Gateway)
+		if !ok {
+			t.Fatalf("Expected a Gateway. Actual: %T", objs[1])
+		}
+		if len(gateway.Spec.Listeners) != 2 || gateway.Spec.Listeners[1].TLS.CertificateRefs[0].Name != "shop-tls" {
+			t.Fatalf("Expected a https listener using the secret shop-tls. Actual: %+v", gateway.Spec.Listeners)
+		}
+	})
+}
+
+func TestTLS(t *testing.T) {
+	qaengine.StartEngine(true, 0, true)
+
+	getTLSIR := func() irtypes.EnhancedIR {
+		ir := getExposedIR()
+		web := ir.Services["web"]
+		web.ServiceToPodPortForwardings = append(web.ServiceToPodPortForwardings, irtypes.ServiceToPodPortForwarding{
+			ServicePort: networking.ServiceBackendPort{Name: "admin", Number: 81}, PodPort: networking.ServiceBackendPort{Number: 8081}, ServiceRelPath: "admin/",
+		})
+		ir.Services["web"] = web
+		ir.TLS = map[string]irtypes.TLSConfig{
+			"":      {SecretName: "shop-tls", Issuer: "letsencrypt", IssuerKind: "ClusterIssuer", Termination: irtypes.EdgeTLSTermination, RedirectHTTP: true},
+			"admin": {SecretName: "admin-tls", Termination: irtypes.ReencryptTLSTermination},
+		}
+		return ir
+	}
+
+	t.Run("ingress", func(t *testing.T) {
+		ingresses := new(Service).createIngresses(getTLSIR(), getClusterWithKinds(common.IngressKind).Spec)
+		if len(ingresses) != 2 {
+			t.Fatalf("Expected an ingress for each host. Actual: %+v", ingresses)
+		}
+		shop, admin := ingresses[0], ingresses[1]
+		if shop.Name != "shop" || shop.Annotations[certManagerClusterIssuerAnnotation] != "letsencrypt" || len(shop.Spec.TLS) != 1 || shop.Spec.TLS[0].SecretName != "shop-tls" {
+			t.Fatalf("Expected the ingress shop to use a certificate issued by letsencrypt. Actual: %+v", shop)
+		}
+		if admin.Name != "shop-admin" || admin.Annotations[nginxBackendProtocolAnnotation] != "HTTPS" || admin.Annotations[nginxSSLRedirectAnnotation] != "false" {
+			t.Fatalf("Expected the ingress shop-admin to re-encrypt without redirecting. Actual: %+v", admin.Annotations)
+		}
+		if admin.Spec.TLS[0].Hosts[0] != "admin.shop.com" {
+			t.Fatalf("Expected the certificate to be used for admin.shop.com. Actual: %+v", admin.Spec.TLS)
+		}
+	})
+
+	t.Run("route", func(t *testing.T) {
+		ir := getTLSIR()
+		routes := new(Service).createRoutes(ir.Services["web"], ir, getClusterWithKinds(routeKind).Spec)
+		if len(routes) != 2 {
+			t.Fatalf("Expected 2 routes. Actual: %+v", routes)
+		}
+		for _, route := range routes {
+			if route.Spec.TLS == nil {
+				t.Fatalf("Expected the route %s to use TLS", route.Spec.Host)
+			}
+			if route.Spec.Host == "shop.com" {
+				if route.Spec.TLS.Termination != okdroutev1.TLSTerminationEdge || route.Spec.TLS.InsecureEdgeTerminationPolicy != okdroutev1.InsecureEdgeTerminationPolicyRedirect {
+					t.Fatalf("Expected edge termination with redirect. Actual: %+v", route.Spec.TLS)
+				}
+				if route.Annotations[certManagerRouteIssuerNameAnnotation] != "letsencrypt" {
+					t.Fatalf("Expected the certificate to be issued by letsencrypt. Actual: %+v", route.Annotations)
+				}
+			} else if route.Spec.TLS.Termination != okdroutev1.TLSTerminationReencrypt || route.Spec.TLS.InsecureEdgeTerminationPolicy != okdroutev1.InsecureEdgeTerminationPolicyNone {
+				t.Fatalf("Expected reencrypt termination without redirect. Actual: %+v", route.Spec.TLS)
+			}
+		}
+	})
+
+	t.Run("gateway", func(t *testing.T) {
+		objs := new(Service).createGatewayResources(getTLSIR(), getGatewayCluster(gatewayapi.GatewayKind, gatewayapi.HTTPRouteKind).Spec)
+		var gateway *gatewayapi.Gateway
+		var redirect, web *gatewayapi.HTTPRoute
+		for _, obj := range objs {
+			switch o := obj.(type) {
+			case *gatewayapi.Gateway:
+				gateway = o
+			case *gatewayapi.HTTPRoute:
+				switch o.Name {
+				case "shop-https-redirect":
+					redirect = o
+				case "web":
+					web = o
+				}
+			}
+		}
+		if gateway == nil || redirect == nil || web == nil {
+			t.Fatalf("Expected a gateway, a redirect route and the route web. Actual: %+v", objs)
+		}
+		if len(gateway.Spec.Listeners) != 3 || gateway.Annotations[certManagerClusterIssuerAnnotation] != "letsencrypt" {
+			t.Fatalf("Expected a http listener and a https listener for each host. Actual: %+v", gateway)
+		}
+		if redirect.Spec.Hostnames[0] != "shop.com" || len(redirect.Spec.Hostnames) != 1 || *redirect.Spec.ParentRefs[0].SectionName != httpListenerName {
+			t.Fatalf("Expected the http requests to shop.com to be redirected. Actual: %+v", redirect.Spec)
+		}
+		if web.Spec.ParentRefs[0].SectionName == nil || *web.Spec.ParentRefs[0].SectionName != httpsListenerName {
+			t.Fatalf("Expected the route web to be attached to the https listener. Actual: %+v", web.Spec.ParentRefs)
+		}
+	})
+}
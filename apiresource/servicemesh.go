@@ -0,0 +1,194 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"sort"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/k8sschema/istio"
+	"github.com/konveyor/move2kube/qaengine"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// The retries and the outlier detection replace the retries and the circuit breakers of client side libraries like ribbon and hystrix
+	meshRetryAttempts                = 3
+	meshRetryPerTryTimeout           = "2s"
+	meshRetryOn                      = "gateway-error,connect-failure,refused-stream"
+	meshOutlierConsecutive5xxErrors  = 5
+	meshOutlierInterval              = "10s"
+	meshOutlierBaseEjectionTime      = "30s"
+	meshOutlierMaxEjectionPercentage = 50
+	// namespacePeerAuthenticationName is the conventional name of the peer authentication of a namespace
+	namespacePeerAuthenticationName = "default"
+)
+
+// ServiceMesh handles the service mesh objects
+type ServiceMesh struct {
+}
+
+// getSupportedKinds returns kinds supported by the service mesh
+func (sm *ServiceMesh) getSupportedKinds() []string {
+	return []string{istio.VirtualServiceKind, istio.DestinationRuleKind, istio.PeerAuthenticationKind}
+}
+
+// createNewResources creates the istio objects of the services in the istio service mesh
+// The services in a linkerd service mesh only need the injection annotation
+func (sm *ServiceMesh) createNewResources(ir irtypes.EnhancedIR, supportedKinds []string, targetCluster collecttypes.ClusterMetadata) []runtime.Object {
+	objs := []runtime.Object{}
+	meshServices := []string{}
+	for sn, s := range ir.Services {
+		if s.Annotations[common.IstioSidecarInjectAnnotation] == common.AnnotationLabelValue {
+			meshServices = append(meshServices, sn)
+		}
+	}
+	if len(meshServices) == 0 {
+		logrus.Debugf("None of the services are in an istio service mesh")
+		return objs
+	}
+	sort.Strings(meshServices)
+	for _, sn := range sm.getCalledServices(ir, meshServices) {
+		objs = append(objs, sm.createVirtualService(sn), sm.createDestinationRule(sn))
+	}
+	hints := []string{
+		"PERMISSIVE : the services accept both plain text and mutual TLS connections, which eases the migration",
+		"STRICT : the services accept only mutual TLS connections. The exposed services still accept plain text connections from the ingress",
+	}
+	mode := istio.MutualTLSMode(qaengine.FetchSelectAnswer(common.ConfigServiceMeshMTLSKey, "Which mutual TLS mode should the services in the service mesh use?", hints, string(istio.MutualTLSPermissive), []string{string(istio.MutualTLSPermissive), string(istio.MutualTLSStrict)}))
+	objs = append(objs, sm.createPeerAuthentication(namespacePeerAuthenticationName, nil, mode))
+	if mode == istio.MutualTLSStrict {
+		// The ingress controllers are usually outside the service mesh
+		for _, sn := range meshServices {
+			s := ir.Services[sn]
+			if s.HasValidAnnotation(common.ExposeSelector) {
+				objs = append(objs, sm.createPeerAuthentication(sn, getServiceLabels(sn), istio.MutualTLSPermissive))
+			}
+		}
+	}
+	return objs
+}
+
+// getCalledServices returns the services in the service mesh which are called by other services.
+// If the calls between the services are unknown, all the services with ports are returned.
+func (sm *ServiceMesh) getCalledServices(ir irtypes.EnhancedIR, meshServices []string) []string {
+	calledServices := []string{}
+	dependenciesFound := false
+	for _, s := range ir.Services {
+		for _, dependency := range s.Dependencies {
+			// The calls to the hosts outside the application do not tell which services are called
+			if _, ok := ir.Services[dependency]; !ok {
+				continue
+			}
+			dependenciesFound = true
+			if common.IsStringPresent(meshServices, dependency) {
+				calledServices = common.MergeStringSlices(calledServices, dependency)
+			}
+		}
+	}
+	if !dependenciesFound {
+		calledServices = meshServices
+	}
+	services := []string{}
+	for _, sn := range calledServices {
+		if len(ir.Services[sn].ServiceToPodPortForwardings) == 0 {
+			logrus.Debugf("The service %s does not have any ports. Not creating a virtual service for it", sn)
+			continue
+		}
+		services = append(services, sn)
+	}
+	sort.Strings(services)
+	return services
+}
+
+func (sm *ServiceMesh) createVirtualService(name string) *istio.VirtualService {
+	return &istio.VirtualService{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       istio.VirtualServiceKind,
+			APIVersion: istio.NetworkingSchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: getServiceLabels(name),
+		},
+		Spec: istio.VirtualServiceSpec{
+			Hosts: []string{name},
+			HTTP: []istio.HTTPRoute{{
+				Route:   []istio.HTTPRouteDestination{{Destination: istio.Destination{Host: name}}},
+				Retries: &istio.HTTPRetry{Attempts: meshRetryAttempts, PerTryTimeout: meshRetryPerTryTimeout, RetryOn: meshRetryOn},
+			}},
+		},
+	}
+}
+
+func (sm *ServiceMesh) createDestinationRule(name string) *istio.DestinationRule {
+	consecutive5xxErrors := int32(meshOutlierConsecutive5xxErrors)
+	return &istio.DestinationRule{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       istio.DestinationRuleKind,
+			APIVersion: istio.NetworkingSchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: getServiceLabels(name),
+		},
+		Spec: istio.DestinationRuleSpec{
+			Host: name,
+			TrafficPolicy: &istio.TrafficPolicy{
+				OutlierDetection: &istio.OutlierDetection{
+					Consecutive5xxErrors: &consecutive5xxErrors,
+					Interval:             meshOutlierInterval,
+					BaseEjectionTime:     meshOutlierBaseEjectionTime,
+					MaxEjectionPercent:   meshOutlierMaxEjectionPercentage,
+				},
+			},
+		},
+	}
+}
+
+// createPeerAuthentication creates a peer authentication for the pods matching the labels, or for the namespace if there are no labels
+func (sm *ServiceMesh) createPeerAuthentication(name string, matchLabels map[string]string, mode istio.MutualTLSMode) *istio.PeerAuthentication {
+	peerAuthentication := &istio.PeerAuthentication{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       istio.PeerAuthenticationKind,
+			APIVersion: istio.SecuritySchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: istio.PeerAuthenticationSpec{
+			MTLS: &istio.MutualTLS{Mode: mode},
+		},
+	}
+	if matchLabels != nil {
+		peerAuthentication.Spec.Selector = &istio.WorkloadSelector{MatchLabels: matchLabels}
+	}
+	return peerAuthentication
+}
+
+// convertToClusterSupportedKinds converts kinds to cluster supported kinds
+func (sm *ServiceMesh) convertToClusterSupportedKinds(obj runtime.Object, supportedKinds []string, otherobjs []runtime.Object, _ irtypes.EnhancedIR, targetCluster collecttypes.ClusterMetadata) ([]runtime.Object, bool) {
+	switch obj.(type) {
+	case *istio.VirtualService, *istio.DestinationRule, *istio.PeerAuthentication:
+		return []runtime.Object{obj}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,92 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package apiresource
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/k8sschema/istio"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	networking "k8s.io/kubernetes/pkg/apis/networking"
+)
+
+func TestServiceMesh(t *testing.T) {
+	qaengine.StartEngine(true, 0, true)
+
+	getIR := func(annotation, value string) irtypes.EnhancedIR {
+		ir := irtypes.NewIR()
+		ir.Name = "petclinic"
+		for _, name := range []string{"api-gateway", "customers", "visits"} {
+			s := irtypes.NewServiceWithName(name)
+			s.Annotations = map[string]string{annotation: value}
+			s.ServiceToPodPortForwardings = []irtypes.ServiceToPodPortForwarding{
+				{ServicePort: networking.ServiceBackendPort{Number: 8080}, PodPort: networking.ServiceBackendPort{Number: 8080}},
+			}
+			ir.Services[name] = s
+		}
+		return irtypes.NewEnhancedIRFromIR(ir)
+	}
+
+	t.Run("istio objects for the called services", func(t *testing.T) {
+		ir := getIR(common.IstioSidecarInjectAnnotation, common.AnnotationLabelValue)
+		gateway := ir.Services["api-gateway"]
+		gateway.Dependencies = []string{"customers", "visits", "config-server"}
+		ir.Services["api-gateway"] = gateway
+		objs := (&APIResource{IAPIResource: new(ServiceMesh)}).ConvertIRToObjects(ir, getClusterWithKinds(common.ServiceKind))
+		virtualServices := []string{}
+		destinationRules := []string{}
+		var peerAuthentication *istio.PeerAuthentication
+		for _, obj := range objs {
+			switch o := obj.(type) {
+			case *istio.VirtualService:
+				virtualServices = append(virtualServices, o.Name)
+				if o.Spec.HTTP[0].Retries == nil || o.Spec.HTTP[0].Retries.Attempts != meshRetryAttempts {
+					t.Fatalf("Expected the requests to %s to be retried. Actual: %+v", o.Name, o.Spec.HTTP[0])
+				}
+			case *istio.DestinationRule:
+				destinationRules = append(destinationRules, o.Name)
+				if o.Spec.TrafficPolicy.OutlierDetection == nil {
+					t.Fatalf("Expected the unhealthy endpoints of %s to be ejected. Actual: %+v", o.Name, o.Spec)
+				}
+			case *istio.PeerAuthentication:
+				peerAuthentication = o
+			}
+		}
+		if len(virtualServices) != 2 || len(destinationRules) != 2 || !common.IsStringPresent(virtualServices, "customers") || !common.IsStringPresent(destinationRules, "visits") {
+			t.Fatalf("Expected virtual services and destination rules for customers and visits. Actual: %v %v", virtualServices, destinationRules)
+		}
+		if peerAuthentication == nil || peerAuthentication.Spec.Selector != nil || peerAuthentication.Spec.MTLS.Mode != istio.MutualTLSPermissive {
+			t.Fatalf("Expected a permissive peer authentication for the namespace. Actual: %+v", peerAuthentication)
+		}
+	})
+
+	t.Run("all the services are called when the calls are unknown", func(t *testing.T) {
+		services := new(ServiceMesh).getCalledServices(getIR(common.IstioSidecarInjectAnnotation, common.AnnotationLabelValue), []string{"api-gateway", "customers", "visits"})
+		if len(services) != 3 {
+			t.Fatalf("Expected all the services to be called. Actual: %v", services)
+		}
+	})
+
+	t.Run("no istio objects in a linkerd service mesh", func(t *testing.T) {
+		objs := (&APIResource{IAPIResource: new(ServiceMesh)}).ConvertIRToObjects(getIR(common.LinkerdInjectAnnotation, "enabled"), getClusterWithKinds(common.ServiceKind))
+		if len(objs) != 0 {
+			t.Fatalf("Expected no objects. Actual: %+v", objs)
+		}
+	})
+}
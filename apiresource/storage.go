@@ -18,6 +18,8 @@ package apiresource
 
 import (
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/k8sschema/externalsecrets"
+	"github.com/konveyor/move2kube/k8sschema/sealedsecrets"
 	collecttypes "github.com/konveyor/move2kube/types/collection"
 	irtypes "github.com/konveyor/move2kube/types/ir"
 	"github.com/sirupsen/logrus"
@@ -32,20 +34,29 @@ type Storage struct {
 
 // getSupportedKinds returns cluster supported kinds
 func (s *Storage) getSupportedKinds() []string {
-	return []string{string(irtypes.PVCKind), string(irtypes.ConfigMapKind), string(irtypes.SecretKind)}
+	return []string{string(irtypes.PVCKind), string(irtypes.ConfigMapKind), string(irtypes.SecretKind), sealedsecrets.SealedSecretKind, externalsecrets.ExternalSecretKind}
 }
 
 // createNewResources converts IR objects to runtime objects
 func (s *Storage) createNewResources(ir irtypes.EnhancedIR, supportedKinds []string, targetCluster collecttypes.ClusterMetadata) []runtime.Object {
 	objs := []runtime.Object{}
+	claimTemplates := getVolumeClaimTemplateNames(ir, targetCluster.Spec)
+	secretsOutput := ""
 	for _, stObj := range ir.Storages {
 		if stObj.StorageType == irtypes.ConfigMapKind {
 			objs = append(objs, s.createConfigMap(stObj))
 		}
 		if stObj.StorageType == irtypes.SecretKind || stObj.StorageType == irtypes.PullSecretKind {
-			objs = append(objs, s.createSecret(stObj))
+			if secretsOutput == "" {
+				secretsOutput = getSecretsOutput()
+			}
+			objs = append(objs, s.createSecretObject(stObj, secretsOutput))
 		}
 		if stObj.StorageType == irtypes.PVCKind {
+			if common.IsStringPresent(claimTemplates, stObj.Name) {
+				logrus.Debugf("Persistent volume claim %s is created by the volume claim templates of a StatefulSet", stObj.Name)
+				continue
+			}
 			objs = append(objs, s.createPVC(stObj))
 		}
 	}
@@ -120,6 +131,34 @@ func (s *Storage) createPVC(st irtypes.Storage) *core.PersistentVolumeClaim {
 	return pvc
 }
 
+// getVolumeClaimTemplateNames returns the claims which are created only through the volume claim templates of StatefulSets
+func getVolumeClaimTemplateNames(ir irtypes.EnhancedIR, cluster collecttypes.ClusterMetadataSpec) []string {
+	if cluster.GetSupportedVersions(statefulSetKind) == nil {
+		return nil
+	}
+	templatedClaims := []string{}
+	sharedClaims := []string{}
+	for _, service := range ir.Services {
+		for _, v := range service.Volumes {
+			if v.PersistentVolumeClaim == nil {
+				continue
+			}
+			if isStatefulSet(service) {
+				templatedClaims = append(templatedClaims, v.PersistentVolumeClaim.ClaimName)
+			} else {
+				sharedClaims = append(sharedClaims, v.PersistentVolumeClaim.ClaimName)
+			}
+		}
+	}
+	claims := []string{}
+	for _, claim := range templatedClaims {
+		if !common.IsStringPresent(sharedClaims, claim) {
+			claims = append(claims, claim)
+		}
+	}
+	return claims
+}
+
 func convertPVCVolumeToEmptyVolume(vPVC core.Volume) *core.Volume {
 	vEmptySrc := &core.VolumeSource{
 		EmptyDir: &core.EmptyDirVolumeSource{},
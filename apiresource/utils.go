@@ -55,6 +55,7 @@ func TransformAndPersist(ir irtypes.EnhancedIR, outputPath string, apis []IAPIRe
 		logrus.Errorf("Failed to write the transformed objects to the directory at path %s . Error: %q", outputPath, err)
 		return nil, err
 	}
+	encryptSecretFiles(outputPath, convertedObjs, filesWritten)
 	return filesWritten, nil
 }
 
@@ -84,6 +85,10 @@ func convertVersion(objs []runtime.Object, clusterSpec collecttypes.ClusterMetad
 	newobjs := []runtime.Object{}
 	for _, obj := range objs {
 		fixedobj := fixer.Fix(obj)
+		if isClusterSupportedHorizontalPodAutoscaler(fixedobj, clusterSpec) {
+			newobjs = append(newobjs, fixedobj)
+			continue
+		}
 		newobj, err := k8sschema.ConvertToSupportedVersion(fixedobj, clusterSpec)
 		if err != nil {
 			logrus.Errorf("Unable to convert to supported version. Writing as is : %s", err)
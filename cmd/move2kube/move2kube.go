@@ -67,6 +67,7 @@ For more documentation and support, visit https://move2kube.konveyor.io/
 	rootCmd.AddCommand(getPlanCommand())
 	rootCmd.AddCommand(getTransformCommand())
 	rootCmd.AddCommand(getParameterizeCommand())
+	rootCmd.AddCommand(getTransformerCommand())
 
 	assetsFilePermissions := map[string]int{}
 	err := yaml.Unmarshal([]byte(assets.AssetFilePermissions), &assetsFilePermissions)
@@ -17,16 +17,23 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/lib"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+const (
+	// dryRunFlag is the name of the flag that prints a report of the parameterization instead of writing the output
+	dryRunFlag = "dry-run"
+)
+
 type parameterizeFlags struct {
 	// outpath contains the path to the output folder
 	outpath string
@@ -34,8 +41,12 @@ type parameterizeFlags struct {
 	srcpath string
 	// customizationsPath contains path to the pack folder
 	customizationsPath string
+	// planfile contains the path to the plan of the transform whose output is parameterized
+	planfile string
 	// overwrite: if the output folder exists then it will be overwritten
 	overwrite bool
+	// dryRun: print the parameters created by each parameterizer for each resource instead of writing the output
+	dryRun bool
 	qaflags
 }
 
@@ -44,12 +55,17 @@ func parameterizeHandler(_ *cobra.Command, flags parameterizeFlags) {
 	if flags.srcpath, err = filepath.Abs(flags.srcpath); err != nil {
 		logrus.Fatalf("Failed to make the source directory path %q absolute. Error: %q", flags.srcpath, err)
 	}
+	if flags.dryRun {
+		previewParameterization(flags)
+		return
+	}
+	if flags.outpath == "" {
+		logrus.Fatalf("The --%s flag is required, unless --%s is used.", outputFlag, dryRunFlag)
+	}
 	if flags.outpath, err = filepath.Abs(flags.outpath); err != nil {
 		logrus.Fatalf("Failed to make the output directory path %q absolute. Error: %q", flags.outpath, err)
 	}
-	if flags.customizationsPath, err = filepath.Abs(flags.customizationsPath); err != nil {
-		logrus.Fatalf("Failed to make the pack directory path %q absolute. Error: %q", flags.customizationsPath, err)
-	}
+	makeCustomizationsAndPlanPathsAbsolute(&flags)
 
 	checkSourcePath(flags.srcpath)
 	checkOutputPath(flags.outpath, flags.overwrite)
@@ -62,7 +78,12 @@ func parameterizeHandler(_ *cobra.Command, flags parameterizeFlags) {
 	startQA(flags.qaflags)
 
 	// Parameterization
-	filesWritten, err := lib.Parameterize(flags.srcpath, flags.customizationsPath, flags.outpath)
+	var filesWritten []string
+	if isTransformOutput(flags) {
+		filesWritten, err = lib.ParameterizeTransformOutput(flags.srcpath, flags.customizationsPath, flags.planfile, flags.outpath)
+	} else {
+		filesWritten, err = lib.Parameterize(flags.srcpath, flags.customizationsPath, flags.outpath)
+	}
 	if err != nil {
 		logrus.Fatalf("Failed to apply all the parameterizations. Error: %q", err)
 	}
@@ -70,6 +91,46 @@ func parameterizeHandler(_ *cobra.Command, flags parameterizeFlags) {
 	logrus.Infof("Parameterized artifacts can be found at [%s].", flags.outpath)
 }
 
+func previewParameterization(flags parameterizeFlags) {
+	makeCustomizationsAndPlanPathsAbsolute(&flags)
+	checkSourcePath(flags.srcpath)
+	var reports []parameterizertypes.ReportT
+	var err error
+	if isTransformOutput(flags) {
+		reports, err = lib.PreviewParameterizeTransformOutput(flags.srcpath, flags.customizationsPath, flags.planfile)
+	} else {
+		reports, err = lib.PreviewParameterize(flags.srcpath, flags.customizationsPath)
+	}
+	if err != nil {
+		logrus.Fatalf("Failed to preview the parameterizations. Error: %q", err)
+	}
+	reportBytes, err := common.ObjectToYamlBytes(reports)
+	if err != nil {
+		logrus.Fatalf("Failed to encode the report of the parameterizations as yaml. Error: %q", err)
+	}
+	fmt.Print(string(reportBytes))
+}
+
+// isTransformOutput returns true if the parameterizers should be created from the metadata in the transform output.
+// This is the case when a plan is given or when there are no customizations.
+func isTransformOutput(flags parameterizeFlags) bool {
+	return flags.planfile != "" || flags.customizationsPath == ""
+}
+
+func makeCustomizationsAndPlanPathsAbsolute(flags *parameterizeFlags) {
+	var err error
+	if flags.customizationsPath != "" {
+		if flags.customizationsPath, err = filepath.Abs(flags.customizationsPath); err != nil {
+			logrus.Fatalf("Failed to make the pack directory path %q absolute. Error: %q", flags.customizationsPath, err)
+		}
+	}
+	if flags.planfile != "" {
+		if flags.planfile, err = filepath.Abs(flags.planfile); err != nil {
+			logrus.Fatalf("Failed to make the plan file path %q absolute. Error: %q", flags.planfile, err)
+		}
+	}
+}
+
 func getParameterizeCommand() *cobra.Command {
 	must := func(err error) {
 		if err != nil {
@@ -89,8 +150,10 @@ func getParameterizeCommand() *cobra.Command {
 	// Basic options
 	parameterizeCmd.Flags().StringVarP(&flags.srcpath, sourceFlag, "s", "", "Specify the directory containing the source code to parameterize.")
 	parameterizeCmd.Flags().StringVarP(&flags.outpath, outputFlag, "o", "", "Specify the directory where the output should be written.")
-	parameterizeCmd.Flags().StringVarP(&flags.customizationsPath, customizationsFlag, "c", "", "Specify directory where customizations are stored.")
+	parameterizeCmd.Flags().StringVarP(&flags.customizationsPath, customizationsFlag, "c", "", "Specify directory where customizations are stored. Without customizations, the output of transform is parameterized using the metadata it contains.")
+	parameterizeCmd.Flags().StringVarP(&flags.planfile, planFlag, "p", "", "Specify the plan used to transform the source, when parameterizing the output of transform.")
 	parameterizeCmd.Flags().BoolVar(&flags.overwrite, overwriteFlag, false, "Overwrite the output directory if it exists. By default we don't overwrite.")
+	parameterizeCmd.Flags().BoolVar(&flags.dryRun, dryRunFlag, false, "Print which parameterizers match each resource and the parameters they create, without writing the output.")
 	parameterizeCmd.Flags().StringVar(&flags.configOut, configOutFlag, ".", "Specify config file output location")
 	parameterizeCmd.Flags().StringVar(&flags.qaCacheOut, qaCacheOutFlag, ".", "Specify cache file output location")
 
@@ -100,8 +163,6 @@ func getParameterizeCommand() *cobra.Command {
 	parameterizeCmd.Flags().IntVar(&flags.qaport, qaportFlag, 0, "Port for the QA service. By default it chooses a random free port.")
 
 	must(parameterizeCmd.MarkFlagRequired(sourceFlag))
-	must(parameterizeCmd.MarkFlagRequired(outputFlag))
-	must(parameterizeCmd.MarkFlagRequired(customizationsFlag))
 
 	must(parameterizeCmd.Flags().MarkHidden(qadisablecliFlag))
 	must(parameterizeCmd.Flags().MarkHidden(qaportFlag))
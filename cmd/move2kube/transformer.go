@@ -0,0 +1,343 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/lib"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/konveyor/move2kube/transformer"
+	"github.com/konveyor/move2kube/transformer/transformertest"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// transformerFlag is the name of the flag that contains the path to the transformer yaml
+	transformerFlag = "transformer"
+	// testCasesFlag is the name of the flag that contains the path to the test cases
+	testCasesFlag = "test-cases"
+	// updateFlag is the name of the flag that lets you overwrite the expected results of the test cases
+	updateFlag = "update"
+	// classFlag is the name of the flag that contains the class of the transformer to create
+	classFlag = "class"
+)
+
+const (
+	inbuiltTransformerSource       = "inbuilt"
+	customizationTransformerSource = "customization"
+)
+
+type transformerTestFlags struct {
+	// transformerPath contains the path to the transformer yaml
+	transformerPath string
+	// testCasesPath contains the path to a test case directory, or a directory containing test case directories
+	testCasesPath string
+	// update overwrites the expected results with the actual results
+	update bool
+}
+
+func transformerTestHandler(flags transformerTestFlags) {
+	testCases, err := getTestCases(flags.testCasesPath)
+	if err != nil {
+		logrus.Fatalf("Failed to find the test cases in %s . Error: %q", flags.testCasesPath, err)
+	}
+	// Questions asked by the transformer are answered with their defaults
+	qaengine.StartEngine(true, 0, true)
+	failed := 0
+	for _, testCase := range testCases {
+		logrus.Infof("Running test case %s", testCase)
+		diffs, err := transformertest.Run(flags.transformerPath, testCase, flags.update)
+		if err != nil {
+			logrus.Errorf("Test case %s failed. Error: %q", testCase, err)
+			failed++
+			continue
+		}
+		if flags.update {
+			logrus.Infof("Updated the expected results of test case %s", testCase)
+			continue
+		}
+		if len(diffs) > 0 {
+			for _, diff := range diffs {
+				fmt.Println(diff)
+			}
+			logrus.Errorf("Test case %s failed", testCase)
+			failed++
+			continue
+		}
+		logrus.Infof("Test case %s passed", testCase)
+	}
+	if failed > 0 {
+		logrus.Fatalf("%d of %d test cases failed", failed, len(testCases))
+	}
+	if flags.update {
+		logrus.Infof("Updated the golden files of %d test cases", len(testCases))
+		return
+	}
+	logrus.Infof("All %d test cases passed", len(testCases))
+}
+
+// getTestCases returns the path itself if it is a test case, else the test cases in its sub directories
+func getTestCases(path string) ([]string, error) {
+	if _, err := os.Stat(filepath.Join(path, transformertest.SourceDirName)); err == nil {
+		return []string{path}, nil
+	}
+	fileInfos, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	testCases := []string{}
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.IsDir() {
+			continue
+		}
+		testCase := filepath.Join(path, fileInfo.Name())
+		if _, err := os.Stat(filepath.Join(testCase, transformertest.SourceDirName)); err == nil {
+			testCases = append(testCases, testCase)
+		}
+	}
+	if len(testCases) == 0 {
+		return nil, fmt.Errorf("no test case directories containing a %s directory were found", transformertest.SourceDirName)
+	}
+	return testCases, nil
+}
+
+func getTransformerTestCommand() *cobra.Command {
+	must := func(err error) {
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	flags := transformerTestFlags{}
+	testCmd := &cobra.Command{
+		Use:   "test",
+		Short: "Test a transformer against golden test cases",
+		Long: `Test a transformer against golden test cases.
+Each test case is a directory containing a source directory on which the transformer is run,
+and an expected directory containing the expected services, transform output and output files.
+Use --update to create or update the expected directory from the actual results.`,
+		Run: func(*cobra.Command, []string) { transformerTestHandler(flags) },
+	}
+
+	testCmd.Flags().StringVarP(&flags.transformerPath, transformerFlag, "t", "", "Specify the path to the transformer yaml.")
+	testCmd.Flags().StringVarP(&flags.testCasesPath, testCasesFlag, "d", "", "Specify a test case directory, or a directory containing test case directories.")
+	testCmd.Flags().BoolVar(&flags.update, updateFlag, false, "Overwrite the expected results of the test cases with the actual results.")
+
+	must(testCmd.MarkFlagRequired(transformerFlag))
+	must(testCmd.MarkFlagRequired(testCasesFlag))
+
+	return testCmd
+}
+
+type transformerListFlags struct {
+	// customizationsPath contains path to the customizations directory
+	customizationsPath string
+}
+
+type transformerInitFlags struct {
+	// class is the class of the transformer to create
+	class string
+	// name is the name of the transformer to create
+	name string
+	// outpath contains the path to the directory in which the transformer is created
+	outpath string
+}
+
+type transformerInfo struct {
+	source string
+	config transformertypes.Transformer
+}
+
+// getTransformerInfos returns the inbuilt transformers and the transformers in the customizations directory, by name
+func getTransformerInfos(customizationsPath string) map[string]transformerInfo {
+	infos := map[string]transformerInfo{}
+	inbuiltConfigs, err := transformer.GetTransformerConfigs(common.AssetsPath)
+	if err != nil {
+		logrus.Fatalf("Failed to load the inbuilt transformers. Error: %q", err)
+	}
+	for tn, tc := range inbuiltConfigs {
+		infos[tn] = transformerInfo{source: inbuiltTransformerSource, config: tc}
+	}
+	if customizationsPath == "" {
+		return infos
+	}
+	customConfigs, err := transformer.GetTransformerConfigs(customizationsPath)
+	if err != nil {
+		logrus.Fatalf("Failed to load the transformers in the customizations directory %s . Error: %q", customizationsPath, err)
+	}
+	for tn, tc := range customConfigs {
+		infos[tn] = transformerInfo{source: customizationTransformerSource, config: tc}
+	}
+	return infos
+}
+
+func transformerListHandler(flags transformerListFlags) {
+	infos := getTransformerInfos(flags.customizationsPath)
+	names := []string{}
+	for tn := range infos {
+		names = append(names, tn)
+	}
+	sort.Strings(names)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCLASS\tMODE\tCONSUMES\tPRODUCES\tSOURCE")
+	for _, tn := range names {
+		info := infos[tn]
+		spec := info.config.Spec
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", tn, spec.Class, spec.Mode, strings.Join(spec.ArtifactsToProcess, ","), strings.Join(spec.ArtifactsToProduce, ","), info.source)
+	}
+	w.Flush()
+}
+
+func transformerDescribeHandler(name string, flags transformerListFlags) {
+	info, ok := getTransformerInfos(flags.customizationsPath)[name]
+	if !ok {
+		logrus.Fatalf("Unable to find the transformer %s", name)
+	}
+	spec := info.config.Spec
+	fmt.Printf("Name:     %s\n", info.config.Name)
+	fmt.Printf("Class:    %s\n", spec.Class)
+	fmt.Printf("Mode:     %s\n", spec.Mode)
+	fmt.Printf("Source:   %s\n", info.source)
+	displayPath := spec.FilePath
+	if info.source == inbuiltTransformerSource {
+		// The extracted assets are deleted on exit, so show the path within the assets instead
+		if relPath, err := filepath.Rel(common.AssetsPath, spec.FilePath); err == nil {
+			displayPath = relPath
+		}
+	}
+	fmt.Printf("Path:     %s\n", displayPath)
+	fmt.Printf("Consumes: %s\n", strings.Join(spec.ArtifactsToProcess, ", "))
+	fmt.Printf("Produces: %s\n", strings.Join(spec.ArtifactsToProduce, ", "))
+	if spec.TemplatesDir != "" {
+		templatesDir := filepath.Join(filepath.Dir(spec.FilePath), spec.TemplatesDir)
+		fmt.Printf("Templates: %s\n", filepath.Join(filepath.Dir(displayPath), spec.TemplatesDir))
+		_ = filepath.Walk(templatesDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return nil
+			}
+			relPath, err := filepath.Rel(templatesDir, path)
+			if err == nil {
+				fmt.Printf("  %s\n", relPath)
+			}
+			return nil
+		})
+	}
+	schema, err := transformer.GetConfigSchema(spec.Class)
+	if err != nil {
+		logrus.Warnf("Unable to get the config schema of the class %s : %s", spec.Class, err)
+	} else if schema != nil {
+		printYamlSection("Config schema", schema)
+	}
+	if spec.Config != nil {
+		printYamlSection("Default config", spec.Config)
+	}
+}
+
+func printYamlSection(title string, obj interface{}) {
+	yamlBytes, err := common.ObjectToYamlBytes(obj)
+	if err != nil {
+		logrus.Errorf("Unable to convert %s to yaml : %s", title, err)
+		return
+	}
+	fmt.Printf("%s:\n", title)
+	for _, line := range strings.Split(strings.TrimRight(string(yamlBytes), "\n"), "\n") {
+		fmt.Printf("  %s\n", line)
+	}
+}
+
+func transformerInitHandler(flags transformerInitFlags) {
+	var err error
+	if flags.outpath, err = filepath.Abs(flags.outpath); err != nil {
+		logrus.Fatalf("Failed to make the output directory path %q absolute. Error: %q", flags.outpath, err)
+	}
+	transformerPath, err := lib.ScaffoldTransformer(flags.class, flags.name, flags.outpath)
+	if err != nil {
+		logrus.Fatalf("Failed to create the transformer. Error: %q", err)
+	}
+	logrus.Infof("The transformer %s has been created at [%s]. See the README.md in it for how to test it.", flags.name, transformerPath)
+}
+
+func getTransformerListCommand() *cobra.Command {
+	flags := transformerListFlags{}
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the available transformers",
+		Long:  "List the inbuilt transformers and the transformers in the customizations directory, along with the artifacts they consume and produce.",
+		Args:  cobra.NoArgs,
+		Run:   func(*cobra.Command, []string) { transformerListHandler(flags) },
+	}
+	listCmd.Flags().StringVarP(&flags.customizationsPath, customizationsFlag, "c", "", "Specify directory where customizations are stored.")
+	return listCmd
+}
+
+func getTransformerDescribeCommand() *cobra.Command {
+	flags := transformerListFlags{}
+	describeCmd := &cobra.Command{
+		Use:   "describe <name>",
+		Short: "Describe a transformer",
+		Long:  "Describe a transformer, including its config schema, default config and templates.",
+		Args:  cobra.ExactArgs(1),
+		Run:   func(_ *cobra.Command, args []string) { transformerDescribeHandler(args[0], flags) },
+	}
+	describeCmd.Flags().StringVarP(&flags.customizationsPath, customizationsFlag, "c", "", "Specify directory where customizations are stored.")
+	return describeCmd
+}
+
+func getTransformerInitCommand() *cobra.Command {
+	must := func(err error) {
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	flags := transformerInitFlags{}
+	initCmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create a new custom transformer",
+		Long:  "Create a new custom transformer with its yaml, a script skeleton, templates and a test case.",
+		Args:  cobra.NoArgs,
+		Run:   func(*cobra.Command, []string) { transformerInitHandler(flags) },
+	}
+	initCmd.Flags().StringVar(&flags.class, classFlag, lib.StarlarkTransformerClass, "Specify the class of the transformer. One of "+lib.StarlarkTransformerClass+", "+lib.ExecutableTransformerClass+".")
+	initCmd.Flags().StringVarP(&flags.name, nameFlag, "n", "", "Specify the name of the transformer.")
+	initCmd.Flags().StringVarP(&flags.outpath, outputFlag, "o", ".", "Specify the directory in which the transformer directory is created.")
+	must(initCmd.MarkFlagRequired(nameFlag))
+	return initCmd
+}
+
+func getTransformerCommand() *cobra.Command {
+	transformerCmd := &cobra.Command{
+		Use:   "transformer",
+		Short: "List, describe, create and test transformers",
+		Long:  "List, describe, create and test transformers",
+	}
+	transformerCmd.AddCommand(getTransformerListCommand())
+	transformerCmd.AddCommand(getTransformerDescribeCommand())
+	transformerCmd.AddCommand(getTransformerInitCommand())
+	transformerCmd.AddCommand(getTransformerTestCommand())
+	return transformerCmd
+}
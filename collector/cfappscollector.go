@@ -83,6 +83,7 @@ func (c *CfAppsCollector) Collect(inputPath string, outputPath string) error {
 		app.Memory = sourcecfapp.CfAppEntity.Memory
 		app.Env = sourcecfapp.CfAppEntity.Env
 		app.Ports = sourcecfapp.CfAppEntity.Ports
+		app.Tasks = c.getTasks(sourcecfapp.CfAppMetadata.GUID)
 		cfinstanceapps.Spec.CfApplications = append(cfinstanceapps.Spec.CfApplications, app)
 
 		fileName = fileName + app.Name
@@ -99,3 +100,38 @@ func (c *CfAppsCollector) Collect(inputPath string, outputPath string) error {
 
 	return nil
 }
+
+// getTasks gets the tasks which were run for the app, most recent first, so that they can be run as jobs
+func (c *CfAppsCollector) getTasks(appGUID string) []collecttypes.CfTask {
+	if appGUID == "" {
+		return nil
+	}
+	//To run: cf curl /v3/apps/:guid/tasks
+	cmd := exec.Command("cf", "curl", "/v3/apps/"+appGUID+"/tasks?order_by=-created_at&per_page=5000")
+	output, err := cmd.Output()
+	if err != nil {
+		logrus.Warnf("Unable to get the tasks of the app %s : %s", appGUID, err)
+		return nil
+	}
+	logrus.Debugf("Cf Curl output %s", output)
+	sourcecftasks := sourcetypes.CfTasks{}
+	if err := json.Unmarshal(output, &sourcecftasks); err != nil {
+		logrus.Warnf("Unable to parse the tasks of the app %s : %s", appGUID, err)
+		return nil
+	}
+	return getCfTasks(sourcecftasks)
+}
+
+// getCfTasks returns the most recent run of each task. The tasks whose command is not visible to the user are skipped.
+func getCfTasks(sourcecftasks sourcetypes.CfTasks) []collecttypes.CfTask {
+	tasks := []collecttypes.CfTask{}
+	names := []string{}
+	for _, sourcecftask := range sourcecftasks.CfResources {
+		if sourcecftask.Command == "" || common.IsStringPresent(names, sourcecftask.Name) {
+			continue
+		}
+		names = append(names, sourcecftask.Name)
+		tasks = append(tasks, collecttypes.CfTask{Name: sourcecftask.Name, Command: sourcecftask.Command})
+	}
+	return tasks
+}
@@ -114,6 +114,7 @@ func getImageInfo(data []byte) collecttypes.ImageInfo {
 			imageInfo.Spec.UserID = -1
 		}
 		imageInfo.Spec.AccessedDirs = append(imageInfo.Spec.AccessedDirs, image.CConfig.WorkingDir)
+		imageInfo.Spec.Env = append(imageInfo.Spec.Env, image.CConfig.Env...)
 		for key := range image.CConfig.EPorts {
 			regex := regexp.MustCompile("[0-9]+")
 			portNumber, err := cast.ToInt32E(string(regex.FindAll([]byte(key), -1)[0]))
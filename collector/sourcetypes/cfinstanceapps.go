@@ -23,7 +23,13 @@ type CfInstanceApps struct {
 
 // CfResource reads entity
 type CfResource struct {
-	CfAppEntity CfSourceApplication `json:"entity"`
+	CfAppMetadata CfSourceMetadata    `json:"metadata"`
+	CfAppEntity   CfSourceApplication `json:"entity"`
+}
+
+// CfSourceMetadata reads the metadata of a source application
+type CfSourceMetadata struct {
+	GUID string `json:"guid"`
 }
 
 // CfSourceApplication reads source application
@@ -37,3 +43,14 @@ type CfSourceApplication struct {
 	Ports             []int32           `json:"ports"`
 	Env               map[string]string `json:"environment_json,omitempty"`
 }
+
+// CfTasks for reading the tasks of a cf application
+type CfTasks struct {
+	CfResources []CfSourceTask `json:"resources"`
+}
+
+// CfSourceTask reads a source task
+type CfSourceTask struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+}
@@ -51,8 +51,24 @@ const (
 	IgnoreFilename = "." + types.AppNameShort + "ignore"
 	// ExposeSelector tag is used to annotate services that are externally exposed
 	ExposeSelector = types.GroupName + "/service.expose"
+	// StatefulSetSelector tag is used to annotate services that should be deployed as stateful sets
+	StatefulSetSelector = types.GroupName + "/service.statefulset"
+	// ScheduleAnnotation tag is used to annotate services that should be run on a cron schedule
+	ScheduleAnnotation = types.GroupName + "/service.schedule"
+	// ProbePathAnnotation tag is used to annotate services with the HTTP path used to check their health
+	ProbePathAnnotation = types.GroupName + "/service.probe.path"
+	// ReadinessProbePathAnnotation tag is used to annotate services whose readiness is checked on a different HTTP path than their liveness
+	ReadinessProbePathAnnotation = types.GroupName + "/service.probe.readiness.path"
+	// ProbePortAnnotation tag is used to annotate services whose health is checked on a different port than the one they serve on
+	ProbePortAnnotation = types.GroupName + "/service.probe.port"
+	// JavaMaxHeapAnnotation tag is used to annotate java services with the maximum heap size of their JVM
+	JavaMaxHeapAnnotation = types.GroupName + "/service.jvm.maxheap"
 	// WindowsAnnotation tag is used tag a service to run on windows nodes
 	WindowsAnnotation = types.GroupName + "/containertype.windows"
+	// IstioSidecarInjectAnnotation tag is used to annotate pods that should get an istio sidecar
+	IstioSidecarInjectAnnotation = "sidecar.istio.io/inject"
+	// LinkerdInjectAnnotation tag is used to annotate pods that should get a linkerd proxy
+	LinkerdInjectAnnotation = "linkerd.io/inject"
 	// AnnotationLabelValue represents the value when an annotation is valid
 	AnnotationLabelValue = "true"
 	// DefaultServicePort is the default port that will be added to a service.
@@ -122,8 +138,66 @@ const (
 	ConfigIngressKey = ConfigTargetKey + d + "ingress"
 	//ConfigIngressHostKey represents Ingress host Key
 	ConfigIngressHostKey = ConfigIngressKey + d + "host"
-	//ConfigIngressTLSKey represents ingress tls Key
-	ConfigIngressTLSKey = ConfigIngressKey + d + "tls"
+	//ConfigTLSKey represents the TLS configuration of the exposed hosts Key
+	ConfigTLSKey = ConfigTargetKey + d + "tls"
+	//ConfigGatewayKey represents Gateway Key
+	ConfigGatewayKey = ConfigTargetKey + d + "gateway"
+	//ConfigGatewayNameKey represents the key of the existing gateway the routes are attached to
+	ConfigGatewayNameKey = ConfigGatewayKey + d + "name"
+	//ConfigGatewayClassKey represents gateway class Key
+	ConfigGatewayClassKey = ConfigGatewayKey + d + "class"
+	//ConfigNetworkPolicyKey represents the strictness of the network policies Key
+	ConfigNetworkPolicyKey = ConfigTargetKey + d + "networkpolicy"
+	//ConfigServiceMeshKey represents the service mesh Key
+	ConfigServiceMeshKey = ConfigTargetKey + d + "servicemesh"
+	//ConfigServiceMeshMTLSKey represents the mutual TLS mode of the service mesh Key
+	ConfigServiceMeshMTLSKey = ConfigServiceMeshKey + d + "mtls"
+	//ConfigHelmKey represents the helm chart Key
+	ConfigHelmKey = ConfigTargetKey + d + "helm"
+	//ConfigHelmUmbrellaKey represents the umbrella helm chart Key
+	ConfigHelmUmbrellaKey = ConfigHelmKey + d + "umbrella"
+	//ConfigHelmExistingChartKey represents the existing helm chart into which the new resources are merged Key
+	ConfigHelmExistingChartKey = ConfigHelmKey + d + "existingchart"
+	//ConfigKustomizeKey represents the kustomize Key
+	ConfigKustomizeKey = ConfigTargetKey + d + "kustomize"
+	//ConfigKustomizePatchTypeKey represents the type of the patches of the kustomize overlays Key
+	ConfigKustomizePatchTypeKey = ConfigKustomizeKey + d + "patchtype"
+	//ConfigKustomizeExistingBaseKey represents the existing kustomization into which the new resources are merged Key
+	ConfigKustomizeExistingBaseKey = ConfigKustomizeKey + d + "existingbase"
+	//ConfigSecretsKey represents the secrets Key
+	ConfigSecretsKey = ConfigTargetKey + d + "secrets"
+	//ConfigSecretsOutputKey represents the kind of the objects the secrets are written as Key
+	ConfigSecretsOutputKey = ConfigSecretsKey + d + "output"
+	//ConfigSealedSecretsCertKey represents the path of the public certificate of the sealed secrets controller Key
+	ConfigSealedSecretsCertKey = ConfigSecretsKey + d + "sealedsecrets" + d + "cert"
+	//ConfigExternalSecretsStoreNameKey represents the name of the store of the external secrets Key
+	ConfigExternalSecretsStoreNameKey = ConfigSecretsKey + d + "externalsecrets" + d + "storename"
+	//ConfigExternalSecretsStoreKindKey represents the kind of the store of the external secrets Key
+	ConfigExternalSecretsStoreKindKey = ConfigSecretsKey + d + "externalsecrets" + d + "storekind"
+	//ConfigSOPSAgeRecipientsKey represents the age recipients the secrets are encrypted for using SOPS Key
+	ConfigSOPSAgeRecipientsKey = ConfigSecretsKey + d + "sops" + d + "agerecipients"
+	//ConfigGitOpsKey represents the GitOps Key
+	ConfigGitOpsKey = ConfigTargetKey + d + "gitops"
+	//ConfigGitOpsToolsKey represents the GitOps tools the deployment is generated for Key
+	ConfigGitOpsToolsKey = ConfigGitOpsKey + d + "tools"
+	//ConfigGitOpsRepoURLKey represents the url of the git repo the GitOps tools sync from Key
+	ConfigGitOpsRepoURLKey = ConfigGitOpsKey + d + "repourl"
+	//ConfigGitOpsRepoBranchKey represents the branch of the git repo the GitOps tools sync from Key
+	ConfigGitOpsRepoBranchKey = ConfigGitOpsKey + d + "repobranch"
+	//ConfigGitOpsNamespaceKey represents the namespace the GitOps tools deploy to Key
+	ConfigGitOpsNamespaceKey = ConfigGitOpsKey + d + "namespace"
+	//ConfigGitOpsPackagingKey represents the parameterized packaging the GitOps tools deploy Key
+	ConfigGitOpsPackagingKey = ConfigGitOpsKey + d + "packaging"
+	//ConfigCICDKey represents the CI/CD pipelines Key
+	ConfigCICDKey = ConfigTargetKey + d + "cicd"
+	//ConfigCICDBranchKey represents the branch on which the CI/CD pipelines deploy Key
+	ConfigCICDBranchKey = ConfigCICDKey + d + "branch"
+	//ConfigCICDDeployKey represents how the CI/CD pipelines deploy the new images Key
+	ConfigCICDDeployKey = ConfigCICDKey + d + "deploy"
+	//ConfigCICDYamlsKey represents the k8s yamls the CI/CD pipelines apply Key
+	ConfigCICDYamlsKey = ConfigCICDKey + d + "yamls"
+	//ConfigPodSecurityStandardKey represents the pod security standard Key
+	ConfigPodSecurityStandardKey = ConfigTargetKey + d + "podsecuritystandard"
 	//ConfigTargetClusterTypeKey represents target cluster type key
 	ConfigTargetClusterTypeKey = ConfigTargetKey + d + "clustertype"
 	//ConfigImageRegistryKey represents image registry Key
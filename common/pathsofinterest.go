@@ -25,6 +25,8 @@ import (
 const (
 	// DefaultPlanFile defines default name for plan file
 	DefaultPlanFile = types.AppNameShort + ".plan"
+	// DefaultTransformMetadataFile defines the name of the file containing the metadata of the k8s resources created by the transform
+	DefaultTransformMetadataFile = types.AppNameShort + ".metadata"
 	// TempDirPrefix defines the prefix of the temp directory
 	TempDirPrefix = types.AppNameShort + "-"
 	// AssetsDir defines the dir of the assets temp directory
@@ -40,6 +42,8 @@ const (
 	DeployDir = "deploy"
 	// CICDDir defines the directory where the deployment artifacts are placed
 	CICDDir = "cicd"
+	// GitOpsDir defines the directory where the GitOps configurations are placed
+	GitOpsDir = "gitops"
 	// HelmDir defines the directory where the helm charts are placed
 	HelmDir = "helm-charts"
 	// OCTemplatesDir defines the directory where the openshift templates are placed
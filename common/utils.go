@@ -36,7 +36,9 @@ import (
 	"strings"
 	"text/template"
 
+	semver "github.com/Masterminds/semver/v3"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/konveyor/move2kube/filesystem"
 	"github.com/konveyor/move2kube/types"
 	"github.com/mitchellh/mapstructure"
@@ -1166,6 +1168,37 @@ func getGitRemoteByName(remotes []*git.Remote, remoteName string) *git.Remote {
 	return nil
 }
 
+// GetGitTagVersion returns the highest semantic version among the tags of the git repo of the path
+func GetGitTagVersion(path string) (*semver.Version, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, err
+	}
+	tags, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	var latest *semver.Version
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		version, err := semver.NewVersion(ref.Name().Short())
+		if err != nil {
+			logrus.Debugf("The tag %s is not a semantic version : %s", ref.Name().Short(), err)
+			return nil
+		}
+		if latest == nil || version.GreaterThan(latest) {
+			latest = version
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("none of the tags of the git repo at path %s are semantic versions", path)
+	}
+	return latest, nil
+}
+
 // GetObjFromInterface loads from map[string]interface{} to struct
 func GetObjFromInterface(obj interface{}, loadinto interface{}) error {
 	decoder, _ := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
@@ -1208,3 +1241,30 @@ func Interrupt() error {
 	}
 	return nil
 }
+
+var javaMaxHeapRegex = regexp.MustCompile(`-Xmx([0-9]+)([kKmMgGtT]?)\b`)
+
+// GetJavaMaxHeapSize returns the maximum heap size in bytes set using -Xmx in the java options. The last -Xmx wins, like in the JVM.
+func GetJavaMaxHeapSize(javaOptions string) (int64, bool) {
+	matches := javaMaxHeapRegex.FindAllStringSubmatch(javaOptions, -1)
+	if len(matches) == 0 {
+		return 0, false
+	}
+	match := matches[len(matches)-1]
+	size, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		logrus.Debugf("Unable to parse the maximum heap size %s : %s", match[0], err)
+		return 0, false
+	}
+	switch strings.ToLower(match[2]) {
+	case "k":
+		size *= 1024
+	case "m":
+		size *= 1024 * 1024
+	case "g":
+		size *= 1024 * 1024 * 1024
+	case "t":
+		size *= 1024 * 1024 * 1024 * 1024
+	}
+	return size, true
+}
@@ -36,6 +36,10 @@ var (
 type ContainerEngine interface {
 	// RunCmdInContainer runs a container
 	RunCmdInContainer(image string, cmd environmenttypes.Command, workingdir string, env []string) (stdout, stderr string, exitcode int, err error)
+	// StartCmdInContainer starts a long running command in a container without waiting for it to finish
+	StartCmdInContainer(containerID string, cmd environmenttypes.Command, workingdir string, env []string) (err error)
+	// GetContainerIP returns the IP address of a running container
+	GetContainerIP(containerID string) (ip string, err error)
 	// InspectImage gets Inspect output for a container
 	InspectImage(image string) (dockertypes.ImageInspect, error)
 	// TODO: Change paths from map to array
@@ -136,6 +136,43 @@ func (e *dockerEngine) RunCmdInContainer(containerID string, cmd environmenttype
 	return
 }
 
+// StartCmdInContainer starts a command in a container and returns without waiting for it to finish
+func (e *dockerEngine) StartCmdInContainer(containerID string, cmd environmenttypes.Command, workingdir string, env []string) (err error) {
+	execConfig := types.ExecConfig{
+		Detach:     true,
+		Cmd:        cmd,
+		WorkingDir: workingdir,
+		Env:        env,
+	}
+	cresp, err := e.cli.ContainerExecCreate(e.ctx, containerID, execConfig)
+	if err != nil {
+		logrus.Debugf("Unable to create exec for %s in container %s : %s", cmd, containerID, err)
+		return err
+	}
+	return e.cli.ContainerExecStart(e.ctx, cresp.ID, types.ExecStartCheck{Detach: true})
+}
+
+// GetContainerIP returns the IP address of the container in the default network
+func (e *dockerEngine) GetContainerIP(containerID string) (ip string, err error) {
+	cjson, err := e.cli.ContainerInspect(e.ctx, containerID)
+	if err != nil {
+		logrus.Debugf("Unable to inspect container %s : %s", containerID, err)
+		return "", err
+	}
+	if cjson.NetworkSettings == nil {
+		return "", fmt.Errorf("no network settings found for container %s", containerID)
+	}
+	if cjson.NetworkSettings.IPAddress != "" {
+		return cjson.NetworkSettings.IPAddress, nil
+	}
+	for _, network := range cjson.NetworkSettings.Networks {
+		if network.IPAddress != "" {
+			return network.IPAddress, nil
+		}
+	}
+	return "", fmt.Errorf("no ip address found for container %s", containerID)
+}
+
 // InspectImage returns inspect output for an image
 func (e *dockerEngine) InspectImage(image string) (types.ImageInspect, error) {
 	inspectOutput, _, err := e.cli.ImageInspectWithRaw(e.ctx, image)
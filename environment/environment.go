@@ -18,6 +18,7 @@ package environment
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"os"
@@ -57,6 +58,8 @@ var (
 	TempPathEnvName = strings.ToUpper(types.AppNameShort) + "_TEMP"
 	// EnvNameEnvName stores the environment name
 	EnvNameEnvName = strings.ToUpper(types.AppNameShort) + "_ENV_NAME"
+	// PluginPortEnvName stores the port on which a spawned plugin server should listen
+	PluginPortEnvName = strings.ToUpper(types.AppNameShort) + "_PLUGIN_PORT"
 )
 
 // Environment is used to manage EnvironmentInstances
@@ -66,6 +69,7 @@ type Environment struct {
 	Children     []*Environment
 	TempPathsMap map[string]string
 	active       bool
+	closers      []io.Closer
 }
 
 // EnvironmentInstance represents a actual instance of an environment which the Environment manages
@@ -74,6 +78,7 @@ type EnvironmentInstance interface {
 	Download(envpath string) (outpath string, err error)
 	Upload(outpath string) (envpath string, err error)
 	Exec(cmd []string) (stdout string, stderr string, exitcode int, err error)
+	Spawn(cmd []string, envs []string) (host string, err error)
 	Destroy() error
 
 	GetSource() string
@@ -160,9 +165,30 @@ func (e *Environment) Exec(cmd []string) (stdout string, stderr string, exitcode
 	return e.Env.Exec(cmd)
 }
 
+// Spawn starts a long running process within the environment and returns the host on which it can be reached
+func (e *Environment) Spawn(cmd []string, envs []string) (host string, err error) {
+	if !e.active {
+		err = &EnvironmentNotActiveError{}
+		logrus.Debug(err)
+		return "", err
+	}
+	return e.Env.Spawn(cmd, envs)
+}
+
+// AddCloser adds a resource, like a connection to a spawned process, which is closed when the environment is destroyed
+func (e *Environment) AddCloser(c io.Closer) {
+	e.closers = append(e.closers, c)
+}
+
 // Destroy destroys all artifacts specific to the environment
 func (e *Environment) Destroy() error {
 	e.active = false
+	for _, c := range e.closers {
+		if err := c.Close(); err != nil {
+			logrus.Debugf("Unable to close %T : %s", c, err)
+		}
+	}
+	e.closers = nil
 	e.Env.Destroy()
 	for _, env := range e.Children {
 		if err := env.Destroy(); err != nil {
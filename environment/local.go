@@ -40,6 +40,8 @@ type Local struct {
 	WorkspaceContext string
 
 	GRPCQAReceiver net.Addr
+
+	spawnedProcesses []*exec.Cmd
 }
 
 // NewLocal creates a new Local environment
@@ -107,8 +109,32 @@ func (e *Local) Exec(cmd environmenttypes.Command) (stdout string, stderr string
 	return outb.String(), errb.String(), exitcode, err
 }
 
+// Spawn starts a long running process within the environment
+func (e *Local) Spawn(cmd environmenttypes.Command, envs []string) (host string, err error) {
+	if len(cmd) == 0 {
+		err := fmt.Errorf("no command found to spawn")
+		logrus.Errorf("%s", err)
+		return "", err
+	}
+	execcmd := exec.Command(cmd[0], cmd[1:]...)
+	execcmd.Dir = e.WorkspaceContext
+	execcmd.Stdout = logrus.StandardLogger().WriterLevel(logrus.DebugLevel)
+	execcmd.Stderr = logrus.StandardLogger().WriterLevel(logrus.DebugLevel)
+	execcmd.Env = append(e.getEnv(), envs...)
+	if err := execcmd.Start(); err != nil {
+		logrus.Errorf("Unable to start process %s : %s", cmd, err)
+		return "", err
+	}
+	e.spawnedProcesses = append(e.spawnedProcesses, execcmd)
+	return "localhost", nil
+}
+
 // Destroy destroys all artifacts specific to the environment
 func (e *Local) Destroy() error {
+	for _, execcmd := range e.spawnedProcesses {
+		stopProcess(execcmd)
+	}
+	e.spawnedProcesses = nil
 	err := os.RemoveAll(e.WorkspaceSource)
 	if err != nil {
 		logrus.Errorf("Unable to remove directory %s : %s", e.WorkspaceSource, err)
@@ -0,0 +1,84 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package environment
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	environmenttypes "github.com/konveyor/move2kube/types/environment"
+)
+
+func TestLocalSpawn(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The spawned test process needs a unix shell")
+	}
+	ei, err := NewLocal(EnvInfo{Name: "test", Source: t.TempDir(), Context: t.TempDir(), TempPath: t.TempDir()}, nil)
+	if err != nil {
+		t.Fatalf("Unable to create the local environment : %s", err)
+	}
+	local := ei.(*Local)
+	portFile := filepath.Join(t.TempDir(), "port")
+	host, err := local.Spawn(environmenttypes.Command{"sh", "-c", `echo "$` + PluginPortEnvName + `" > ` + portFile + `; exec sleep 60`}, []string{PluginPortEnvName + "=8080"})
+	if err != nil {
+		t.Fatalf("Unable to spawn the process : %s", err)
+	}
+	if host != "localhost" {
+		t.Fatalf("Expected the host to be localhost. Actual: %s", host)
+	}
+	if len(local.spawnedProcesses) != 1 {
+		t.Fatalf("Expected the spawned process to be tracked. Actual: %d processes", len(local.spawnedProcesses))
+	}
+	execcmd := local.spawnedProcesses[0]
+	var port []byte
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+		if port, err = ioutil.ReadFile(portFile); err == nil && len(port) > 0 {
+			break
+		}
+	}
+	if strings.TrimSpace(string(port)) != "8080" {
+		t.Fatalf("Expected the port to be passed to the process. Actual: %q", port)
+	}
+	start := time.Now()
+	if err := local.Destroy(); err != nil {
+		t.Fatalf("Unable to destroy the local environment : %s", err)
+	}
+	if execcmd.ProcessState == nil {
+		t.Fatal("Expected the spawned process to be stopped")
+	}
+	if elapsed := time.Since(start); elapsed >= processStopTimeout {
+		t.Fatalf("Expected the process to stop on interrupt without being killed. Took %s", elapsed)
+	}
+	if local.spawnedProcesses != nil {
+		t.Fatal("Expected the spawned processes to be cleared")
+	}
+}
+
+func TestLocalSpawnWithoutCommand(t *testing.T) {
+	ei, err := NewLocal(EnvInfo{Name: "test", Source: t.TempDir(), Context: t.TempDir(), TempPath: t.TempDir()}, nil)
+	if err != nil {
+		t.Fatalf("Unable to create the local environment : %s", err)
+	}
+	defer ei.Destroy()
+	if _, err := ei.Spawn(environmenttypes.Command{}, nil); err == nil {
+		t.Fatal("Expected an error when no command is given")
+	}
+}
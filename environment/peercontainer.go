@@ -48,6 +48,8 @@ type PeerContainer struct {
 	ImageName     string
 	ImageWithData string
 	CID           string // A started instance of ImageWithData
+
+	spawned bool
 }
 
 // NewPeerContainer creates an instance of peer container based environment
@@ -97,6 +99,11 @@ func NewPeerContainer(envInfo EnvInfo, grpcQAReceiver net.Addr, c environmenttyp
 
 // Reset resets the PeerContainer environment
 func (e *PeerContainer) Reset() error {
+	if e.spawned {
+		// Recreating the container would kill the long running processes spawned in it
+		logrus.Debugf("Not resetting container %s since it has spawned processes", e.CID)
+		return nil
+	}
 	cengine := container.GetContainerEngine()
 	err := cengine.StopAndRemoveContainer(e.CID)
 	if err != nil {
@@ -123,9 +130,31 @@ func (e *PeerContainer) Exec(cmd environmenttypes.Command) (stdout string, stder
 	return cengine.RunCmdInContainer(e.CID, cmd, e.WorkspaceContext, envs)
 }
 
+// Spawn starts a long running process in the container
+func (e *PeerContainer) Spawn(cmd environmenttypes.Command, envs []string) (host string, err error) {
+	cengine := container.GetContainerEngine()
+	if e.GRPCQAReceiver != nil {
+		hostname := getIP()
+		port := cast.ToString(e.GRPCQAReceiver.(*net.TCPAddr).Port)
+		envs = append(envs, GRPCEnvName+"="+hostname+":"+port)
+	}
+	if err := cengine.StartCmdInContainer(e.CID, cmd, e.WorkspaceContext, envs); err != nil {
+		logrus.Errorf("Unable to start %s in container %s : %s", cmd, e.CID, err)
+		return "", err
+	}
+	e.spawned = true
+	return cengine.GetContainerIP(e.CID)
+}
+
 // Destroy destroys the container instance
 func (e *PeerContainer) Destroy() error {
 	cengine := container.GetContainerEngine()
+	if e.spawned {
+		// Give the spawned processes a chance to shutdown gracefully before the container is removed
+		if _, stderr, _, err := cengine.RunCmdInContainer(e.CID, environmenttypes.Command{"sh", "-c", "kill -TERM -1; sleep 2"}, e.WorkspaceContext, nil); err != nil {
+			logrus.Debugf("Unable to stop spawned processes in container %s : %s : %s", e.CID, stderr, err)
+		}
+	}
 	err := cengine.StopAndRemoveContainer(e.CID)
 	if err != nil {
 		logrus.Errorf("Unable to stop and remove container %s : %s", e.CID, err)
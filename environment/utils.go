@@ -17,11 +17,18 @@
 package environment
 
 import (
+	"io"
 	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
+const processStopTimeout = 10 * time.Second
+
 func getIP() string {
 	conn, err := net.Dial("udp", "8.8.8.8:80")
 	if err != nil {
@@ -31,3 +38,38 @@ func getIP() string {
 	localAddr := conn.LocalAddr().(*net.UDPAddr)
 	return localAddr.IP.String()
 }
+
+// stopProcess asks a spawned process to terminate and kills it if it does not exit within the timeout
+func stopProcess(execcmd *exec.Cmd) {
+	if execcmd.Process == nil {
+		return
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- execcmd.Wait()
+		for _, w := range []io.Writer{execcmd.Stdout, execcmd.Stderr} {
+			if c, ok := w.(io.Closer); ok {
+				c.Close()
+			}
+		}
+	}()
+	if runtime.GOOS == "windows" {
+		if err := execcmd.Process.Kill(); err != nil {
+			logrus.Debugf("Unable to kill process %d : %s", execcmd.Process.Pid, err)
+		}
+		<-done
+		return
+	}
+	if err := execcmd.Process.Signal(os.Interrupt); err != nil {
+		logrus.Debugf("Unable to interrupt process %d : %s", execcmd.Process.Pid, err)
+	}
+	select {
+	case <-done:
+	case <-time.After(processStopTimeout):
+		logrus.Warnf("Process %d did not stop within %s. Killing it.", execcmd.Process.Pid, processStopTimeout)
+		if err := execcmd.Process.Kill(); err != nil {
+			logrus.Errorf("Unable to kill process %d : %s", execcmd.Process.Pid, err)
+		}
+		<-done
+	}
+}
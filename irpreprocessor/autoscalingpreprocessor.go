@@ -0,0 +1,105 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// autoscalingPreprocessor sets the autoscaling and disruption budget of the services
+type autoscalingPreprocessor struct {
+}
+
+const (
+	defaultTargetCPUUtilizationPercentage = 80
+	defaultMaxUnavailable                 = 1
+)
+
+func (ap autoscalingPreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
+	for sn, s := range ir.Services {
+		if s.OnlyIngress || s.Daemon || s.Schedule != "" || s.RestartPolicy == core.RestartPolicyNever || s.RestartPolicy == core.RestartPolicyOnFailure {
+			continue
+		}
+		keyPrefix := common.ConfigServicesKey + common.Delim + `"` + sn + `"` + common.Delim
+		// Stateful services are not always safe to scale, so they are not autoscaled unless the IR says so
+		if s.Autoscaling.MaxReplicas == 0 && !s.StatefulSet {
+			key := keyPrefix + "autoscaling" + common.Delim + "enable"
+			message := fmt.Sprintf("Should the service %s be horizontally autoscaled?", sn)
+			hints := []string{"A HorizontalPodAutoscaler scales the replicas of the service based on its CPU and memory utilization"}
+			if qaengine.FetchBoolAnswer(key, message, hints, false) {
+				s.Autoscaling = ap.getAutoscaling(keyPrefix+"autoscaling"+common.Delim, sn, s.Replicas)
+			}
+		}
+		if s.Autoscaling.MaxReplicas != 0 {
+			if s.Autoscaling.MinReplicas < 1 {
+				s.Autoscaling.MinReplicas = 1
+			}
+			if s.Autoscaling.MaxReplicas < s.Autoscaling.MinReplicas {
+				logrus.Warnf("The maximum replicas %d of the service %s is less than its minimum replicas %d. Using %d.", s.Autoscaling.MaxReplicas, sn, s.Autoscaling.MinReplicas, s.Autoscaling.MinReplicas)
+				s.Autoscaling.MaxReplicas = s.Autoscaling.MinReplicas
+			}
+			s.Replicas = s.Autoscaling.MinReplicas
+		}
+		if s.MaxUnavailable == 0 && s.Replicas > 1 {
+			key := keyPrefix + "maxunavailable"
+			message := fmt.Sprintf("How many pods of the service %s can be unavailable during voluntary disruptions like node drains?", sn)
+			hints := []string{"Enter 0 to not create a PodDisruptionBudget"}
+			// The autoscaled services get a PodDisruptionBudget by default, since their replicas change on their own
+			def := 0
+			if s.Autoscaling.MaxReplicas != 0 {
+				def = defaultMaxUnavailable
+			}
+			s.MaxUnavailable = getIntAnswer(key, message, hints, def)
+		}
+		if s.MaxUnavailable < 0 {
+			s.MaxUnavailable = 0
+		}
+		ir.Services[sn] = s
+	}
+	return ir, nil
+}
+
+func (ap autoscalingPreprocessor) getAutoscaling(keyPrefix, serviceName string, replicas int) irtypes.Autoscaling {
+	if replicas < 1 {
+		replicas = 1
+	}
+	autoscaling := irtypes.Autoscaling{}
+	autoscaling.MinReplicas = getIntAnswer(keyPrefix+"minreplicas", fmt.Sprintf("What is the minimum number of replicas of the service %s?", serviceName), []string{}, replicas)
+	autoscaling.MaxReplicas = getIntAnswer(keyPrefix+"maxreplicas", fmt.Sprintf("What is the maximum number of replicas of the service %s?", serviceName), []string{}, 2*autoscaling.MinReplicas)
+	autoscaling.TargetCPUUtilizationPercentage = getIntAnswer(keyPrefix+"cpu", fmt.Sprintf("What CPU utilization percentage should the service %s be scaled at?", serviceName), []string{"Enter 0 to not scale on CPU utilization"}, defaultTargetCPUUtilizationPercentage)
+	autoscaling.TargetMemoryUtilizationPercentage = getIntAnswer(keyPrefix+"memory", fmt.Sprintf("What memory utilization percentage should the service %s be scaled at?", serviceName), []string{"Enter 0 to not scale on memory utilization"}, 0)
+	return autoscaling
+}
+
+// getIntAnswer asks a question whose answer is a non negative number
+func getIntAnswer(key, message string, hints []string, def int) int {
+	answer := strings.TrimSpace(qaengine.FetchStringAnswer(key, message, hints, strconv.Itoa(def)))
+	value, err := strconv.Atoi(answer)
+	if err != nil || value < 0 {
+		logrus.Errorf("%s is not a valid number for %s. Reverting to default %d.", answer, key, def)
+		return def
+	}
+	return value
+}
@@ -0,0 +1,99 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestAutoscalingPreprocessor(t *testing.T) {
+	logrus.SetLevel(logrus.DebugLevel)
+	qaengine.StartEngine(true, 0, true)
+
+	t.Run("service is not autoscaled by default", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		svc := irtypes.NewServiceWithName("web")
+		svc.Replicas = 3
+		ir.Services["web"] = svc
+		actual, err := autoscalingPreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		if actual.Services["web"].Autoscaling.MaxReplicas != 0 || actual.Services["web"].MaxUnavailable != 0 || actual.Services["web"].Replicas != 3 {
+			t.Fatalf("Expected the service to be unchanged. Actual: %+v", actual.Services["web"])
+		}
+	})
+
+	t.Run("service with replicas from the source", func(t *testing.T) {
+		qaengine.SetupConfigFile("", []string{`move2kube.services."api".autoscaling.enable=true`}, nil, nil)
+		ir := irtypes.NewIR()
+		svc := irtypes.NewServiceWithName("api")
+		svc.Replicas = 3
+		ir.Services["api"] = svc
+		actual, err := autoscalingPreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		want := irtypes.Autoscaling{MinReplicas: 3, MaxReplicas: 6, TargetCPUUtilizationPercentage: defaultTargetCPUUtilizationPercentage}
+		if !cmp.Equal(actual.Services["api"].Autoscaling, want) {
+			t.Fatalf("Failed to get the autoscaling properly. Differences:\n%s", cmp.Diff(want, actual.Services["api"].Autoscaling))
+		}
+		if actual.Services["api"].MaxUnavailable != defaultMaxUnavailable {
+			t.Fatalf("Expected %d max unavailable pods. Actual: %d", defaultMaxUnavailable, actual.Services["api"].MaxUnavailable)
+		}
+	})
+
+	t.Run("invalid autoscaling from the IR is fixed", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		svc := irtypes.NewServiceWithName("web")
+		svc.Autoscaling = irtypes.Autoscaling{MinReplicas: 4, MaxReplicas: 2}
+		ir.Services["web"] = svc
+		actual, err := autoscalingPreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		if actual.Services["web"].Autoscaling.MaxReplicas != 4 || actual.Services["web"].Replicas != 4 {
+			t.Fatalf("Expected 4 replicas and 4 maximum replicas. Actual: %+v", actual.Services["web"])
+		}
+	})
+
+	t.Run("jobs and stateful services are not autoscaled", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		job := irtypes.NewServiceWithName("job")
+		job.RestartPolicy = core.RestartPolicyOnFailure
+		db := irtypes.NewServiceWithName("db")
+		db.StatefulSet = true
+		db.Replicas = 1
+		ir.Services["job"] = job
+		ir.Services["db"] = db
+		actual, err := autoscalingPreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		for _, name := range []string{"job", "db"} {
+			if actual.Services[name].Autoscaling.MaxReplicas != 0 || actual.Services[name].MaxUnavailable != 0 {
+				t.Fatalf("Expected the service %s to not be autoscaled. Actual: %+v", name, actual.Services[name])
+			}
+		}
+	})
+}
@@ -0,0 +1,105 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+)
+
+// dependencyPreprocessor infers the services a service calls from the hostnames in its environment variables.
+// The hosts outside the application are recorded too, so that the network policies can allow the calls to them.
+type dependencyPreprocessor struct {
+}
+
+var (
+	envValueSeparatorRegex = regexp.MustCompile(`[\s,;]+`)
+)
+
+func (dp dependencyPreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
+	for sn, s := range ir.Services {
+		for _, c := range s.Containers {
+			for _, env := range c.Env {
+				for _, host := range getEnvHosts(env.Value) {
+					dependency := getDependency(host, ir.Services)
+					if dependency == "" || dependency == sn || common.IsStringPresent(s.Dependencies, dependency) {
+						continue
+					}
+					logrus.Debugf("The service %s calls %s according to the environment variable %s", sn, dependency, env.Name)
+					s.Dependencies = append(s.Dependencies, dependency)
+				}
+			}
+		}
+		ir.Services[sn] = s
+	}
+	return ir, nil
+}
+
+// getEnvHosts returns the hostnames in an environment variable value, like in http://orders:8080/api or db:5432.
+// Words with dots are returned only when they are used as hosts, like in a url or with a port, so that the
+// class names and version numbers are not mistaken for hosts.
+func getEnvHosts(value string) []string {
+	hosts := []string{}
+	for _, token := range envValueSeparatorRegex.Split(value, -1) {
+		host := token
+		isHost := false
+		// The scheme can have several parts, like in jdbc:mysql://
+		if i := strings.LastIndex(host, "://"); i != -1 {
+			host = host[i+len("://"):]
+			isHost = true
+		}
+		host = strings.SplitN(host, "/", 2)[0]
+		if i := strings.LastIndex(host, "@"); i != -1 {
+			host = host[i+1:]
+			isHost = true
+		}
+		hostAndPort := strings.SplitN(host, ":", 2)
+		host = strings.ToLower(hostAndPort[0])
+		if len(hostAndPort) == 2 {
+			isHost = true
+		}
+		if labels := strings.Split(host, "."); host == "" || (len(labels) > 1 && !isHost && (len(labels) < 3 || labels[2] != "svc")) {
+			continue
+		}
+		hosts = common.MergeStringSlices(hosts, host)
+	}
+	return hosts
+}
+
+// getDependency returns the name of the service called through the host, or the host itself if it is outside the application.
+// The first label of the host is resolved against the services when the rest is a cluster domain, like in orders.shop.svc.cluster.local.
+// It returns an empty string for the words without dots which are not services.
+func getDependency(host string, services map[string]irtypes.Service) string {
+	labels := strings.SplitN(host, ".", 2)
+	if _, ok := services[labels[0]]; ok && (len(labels) == 1 || isClusterDomain(labels[1])) {
+		return labels[0]
+	}
+	if len(labels) == 1 {
+		return ""
+	}
+	return host
+}
+
+// isClusterDomain returns true for the parts of the kubernetes service hostnames after the service name, like shop or shop.svc.cluster.local
+func isClusterDomain(domain string) bool {
+	labels := strings.Split(domain, ".")
+	return len(labels) == 1 || labels[1] == "svc"
+}
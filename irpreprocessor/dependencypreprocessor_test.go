@@ -0,0 +1,92 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestDependencyPreprocessor(t *testing.T) {
+	logrus.SetLevel(logrus.DebugLevel)
+
+	t.Run("hosts in environment variable values", func(t *testing.T) {
+		testcases := map[string][]string{
+			"http://orders:8080/api":    {"orders"},
+			"db:5432":                   {"db"},
+			"redis":                     {"redis"},
+			"kafka-0:9092,kafka-1:9092": {"kafka-0", "kafka-1"},
+			"jdbc:mysql://mysql.shop.svc.cluster.local/store": {"mysql.shop.svc.cluster.local"},
+			"mysql.shop.svc":          {"mysql.shop.svc"},
+			"https://api.example.com": {"api.example.com"},
+			"db.example.com:5432":     {"db.example.com"},
+			"user@smtp.example.com":   {"smtp.example.com"},
+			"com.example.Main":        {},
+			"1.2.3":                   {},
+			"":                        {},
+		}
+		for value, want := range testcases {
+			if actual := getEnvHosts(value); !cmp.Equal(actual, want) {
+				t.Errorf("Failed to get the hosts in %q. Expected: %v Actual: %v", value, want, actual)
+			}
+		}
+	})
+
+	t.Run("classification of the hosts", func(t *testing.T) {
+		services := map[string]irtypes.Service{"orders": irtypes.NewServiceWithName("orders"), "db": irtypes.NewServiceWithName("db")}
+		testcases := map[string]string{
+			"orders":                          "orders",
+			"orders.shop":                     "orders",
+			"orders.shop.svc.cluster.local":   "orders",
+			"db.example.com":                  "db.example.com",
+			"payments.shop.svc.cluster.local": "payments.shop.svc.cluster.local",
+			"10.0.0.5":                        "10.0.0.5",
+			"localhost":                       "",
+		}
+		for host, want := range testcases {
+			if actual := getDependency(host, services); actual != want {
+				t.Errorf("Failed to classify the host %q. Expected: %q Actual: %q", host, want, actual)
+			}
+		}
+	})
+
+	t.Run("services called through environment variables", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		web := irtypes.NewServiceWithName("web")
+		web.Containers = []core.Container{{Name: "web", Env: []core.EnvVar{
+			{Name: "ORDERS_URL", Value: "http://orders:8080"},
+			{Name: "SELF_URL", Value: "http://web"},
+			{Name: "PAYMENTS_URL", Value: "https://payments.example.com"},
+			{Name: "DB_URL", Value: "jdbc:postgresql://db.shop.svc.cluster.local:5432/store"},
+			{Name: "MAIN_CLASS", Value: "com.example.Main"},
+		}}}
+		ir.Services["web"] = web
+		ir.Services["orders"] = irtypes.NewServiceWithName("orders")
+		ir.Services["db"] = irtypes.NewServiceWithName("db")
+		ir, err := dependencyPreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatalf("Failed to preprocess the IR. Error: %q", err)
+		}
+		if want := []string{"orders", "payments.example.com", "db"}; !cmp.Equal(ir.Services["web"].Dependencies, want) {
+			t.Fatalf("Expected web to call orders, db and the external payments service. Actual: %v", ir.Services["web"].Dependencies)
+		}
+	})
+}
@@ -0,0 +1,236 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// podSecurityPreprocessor hardens the security context of the services to meet a pod security standard
+type podSecurityPreprocessor struct {
+}
+
+const (
+	privilegedPodSecurityStandard = "privileged"
+	baselinePodSecurityStandard   = "baseline"
+	restrictedPodSecurityStandard = "restricted"
+	// podSecurityTODOAnnotation lists the reasons why a service does not meet the chosen pod security standard
+	podSecurityTODOAnnotation = common.TODOAnnotation + "podsecurity"
+	tmpDir                    = "/tmp"
+)
+
+var (
+	// baselineCapabilities are the capabilities which can be added under the baseline pod security standard
+	baselineCapabilities = []string{"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD", "NET_BIND_SERVICE", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT"}
+	// restrictedCapabilities are the capabilities which can be added under the restricted pod security standard
+	restrictedCapabilities = []string{"NET_BIND_SERVICE"}
+)
+
+func (pp podSecurityPreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
+	if len(ir.Services) == 0 {
+		return ir, nil
+	}
+	message := "Which Pod Security Standard should the services meet?"
+	hints := []string{
+		"privileged : the services are not changed",
+		"baseline : the services run with the default seccomp profile and can not escalate privileges",
+		"restricted : the services additionally run as non root users, with all capabilities dropped and read only root filesystems",
+	}
+	level := qaengine.FetchSelectAnswer(common.ConfigPodSecurityStandardKey, message, hints, privilegedPodSecurityStandard, []string{privilegedPodSecurityStandard, baselinePodSecurityStandard, restrictedPodSecurityStandard})
+	if level == privilegedPodSecurityStandard {
+		return ir, nil
+	}
+	violations := map[string][]string{}
+	for sn, s := range ir.Services {
+		// The security context fields used here are not supported by windows containers
+		if _, ok := s.Annotations[common.WindowsAnnotation]; ok || s.OnlyIngress {
+			continue
+		}
+		reasons := pp.harden(&s, ir.ContainerImages, level)
+		if len(reasons) != 0 {
+			violations[sn] = reasons
+			if s.Annotations == nil {
+				s.Annotations = map[string]string{}
+			}
+			s.Annotations[podSecurityTODOAnnotation] = strings.Join(reasons, "; ")
+		}
+		ir.Services[sn] = s
+	}
+	if len(violations) != 0 {
+		serviceNames := []string{}
+		for sn := range violations {
+			serviceNames = append(serviceNames, sn)
+		}
+		sort.Strings(serviceNames)
+		logrus.Warnf("The following services do not meet the %s Pod Security Standard and have been annotated with %s :", level, podSecurityTODOAnnotation)
+		for _, sn := range serviceNames {
+			logrus.Warnf("%s : %s", sn, strings.Join(violations[sn], "; "))
+		}
+	}
+	return ir, nil
+}
+
+// harden sets the security context of the service and returns the reasons why it can not meet the pod security standard
+func (pp podSecurityPreprocessor) harden(s *irtypes.Service, images map[string]irtypes.ContainerImage, level string) []string {
+	reasons := []string{}
+	if s.SecurityContext == nil {
+		s.SecurityContext = &core.PodSecurityContext{}
+	}
+	if s.SecurityContext.HostNetwork || s.SecurityContext.HostPID || s.SecurityContext.HostIPC {
+		reasons = append(reasons, "shares the host namespaces")
+	}
+	if s.SecurityContext.SeccompProfile == nil {
+		s.SecurityContext.SeccompProfile = &core.SeccompProfile{Type: core.SeccompProfileTypeRuntimeDefault}
+	} else if s.SecurityContext.SeccompProfile.Type == core.SeccompProfileTypeUnconfined {
+		reasons = append(reasons, "uses the unconfined seccomp profile")
+	}
+	for _, volume := range s.Volumes {
+		if volume.HostPath != nil {
+			reasons = append(reasons, fmt.Sprintf("mounts the host path %s", volume.HostPath.Path))
+		} else if level == restrictedPodSecurityStandard && !pp.isRestrictedVolume(volume) {
+			reasons = append(reasons, fmt.Sprintf("uses the volume %s whose type is not allowed", volume.Name))
+		}
+	}
+	allowedCapabilities := baselineCapabilities
+	if level == restrictedPodSecurityStandard {
+		allowedCapabilities = restrictedCapabilities
+	}
+	for ci, c := range s.Containers {
+		if c.SecurityContext == nil {
+			c.SecurityContext = &core.SecurityContext{}
+		}
+		if c.SecurityContext.Privileged != nil && *c.SecurityContext.Privileged {
+			reasons = append(reasons, fmt.Sprintf("the container %s is privileged", c.Name))
+		}
+		for _, port := range c.Ports {
+			if port.HostPort != 0 {
+				reasons = append(reasons, fmt.Sprintf("the container %s uses the host port %d", c.Name, port.HostPort))
+			}
+		}
+		if c.SecurityContext.Capabilities != nil {
+			for _, capability := range c.SecurityContext.Capabilities.Add {
+				if !common.IsStringPresent(allowedCapabilities, strings.TrimPrefix(string(capability), "CAP_")) {
+					reasons = append(reasons, fmt.Sprintf("the container %s adds the capability %s", c.Name, capability))
+				}
+			}
+		}
+		if c.SecurityContext.AllowPrivilegeEscalation == nil {
+			c.SecurityContext.AllowPrivilegeEscalation = pp.boolPtr(false)
+		}
+		if level == restrictedPodSecurityStandard {
+			image, ok := images[c.Image]
+			if !ok {
+				image = irtypes.NewContainer()
+			}
+			reasons = append(reasons, pp.restrict(s, &c, image)...)
+		}
+		s.Containers[ci] = c
+	}
+	return reasons
+}
+
+// restrict applies the settings required by the restricted pod security standard to the container
+func (pp podSecurityPreprocessor) restrict(s *irtypes.Service, c *core.Container, image irtypes.ContainerImage) []string {
+	reasons := []string{}
+	if c.SecurityContext.Capabilities == nil {
+		c.SecurityContext.Capabilities = &core.Capabilities{}
+	}
+	if !pp.isCapabilityPresent(c.SecurityContext.Capabilities.Drop, "ALL") {
+		c.SecurityContext.Capabilities.Drop = append(c.SecurityContext.Capabilities.Drop, "ALL")
+	}
+	switch {
+	case image.UserID == 0:
+		reasons = append(reasons, fmt.Sprintf("the image %s of the container %s runs as root", c.Image, c.Name))
+	case image.UserID > 0:
+		if c.SecurityContext.RunAsUser == nil {
+			userID := int64(image.UserID)
+			c.SecurityContext.RunAsUser = &userID
+		}
+		c.SecurityContext.RunAsNonRoot = pp.boolPtr(true)
+	default:
+		// The user of the image is unknown, so the kubelet verifies that it is not root when the container starts
+		c.SecurityContext.RunAsNonRoot = pp.boolPtr(true)
+	}
+	if c.SecurityContext.RunAsUser != nil && *c.SecurityContext.RunAsUser == 0 {
+		reasons = append(reasons, fmt.Sprintf("the container %s runs as root", c.Name))
+	}
+	if c.SecurityContext.ReadOnlyRootFilesystem != nil {
+		return reasons
+	}
+	writableDirs := []string{tmpDir}
+	for _, dir := range image.AccessedDirs {
+		dir = path.Clean(dir)
+		if dir == "/" || dir == "." {
+			logrus.Debugf("The container %s writes to the root directory. Not making its root filesystem read only.", c.Name)
+			return reasons
+		}
+		writableDirs = common.MergeStringSlices(writableDirs, dir)
+	}
+	key := common.ConfigServicesKey + common.Delim + `"` + s.Name + `"` + common.Delim + "readonlyrootfilesystem"
+	message := fmt.Sprintf("Should the root filesystem of the container %s be read only?", c.Name)
+	hints := []string{fmt.Sprintf("Empty directories will be mounted at %s, which hides the files in the image at those paths", strings.Join(writableDirs, ", "))}
+	if !qaengine.FetchBoolAnswer(key, message, hints, len(writableDirs) == 1) {
+		return reasons
+	}
+	c.SecurityContext.ReadOnlyRootFilesystem = pp.boolPtr(true)
+	for _, dir := range writableDirs {
+		if pp.isMounted(*c, dir) {
+			continue
+		}
+		volumeName := common.MakeStringDNSLabelNameCompliant(c.Name + "-" + strings.ReplaceAll(strings.Trim(dir, "/"), "/", "-"))
+		s.Volumes = append(s.Volumes, core.Volume{Name: volumeName, VolumeSource: core.VolumeSource{EmptyDir: &core.EmptyDirVolumeSource{}}})
+		c.VolumeMounts = append(c.VolumeMounts, core.VolumeMount{Name: volumeName, MountPath: dir})
+	}
+	return reasons
+}
+
+// isRestrictedVolume checks if the volume type is allowed by the restricted pod security standard
+func (pp podSecurityPreprocessor) isRestrictedVolume(volume core.Volume) bool {
+	vs := volume.VolumeSource
+	return vs.ConfigMap != nil || vs.CSI != nil || vs.DownwardAPI != nil || vs.EmptyDir != nil || vs.Ephemeral != nil || vs.PersistentVolumeClaim != nil || vs.Projected != nil || vs.Secret != nil
+}
+
+func (pp podSecurityPreprocessor) isMounted(c core.Container, dir string) bool {
+	for _, volumeMount := range c.VolumeMounts {
+		if path.Clean(volumeMount.MountPath) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+func (pp podSecurityPreprocessor) isCapabilityPresent(capabilities []core.Capability, capability string) bool {
+	for _, c := range capabilities {
+		if strings.EqualFold(string(c), capability) {
+			return true
+		}
+	}
+	return false
+}
+
+func (pp podSecurityPreprocessor) boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,124 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestPodSecurityPreprocessor(t *testing.T) {
+	logrus.SetLevel(logrus.DebugLevel)
+	qaengine.StartEngine(true, 0, true)
+
+	getIR := func(userID int, accessedDirs ...string) irtypes.IR {
+		ir := irtypes.NewIR()
+		svc := irtypes.NewServiceWithName("web")
+		svc.Containers = []core.Container{{Name: "web", Image: "web:latest"}}
+		ir.Services["web"] = svc
+		image := irtypes.NewContainer()
+		image.UserID = userID
+		image.AccessedDirs = accessedDirs
+		ir.ContainerImages["web:latest"] = image
+		return ir
+	}
+
+	t.Run("services are not changed by default", func(t *testing.T) {
+		actual, err := podSecurityPreprocessor{}.preprocess(getIR(0))
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		if sc := actual.Services["web"].Containers[0].SecurityContext; sc != nil {
+			t.Fatalf("Expected no security context. Actual: %+v", sc)
+		}
+	})
+
+	t.Run("baseline", func(t *testing.T) {
+		ir := getIR(0)
+		s := ir.Services["web"]
+		reasons := podSecurityPreprocessor{}.harden(&s, ir.ContainerImages, baselinePodSecurityStandard)
+		if len(reasons) != 0 {
+			t.Fatalf("Expected the service to meet the baseline standard. Actual: %v", reasons)
+		}
+		if s.Containers[0].SecurityContext.RunAsNonRoot != nil {
+			t.Fatalf("Expected the user to not be changed. Actual: %+v", s.Containers[0].SecurityContext)
+		}
+	})
+
+	t.Run("restricted with a non root image", func(t *testing.T) {
+		ir := getIR(1001)
+		s := ir.Services["web"]
+		reasons := podSecurityPreprocessor{}.harden(&s, ir.ContainerImages, restrictedPodSecurityStandard)
+		if len(reasons) != 0 {
+			t.Fatalf("Expected the service to meet the restricted standard. Actual: %v", reasons)
+		}
+		if s.SecurityContext.SeccompProfile == nil || s.SecurityContext.SeccompProfile.Type != core.SeccompProfileTypeRuntimeDefault {
+			t.Fatalf("Expected the RuntimeDefault seccomp profile. Actual: %+v", s.SecurityContext.SeccompProfile)
+		}
+		sc := s.Containers[0].SecurityContext
+		if sc.RunAsUser == nil || *sc.RunAsUser != 1001 || sc.RunAsNonRoot == nil || !*sc.RunAsNonRoot {
+			t.Fatalf("Expected the container to run as the user 1001. Actual: %+v", sc)
+		}
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			t.Fatalf("Expected privilege escalation to be disallowed. Actual: %+v", sc)
+		}
+		if sc.Capabilities == nil || len(sc.Capabilities.Drop) != 1 || sc.Capabilities.Drop[0] != "ALL" {
+			t.Fatalf("Expected all capabilities to be dropped. Actual: %+v", sc.Capabilities)
+		}
+		if sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem {
+			t.Fatalf("Expected a read only root filesystem. Actual: %+v", sc)
+		}
+		if len(s.Volumes) != 1 || s.Volumes[0].EmptyDir == nil || s.Containers[0].VolumeMounts[0].MountPath != tmpDir {
+			t.Fatalf("Expected an empty directory to be mounted at /tmp. Actual: %+v %+v", s.Volumes, s.Containers[0].VolumeMounts)
+		}
+	})
+
+	t.Run("restricted with a root image", func(t *testing.T) {
+		ir := getIR(0)
+		actual, err := podSecurityPreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		// The default standard is baseline, which a root image meets
+		if _, ok := actual.Services["web"].Annotations[podSecurityTODOAnnotation]; ok {
+			t.Fatalf("Expected no violations for the baseline standard. Actual: %+v", actual.Services["web"].Annotations)
+		}
+		s := getIR(0).Services["web"]
+		reasons := podSecurityPreprocessor{}.harden(&s, ir.ContainerImages, restrictedPodSecurityStandard)
+		if len(reasons) != 1 {
+			t.Fatalf("Expected the root user to be reported. Actual: %v", reasons)
+		}
+	})
+
+	t.Run("restricted with a host path volume", func(t *testing.T) {
+		ir := getIR(1001, "/app")
+		s := ir.Services["web"]
+		s.Volumes = []core.Volume{{Name: "docker", VolumeSource: core.VolumeSource{HostPath: &core.HostPathVolumeSource{Path: "/var/run/docker.sock"}}}}
+		reasons := podSecurityPreprocessor{}.harden(&s, ir.ContainerImages, restrictedPodSecurityStandard)
+		if len(reasons) != 1 {
+			t.Fatalf("Expected the host path to be reported. Actual: %v", reasons)
+		}
+		// The working directory of the image would be hidden by an empty directory, so the root filesystem is writable by default
+		if s.Containers[0].SecurityContext.ReadOnlyRootFilesystem != nil {
+			t.Fatalf("Expected the root filesystem to be writable. Actual: %+v", s.Containers[0].SecurityContext)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// probePreprocessor adds liveness, readiness and startup probes to the long running services
+type probePreprocessor struct {
+}
+
+const (
+	defaultProbePeriodSeconds = 10
+	// defaultStartupProbeFailureThreshold gives slow starting services 5 minutes to start before the liveness probe takes over
+	defaultStartupProbeFailureThreshold = 30
+)
+
+func (pp probePreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
+	for sn, s := range ir.Services {
+		if s.OnlyIngress || s.Schedule != "" || s.RestartPolicy == core.RestartPolicyNever || s.RestartPolicy == core.RestartPolicyOnFailure {
+			continue
+		}
+		for ci, c := range s.Containers {
+			if c.LivenessProbe != nil || c.ReadinessProbe != nil {
+				// The health check given in the source is used to check both the liveness and the readiness
+				if c.ReadinessProbe == nil {
+					c.ReadinessProbe = c.LivenessProbe.DeepCopy()
+				} else if c.LivenessProbe == nil {
+					c.LivenessProbe = c.ReadinessProbe.DeepCopy()
+				}
+				s.Containers[ci] = c
+				continue
+			}
+			// Only the main container of the service is probed
+			if ci != 0 {
+				continue
+			}
+			port := pp.getProbePort(s, c)
+			if port == 0 {
+				logrus.Debugf("Unable to find a port to probe the service %s on", sn)
+				continue
+			}
+			detectedPath := strings.TrimSpace(s.Annotations[common.ProbePathAnnotation])
+			keyPrefix := common.ConfigServicesKey + common.Delim + `"` + sn + `"` + common.Delim + "probes" + common.Delim
+			message := fmt.Sprintf("Should liveness and readiness probes be added to the service %s?", sn)
+			hints := []string{fmt.Sprintf("Detected health check : %s", pp.describeProbe(detectedPath, port)), "Kubernetes restarts the containers which fail the liveness probe and stops sending traffic to the ones which fail the readiness probe"}
+			if !qaengine.FetchBoolAnswer(keyPrefix+"enable", message, hints, true) {
+				continue
+			}
+			message = fmt.Sprintf("What is the HTTP path of the health check of the service %s?", sn)
+			hints = []string{"Leave empty to only check that the port accepts connections"}
+			path := strings.TrimSpace(qaengine.FetchStringAnswer(keyPrefix+"path", message, hints, detectedPath))
+			readinessPath := path
+			// The readiness path is only used when the detected health check has been accepted
+			if val, ok := s.Annotations[common.ReadinessProbePathAnnotation]; ok && path != "" && path == detectedPath {
+				readinessPath = strings.TrimSpace(val)
+			}
+			c.LivenessProbe = pp.getProbe(path, port)
+			c.ReadinessProbe = pp.getProbe(readinessPath, port)
+			c.StartupProbe = pp.getProbe(path, port)
+			c.StartupProbe.FailureThreshold = defaultStartupProbeFailureThreshold
+			s.Containers[ci] = c
+		}
+		ir.Services[sn] = s
+	}
+	return ir, nil
+}
+
+// getProbePort returns the port given in the annotations or the first port of the container
+func (pp probePreprocessor) getProbePort(s irtypes.Service, c core.Container) int32 {
+	if val, ok := s.Annotations[common.ProbePortAnnotation]; ok {
+		port, err := strconv.Atoi(strings.TrimSpace(val))
+		if err == nil && port > 0 {
+			return int32(port)
+		}
+		logrus.Errorf("%s is not a valid probe port for the service %s. Ignoring it.", val, s.Name)
+	}
+	if len(c.Ports) == 0 {
+		return 0
+	}
+	return c.Ports[0].ContainerPort
+}
+
+// getProbe returns a HTTP probe if the path is known and a TCP probe otherwise
+func (pp probePreprocessor) getProbe(path string, port int32) *core.Probe {
+	probe := &core.Probe{PeriodSeconds: defaultProbePeriodSeconds}
+	if path == "" {
+		probe.TCPSocket = &core.TCPSocketAction{Port: intstr.FromInt(int(port))}
+		return probe
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	probe.HTTPGet = &core.HTTPGetAction{Path: path, Port: intstr.FromInt(int(port)), Scheme: core.URISchemeHTTP}
+	return probe
+}
+
+func (pp probePreprocessor) describeProbe(path string, port int32) string {
+	if path == "" {
+		return fmt.Sprintf("TCP connection to port %d", port)
+	}
+	return fmt.Sprintf("HTTP GET %s on port %d", path, port)
+}
@@ -0,0 +1,111 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestProbePreprocessor(t *testing.T) {
+	logrus.SetLevel(logrus.DebugLevel)
+	qaengine.StartEngine(true, 0, true)
+
+	getService := func(name string) irtypes.Service {
+		svc := irtypes.NewServiceWithName(name)
+		svc.Containers = []core.Container{{Name: name, Image: name, Ports: []core.ContainerPort{{ContainerPort: 8080}}}}
+		return svc
+	}
+
+	t.Run("http probes from annotations", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		svc := getService("api")
+		svc.Annotations = map[string]string{
+			common.ProbePathAnnotation:          "/actuator/health/liveness",
+			common.ReadinessProbePathAnnotation: "/actuator/health/readiness",
+			common.ProbePortAnnotation:          "8081",
+		}
+		ir.Services["api"] = svc
+		actual, err := probePreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		c := actual.Services["api"].Containers[0]
+		if c.LivenessProbe == nil || c.LivenessProbe.HTTPGet == nil || c.LivenessProbe.HTTPGet.Path != "/actuator/health/liveness" || c.LivenessProbe.HTTPGet.Port.IntValue() != 8081 {
+			t.Fatalf("Expected a liveness probe on /actuator/health/liveness port 8081. Actual: %+v", c.LivenessProbe)
+		}
+		if c.ReadinessProbe == nil || c.ReadinessProbe.HTTPGet == nil || c.ReadinessProbe.HTTPGet.Path != "/actuator/health/readiness" {
+			t.Fatalf("Expected a readiness probe on /actuator/health/readiness. Actual: %+v", c.ReadinessProbe)
+		}
+		if c.StartupProbe == nil || c.StartupProbe.FailureThreshold != defaultStartupProbeFailureThreshold {
+			t.Fatalf("Expected a startup probe. Actual: %+v", c.StartupProbe)
+		}
+	})
+
+	t.Run("tcp probes when the health endpoint is unknown", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		ir.Services["web"] = getService("web")
+		actual, err := probePreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		c := actual.Services["web"].Containers[0]
+		if c.LivenessProbe == nil || c.LivenessProbe.TCPSocket == nil || c.LivenessProbe.TCPSocket.Port.IntValue() != 8080 {
+			t.Fatalf("Expected a tcp liveness probe on port 8080. Actual: %+v", c.LivenessProbe)
+		}
+		if c.ReadinessProbe == nil || c.ReadinessProbe.TCPSocket == nil {
+			t.Fatalf("Expected a tcp readiness probe. Actual: %+v", c.ReadinessProbe)
+		}
+	})
+
+	t.Run("health check from the source is used for readiness", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		svc := getService("db")
+		svc.Containers[0].LivenessProbe = &core.Probe{Handler: core.Handler{Exec: &core.ExecAction{Command: []string{"pg_isready"}}}}
+		ir.Services["db"] = svc
+		actual, err := probePreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		c := actual.Services["db"].Containers[0]
+		if c.ReadinessProbe == nil || c.ReadinessProbe.Exec == nil || c.ReadinessProbe.Exec.Command[0] != "pg_isready" {
+			t.Fatalf("Expected the readiness probe to run pg_isready. Actual: %+v", c.ReadinessProbe)
+		}
+		if c.StartupProbe != nil {
+			t.Fatalf("Expected no startup probe. Actual: %+v", c.StartupProbe)
+		}
+	})
+
+	t.Run("jobs are not probed", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		svc := getService("migrate")
+		svc.RestartPolicy = core.RestartPolicyOnFailure
+		ir.Services["migrate"] = svc
+		actual, err := probePreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		if c := actual.Services["migrate"].Containers[0]; c.LivenessProbe != nil || c.ReadinessProbe != nil {
+			t.Fatalf("Expected no probes. Actual: %+v", c)
+		}
+	})
+}
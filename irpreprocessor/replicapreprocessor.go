@@ -41,6 +41,10 @@ func (ep replicaPreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
 		replicaCount = minReplicas
 	}
 	for k, scObj := range ir.Services {
+		// Stateful services are not always safe to scale, so their replica count is left as is
+		if scObj.StatefulSet {
+			continue
+		}
 		if scObj.Replicas < replicaCount {
 			scObj.Replicas = replicaCount
 		}
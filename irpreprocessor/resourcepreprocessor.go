@@ -0,0 +1,194 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// resourcePreprocessor sets the resource requests and limits of the containers
+type resourcePreprocessor struct {
+}
+
+// resourcePreset is a set of resource requests and limits to choose from
+type resourcePreset struct {
+	name          string
+	cpuRequest    string
+	cpuLimit      string
+	memoryRequest string
+	memoryLimit   string
+}
+
+const (
+	noResourcePreset = "none"
+	// javaHeapPercentage is the percentage of the container memory which is used by the JVM heap. The rest is used by the JVM itself.
+	javaHeapPercentage = 75
+)
+
+var (
+	// resourcePresets are ordered by size
+	resourcePresets = []resourcePreset{
+		{name: "small", cpuRequest: "100m", cpuLimit: "500m", memoryRequest: "128Mi", memoryLimit: "256Mi"},
+		{name: "medium", cpuRequest: "250m", cpuLimit: "1", memoryRequest: "512Mi", memoryLimit: "1Gi"},
+		{name: "large", cpuRequest: "500m", cpuLimit: "2", memoryRequest: "1Gi", memoryLimit: "2Gi"},
+	}
+	javaOptionsEnvNames = []string{"JAVA_OPTS", "JAVA_TOOL_OPTIONS", "JDK_JAVA_OPTIONS", "_JAVA_OPTIONS", "CATALINA_OPTS"}
+)
+
+func (rp resourcePreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
+	for sn, s := range ir.Services {
+		if s.OnlyIngress {
+			continue
+		}
+		detectedMemory := map[string]resource.Quantity{}
+		maxDetectedMemory := resource.Quantity{}
+		isSized := true
+		for _, c := range s.Containers {
+			if memory, ok := rp.getDetectedMemory(s, c, ir.ContainerImages[c.Image]); ok {
+				detectedMemory[c.Name] = memory
+				if memory.Cmp(maxDetectedMemory) > 0 {
+					maxDetectedMemory = memory
+				}
+			}
+			for _, resourceName := range []core.ResourceName{core.ResourceCPU, core.ResourceMemory} {
+				_, isRequested := c.Resources.Requests[resourceName]
+				_, isLimited := c.Resources.Limits[resourceName]
+				isSized = isSized && isRequested && isLimited
+			}
+		}
+		if isSized || len(s.Containers) == 0 {
+			continue
+		}
+		preset, ok := rp.getResourcePreset(sn, maxDetectedMemory)
+		if !ok {
+			continue
+		}
+		for ci, c := range s.Containers {
+			if c.Resources.Requests == nil {
+				c.Resources.Requests = core.ResourceList{}
+			}
+			if c.Resources.Limits == nil {
+				c.Resources.Limits = core.ResourceList{}
+			}
+			if memory, ok := detectedMemory[c.Name]; ok {
+				// The memory found in the source is both requested and used as limit, so that the container is not killed before it reaches it
+				if _, ok := c.Resources.Limits[core.ResourceMemory]; !ok {
+					c.Resources.Limits[core.ResourceMemory] = memory
+				}
+				if _, ok := c.Resources.Requests[core.ResourceMemory]; !ok {
+					c.Resources.Requests[core.ResourceMemory] = memory
+				}
+			}
+			rp.setDefault(c.Resources.Requests, core.ResourceCPU, preset.cpuRequest)
+			rp.setDefault(c.Resources.Limits, core.ResourceCPU, preset.cpuLimit)
+			rp.setDefault(c.Resources.Requests, core.ResourceMemory, preset.memoryRequest)
+			rp.setDefault(c.Resources.Limits, core.ResourceMemory, preset.memoryLimit)
+			for _, resourceName := range []core.ResourceName{core.ResourceCPU, core.ResourceMemory} {
+				request, limit := c.Resources.Requests[resourceName], c.Resources.Limits[resourceName]
+				if request.Cmp(limit) > 0 {
+					logrus.Debugf("The %s request of the container %s in the service %s is more than its limit. Using the limit %s.", resourceName, c.Name, sn, limit.String())
+					c.Resources.Requests[resourceName] = limit
+				}
+			}
+			s.Containers[ci] = c
+		}
+		ir.Services[sn] = s
+	}
+	return ir, nil
+}
+
+// getResourcePreset asks for the preset to use for the service. The default is the smallest preset which fits the memory found in the source.
+func (rp resourcePreprocessor) getResourcePreset(serviceName string, detectedMemory resource.Quantity) (resourcePreset, bool) {
+	def := resourcePresets[0].name
+	options := []string{}
+	hints := []string{}
+	for _, preset := range resourcePresets {
+		options = append(options, preset.name)
+		hints = append(hints, fmt.Sprintf("%s : cpu %s/%s memory %s/%s (request/limit)", preset.name, preset.cpuRequest, preset.cpuLimit, preset.memoryRequest, preset.memoryLimit))
+	}
+	options = append(options, noResourcePreset)
+	if !detectedMemory.IsZero() {
+		hints = append(hints, fmt.Sprintf("The service uses %s of memory, which is used instead of the memory of the preset", detectedMemory.String()))
+		def = resourcePresets[len(resourcePresets)-1].name
+		for _, preset := range resourcePresets {
+			if detectedMemory.Cmp(resource.MustParse(preset.memoryLimit)) <= 0 {
+				def = preset.name
+				break
+			}
+		}
+	}
+	key := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim + "resources"
+	message := fmt.Sprintf("What size of resources should be requested for the service %s?", serviceName)
+	answer := qaengine.FetchSelectAnswer(key, message, hints, def, options)
+	for _, preset := range resourcePresets {
+		if preset.name == answer {
+			return preset, true
+		}
+	}
+	return resourcePreset{}, false
+}
+
+// getDetectedMemory returns the memory which the source says the container needs
+func (rp resourcePreprocessor) getDetectedMemory(s irtypes.Service, c core.Container, image irtypes.ContainerImage) (resource.Quantity, bool) {
+	if memory, ok := c.Resources.Limits[core.ResourceMemory]; ok {
+		return memory, true
+	}
+	heapSize := int64(0)
+	if val, ok := s.Annotations[common.JavaMaxHeapAnnotation]; ok {
+		heap, err := resource.ParseQuantity(strings.TrimSpace(val))
+		if err != nil {
+			logrus.Errorf("%s is not a valid maximum heap size for the service %s : %s", val, s.Name, err)
+		} else {
+			heapSize = heap.Value()
+		}
+	}
+	javaOptions := []string{}
+	for _, env := range c.Env {
+		if common.IsStringPresent(javaOptionsEnvNames, env.Name) {
+			javaOptions = append(javaOptions, env.Value)
+		}
+	}
+	for _, env := range image.Env {
+		if nameValue := strings.SplitN(env, "=", 2); len(nameValue) == 2 && common.IsStringPresent(javaOptionsEnvNames, nameValue[0]) {
+			javaOptions = append(javaOptions, nameValue[1])
+		}
+	}
+	javaOptions = append(javaOptions, c.Command...)
+	javaOptions = append(javaOptions, c.Args...)
+	if size, ok := common.GetJavaMaxHeapSize(strings.Join(javaOptions, " ")); ok {
+		heapSize = size
+	}
+	if heapSize == 0 {
+		return resource.Quantity{}, false
+	}
+	memoryMi := (heapSize*100/javaHeapPercentage + 1024*1024 - 1) / (1024 * 1024)
+	return *resource.NewQuantity(memoryMi*1024*1024, resource.BinarySI), true
+}
+
+func (rp resourcePreprocessor) setDefault(resources core.ResourceList, resourceName core.ResourceName, quantity string) {
+	if _, ok := resources[resourceName]; !ok {
+		resources[resourceName] = resource.MustParse(quantity)
+	}
+}
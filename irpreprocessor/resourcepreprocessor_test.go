@@ -0,0 +1,123 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestResourcePreprocessor(t *testing.T) {
+	logrus.SetLevel(logrus.DebugLevel)
+	qaengine.StartEngine(true, 0, true)
+
+	getService := func(name string) irtypes.Service {
+		svc := irtypes.NewServiceWithName(name)
+		svc.Containers = []core.Container{{Name: name, Image: name}}
+		return svc
+	}
+	expectQuantity := func(t *testing.T, resources core.ResourceList, resourceName core.ResourceName, want string) {
+		t.Helper()
+		actual, ok := resources[resourceName]
+		if !ok || actual.Cmp(resource.MustParse(want)) != 0 {
+			t.Fatalf("Expected %s to be %s. Actual: %+v", resourceName, want, resources)
+		}
+	}
+
+	t.Run("small preset when nothing is known", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		ir.Services["web"] = getService("web")
+		actual, err := resourcePreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		c := actual.Services["web"].Containers[0]
+		expectQuantity(t, c.Resources.Requests, core.ResourceCPU, "100m")
+		expectQuantity(t, c.Resources.Limits, core.ResourceCPU, "500m")
+		expectQuantity(t, c.Resources.Requests, core.ResourceMemory, "128Mi")
+		expectQuantity(t, c.Resources.Limits, core.ResourceMemory, "256Mi")
+	})
+
+	t.Run("memory from the java options", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		svc := getService("api")
+		svc.Containers[0].Env = []core.EnvVar{{Name: "JAVA_OPTS", Value: "-Xms256m -Xmx768m"}}
+		ir.Services["api"] = svc
+		actual, err := resourcePreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		c := actual.Services["api"].Containers[0]
+		expectQuantity(t, c.Resources.Limits, core.ResourceMemory, "1024Mi")
+		expectQuantity(t, c.Resources.Requests, core.ResourceMemory, "1024Mi")
+		// The medium preset is the smallest which fits 1Gi of memory
+		expectQuantity(t, c.Resources.Limits, core.ResourceCPU, "1")
+	})
+
+	t.Run("memory from the annotation and the image", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		svc := getService("app")
+		svc.Annotations = map[string]string{common.JavaMaxHeapAnnotation: "3Gi"}
+		ir.Services["app"] = svc
+		image := irtypes.NewContainer()
+		image.Env = []string{"PATH=/usr/bin", "JAVA_TOOL_OPTIONS=-Xmx192m"}
+		ir.ContainerImages["app"] = image
+		actual, err := resourcePreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		c := actual.Services["app"].Containers[0]
+		expectQuantity(t, c.Resources.Limits, core.ResourceMemory, "256Mi")
+	})
+
+	t.Run("limits from the source are kept", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		svc := getService("cfapp")
+		svc.Containers[0].Resources.Limits = core.ResourceList{core.ResourceMemory: resource.MustParse("64Mi")}
+		ir.Services["cfapp"] = svc
+		actual, err := resourcePreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		c := actual.Services["cfapp"].Containers[0]
+		expectQuantity(t, c.Resources.Limits, core.ResourceMemory, "64Mi")
+		expectQuantity(t, c.Resources.Requests, core.ResourceMemory, "64Mi")
+		expectQuantity(t, c.Resources.Requests, core.ResourceCPU, "100m")
+	})
+}
+
+func TestGetJavaMaxHeapSize(t *testing.T) {
+	options := map[string]int64{
+		"-Xmx512m":              512 * 1024 * 1024,
+		"-Xmx1G -XX:+UseG1GC":   1024 * 1024 * 1024,
+		"-Xmx256m -Xmx2g":       2 * 1024 * 1024 * 1024,
+		"-Xmx1048576":           1048576,
+		"-Xms512m -Dfoo=-Xmxyz": 0,
+	}
+	for option, want := range options {
+		actual, _ := common.GetJavaMaxHeapSize(option)
+		if actual != want {
+			t.Errorf("Java options %q : expected %d, actual %d", option, want, actual)
+		}
+	}
+}
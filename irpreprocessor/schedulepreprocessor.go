@@ -0,0 +1,92 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// schedulePreprocessor sets the cron schedule of the services which do periodic work
+type schedulePreprocessor struct {
+}
+
+var (
+	scheduleMacros     = []string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+	scheduleFieldRegex = regexp.MustCompile(`^[0-9A-Za-z*?/,\-]+$`)
+)
+
+func (sp schedulePreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
+	for sn, s := range ir.Services {
+		if s.Daemon {
+			continue
+		}
+		// The services marked using the schedule annotation or label, and the batch services which are run until they complete, can be scheduled
+		val, ok := s.Annotations[common.ScheduleAnnotation]
+		if !ok {
+			val, ok = s.Labels[common.ScheduleAnnotation]
+		}
+		if ok {
+			s.Schedule = strings.TrimSpace(val)
+		}
+		isBatch := s.RestartPolicy == core.RestartPolicyNever || s.RestartPolicy == core.RestartPolicyOnFailure
+		if s.Schedule == "" && (ok || isBatch) {
+			key := common.ConfigServicesKey + common.Delim + `"` + sn + `"` + common.Delim + "schedule"
+			message := fmt.Sprintf("What schedule should the service %s be run on?", sn)
+			hints := []string{`Use the cron format. For example "*/10 * * * *" runs every 10 minutes`}
+			if isBatch {
+				hints = append(hints, "Leave empty to run the service once as a Job")
+			}
+			s.Schedule = strings.TrimSpace(qaengine.FetchStringAnswer(key, message, hints, ""))
+		}
+		if s.Schedule == "" {
+			continue
+		}
+		if !isValidSchedule(s.Schedule) {
+			logrus.Errorf("The schedule %q of the service %s is not a valid cron schedule. Ignoring it.", s.Schedule, sn)
+			s.Schedule = ""
+		} else if s.RestartPolicy != core.RestartPolicyNever {
+			s.RestartPolicy = core.RestartPolicyOnFailure
+		}
+		ir.Services[sn] = s
+	}
+	return ir, nil
+}
+
+// isValidSchedule checks if the schedule is either a cron macro or has the five cron fields
+func isValidSchedule(schedule string) bool {
+	if strings.HasPrefix(schedule, "@") {
+		return common.IsStringPresent(scheduleMacros, schedule)
+	}
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return false
+	}
+	for _, field := range fields {
+		if !scheduleFieldRegex.MatchString(field) {
+			return false
+		}
+	}
+	return true
+}
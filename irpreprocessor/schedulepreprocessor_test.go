@@ -0,0 +1,140 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestSchedulePreprocessor(t *testing.T) {
+	logrus.SetLevel(logrus.DebugLevel)
+	qaengine.StartEngine(true, 0, true)
+
+	t.Run("schedule from annotation", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		svc := irtypes.NewServiceWithName("worker")
+		svc.Annotations = map[string]string{common.ScheduleAnnotation: " */5 * * * * "}
+		ir.Services["worker"] = svc
+		actual, err := schedulePreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		if actual.Services["worker"].Schedule != "*/5 * * * *" {
+			t.Fatalf("Expected the schedule */5 * * * *. Actual: %q", actual.Services["worker"].Schedule)
+		}
+		if actual.Services["worker"].RestartPolicy != core.RestartPolicyOnFailure {
+			t.Fatalf("Expected the restart policy OnFailure. Actual: %q", actual.Services["worker"].RestartPolicy)
+		}
+	})
+
+	t.Run("schedule from label", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		svc := irtypes.NewServiceWithName("worker")
+		svc.Labels = map[string]string{common.ScheduleAnnotation: "@hourly"}
+		ir.Services["worker"] = svc
+		actual, err := schedulePreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		if actual.Services["worker"].Schedule != "@hourly" {
+			t.Fatalf("Expected the schedule @hourly. Actual: %q", actual.Services["worker"].Schedule)
+		}
+	})
+
+	t.Run("batch service is scheduled using the answer", func(t *testing.T) {
+		qaengine.SetupConfigFile("", []string{`move2kube.services."report".schedule="@daily"`}, nil, nil)
+		ir := irtypes.NewIR()
+		svc := irtypes.NewServiceWithName("report")
+		svc.RestartPolicy = core.RestartPolicyOnFailure
+		ir.Services["report"] = svc
+		actual, err := schedulePreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		if actual.Services["report"].Schedule != "@daily" {
+			t.Fatalf("Expected the schedule @daily. Actual: %q", actual.Services["report"].Schedule)
+		}
+	})
+
+	t.Run("long running service is not scheduled", func(t *testing.T) {
+		qaengine.SetupConfigFile("", []string{`move2kube.services."api".schedule="@daily"`}, nil, nil)
+		ir := irtypes.NewIR()
+		ir.Services["api"] = irtypes.NewServiceWithName("api")
+		actual, err := schedulePreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		if actual.Services["api"].Schedule != "" {
+			t.Fatalf("Expected no schedule. Actual: %q", actual.Services["api"].Schedule)
+		}
+	})
+
+	t.Run("invalid schedule is ignored", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		svc := irtypes.NewServiceWithName("worker")
+		svc.Schedule = "every five minutes"
+		ir.Services["worker"] = svc
+		actual, err := schedulePreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		if actual.Services["worker"].Schedule != "" {
+			t.Fatalf("Expected the invalid schedule to be removed. Actual: %q", actual.Services["worker"].Schedule)
+		}
+	})
+
+	t.Run("batch service without a schedule stays a job", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		svc := irtypes.NewServiceWithName("migrate")
+		svc.RestartPolicy = core.RestartPolicyNever
+		ir.Services["migrate"] = svc
+		actual, err := schedulePreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		if actual.Services["migrate"].Schedule != "" {
+			t.Fatalf("Expected no schedule. Actual: %q", actual.Services["migrate"].Schedule)
+		}
+		if actual.Services["migrate"].RestartPolicy != core.RestartPolicyNever {
+			t.Fatalf("Expected the restart policy Never. Actual: %q", actual.Services["migrate"].RestartPolicy)
+		}
+	})
+}
+
+func TestIsValidSchedule(t *testing.T) {
+	schedules := map[string]bool{
+		"*/10 * * * *":      true,
+		"0 2 * * MON-FRI":   true,
+		"0,30 8-18 * * 1":   true,
+		"@daily":            true,
+		"@every 5m":         false,
+		"* * * *":           false,
+		"0 2 * * * *":       false,
+		"0 2 * * $(reboot)": false,
+	}
+	for schedule, want := range schedules {
+		if actual := isValidSchedule(schedule); actual != want {
+			t.Errorf("Schedule %q : expected %t, actual %t", schedule, want, actual)
+		}
+	}
+}
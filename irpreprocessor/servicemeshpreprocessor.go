@@ -0,0 +1,77 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+)
+
+// serviceMeshPreprocessor adds the services to a service mesh
+type serviceMeshPreprocessor struct {
+}
+
+const (
+	noServiceMesh      = "none"
+	istioServiceMesh   = "istio"
+	linkerdServiceMesh = "linkerd"
+)
+
+func (sp serviceMeshPreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
+	if len(ir.Services) == 0 {
+		return ir, nil
+	}
+	hints := []string{
+		"istio : an istio sidecar is injected in the pods. With the restricted pod security standard, the istio CNI plugin is required",
+		"linkerd : a linkerd proxy is injected in the pods",
+	}
+	mesh := qaengine.FetchSelectAnswer(common.ConfigServiceMeshKey, "Which service mesh should the services be added to?", hints, noServiceMesh, []string{noServiceMesh, istioServiceMesh, linkerdServiceMesh})
+	var annotation, value string
+	switch mesh {
+	case istioServiceMesh:
+		annotation, value = common.IstioSidecarInjectAnnotation, common.AnnotationLabelValue
+	case linkerdServiceMesh:
+		annotation, value = common.LinkerdInjectAnnotation, "enabled"
+	default:
+		return ir, nil
+	}
+	for sn, s := range ir.Services {
+		if len(s.Containers) == 0 || s.OnlyIngress {
+			continue
+		}
+		// The proxies only run on linux nodes
+		if _, ok := s.Annotations[common.WindowsAnnotation]; ok {
+			continue
+		}
+		// The proxy keeps running after the job is complete
+		if s.Schedule != "" {
+			logrus.Debugf("The service %s runs on a schedule. Not adding it to the %s service mesh", sn, mesh)
+			continue
+		}
+		if _, ok := s.Annotations[annotation]; ok {
+			continue
+		}
+		if s.Annotations == nil {
+			s.Annotations = map[string]string{}
+		}
+		s.Annotations[annotation] = value
+		ir.Services[sn] = s
+	}
+	return ir, nil
+}
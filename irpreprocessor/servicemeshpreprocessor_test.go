@@ -0,0 +1,154 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestServiceMeshPreprocessor(t *testing.T) {
+	logrus.SetLevel(logrus.DebugLevel)
+	qaengine.StartEngine(true, 0, true)
+	defer qaengine.SetupConfigFile("", []string{common.ConfigServiceMeshKey + `="` + noServiceMesh + `"`}, nil, nil)
+
+	getService := func(name string, dependencies ...string) irtypes.Service {
+		svc := irtypes.NewServiceWithName(name)
+		svc.Containers = []core.Container{{Name: name, Image: name}}
+		svc.Dependencies = dependencies
+		return svc
+	}
+	// getComposeIR returns the IR of a compose file in which web depends_on orders and links to db:database
+	getComposeIR := func() irtypes.IR {
+		ir := irtypes.NewIR()
+		ir.Services["web"] = getService("web", "orders", "db")
+		ir.Services["orders"] = getService("orders")
+		ir.Services["db"] = getService("db")
+		return ir
+	}
+	// getEurekaIR returns the IR of the compose file merged with the IR artifact which records the feign clients of orders
+	getEurekaIR := func() irtypes.IR {
+		ir := getComposeIR()
+		eurekaIR := irtypes.NewIR()
+		eurekaIR.Services["orders"] = irtypes.Service{Name: "orders", Dependencies: []string{"payments"}}
+		eurekaIR.Services["payments"] = getService("payments")
+		ir.Merge(eurekaIR)
+		return ir
+	}
+
+	testcases := []struct {
+		name                string
+		mesh                string
+		ir                  irtypes.IR
+		want                map[string]string
+		wantDependencies    map[string][]string
+		wantWithoutSidecars []string
+	}{
+		{
+			name: "no service mesh",
+			mesh: noServiceMesh,
+			ir:   getComposeIR(),
+			want: map[string]string{},
+		},
+		{
+			name:             "istio with the depends_on and links edges of compose",
+			mesh:             istioServiceMesh,
+			ir:               getComposeIR(),
+			want:             map[string]string{common.IstioSidecarInjectAnnotation: common.AnnotationLabelValue},
+			wantDependencies: map[string][]string{"web": {"orders", "db"}, "orders": nil, "db": nil},
+		},
+		{
+			name:             "linkerd with the feign client edges of eureka",
+			mesh:             linkerdServiceMesh,
+			ir:               getEurekaIR(),
+			want:             map[string]string{common.LinkerdInjectAnnotation: "enabled"},
+			wantDependencies: map[string][]string{"web": {"orders", "db"}, "orders": {"payments"}, "payments": nil},
+		},
+		{
+			name: "windows, scheduled and ingress only services are not added",
+			mesh: istioServiceMesh,
+			ir: func() irtypes.IR {
+				ir := getComposeIR()
+				web := ir.Services["web"]
+				web.Annotations = map[string]string{common.WindowsAnnotation: common.AnnotationLabelValue}
+				ir.Services["web"] = web
+				orders := ir.Services["orders"]
+				orders.Schedule = "@daily"
+				ir.Services["orders"] = orders
+				db := ir.Services["db"]
+				db.OnlyIngress = true
+				ir.Services["db"] = db
+				return ir
+			}(),
+			want:                map[string]string{common.IstioSidecarInjectAnnotation: common.AnnotationLabelValue},
+			wantWithoutSidecars: []string{"web", "orders", "db"},
+		},
+		{
+			name: "annotation given in the source is kept",
+			mesh: istioServiceMesh,
+			ir: func() irtypes.IR {
+				ir := getComposeIR()
+				db := ir.Services["db"]
+				db.Annotations = map[string]string{common.IstioSidecarInjectAnnotation: "false"}
+				ir.Services["db"] = db
+				return ir
+			}(),
+			want:                map[string]string{common.IstioSidecarInjectAnnotation: common.AnnotationLabelValue},
+			wantWithoutSidecars: []string{"db"},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			qaengine.SetupConfigFile("", []string{common.ConfigServiceMeshKey + `="` + tc.mesh + `"`}, nil, nil)
+			original := irtypes.NewIR()
+			for sn, s := range tc.ir.Services {
+				original.Services[sn] = s
+			}
+			actual, err := serviceMeshPreprocessor{}.preprocess(tc.ir)
+			if err != nil {
+				t.Fatal("Failed to preprocess the IR. Error:", err)
+			}
+			for sn, s := range actual.Services {
+				if common.IsStringPresent(tc.wantWithoutSidecars, sn) {
+					if !cmp.Equal(s.Annotations, original.Services[sn].Annotations) {
+						t.Fatalf("Expected the annotations of the service %s to be unchanged. Differences:\n%s", sn, cmp.Diff(original.Services[sn].Annotations, s.Annotations))
+					}
+					continue
+				}
+				for k, v := range tc.want {
+					if s.Annotations[k] != v {
+						t.Fatalf("Expected the service %s to have the annotation %s=%s. Actual: %+v", sn, k, v, s.Annotations)
+					}
+				}
+				if len(tc.want) == 0 && len(s.Annotations) != 0 {
+					t.Fatalf("Expected the service %s to have no annotations. Actual: %+v", sn, s.Annotations)
+				}
+			}
+			for sn, dependencies := range tc.wantDependencies {
+				if !cmp.Equal(actual.Services[sn].Dependencies, dependencies) {
+					t.Fatalf("Expected the dependencies of the service %s to be kept. Differences:\n%s", sn, cmp.Diff(dependencies, actual.Services[sn].Dependencies))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// statefulSetPreprocessor marks the services which store state as stateful sets
+type statefulSetPreprocessor struct {
+}
+
+// statefulImages are the prefixes of the names of well known images which store state
+var statefulImages = []string{"postgres", "postgis", "mysql", "mariadb", "mongo", "redis", "kafka", "cp-kafka", "zookeeper", "cp-zookeeper", "elasticsearch", "opensearch", "rabbitmq", "cassandra", "etcd", "minio", "couchdb", "couchbase", "neo4j", "influxdb", "nats", "consul"}
+
+func (sp statefulSetPreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
+	for sn, s := range ir.Services {
+		if s.Daemon || s.RestartPolicy == core.RestartPolicyNever || s.RestartPolicy == core.RestartPolicyOnFailure {
+			continue
+		}
+		if val, ok := s.Annotations[common.StatefulSetSelector]; ok {
+			s.StatefulSet = val == common.AnnotationLabelValue
+		} else if !s.StatefulSet {
+			claims := getClaimNames(s)
+			if len(claims) == 0 {
+				continue
+			}
+			isStatefulImage := false
+			for _, c := range s.Containers {
+				if isStatefulImageName(c.Image) {
+					isStatefulImage = true
+					break
+				}
+			}
+			key := common.ConfigServicesKey + common.Delim + `"` + sn + `"` + common.Delim + "statefulset"
+			message := fmt.Sprintf("Should the service %s be deployed as a StatefulSet?", sn)
+			hints := []string{fmt.Sprintf("The service stores data in the volumes %s", strings.Join(claims, ", ")), "A StatefulSet gets a stable network identity and a persistent volume per replica"}
+			s.StatefulSet = qaengine.FetchBoolAnswer(key, message, hints, isStatefulImage)
+		}
+		if s.StatefulSet && s.Replicas == 0 {
+			s.Replicas = 1
+		}
+		logrus.Debugf("Service %s stateful : %t", sn, s.StatefulSet)
+		ir.Services[sn] = s
+	}
+	return ir, nil
+}
+
+// getClaimNames returns the persistent volume claims used by the service
+func getClaimNames(s irtypes.Service) []string {
+	claims := []string{}
+	for _, v := range s.Volumes {
+		if v.PersistentVolumeClaim != nil {
+			claims = append(claims, v.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	return claims
+}
+
+// isStatefulImageName checks if the image is a well known image which stores state
+func isStatefulImageName(image string) bool {
+	name := image
+	if i := strings.LastIndex(name, "/"); i != -1 {
+		name = name[i+1:]
+	}
+	if i := strings.IndexAny(name, ":@"); i != -1 {
+		name = name[:i]
+	}
+	for _, prefix := range statefulImages {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func getServiceWithClaim(name, image, claim string) irtypes.Service {
+	svc := irtypes.NewServiceWithName(name)
+	svc.Containers = []core.Container{{Name: name, Image: image, VolumeMounts: []core.VolumeMount{{Name: claim, MountPath: "/data"}}}}
+	svc.Volumes = []core.Volume{{Name: claim, VolumeSource: core.VolumeSource{PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{ClaimName: claim}}}}
+	return svc
+}
+
+func TestStatefulSetPreprocessor(t *testing.T) {
+	logrus.SetLevel(logrus.DebugLevel)
+	qaengine.StartEngine(true, 0, true)
+
+	t.Run("well known stateful image with a persistent volume", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		ir.Services["db"] = getServiceWithClaim("db", "docker.io/library/postgres:13", "dbdata")
+		actual, err := statefulSetPreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		if !actual.Services["db"].StatefulSet {
+			t.Fatal("The service should have been marked as stateful")
+		}
+		if actual.Services["db"].Replicas != 1 {
+			t.Fatalf("Expected the stateful service to have 1 replica. Actual: %d", actual.Services["db"].Replicas)
+		}
+	})
+
+	t.Run("well known stateful image without a persistent volume", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		svc := irtypes.NewServiceWithName("cache")
+		svc.Containers = []core.Container{{Name: "cache", Image: "redis"}}
+		ir.Services["cache"] = svc
+		actual, err := statefulSetPreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		if actual.Services["cache"].StatefulSet {
+			t.Fatal("The service should not have been marked as stateful")
+		}
+	})
+
+	t.Run("unknown image with a persistent volume", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		ir.Services["web"] = getServiceWithClaim("web", "nginx:latest", "webdata")
+		actual, err := statefulSetPreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		if actual.Services["web"].StatefulSet {
+			t.Fatal("The service should not have been marked as stateful")
+		}
+	})
+
+	t.Run("annotations override the heuristics", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		db := getServiceWithClaim("db", "mysql", "dbdata")
+		db.Annotations = map[string]string{common.StatefulSetSelector: "false"}
+		web := getServiceWithClaim("web", "nginx", "webdata")
+		web.Annotations = map[string]string{common.StatefulSetSelector: common.AnnotationLabelValue}
+		ir.Services["db"] = db
+		ir.Services["web"] = web
+		actual, err := statefulSetPreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		if actual.Services["db"].StatefulSet {
+			t.Fatal("The service db should not have been marked as stateful")
+		}
+		if !actual.Services["web"].StatefulSet {
+			t.Fatal("The service web should have been marked as stateful")
+		}
+	})
+
+	t.Run("daemons are never stateful", func(t *testing.T) {
+		ir := irtypes.NewIR()
+		svc := getServiceWithClaim("db", "mongo", "dbdata")
+		svc.Daemon = true
+		ir.Services["db"] = svc
+		actual, err := statefulSetPreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatal("Failed to preprocess the IR. Error:", err)
+		}
+		if actual.Services["db"].StatefulSet {
+			t.Fatal("The service should not have been marked as stateful")
+		}
+	})
+}
+
+func TestIsStatefulImageName(t *testing.T) {
+	images := map[string]bool{
+		"postgres":                         true,
+		"bitnami/postgresql:11":            true,
+		"quay.io/strimzi/kafka@sha256:abc": true,
+		"confluentinc/cp-zookeeper:7.0.0":  true,
+		"nginx":                            false,
+		"myregistry:5000/myapp:redis":      false,
+	}
+	for image, want := range images {
+		if actual := isStatefulImageName(image); actual != want {
+			t.Errorf("Image %s : expected %t, actual %t", image, want, actual)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+)
+
+// tlsPreprocessor configures TLS for the hosts the services are exposed on
+type tlsPreprocessor struct {
+}
+
+const (
+	noTLSMode          = "none"
+	secretTLSMode      = "secret"
+	certManagerTLSMode = "cert-manager"
+	passthroughTLSMode = "passthrough"
+	// defaultHostLabel identifies the host without a prefix in the QA keys
+	defaultHostLabel         = "default"
+	defaultCertManagerIssuer = "letsencrypt"
+)
+
+func (tp tlsPreprocessor) preprocess(ir irtypes.IR) (irtypes.IR, error) {
+	hostPrefixes := []string{}
+	for _, s := range ir.Services {
+		if !s.HasValidAnnotation(common.ExposeSelector) && !s.OnlyIngress {
+			continue
+		}
+		for _, pf := range s.ServiceToPodPortForwardings {
+			hostPrefix, relPath, serviceType := pf.ParseServiceRelPath()
+			if relPath == "" || serviceType == "" {
+				continue
+			}
+			hostPrefixes = common.MergeStringSlices(hostPrefixes, hostPrefix)
+		}
+	}
+	sort.Strings(hostPrefixes)
+	if ir.TLS == nil {
+		ir.TLS = map[string]irtypes.TLSConfig{}
+	}
+	for _, hostPrefix := range hostPrefixes {
+		if tls, ok := tp.getTLSConfig(ir.Name, hostPrefix); ok {
+			ir.TLS[hostPrefix] = tls
+		}
+	}
+	return ir, nil
+}
+
+// getTLSConfig asks how TLS should be configured for the host. It returns false if the host is served over http.
+func (tp tlsPreprocessor) getTLSConfig(irName, hostPrefix string) (irtypes.TLSConfig, bool) {
+	label, host, secretName := hostPrefix, hostPrefix+".<domain>", irName+"-"+hostPrefix+"-tls"
+	if hostPrefix == "" {
+		label, host, secretName = defaultHostLabel, "<domain>", irName+"-tls"
+	}
+	secretName = common.MakeStringDNSLabelNameCompliant(secretName)
+	key := common.ConfigTLSKey + common.Delim + `"` + label + `"` + common.Delim
+	message := fmt.Sprintf("How should TLS be configured for the host %s?", host)
+	hints := []string{
+		"none : the host is served over http",
+		"secret : the certificate is in an existing secret",
+		"cert-manager : cert-manager issues the certificate",
+		"passthrough : the services terminate TLS themselves",
+	}
+	mode := qaengine.FetchSelectAnswer(key+"mode", message, hints, noTLSMode, []string{noTLSMode, secretTLSMode, certManagerTLSMode, passthroughTLSMode})
+	tls := irtypes.TLSConfig{Termination: irtypes.EdgeTLSTermination}
+	switch mode {
+	case noTLSMode:
+		return tls, false
+	case passthroughTLSMode:
+		tls.Termination = irtypes.PassthroughTLSTermination
+	default:
+		if mode == certManagerTLSMode {
+			tls.Issuer = qaengine.FetchStringAnswer(key+"issuer", fmt.Sprintf("Provide the cert-manager issuer of the certificate for the host %s", host), []string{"The issuer has to exist in the cluster"}, defaultCertManagerIssuer)
+			tls.IssuerKind = qaengine.FetchSelectAnswer(key+"issuerkind", fmt.Sprintf("What is the kind of the issuer %s?", tls.Issuer), []string{"An Issuer issues certificates only in its own namespace"}, irtypes.ClusterIssuerKind, []string{irtypes.ClusterIssuerKind, irtypes.IssuerKind})
+			tls.SecretName = secretName
+		} else {
+			tls.SecretName = qaengine.FetchStringAnswer(key+"secret", fmt.Sprintf("Provide the name of the secret holding the TLS certificate for the host %s", host), []string{"The secret has to be of type kubernetes.io/tls"}, secretName)
+		}
+		message := fmt.Sprintf("Where should the TLS connections to the host %s be terminated?", host)
+		hints := []string{
+			"edge : the services are reached over http",
+			"reencrypt : the services are reached over https",
+		}
+		tls.Termination = irtypes.TLSTermination(qaengine.FetchSelectAnswer(key+"termination", message, hints, string(irtypes.EdgeTLSTermination), []string{string(irtypes.EdgeTLSTermination), string(irtypes.ReencryptTLSTermination)}))
+	}
+	tls.RedirectHTTP = qaengine.FetchBoolAnswer(key+"redirect", fmt.Sprintf("Should http requests to the host %s be redirected to https?", host), []string{}, true)
+	return tls, true
+}
@@ -0,0 +1,68 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package irpreprocessor
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	"github.com/sirupsen/logrus"
+	networking "k8s.io/kubernetes/pkg/apis/networking"
+)
+
+func TestTLSPreprocessor(t *testing.T) {
+	logrus.SetLevel(logrus.DebugLevel)
+	qaengine.StartEngine(true, 0, true)
+
+	getIR := func() irtypes.IR {
+		ir := irtypes.NewIR()
+		ir.Name = "shop"
+		svc := irtypes.NewServiceWithName("web")
+		svc.Annotations = map[string]string{common.ExposeSelector: common.AnnotationLabelValue}
+		svc.ServiceToPodPortForwardings = []irtypes.ServiceToPodPortForwarding{
+			{ServicePort: networking.ServiceBackendPort{Number: 80}, PodPort: networking.ServiceBackendPort{Number: 8080}, ServiceRelPath: "/web"},
+			{ServicePort: networking.ServiceBackendPort{Number: 81}, PodPort: networking.ServiceBackendPort{Number: 8081}, ServiceRelPath: "admin/"},
+		}
+		ir.Services["web"] = svc
+		return ir
+	}
+
+	t.Run("hosts are served over http by default", func(t *testing.T) {
+		ir, err := tlsPreprocessor{}.preprocess(getIR())
+		if err != nil {
+			t.Fatalf("Failed to preprocess the IR. Error: %q", err)
+		}
+		if len(ir.TLS) != 0 {
+			t.Fatalf("Expected no TLS configuration. Actual: %+v", ir.TLS)
+		}
+	})
+
+	t.Run("the TLS configuration of the source is kept", func(t *testing.T) {
+		ir := getIR()
+		want := irtypes.TLSConfig{SecretName: "admin-tls", Termination: irtypes.ReencryptTLSTermination}
+		ir.TLS["admin"] = want
+		ir, err := tlsPreprocessor{}.preprocess(ir)
+		if err != nil {
+			t.Fatalf("Failed to preprocess the IR. Error: %q", err)
+		}
+		if len(ir.TLS) != 1 || ir.TLS["admin"] != want {
+			t.Fatalf("Expected the TLS configuration of the host admin to be kept. Actual: %+v", ir.TLS)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package externalsecrets
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyObject implements runtime.Object
+func (in *ExternalSecret) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecret)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.SecretStoreRef = in.Spec.SecretStoreRef
+	out.Spec.Target.Name = in.Spec.Target.Name
+	if in.Spec.Target.Template != nil {
+		template := *in.Spec.Target.Template
+		out.Spec.Target.Template = &template
+	}
+	if in.Spec.RefreshInterval != nil {
+		refreshInterval := *in.Spec.RefreshInterval
+		out.Spec.RefreshInterval = &refreshInterval
+	}
+	if in.Spec.Data != nil {
+		out.Spec.Data = append([]ExternalSecretData{}, in.Spec.Data...)
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package externalsecrets contains the ExternalSecret API of the External Secrets Operator (github.com/external-secrets/external-secrets) used by move2kube.
+// The upstream module depends on newer Kubernetes libraries than move2kube, so the types are defined here.
+// The field names and json tags match upstream, so that the objects are serialized the same way.
+package externalsecrets
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// GroupName is the group name of the External Secrets API
+	GroupName = "external-secrets.io"
+	// ExternalSecretKind is the kind of ExternalSecret
+	ExternalSecretKind = "ExternalSecret"
+	// SecretStoreKind is the kind of a store in the namespace of the ExternalSecret
+	SecretStoreKind = "SecretStore"
+	// ClusterSecretStoreKind is the kind of a store shared by all the namespaces
+	ClusterSecretStoreKind = "ClusterSecretStore"
+)
+
+var (
+	// SchemeGroupVersion is the group version of the External Secrets API
+	SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1beta1"}
+	// SchemeBuilder adds the types to a scheme
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	// AddToScheme adds the types to a scheme
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion, &ExternalSecret{})
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
+
+// ExternalSecret creates a Secret from the values in an external secret store
+type ExternalSecret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ExternalSecretSpec `json:"spec"`
+}
+
+// ExternalSecretSpec is the specification of an ExternalSecret
+type ExternalSecretSpec struct {
+	SecretStoreRef  SecretStoreRef       `json:"secretStoreRef"`
+	Target          ExternalSecretTarget `json:"target,omitempty"`
+	RefreshInterval *metav1.Duration     `json:"refreshInterval,omitempty"`
+	Data            []ExternalSecretData `json:"data,omitempty"`
+}
+
+// SecretStoreRef refers to the store containing the values
+type SecretStoreRef struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+}
+
+// ExternalSecretTarget describes the Secret created by the operator
+type ExternalSecretTarget struct {
+	Name     string                  `json:"name,omitempty"`
+	Template *ExternalSecretTemplate `json:"template,omitempty"`
+}
+
+// ExternalSecretTemplate is the template of the Secret created by the operator
+type ExternalSecretTemplate struct {
+	Type string `json:"type,omitempty"`
+}
+
+// ExternalSecretData maps a key of the Secret to a value in the store
+type ExternalSecretData struct {
+	SecretKey string                      `json:"secretKey"`
+	RemoteRef ExternalSecretDataRemoteRef `json:"remoteRef"`
+}
+
+// ExternalSecretDataRemoteRef refers to a value in the store
+type ExternalSecretDataRemoteRef struct {
+	Key      string `json:"key"`
+	Property string `json:"property,omitempty"`
+}
@@ -0,0 +1,164 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package gatewayapi
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyObject implements runtime.Object
+func (in *Gateway) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(Gateway)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.GatewayClassName = in.Spec.GatewayClassName
+	if in.Spec.Listeners != nil {
+		out.Spec.Listeners = make([]Listener, len(in.Spec.Listeners))
+		for i, listener := range in.Spec.Listeners {
+			out.Spec.Listeners[i] = Listener{Name: listener.Name, Hostname: copyString(listener.Hostname), Port: listener.Port, Protocol: listener.Protocol}
+			if listener.TLS != nil {
+				tls := &GatewayTLSConfig{Mode: copyString(listener.TLS.Mode)}
+				for _, ref := range listener.TLS.CertificateRefs {
+					tls.CertificateRefs = append(tls.CertificateRefs, SecretObjectReference{Group: copyString(ref.Group), Kind: copyString(ref.Kind), Name: ref.Name, Namespace: copyString(ref.Namespace)})
+				}
+				out.Spec.Listeners[i].TLS = tls
+			}
+		}
+	}
+	return out
+}
+
+// DeepCopyObject implements runtime.Object
+func (in *HTTPRoute) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRoute)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.CommonRouteSpec = in.Spec.CommonRouteSpec.deepCopy()
+	out.Spec.Hostnames = copyStrings(in.Spec.Hostnames)
+	if in.Spec.Rules != nil {
+		out.Spec.Rules = make([]HTTPRouteRule, len(in.Spec.Rules))
+		for i, rule := range in.Spec.Rules {
+			if rule.Matches != nil {
+				out.Spec.Rules[i].Matches = make([]HTTPRouteMatch, len(rule.Matches))
+				for j, match := range rule.Matches {
+					if match.Path != nil {
+						path := &HTTPPathMatch{Value: copyString(match.Path.Value)}
+						if match.Path.Type != nil {
+							pathType := *match.Path.Type
+							path.Type = &pathType
+						}
+						out.Spec.Rules[i].Matches[j].Path = path
+					}
+				}
+			}
+			for _, filter := range rule.Filters {
+				outFilter := HTTPRouteFilter{Type: filter.Type}
+				if filter.RequestRedirect != nil {
+					outFilter.RequestRedirect = &HTTPRequestRedirectFilter{Scheme: copyString(filter.RequestRedirect.Scheme)}
+					if filter.RequestRedirect.StatusCode != nil {
+						statusCode := *filter.RequestRedirect.StatusCode
+						outFilter.RequestRedirect.StatusCode = &statusCode
+					}
+				}
+				out.Spec.Rules[i].Filters = append(out.Spec.Rules[i].Filters, outFilter)
+			}
+			for _, backendRef := range rule.BackendRefs {
+				out.Spec.Rules[i].BackendRefs = append(out.Spec.Rules[i].BackendRefs, HTTPBackendRef{BackendRef: backendRef.BackendRef.deepCopy()})
+			}
+		}
+	}
+	return out
+}
+
+// DeepCopyObject implements runtime.Object
+func (in *GRPCRoute) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCRoute)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.CommonRouteSpec = in.Spec.CommonRouteSpec.deepCopy()
+	out.Spec.Hostnames = copyStrings(in.Spec.Hostnames)
+	if in.Spec.Rules != nil {
+		out.Spec.Rules = make([]GRPCRouteRule, len(in.Spec.Rules))
+		for i, rule := range in.Spec.Rules {
+			if rule.Matches != nil {
+				out.Spec.Rules[i].Matches = make([]GRPCRouteMatch, len(rule.Matches))
+				for j, match := range rule.Matches {
+					if match.Method != nil {
+						out.Spec.Rules[i].Matches[j].Method = &GRPCMethodMatch{Type: copyString(match.Method.Type), Service: copyString(match.Method.Service), Method: copyString(match.Method.Method)}
+					}
+				}
+			}
+			for _, backendRef := range rule.BackendRefs {
+				out.Spec.Rules[i].BackendRefs = append(out.Spec.Rules[i].BackendRefs, GRPCBackendRef{BackendRef: backendRef.BackendRef.deepCopy()})
+			}
+		}
+	}
+	return out
+}
+
+func (in CommonRouteSpec) deepCopy() CommonRouteSpec {
+	out := CommonRouteSpec{}
+	for _, ref := range in.ParentRefs {
+		out.ParentRefs = append(out.ParentRefs, ParentReference{Group: copyString(ref.Group), Kind: copyString(ref.Kind), Namespace: copyString(ref.Namespace), Name: ref.Name, SectionName: copyString(ref.SectionName), Port: copyInt32(ref.Port)})
+	}
+	return out
+}
+
+func (in BackendRef) deepCopy() BackendRef {
+	return BackendRef{
+		BackendObjectReference: BackendObjectReference{
+			Group:     copyString(in.Group),
+			Kind:      copyString(in.Kind),
+			Name:      in.Name,
+			Namespace: copyString(in.Namespace),
+			Port:      copyInt32(in.Port),
+		},
+		Weight: copyInt32(in.Weight),
+	}
+}
+
+func copyString(in *string) *string {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
+
+func copyInt32(in *int32) *int32 {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
+
+func copyStrings(in []string) []string {
+	if in == nil {
+		return nil
+	}
+	return append([]string{}, in...)
+}
@@ -0,0 +1,241 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package gatewayapi contains the subset of the Kubernetes Gateway API (sigs.k8s.io/gateway-api) used by move2kube.
+// The upstream module depends on newer Kubernetes libraries than move2kube, so the types are defined here.
+// The field names and json tags match upstream, so that the objects are serialized the same way.
+package gatewayapi
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// GroupName is the group name of the Gateway API
+	GroupName = "gateway.networking.k8s.io"
+	// GatewayKind is the kind of Gateway
+	GatewayKind = "Gateway"
+	// HTTPRouteKind is the kind of HTTPRoute
+	HTTPRouteKind = "HTTPRoute"
+	// GRPCRouteKind is the kind of GRPCRoute
+	GRPCRouteKind = "GRPCRoute"
+)
+
+var (
+	// SchemeGroupVersion is the preferred group version of the Gateway API
+	SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1"}
+	// SchemeGroupVersions are the group versions served by the types in this package
+	SchemeGroupVersions = []schema.GroupVersion{SchemeGroupVersion, {Group: GroupName, Version: "v1beta1"}}
+	// SchemeBuilder adds the types to a scheme
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	// AddToScheme adds the types to a scheme
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	for _, gv := range SchemeGroupVersions {
+		scheme.AddKnownTypes(gv, &Gateway{}, &HTTPRoute{}, &GRPCRoute{})
+		metav1.AddToGroupVersion(scheme, gv)
+	}
+	return nil
+}
+
+// ProtocolType is the protocol of a listener
+type ProtocolType string
+
+const (
+	// HTTPProtocolType accepts plain text HTTP/1.1 and h2c connections
+	HTTPProtocolType ProtocolType = "HTTP"
+	// HTTPSProtocolType accepts HTTP/1.1 and HTTP/2 connections over TLS
+	HTTPSProtocolType ProtocolType = "HTTPS"
+)
+
+// PathMatchType specifies how a path is matched
+type PathMatchType string
+
+const (
+	// PathMatchExact matches the exact path
+	PathMatchExact PathMatchType = "Exact"
+	// PathMatchPathPrefix matches the path prefix split by "/"
+	PathMatchPathPrefix PathMatchType = "PathPrefix"
+	// PathMatchRegularExpression matches the path with a regular expression
+	PathMatchRegularExpression PathMatchType = "RegularExpression"
+)
+
+// Gateway represents an instance of a service-traffic handling infrastructure
+type Gateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GatewaySpec `json:"spec"`
+}
+
+// GatewaySpec defines the desired state of Gateway
+type GatewaySpec struct {
+	GatewayClassName string     `json:"gatewayClassName"`
+	Listeners        []Listener `json:"listeners"`
+}
+
+// Listener is a logical endpoint where the Gateway accepts connections
+type Listener struct {
+	Name     string            `json:"name"`
+	Hostname *string           `json:"hostname,omitempty"`
+	Port     int32             `json:"port"`
+	Protocol ProtocolType      `json:"protocol"`
+	TLS      *GatewayTLSConfig `json:"tls,omitempty"`
+}
+
+// GatewayTLSConfig describes the TLS configuration of a listener
+type GatewayTLSConfig struct {
+	Mode            *string                 `json:"mode,omitempty"`
+	CertificateRefs []SecretObjectReference `json:"certificateRefs,omitempty"`
+}
+
+// SecretObjectReference identifies a secret holding a certificate
+type SecretObjectReference struct {
+	Group     *string `json:"group,omitempty"`
+	Kind      *string `json:"kind,omitempty"`
+	Name      string  `json:"name"`
+	Namespace *string `json:"namespace,omitempty"`
+}
+
+// CommonRouteSpec defines the fields shared by all routes
+type CommonRouteSpec struct {
+	ParentRefs []ParentReference `json:"parentRefs,omitempty"`
+}
+
+// ParentReference identifies the gateway a route attaches to
+type ParentReference struct {
+	Group       *string `json:"group,omitempty"`
+	Kind        *string `json:"kind,omitempty"`
+	Namespace   *string `json:"namespace,omitempty"`
+	Name        string  `json:"name"`
+	SectionName *string `json:"sectionName,omitempty"`
+	Port        *int32  `json:"port,omitempty"`
+}
+
+// BackendObjectReference identifies the backend of a route
+type BackendObjectReference struct {
+	Group     *string `json:"group,omitempty"`
+	Kind      *string `json:"kind,omitempty"`
+	Name      string  `json:"name"`
+	Namespace *string `json:"namespace,omitempty"`
+	Port      *int32  `json:"port,omitempty"`
+}
+
+// BackendRef is a weighted reference to a backend
+type BackendRef struct {
+	BackendObjectReference `json:",inline"`
+	Weight                 *int32 `json:"weight,omitempty"`
+}
+
+// HTTPRoute routes HTTP requests from a gateway to the backends
+type HTTPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HTTPRouteSpec `json:"spec"`
+}
+
+// HTTPRouteSpec defines the desired state of HTTPRoute
+type HTTPRouteSpec struct {
+	CommonRouteSpec `json:",inline"`
+	Hostnames       []string        `json:"hostnames,omitempty"`
+	Rules           []HTTPRouteRule `json:"rules,omitempty"`
+}
+
+// HTTPRouteRule routes the requests matching any of the matches to the backends
+type HTTPRouteRule struct {
+	Matches     []HTTPRouteMatch  `json:"matches,omitempty"`
+	Filters     []HTTPRouteFilter `json:"filters,omitempty"`
+	BackendRefs []HTTPBackendRef  `json:"backendRefs,omitempty"`
+}
+
+// HTTPRouteFilterType is the type of a filter
+type HTTPRouteFilterType string
+
+const (
+	// HTTPRouteFilterRequestRedirect redirects the requests
+	HTTPRouteFilterRequestRedirect HTTPRouteFilterType = "RequestRedirect"
+)
+
+// HTTPRouteFilter processes the requests matched by a rule
+type HTTPRouteFilter struct {
+	Type            HTTPRouteFilterType        `json:"type"`
+	RequestRedirect *HTTPRequestRedirectFilter `json:"requestRedirect,omitempty"`
+}
+
+// HTTPRequestRedirectFilter redirects the requests
+type HTTPRequestRedirectFilter struct {
+	Scheme     *string `json:"scheme,omitempty"`
+	StatusCode *int    `json:"statusCode,omitempty"`
+}
+
+// HTTPRouteMatch defines the predicate used to match requests
+type HTTPRouteMatch struct {
+	Path *HTTPPathMatch `json:"path,omitempty"`
+}
+
+// HTTPPathMatch describes how to select a HTTP route by matching the HTTP request path
+type HTTPPathMatch struct {
+	Type  *PathMatchType `json:"type,omitempty"`
+	Value *string        `json:"value,omitempty"`
+}
+
+// HTTPBackendRef defines how a HTTPRoute forwards a HTTP request
+type HTTPBackendRef struct {
+	BackendRef `json:",inline"`
+}
+
+// GRPCRoute routes gRPC requests from a gateway to the backends
+type GRPCRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GRPCRouteSpec `json:"spec"`
+}
+
+// GRPCRouteSpec defines the desired state of GRPCRoute
+type GRPCRouteSpec struct {
+	CommonRouteSpec `json:",inline"`
+	Hostnames       []string        `json:"hostnames,omitempty"`
+	Rules           []GRPCRouteRule `json:"rules,omitempty"`
+}
+
+// GRPCRouteRule routes the requests matching any of the matches to the backends
+type GRPCRouteRule struct {
+	Matches     []GRPCRouteMatch `json:"matches,omitempty"`
+	BackendRefs []GRPCBackendRef `json:"backendRefs,omitempty"`
+}
+
+// GRPCRouteMatch defines the predicate used to match requests
+type GRPCRouteMatch struct {
+	Method *GRPCMethodMatch `json:"method,omitempty"`
+}
+
+// GRPCMethodMatch describes how to select a gRPC route by matching the gRPC service and method
+type GRPCMethodMatch struct {
+	Type    *string `json:"type,omitempty"`
+	Service *string `json:"service,omitempty"`
+	Method  *string `json:"method,omitempty"`
+}
+
+// GRPCBackendRef defines how a GRPCRoute forwards a gRPC request
+type GRPCBackendRef struct {
+	BackendRef `json:",inline"`
+}
@@ -0,0 +1,100 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package istio
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyObject implements runtime.Object
+func (in *VirtualService) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualService)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec.Hosts != nil {
+		out.Spec.Hosts = append([]string{}, in.Spec.Hosts...)
+	}
+	if in.Spec.HTTP != nil {
+		out.Spec.HTTP = make([]HTTPRoute, len(in.Spec.HTTP))
+		for i, route := range in.Spec.HTTP {
+			out.Spec.HTTP[i] = HTTPRoute{Name: route.Name, Timeout: route.Timeout}
+			for _, destination := range route.Route {
+				outDestination := destination
+				if destination.Destination.Port != nil {
+					port := *destination.Destination.Port
+					outDestination.Destination.Port = &port
+				}
+				out.Spec.HTTP[i].Route = append(out.Spec.HTTP[i].Route, outDestination)
+			}
+			if route.Retries != nil {
+				retries := *route.Retries
+				out.Spec.HTTP[i].Retries = &retries
+			}
+		}
+	}
+	return out
+}
+
+// DeepCopyObject implements runtime.Object
+func (in *DestinationRule) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(DestinationRule)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.Host = in.Spec.Host
+	if in.Spec.TrafficPolicy != nil {
+		out.Spec.TrafficPolicy = &TrafficPolicy{}
+		if in.Spec.TrafficPolicy.OutlierDetection != nil {
+			outlierDetection := *in.Spec.TrafficPolicy.OutlierDetection
+			if outlierDetection.Consecutive5xxErrors != nil {
+				consecutive5xxErrors := *outlierDetection.Consecutive5xxErrors
+				outlierDetection.Consecutive5xxErrors = &consecutive5xxErrors
+			}
+			out.Spec.TrafficPolicy.OutlierDetection = &outlierDetection
+		}
+	}
+	return out
+}
+
+// DeepCopyObject implements runtime.Object
+func (in *PeerAuthentication) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(PeerAuthentication)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec.Selector != nil {
+		out.Spec.Selector = &WorkloadSelector{}
+		if in.Spec.Selector.MatchLabels != nil {
+			out.Spec.Selector.MatchLabels = map[string]string{}
+			for k, v := range in.Spec.Selector.MatchLabels {
+				out.Spec.Selector.MatchLabels[k] = v
+			}
+		}
+	}
+	if in.Spec.MTLS != nil {
+		mtls := *in.Spec.MTLS
+		out.Spec.MTLS = &mtls
+	}
+	return out
+}
@@ -0,0 +1,166 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package istio contains the subset of the Istio networking and security APIs (istio.io/client-go) used by move2kube.
+// The upstream module depends on newer Kubernetes libraries than move2kube, so the types are defined here.
+// The field names and json tags match upstream, so that the objects are serialized the same way.
+package istio
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// NetworkingGroupName is the group name of the Istio networking API
+	NetworkingGroupName = "networking.istio.io"
+	// SecurityGroupName is the group name of the Istio security API
+	SecurityGroupName = "security.istio.io"
+	// VirtualServiceKind is the kind of VirtualService
+	VirtualServiceKind = "VirtualService"
+	// DestinationRuleKind is the kind of DestinationRule
+	DestinationRuleKind = "DestinationRule"
+	// PeerAuthenticationKind is the kind of PeerAuthentication
+	PeerAuthenticationKind = "PeerAuthentication"
+)
+
+var (
+	// NetworkingSchemeGroupVersion is the group version of the Istio networking API
+	NetworkingSchemeGroupVersion = schema.GroupVersion{Group: NetworkingGroupName, Version: "v1beta1"}
+	// SecuritySchemeGroupVersion is the group version of the Istio security API
+	SecuritySchemeGroupVersion = schema.GroupVersion{Group: SecurityGroupName, Version: "v1beta1"}
+	// SchemeBuilder adds the types to a scheme
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	// AddToScheme adds the types to a scheme
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(NetworkingSchemeGroupVersion, &VirtualService{}, &DestinationRule{})
+	metav1.AddToGroupVersion(scheme, NetworkingSchemeGroupVersion)
+	scheme.AddKnownTypes(SecuritySchemeGroupVersion, &PeerAuthentication{})
+	metav1.AddToGroupVersion(scheme, SecuritySchemeGroupVersion)
+	return nil
+}
+
+// MutualTLSMode is the mutual TLS mode of a workload
+type MutualTLSMode string
+
+const (
+	// MutualTLSPermissive accepts both plain text and mutual TLS connections
+	MutualTLSPermissive MutualTLSMode = "PERMISSIVE"
+	// MutualTLSStrict accepts only mutual TLS connections
+	MutualTLSStrict MutualTLSMode = "STRICT"
+)
+
+// VirtualService configures how requests to a host are routed
+type VirtualService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VirtualServiceSpec `json:"spec"`
+}
+
+// VirtualServiceSpec defines the desired state of VirtualService
+type VirtualServiceSpec struct {
+	Hosts []string    `json:"hosts,omitempty"`
+	HTTP  []HTTPRoute `json:"http,omitempty"`
+}
+
+// HTTPRoute routes HTTP and gRPC requests to the destinations
+type HTTPRoute struct {
+	Name    string                 `json:"name,omitempty"`
+	Route   []HTTPRouteDestination `json:"route,omitempty"`
+	Timeout string                 `json:"timeout,omitempty"`
+	Retries *HTTPRetry             `json:"retries,omitempty"`
+}
+
+// HTTPRouteDestination is a weighted destination of a route
+type HTTPRouteDestination struct {
+	Destination Destination `json:"destination"`
+	Weight      int32       `json:"weight,omitempty"`
+}
+
+// Destination identifies the service the requests are forwarded to
+type Destination struct {
+	Host   string        `json:"host"`
+	Subset string        `json:"subset,omitempty"`
+	Port   *PortSelector `json:"port,omitempty"`
+}
+
+// PortSelector selects a port of a service
+type PortSelector struct {
+	Number uint32 `json:"number,omitempty"`
+}
+
+// HTTPRetry describes the retries of a failed request
+type HTTPRetry struct {
+	Attempts      int32  `json:"attempts"`
+	PerTryTimeout string `json:"perTryTimeout,omitempty"`
+	RetryOn       string `json:"retryOn,omitempty"`
+}
+
+// DestinationRule configures the traffic to a service after routing
+type DestinationRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DestinationRuleSpec `json:"spec"`
+}
+
+// DestinationRuleSpec defines the desired state of DestinationRule
+type DestinationRuleSpec struct {
+	Host          string         `json:"host"`
+	TrafficPolicy *TrafficPolicy `json:"trafficPolicy,omitempty"`
+}
+
+// TrafficPolicy describes the load balancing, connection pool and outlier detection of a service
+type TrafficPolicy struct {
+	OutlierDetection *OutlierDetection `json:"outlierDetection,omitempty"`
+}
+
+// OutlierDetection ejects the unhealthy endpoints of a service, like a circuit breaker
+type OutlierDetection struct {
+	Consecutive5xxErrors *int32 `json:"consecutive5xxErrors,omitempty"`
+	Interval             string `json:"interval,omitempty"`
+	BaseEjectionTime     string `json:"baseEjectionTime,omitempty"`
+	MaxEjectionPercent   int32  `json:"maxEjectionPercent,omitempty"`
+}
+
+// PeerAuthentication configures the mutual TLS of the workloads
+type PeerAuthentication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PeerAuthenticationSpec `json:"spec"`
+}
+
+// PeerAuthenticationSpec defines the desired state of PeerAuthentication
+type PeerAuthenticationSpec struct {
+	Selector *WorkloadSelector `json:"selector,omitempty"`
+	MTLS     *MutualTLS        `json:"mtls,omitempty"`
+}
+
+// WorkloadSelector selects the pods a policy applies to
+type WorkloadSelector struct {
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+// MutualTLS describes the mutual TLS mode of the workloads
+type MutualTLS struct {
+	Mode MutualTLSMode `json:"mode,omitempty"`
+}
@@ -58,6 +58,10 @@ import (
 	schedulinginstall "k8s.io/kubernetes/pkg/apis/scheduling/install"
 	storageinstall "k8s.io/kubernetes/pkg/apis/storage/install"
 
+	"github.com/konveyor/move2kube/k8sschema/externalsecrets"
+	"github.com/konveyor/move2kube/k8sschema/gatewayapi"
+	"github.com/konveyor/move2kube/k8sschema/istio"
+	"github.com/konveyor/move2kube/k8sschema/sealedsecrets"
 	okdapi "github.com/openshift/api"
 	tektonscheme "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/scheme"
 	k8sapischeme "k8s.io/client-go/kubernetes/scheme"
@@ -74,6 +78,10 @@ func init() {
 
 	must(k8sapischeme.AddToScheme(scheme))
 	must(tektonscheme.AddToScheme(scheme))
+	must(gatewayapi.AddToScheme(scheme))
+	must(istio.AddToScheme(scheme))
+	must(sealedsecrets.AddToScheme(scheme))
+	must(externalsecrets.AddToScheme(scheme))
 
 	appsinstall.Install(scheme)
 	admissionregistrationinstall.Install(scheme)
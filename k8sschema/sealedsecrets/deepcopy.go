@@ -0,0 +1,40 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package sealedsecrets
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyObject implements runtime.Object
+func (in *SealedSecret) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(SealedSecret)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.Template.ObjectMeta.DeepCopyInto(&out.Spec.Template.ObjectMeta)
+	out.Spec.Template.Type = in.Spec.Template.Type
+	if in.Spec.EncryptedData != nil {
+		out.Spec.EncryptedData = map[string]string{}
+		for k, v := range in.Spec.EncryptedData {
+			out.Spec.EncryptedData[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package sealedsecrets contains the SealedSecret API of Bitnami Sealed Secrets (github.com/bitnami-labs/sealed-secrets) used by move2kube.
+// The upstream module depends on newer Kubernetes libraries than move2kube, so the types are defined here.
+// The field names and json tags match upstream, so that the objects are serialized the same way.
+package sealedsecrets
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// GroupName is the group name of the Sealed Secrets API
+	GroupName = "bitnami.com"
+	// SealedSecretKind is the kind of SealedSecret
+	SealedSecretKind = "SealedSecret"
+	// ClusterWideAnnotation marks a SealedSecret which can be unsealed with any name in any namespace
+	ClusterWideAnnotation = "sealedsecrets.bitnami.com/cluster-wide"
+)
+
+var (
+	// SchemeGroupVersion is the group version of the Sealed Secrets API
+	SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+	// SchemeBuilder adds the types to a scheme
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	// AddToScheme adds the types to a scheme
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion, &SealedSecret{})
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
+
+// SealedSecret holds the encrypted data of a Secret, which only the controller in the cluster can decrypt
+type SealedSecret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              SealedSecretSpec `json:"spec"`
+}
+
+// SealedSecretSpec is the specification of a SealedSecret
+type SealedSecretSpec struct {
+	Template      SecretTemplateSpec `json:"template,omitempty"`
+	EncryptedData map[string]string  `json:"encryptedData"`
+}
+
+// SecretTemplateSpec describes the Secret created by the controller
+type SecretTemplateSpec struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Type              string `json:"type,omitempty"`
+}
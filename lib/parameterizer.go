@@ -17,11 +17,14 @@
 package lib
 
 import (
+	"fmt"
 	"path/filepath"
+	"sort"
 
 	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/parameterizer"
 	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+	plantypes "github.com/konveyor/move2kube/types/plan"
 	"github.com/sirupsen/logrus"
 )
 
@@ -41,27 +44,157 @@ func Parameterize(srcDir string, packDir string, outDir string) ([]string, error
 	}
 	filesWritten := []string{}
 	for _, pack := range packs {
-		ps := []parameterizertypes.ParameterizerT{}
-		for _, name := range pack.Spec.ParameterizerRefs {
-			if currPs, ok := namedPs[name]; ok {
-				ps = append(ps, currPs...)
+		ps := getPackParameterizers(pack, namedPs, cleanPackDir)
+		for _, path := range pack.Spec.Paths {
+			fw, err := parameterizer.Parameterize(srcDir, outDir, path, ps)
+			if err != nil {
+				logrus.Errorf("Unable to process path %s : %s", path.Src, err)
 				continue
 			}
-			logrus.Errorf("failed to find the paramterizers with the name %s referred to by the packaging with the name %s , in the folder %s", name, pack.ObjectMeta.Name, cleanPackDir)
+			filesWritten = append(filesWritten, fw...)
 		}
-		ps = append(ps, pack.Spec.Parameterizers...)
+	}
+	return filesWritten, nil
+}
+
+// PreviewParameterize returns a report of what the parameterizers would do for each path of each packaging, without writing any files
+func PreviewParameterize(srcDir string, packDir string) ([]parameterizertypes.ReportT, error) {
+	cleanPackDir, err := filepath.Abs(packDir)
+	if err != nil {
+		return nil, err
+	}
+	packs, err := collectPacksFromPath(cleanPackDir)
+	if err != nil {
+		return nil, err
+	}
+	namedPs, err := parameterizer.CollectParamsFromPath(cleanPackDir)
+	if err != nil {
+		return nil, err
+	}
+	reports := []parameterizertypes.ReportT{}
+	for _, pack := range packs {
+		ps := getPackParameterizers(pack, namedPs, cleanPackDir)
 		for _, path := range pack.Spec.Paths {
-			fw, err := parameterizer.Parameterize(srcDir, outDir, path, ps)
+			report, err := parameterizer.Preview(srcDir, path, ps)
 			if err != nil {
 				logrus.Errorf("Unable to process path %s : %s", path.Src, err)
 				continue
 			}
-			filesWritten = append(filesWritten, fw...)
+			reports = append(reports, report)
+		}
+	}
+	return reports, nil
+}
+
+// ParameterizeTransformOutput parameterizes each directory of k8s resources created by the transform.
+// The parameterizers are created from the metadata written by the transform, followed by the parameterizers in the customizations.
+// The plan, when given, provides the name and the version of the helm charts.
+func ParameterizeTransformOutput(srcDir string, packDir string, planPath string, outDir string) ([]string, error) {
+	paths, ps, err := getTransformOutputPaths(srcDir, packDir, planPath)
+	if err != nil {
+		return nil, err
+	}
+	filesWritten := []string{}
+	for i, path := range paths {
+		fw, err := parameterizer.Parameterize(srcDir, outDir, path, ps[i])
+		if err != nil {
+			logrus.Errorf("Unable to process path %s : %s", path.Src, err)
+			continue
 		}
+		filesWritten = append(filesWritten, fw...)
 	}
 	return filesWritten, nil
 }
 
+// PreviewParameterizeTransformOutput returns a report of what the parameterizers created for the transform output would do, without writing any files
+func PreviewParameterizeTransformOutput(srcDir string, packDir string, planPath string) ([]parameterizertypes.ReportT, error) {
+	paths, ps, err := getTransformOutputPaths(srcDir, packDir, planPath)
+	if err != nil {
+		return nil, err
+	}
+	reports := []parameterizertypes.ReportT{}
+	for i, path := range paths {
+		report, err := parameterizer.Preview(srcDir, path, ps[i])
+		if err != nil {
+			logrus.Errorf("Unable to process path %s : %s", path.Src, err)
+			continue
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// getTransformOutputPaths returns a path, along with its parameterizers, for each directory containing the metadata of the transform
+func getTransformOutputPaths(srcDir string, packDir string, planPath string) ([]parameterizertypes.PackagingSpecPathT, [][]parameterizertypes.ParameterizerT, error) {
+	metadatas, err := parameterizer.CollectTransformMetadataFromPath(srcDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(metadatas) == 0 {
+		return nil, nil, fmt.Errorf("no %s files were found in the transform output at %s", common.DefaultTransformMetadataFile, srcDir)
+	}
+	customPs := []parameterizertypes.ParameterizerT{}
+	if packDir != "" {
+		namedPs, err := parameterizer.CollectParamsFromPath(packDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		names := []string{}
+		for name := range namedPs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			customPs = append(customPs, namedPs[name]...)
+		}
+	}
+	basePath := parameterizertypes.PackagingSpecPathT{}
+	if planPath != "" {
+		plan, err := plantypes.ReadPlan(planPath, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		basePath.HelmChartName = plan.Name
+		if version, err := common.GetGitTagVersion(plan.Spec.RootDir); err == nil {
+			basePath.HelmChartVersion, basePath.HelmAppVersion = version.String(), version.Original()
+		} else {
+			logrus.Debugf("Unable to get the version of the helm chart from the git tags of the source : %s", err)
+		}
+	}
+	dirs := []string{}
+	for dir := range metadatas {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	paths := []parameterizertypes.PackagingSpecPathT{}
+	ps := [][]parameterizertypes.ParameterizerT{}
+	for _, dir := range dirs {
+		relDir, err := filepath.Rel(srcDir, dir)
+		if err != nil {
+			logrus.Errorf("Unable to make the path %s relative to the transform output %s : %s", dir, srcDir, err)
+			continue
+		}
+		path := basePath
+		path.Src, path.Out = relDir, relDir
+		paths = append(paths, path)
+		ps = append(ps, append(parameterizer.GetParameterizersFromTransformMetadata(metadatas[dir].Spec), customPs...))
+	}
+	return paths, ps, nil
+}
+
+// getPackParameterizers returns the parameterizers referred to by the packaging followed by its own parameterizers
+func getPackParameterizers(pack parameterizertypes.PackagingFileT, namedPs map[string][]parameterizertypes.ParameterizerT, packDir string) []parameterizertypes.ParameterizerT {
+	ps := []parameterizertypes.ParameterizerT{}
+	for _, name := range pack.Spec.ParameterizerRefs {
+		if currPs, ok := namedPs[name]; ok {
+			ps = append(ps, currPs...)
+			continue
+		}
+		logrus.Errorf("failed to find the paramterizers with the name %s referred to by the packaging with the name %s , in the folder %s", name, pack.ObjectMeta.Name, packDir)
+	}
+	return append(ps, pack.Spec.Parameterizers...)
+}
+
 func collectPacksFromPath(packDir string) ([]parameterizertypes.PackagingFileT, error) {
 	yamlPaths, err := common.GetFilesByExt(packDir, []string{".yaml", ".yml"})
 	if err != nil {
@@ -24,11 +24,13 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/konveyor/move2kube/lib"
+	"github.com/konveyor/move2kube/qaengine"
 	log "github.com/sirupsen/logrus"
 )
 
 func TestGettingAndParameterizingResources(t *testing.T) {
 	log.SetLevel(log.TraceLevel)
+	qaengine.StartEngine(true, 0, true)
 	relBaseDir := "testdata"
 	baseDir, err := filepath.Abs(relBaseDir)
 	if err != nil {
@@ -43,8 +45,8 @@ func TestGettingAndParameterizingResources(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to apply all the parameterizations. Error: %q", err)
 	}
-	if len(filesWritten) != 26 {
-		t.Fatalf("Expected %d files to be written. Actual: %d", 26, len(filesWritten))
+	if len(filesWritten) != 29 {
+		t.Fatalf("Expected %d files to be written. Actual: %d", 29, len(filesWritten))
 	}
 	wantDataDir := filepath.Join(baseDir, "want")
 	for _, fileWritten := range filesWritten {
@@ -0,0 +1,171 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/transformer/transformertest"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// StarlarkTransformerClass is the class of transformers written in starlark
+	StarlarkTransformerClass = "Starlark"
+	// ExecutableTransformerClass is the class of transformers written as executables
+	ExecutableTransformerClass = "Executable"
+	// scaffoldMarkerFile is the file whose presence in a directory is detected by the scaffolded transformers
+	scaffoldMarkerFile = "marker.txt"
+)
+
+type scaffoldFile struct {
+	path       string
+	content    string
+	executable bool
+}
+
+const scaffoldStarlarkYaml = `apiVersion: move2kube.konveyor.io/v1alpha1
+kind: Transformer
+metadata:
+  name: {{ .Name }}
+spec:
+  mode: "Container"
+  class: "Starlark"
+  produces: []
+  templates: "templates/"
+  config:
+    starFile: "{{ .FileName }}.star"
+`
+
+const scaffoldStarlarkScript = `# Detects a service in every directory containing a {{ .MarkerFile }} file
+def directory_detect(dir):
+    if not fs.exists(fs.pathjoin(dir, "{{ .MarkerFile }}")):
+        return {}
+    name = dir.split("/")[-1]
+    plan = m2k.transformer_plan(mode="Container", generates=[], paths={"ProjectPath": [dir]})
+    return {"namedServices": {name: [plan]}}
+
+# Copies the source of each service and renders the templates into it
+def transform(new_artifacts, old_artifacts):
+    path_mappings = []
+    for a in new_artifacts:
+        project_path = a["paths"]["ProjectPath"][0]
+        rel_path = project_path[len(source_dir) + 1:]
+        path_mappings.append(m2k.path_mapping(type="Source", sourcePath="", destinationPath="source"))
+        path_mappings.append(m2k.path_mapping(type="Template", sourcePath="", destinationPath=fs.pathjoin("source", rel_path), templateConfig={"name": a["name"]}))
+    return {"pathMappings": path_mappings}
+`
+
+const scaffoldExecutableYaml = `apiVersion: move2kube.konveyor.io/v1alpha1
+kind: Transformer
+metadata:
+  name: {{ .Name }}
+spec:
+  mode: "Container"
+  class: "Executable"
+  produces: []
+  templates: "templates/"
+  config:
+    directoryDetectCMD: ["./m2kdetect.sh"]
+    platforms: ["linux", "darwin"]
+`
+
+const scaffoldExecutableScript = `#!/usr/bin/env bash
+
+# Takes as input the source folder and returns error if it is not fit
+BASE_DIR="$1"
+
+if [ -f "$BASE_DIR/{{ .MarkerFile }}" ]; then
+   printf '{"name": "%s"}' "$(basename "$BASE_DIR")"
+   exit 0
+fi
+
+exit 1
+`
+
+const scaffoldTemplate = `# Generated by {{ "{{ .name }}" }}
+`
+
+const scaffoldReadme = `# {{ .Name }}
+
+A {{ .Class }} transformer. Copy this directory into your customizations directory to use it.
+
+The test cases are in the tests directory. Each test case has a source directory on which the transformer is run.
+To create or update the expected results of the test cases run:
+
+    move2kube transformer test -t {{ .FileName }}.yaml -d tests --update
+
+and to test the transformer against them run:
+
+    move2kube transformer test -t {{ .FileName }}.yaml -d tests
+`
+
+// ScaffoldTransformer creates a new custom transformer of the given class, along with a test case, in a directory
+func ScaffoldTransformer(class, name, outputPath string) (transformerPath string, err error) {
+	fileName := strings.ToLower(common.MakeFileNameCompliant(name))
+	transformerPath = filepath.Join(outputPath, fileName)
+	if _, err := os.Stat(transformerPath); err == nil {
+		return transformerPath, fmt.Errorf("the directory %s already exists", transformerPath)
+	}
+	files := []scaffoldFile{
+		{path: "README.md", content: scaffoldReadme},
+		{path: filepath.Join("templates", "generated.txt"), content: scaffoldTemplate},
+		{path: filepath.Join("tests", "basic", transformertest.SourceDirName, "app", scaffoldMarkerFile), content: "app\n"},
+	}
+	switch class {
+	case StarlarkTransformerClass:
+		files = append(files,
+			scaffoldFile{path: fileName + ".yaml", content: scaffoldStarlarkYaml},
+			scaffoldFile{path: fileName + ".star", content: scaffoldStarlarkScript})
+	case ExecutableTransformerClass:
+		files = append(files,
+			scaffoldFile{path: fileName + ".yaml", content: scaffoldExecutableYaml},
+			scaffoldFile{path: "m2kdetect.sh", content: scaffoldExecutableScript, executable: true})
+	default:
+		return transformerPath, fmt.Errorf("scaffolding is supported only for the classes %s and %s. Actual: %s", StarlarkTransformerClass, ExecutableTransformerClass, class)
+	}
+	config := map[string]string{
+		"Name":       name,
+		"Class":      class,
+		"FileName":   fileName,
+		"MarkerFile": scaffoldMarkerFile,
+	}
+	for _, file := range files {
+		content, err := common.GetStringFromTemplate(file.content, config)
+		if err != nil {
+			return transformerPath, fmt.Errorf("unable to fill the template for %s : %s", file.path, err)
+		}
+		filePath := filepath.Join(transformerPath, file.path)
+		if err := os.MkdirAll(filepath.Dir(filePath), common.DefaultDirectoryPermission); err != nil {
+			return transformerPath, err
+		}
+		permission := common.DefaultFilePermission
+		if file.executable {
+			permission = common.DefaultExecutablePermission
+		}
+		if err := ioutil.WriteFile(filePath, []byte(content), permission); err != nil {
+			return transformerPath, err
+		}
+		logrus.Debugf("Created %s", filePath)
+	}
+	return transformerPath, nil
+}
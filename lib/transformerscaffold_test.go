@@ -0,0 +1,64 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lib_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/move2kube/lib"
+	"github.com/konveyor/move2kube/transformer/transformertest"
+	"github.com/konveyor/move2kube/transformer/transformertest/transformertesting"
+)
+
+func TestScaffoldTransformer(t *testing.T) {
+	t.Run("starlark transformer passes its own test case", func(t *testing.T) {
+		transformertesting.Setup(t)
+		transformerPath, err := lib.ScaffoldTransformer(lib.StarlarkTransformerClass, "MyTransformer", t.TempDir())
+		if err != nil {
+			t.Fatalf("Failed to scaffold the transformer. Error: %q", err)
+		}
+		transformerYamlPath := filepath.Join(transformerPath, "mytransformer.yaml")
+		testCaseDir := filepath.Join(transformerPath, "tests", "basic")
+		if _, err := transformertest.Run(transformerYamlPath, testCaseDir, true); err != nil {
+			t.Fatalf("Failed to create the expected results. Error: %q", err)
+		}
+		transformertesting.AssertTestCase(t, transformerYamlPath, testCaseDir, false)
+		generated, err := ioutil.ReadFile(filepath.Join(testCaseDir, "expected", "output", "source", "app", "generated.txt"))
+		if err != nil {
+			t.Fatalf("Failed to read the generated file. Error: %q", err)
+		}
+		if want := "# Generated by app\n"; string(generated) != want {
+			t.Fatalf("Expected the generated file to be %q. Actual: %q", want, string(generated))
+		}
+	})
+	t.Run("existing directory is not overwritten", func(t *testing.T) {
+		outputPath := t.TempDir()
+		if _, err := lib.ScaffoldTransformer(lib.ExecutableTransformerClass, "MyTransformer", outputPath); err != nil {
+			t.Fatalf("Failed to scaffold the transformer. Error: %q", err)
+		}
+		if _, err := lib.ScaffoldTransformer(lib.ExecutableTransformerClass, "MyTransformer", outputPath); err == nil {
+			t.Fatalf("Expected an error when the transformer directory already exists")
+		}
+	})
+	t.Run("unsupported class", func(t *testing.T) {
+		if _, err := lib.ScaffoldTransformer("Kubernetes", "MyTransformer", t.TempDir()); err == nil {
+			t.Fatalf("Expected an error for an unsupported class")
+		}
+	})
+}
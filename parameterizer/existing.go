@@ -0,0 +1,389 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/k8sschema"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+)
+
+var (
+	yamlDocumentSeparatorRegex = regexp.MustCompile(`(?m)^(---|\.\.\.)\s*$`)
+	helmDefineRegex            = regexp.MustCompile(`define\s+"([^"]+)"`)
+	// KustomizationFileNames are the names of the files which make a directory a kustomization
+	KustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+)
+
+// mergeIntoExistingKustomize adds the new resources to the base of an existing kustomization.
+// The overlays of the existing kustomization are not changed.
+func mergeIntoExistingKustomize(srcDir, kustDir string, pathedKs map[string][]parameterizertypes.K8sResourceT, packSpecPath parameterizertypes.PackagingSpecPathT) ([]string, error) {
+	existingDir := getExistingPackagingPath(srcDir, packSpecPath.ExistingKustomize)
+	baseDir := filepath.Join(kustDir, "base")
+	filesWritten, err := copyDir(baseDir, existingDir)
+	if err != nil {
+		return filesWritten, fmt.Errorf("failed to copy the existing kustomization at path %s : %s", existingDir, err)
+	}
+	pathedKs = removeExistingResources(pathedKs, renderKustomization(existingDir), getKustomizationNamePrefix(existingDir))
+	kustomizationPath := filepath.Join(baseDir, KustomizationFileNames[0])
+	for _, name := range KustomizationFileNames {
+		if _, err := os.Stat(filepath.Join(baseDir, name)); err == nil {
+			kustomizationPath = filepath.Join(baseDir, name)
+			break
+		}
+	}
+	kustomization := map[string]interface{}{}
+	if _, err := os.Stat(kustomizationPath); err == nil {
+		if err := common.ReadYaml(kustomizationPath, &kustomization); err != nil {
+			return filesWritten, fmt.Errorf("failed to read the existing kustomization at path %s : %s", kustomizationPath, err)
+		}
+	}
+	resources, _ := kustomization["resources"].([]interface{})
+	kPaths := []string{}
+	for kPath := range pathedKs {
+		kPaths = append(kPaths, kPath)
+	}
+	sort.Strings(kPaths)
+	for _, kPath := range kPaths {
+		finalKPath := getNewFilePath(filepath.Join(baseDir, kPath))
+		if err := os.MkdirAll(filepath.Dir(finalKPath), common.DefaultDirectoryPermission); err != nil {
+			return filesWritten, err
+		}
+		for _, k := range pathedKs[kPath] {
+			if err := writeResourceAppendToFile(k, finalKPath); err != nil {
+				return filesWritten, err
+			}
+		}
+		relKPath, err := filepath.Rel(baseDir, finalKPath)
+		if err != nil {
+			return filesWritten, err
+		}
+		resources = append(resources, filepath.ToSlash(relKPath))
+		filesWritten = append(filesWritten, finalKPath)
+	}
+	kustomization["resources"] = resources
+	if err := common.WriteYaml(kustomizationPath, kustomization); err != nil {
+		return filesWritten, err
+	}
+	logrus.Infof("Added %d new resource files to the existing kustomization at path %s . Its overlays are not changed.", len(kPaths), existingDir)
+	return common.MergeStringSlices(filesWritten, kustomizationPath), nil
+}
+
+// getExistingPackagingPath returns the path of an existing helm chart or kustomization. Relative paths are relative to the source directory.
+func getExistingPackagingPath(srcDir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(srcDir, path)
+}
+
+// getKustomizationNamePrefix returns the prefix the kustomization adds to the names of its resources
+func getKustomizationNamePrefix(kustDir string) string {
+	for _, name := range KustomizationFileNames {
+		kustomization := map[string]interface{}{}
+		if err := common.ReadYaml(filepath.Join(kustDir, name), &kustomization); err != nil {
+			continue
+		}
+		namePrefix, _ := kustomization["namePrefix"].(string)
+		return namePrefix
+	}
+	return ""
+}
+
+// renderHelmChart renders the templates of an existing helm chart using the helm template engine
+func renderHelmChart(chartDir, releaseName string) []parameterizertypes.K8sResourceT {
+	chrt, err := loader.Load(chartDir)
+	if err != nil {
+		logrus.Warnf("Failed to load the existing helm chart at path %s , so the same resources might be generated again. Error: %q", chartDir, err)
+		return nil
+	}
+	if err := chartutil.ProcessDependencies(chrt, chrt.Values); err != nil {
+		logrus.Warnf("Failed to process the dependencies of the existing helm chart at path %s : %s", chartDir, err)
+	}
+	options := chartutil.ReleaseOptions{Name: releaseName, Namespace: "default", Revision: 1, IsInstall: true}
+	values, err := chartutil.ToRenderValues(chrt, chrt.Values, options, chartutil.DefaultCapabilities)
+	if err != nil {
+		logrus.Warnf("Failed to get the values of the existing helm chart at path %s , so the same resources might be generated again. Error: %q", chartDir, err)
+		return nil
+	}
+	templates, err := engine.Render(chrt, values)
+	if err != nil {
+		logrus.Warnf("Failed to render the existing helm chart at path %s , so the same resources might be generated again. Error: %q", chartDir, err)
+		return nil
+	}
+	templatePaths := []string{}
+	for templatePath := range templates {
+		// The partials like _helpers.tpl do not contain resources
+		if strings.HasPrefix(filepath.Base(templatePath), "_") {
+			continue
+		}
+		templatePaths = append(templatePaths, templatePath)
+	}
+	sort.Strings(templatePaths)
+	ks := []parameterizertypes.K8sResourceT{}
+	for _, templatePath := range templatePaths {
+		ks = append(ks, getK8sResourcesFromYamls([]byte(templates[templatePath]))...)
+	}
+	return ks
+}
+
+// renderKustomization renders an existing kustomization using kustomize.
+// If it cannot be built, like when it has remote bases, the resources in the directory of the kustomization are used.
+func renderKustomization(kustDir string) []parameterizertypes.K8sResourceT {
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(filesys.MakeFsOnDisk(), kustDir)
+	if err == nil {
+		yamlBytes, err := resMap.AsYaml()
+		if err == nil {
+			return getK8sResourcesFromYamls(yamlBytes)
+		}
+		logrus.Debugf("Failed to convert the resources of the kustomization at path %s to yaml : %s", kustDir, err)
+	} else {
+		logrus.Warnf("Failed to build the kustomization at path %s . Using the resources in its directory. Error: %q", kustDir, err)
+	}
+	yamlPaths, err := common.GetFilesByExt(kustDir, []string{".yaml", ".yml"})
+	if err != nil {
+		logrus.Warnf("Failed to get the resources of the kustomization at path %s : %s", kustDir, err)
+		return nil
+	}
+	ks := []parameterizertypes.K8sResourceT{}
+	for _, yamlPath := range yamlPaths {
+		if common.IsStringPresent(KustomizationFileNames, filepath.Base(yamlPath)) {
+			continue
+		}
+		data, err := ioutil.ReadFile(yamlPath)
+		if err != nil {
+			logrus.Debugf("Failed to read the file at path %s : %s", yamlPath, err)
+			continue
+		}
+		ks = append(ks, getK8sResourcesFromYamls(data)...)
+	}
+	return ks
+}
+
+// getK8sResourcesFromYamls decodes the k8s resources in a yaml with multiple documents
+func getK8sResourcesFromYamls(data []byte) []parameterizertypes.K8sResourceT {
+	ks := []parameterizertypes.K8sResourceT{}
+	for _, document := range yamlDocumentSeparatorRegex.Split(string(data), -1) {
+		k := parameterizertypes.K8sResourceT{}
+		if err := yaml.Unmarshal([]byte(document), &k); err != nil {
+			logrus.Debugf("Failed to decode the yaml document %s : %s", document, err)
+			continue
+		}
+		if _, _, _, err := k8sschema.GetInfoFromK8sResource(k); err != nil {
+			continue
+		}
+		ks = append(ks, k)
+	}
+	return ks
+}
+
+// removeExistingResources removes the resources which are already deployed by the existing helm chart or kustomization.
+// The names of the existing resources might have the prefix added while rendering, like the name of the helm release.
+func removeExistingResources(pathedKs map[string][]parameterizertypes.K8sResourceT, existingKs []parameterizertypes.K8sResourceT, namePrefix string) map[string][]parameterizertypes.K8sResourceT {
+	newPathedKs := map[string][]parameterizertypes.K8sResourceT{}
+	for kPath, ks := range pathedKs {
+		for _, k := range ks {
+			kind, _, name, err := k8sschema.GetInfoFromK8sResource(k)
+			if err == nil && isExistingResource(kind, name, namePrefix, existingKs) {
+				logrus.Infof("The %s %s already exists. Keeping the existing one.", kind, name)
+				continue
+			}
+			newPathedKs[kPath] = append(newPathedKs[kPath], k)
+		}
+	}
+	return newPathedKs
+}
+
+func isExistingResource(kind, name, namePrefix string, existingKs []parameterizertypes.K8sResourceT) bool {
+	for _, existingK := range existingKs {
+		existingKind, _, existingName, err := k8sschema.GetInfoFromK8sResource(existingK)
+		if err != nil || existingKind != kind {
+			continue
+		}
+		if existingName == name || (namePrefix != "" && existingName == namePrefix+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// getNewFilePath returns the path, or a numbered path if a file already exists at the path
+func getNewFilePath(path string) string {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return path
+	}
+	ext := filepath.Ext(path)
+	for i := 1; ; i++ {
+		newPath := fmt.Sprintf("%s-%d%s", strings.TrimSuffix(path, ext), i, ext)
+		if _, err := os.Stat(newPath); os.IsNotExist(err) {
+			return newPath
+		}
+	}
+}
+
+// copyDir copies all the files in a directory and returns the paths of the copied files
+func copyDir(dst, src string) ([]string, error) {
+	filesWritten := []string{}
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		dstPath := filepath.Join(dst, relPath)
+		if info.IsDir() {
+			return os.MkdirAll(dstPath, common.DefaultDirectoryPermission)
+		}
+		if err := common.CopyFile(dstPath, path); err != nil {
+			return err
+		}
+		filesWritten = append(filesWritten, dstPath)
+		return nil
+	})
+	return filesWritten, err
+}
+
+// mergeYamlFile writes the data to a yaml file. The keys already in the file take precedence.
+func mergeYamlFile(path string, data map[string]interface{}) error {
+	if _, err := os.Stat(path); err != nil {
+		return common.WriteYaml(path, data)
+	}
+	existingData := map[string]interface{}{}
+	if err := common.ReadYaml(path, &existingData); err != nil {
+		return fmt.Errorf("failed to read the existing yaml file at path %s : %s", path, err)
+	}
+	mergeMissingKeys(existingData, data)
+	return common.WriteYaml(path, existingData)
+}
+
+// mergeMissingKeys adds the keys of the source which are missing in the destination
+func mergeMissingKeys(dst, src map[string]interface{}) {
+	for key, value := range src {
+		dstValue, ok := dst[key]
+		if !ok {
+			dst[key] = value
+			continue
+		}
+		dstMap, ok := toMap(dstValue)
+		if !ok {
+			continue
+		}
+		if srcMap, ok := toMap(value); ok {
+			mergeMissingKeys(dstMap, srcMap)
+			dst[key] = dstMap
+		}
+	}
+}
+
+func toMap(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case parameterizertypes.HelmValuesT:
+		return map[string]interface{}(v), true
+	case map[string]interface{}:
+		return v, true
+	}
+	return nil, false
+}
+
+// mergeHelmChartFile writes the Chart.yaml. An existing Chart.yaml is kept and only the missing dependencies are added to it.
+func mergeHelmChartFile(path string, chart helmChartT) error {
+	if _, err := os.Stat(path); err != nil {
+		return common.WriteYaml(path, chart)
+	}
+	existingChart := map[string]interface{}{}
+	if err := common.ReadYaml(path, &existingChart); err != nil {
+		return fmt.Errorf("failed to read the existing Chart.yaml at path %s : %s", path, err)
+	}
+	dependencies, _ := existingChart["dependencies"].([]interface{})
+	existingNames := []string{}
+	for _, dependencyI := range dependencies {
+		if dependency, ok := dependencyI.(map[string]interface{}); ok {
+			name, _ := dependency["name"].(string)
+			if alias, ok := dependency["alias"].(string); ok && alias != "" {
+				name = alias
+			}
+			existingNames = append(existingNames, name)
+		}
+	}
+	for _, dependency := range chart.Dependencies {
+		name := dependency.Name
+		if dependency.Alias != "" {
+			name = dependency.Alias
+		}
+		if common.IsStringPresent(existingNames, name) {
+			continue
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	if len(dependencies) != 0 {
+		existingChart["dependencies"] = dependencies
+	}
+	return common.WriteYaml(path, existingChart)
+}
+
+// writeHelmHelpers appends the helpers which are not defined in the templates of the chart to the _helpers.tpl
+func writeHelmHelpers(templatesDir, chartName string) (string, error) {
+	helpersPath := filepath.Join(templatesDir, "_helpers.tpl")
+	tplPaths, err := common.GetFilesByExt(templatesDir, []string{".tpl"})
+	if err != nil {
+		return "", err
+	}
+	defined := []string{}
+	for _, tplPath := range tplPaths {
+		tpl, err := ioutil.ReadFile(tplPath)
+		if err != nil {
+			return "", err
+		}
+		for _, match := range helmDefineRegex.FindAllStringSubmatch(string(tpl), -1) {
+			defined = append(defined, match[1])
+		}
+	}
+	helpers := []string{}
+	// Each helper is a comment followed by its definition
+	for _, helper := range strings.Split(strings.TrimSuffix(getHelmHelpers(chartName), "\n"), "\n\n") {
+		match := helmDefineRegex.FindStringSubmatch(helper)
+		if match != nil && common.IsStringPresent(defined, match[1]) {
+			continue
+		}
+		helpers = append(helpers, helper)
+	}
+	if len(helpers) == 0 {
+		return "", nil
+	}
+	data := strings.Join(helpers, "\n\n") + "\n"
+	if existingData, err := ioutil.ReadFile(helpersPath); err == nil {
+		data = strings.TrimSuffix(string(existingData), "\n") + "\n\n" + data
+	}
+	return helpersPath, ioutil.WriteFile(helpersPath, []byte(data), common.DefaultFilePermission)
+}
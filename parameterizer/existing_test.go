@@ -0,0 +1,161 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/parameterizer"
+	"github.com/konveyor/move2kube/qaengine"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), common.DefaultDirectoryPermission); err != nil {
+			t.Fatalf("Failed to create the directory of the file %s. Error: %q", name, err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), common.DefaultFilePermission); err != nil {
+			t.Fatalf("Failed to write the file %s. Error: %q", name, err)
+		}
+	}
+}
+
+func TestParameterizeExistingHelmChart(t *testing.T) {
+	qaengine.StartEngine(true, 0, true)
+	qaengine.SetupConfigFile("", []string{`move2kube.services."db".helmchart=true`}, nil, nil)
+	srcDir := t.TempDir()
+	writeFiles(t, srcDir, map[string]string{
+		filepath.Join("yamls", "web-deployment.yaml"): webYaml,
+		filepath.Join("yamls", "db.yaml"):             dbYaml,
+		filepath.Join("chart", "Chart.yaml"):          "apiVersion: v2\nname: shop\nversion: 2.0.0\nmaintainers:\n  - name: shop-team\n",
+		filepath.Join("chart", "values.yaml"):         "replicas: 5\nnameOverride: store\n",
+		filepath.Join("chart", "templates", "_helpers.tpl"): `{{- define "shop.name" -}}
+{{- .Chart.Name }}
+{{- end }}
+`,
+		filepath.Join("chart", "templates", "web-deployment.yaml"): "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web-settings\n",
+	})
+	outDir := t.TempDir()
+	ps := []parameterizertypes.ParameterizerT{{Target: "spec.replicas", Template: "${replicas}", Filters: []parameterizertypes.FilterT{{Kind: common.DeploymentKind}}}}
+	packSpecPath := parameterizertypes.PackagingSpecPathT{Src: "yamls", ExistingHelmChart: "chart", Envs: []string{"dev"}}
+	if _, err := parameterizer.Parameterize(srcDir, outDir, packSpecPath, ps); err != nil {
+		t.Fatalf("Failed to parameterize. Error: %q", err)
+	}
+	chartDir := filepath.Join(outDir, "helm-chart", "shop")
+	readFile := func(path string) string {
+		data, err := ioutil.ReadFile(filepath.Join(chartDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read the file %s. Error: %q", path, err)
+		}
+		return string(data)
+	}
+
+	t.Run("existing chart with the new dependencies", func(t *testing.T) {
+		chart := readFile("Chart.yaml")
+		if !strings.Contains(chart, "version: 2.0.0") || !strings.Contains(chart, "shop-team") || !strings.Contains(chart, "alias: db") {
+			t.Fatalf("Expected the existing Chart.yaml with the postgresql dependency. Actual: %s", chart)
+		}
+		values := readFile("values.yaml")
+		if !strings.Contains(values, "replicas: 5") || !strings.Contains(values, "nameOverride: store") || !strings.Contains(values, "fullnameOverride: db") {
+			t.Fatalf("Expected the existing values with the values of the postgresql chart. Actual: %s", values)
+		}
+	})
+
+	t.Run("existing templates are kept", func(t *testing.T) {
+		if settings := readFile(filepath.Join("templates", "web-deployment.yaml")); !strings.Contains(settings, "web-settings") || strings.Contains(settings, "kind: Deployment") {
+			t.Fatalf("Expected the existing template to be unchanged. Actual: %s", settings)
+		}
+		if web := readFile(filepath.Join("templates", "web-deployment-1.yaml")); !strings.Contains(web, "kind: Deployment") {
+			t.Fatalf("Expected the web deployment in a new template. Actual: %s", web)
+		}
+		helpers := readFile(filepath.Join("templates", "_helpers.tpl"))
+		if strings.Count(helpers, `define "shop.name"`) != 1 || !strings.Contains(helpers, `define "shop.chart"`) {
+			t.Fatalf("Expected only the missing helpers to be added. Actual: %s", helpers)
+		}
+	})
+}
+
+func TestParameterizeExistingKustomize(t *testing.T) {
+	qaengine.StartEngine(true, 0, true)
+	srcDir := t.TempDir()
+	writeFiles(t, srcDir, map[string]string{
+		filepath.Join("yamls", "orders-deployment.yaml"):      ordersYaml,
+		filepath.Join("yamls", "orders-config.yaml"):          ordersConfigYaml,
+		filepath.Join("deploy", "base", "kustomization.yaml"): "resources:\n  - orders.yaml\n  - legacy.yaml\ncommonLabels:\n  app: shop\n",
+		filepath.Join("deploy", "base", "orders.yaml"):        ordersYaml,
+		// The config map of the new resources is not the same as this one, even if the name ends with its name
+		filepath.Join("deploy", "base", "legacy.yaml"): "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: legacy-orders-config\n",
+	})
+	outDir := t.TempDir()
+	packSpecPath := parameterizertypes.PackagingSpecPathT{Src: "yamls", ExistingKustomize: filepath.Join("deploy", "base"), Envs: []string{"dev"}}
+	if _, err := parameterizer.Parameterize(srcDir, outDir, packSpecPath, nil); err != nil {
+		t.Fatalf("Failed to parameterize. Error: %q", err)
+	}
+	baseDir := filepath.Join(outDir, "kustomize", "base")
+	kustomization := map[string]interface{}{}
+	if err := common.ReadYaml(filepath.Join(baseDir, "kustomization.yaml"), &kustomization); err != nil {
+		t.Fatalf("Failed to read the kustomization. Error: %q", err)
+	}
+	resources, ok := kustomization["resources"].([]interface{})
+	if !ok || len(resources) != 3 || resources[0] != "orders.yaml" || resources[2] != "orders-config.yaml" {
+		t.Fatalf("Expected only the config map to be added to the resources. Actual: %+v", kustomization["resources"])
+	}
+	if kustomization["commonLabels"] == nil {
+		t.Fatalf("Expected the existing fields of the kustomization to be kept. Actual: %+v", kustomization)
+	}
+	if _, err := os.Stat(filepath.Join(baseDir, "orders-deployment.yaml")); err == nil {
+		t.Fatalf("Expected the existing orders deployment not to be generated again")
+	}
+}
+
+func TestParameterizeExistingHelmChartWithReleaseNames(t *testing.T) {
+	qaengine.StartEngine(true, 0, true)
+	srcDir := t.TempDir()
+	writeFiles(t, srcDir, map[string]string{
+		filepath.Join("yamls", "orders-deployment.yaml"): ordersYaml,
+		filepath.Join("yamls", "orders-config.yaml"):     ordersConfigYaml,
+		filepath.Join("chart", "Chart.yaml"):             "apiVersion: v2\nname: shop\nversion: 2.0.0\n",
+		filepath.Join("chart", "values.yaml"):            "replicas: 2\n",
+		filepath.Join("chart", "templates", "deployment.yaml"): `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}-orders
+spec:
+  replicas: {{ .Values.replicas }}
+`,
+		filepath.Join("chart", "templates", "legacy.yaml"): "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: legacy-orders-config\n",
+	})
+	outDir := t.TempDir()
+	packSpecPath := parameterizertypes.PackagingSpecPathT{Src: "yamls", ExistingHelmChart: "chart", Envs: []string{"dev"}}
+	if _, err := parameterizer.Parameterize(srcDir, outDir, packSpecPath, nil); err != nil {
+		t.Fatalf("Failed to parameterize. Error: %q", err)
+	}
+	templatesDir := filepath.Join(outDir, "helm-chart", "shop", "templates")
+	if _, err := os.Stat(filepath.Join(templatesDir, "orders-deployment.yaml")); err == nil {
+		t.Fatalf("Expected the deployment rendered with the release name not to be generated again")
+	}
+	if _, err := os.Stat(filepath.Join(templatesDir, "orders-config.yaml")); err != nil {
+		t.Fatalf("Expected the config map to be added to the templates. Error: %q", err)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer
+
+import (
+	"fmt"
+
+	"github.com/konveyor/move2kube/k8sschema"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+	starutil "github.com/qri-io/starlib/util"
+	"go.starlark.net/starlark"
+)
+
+const (
+	// The variables and functions available in the starlark expressions
+	exprResourceVarName   = "resource"
+	exprKindVarName       = "kind"
+	exprAPIVersionVarName = "apiVersion"
+	exprNameVarName       = "name"
+	exprEnvsVarName       = "envs"
+	exprEnvVarName        = "env"
+	exprValueVarName      = "value"
+	exprMatchesVarName    = "matches"
+	exprHasFnName         = "has"
+	exprGetFnName         = "get"
+	// exprMaxExecutionSteps stops the expressions which run for too long, like the starlark transformers do
+	exprMaxExecutionSteps = 100000000
+)
+
+// evalParameterExpression computes the value of the parameter for an env using the expression, or else the expression of the parameter.
+// Without an expression, the value is returned as it is.
+func evalParameterExpression(expr string, parameters []parameterizertypes.ParameterT, parameter, env string, value interface{}, k parameterizertypes.K8sResourceT, matches map[string]string) (interface{}, error) {
+	if expr == "" {
+		for _, param := range parameters {
+			if param.Name == parameter {
+				expr = param.Expression
+				break
+			}
+		}
+	}
+	if expr == "" {
+		return value, nil
+	}
+	return evalValueExpression(expr, env, value, k, matches)
+}
+
+// evalFilterExpression evaluates the starlark expression of a filter, which decides if the parameterizer is applied to the resource
+func evalFilterExpression(expr string, envs []string, k parameterizertypes.K8sResourceT) (bool, error) {
+	envsI := []interface{}{}
+	for _, env := range envs {
+		envsI = append(envsI, env)
+	}
+	result, err := evalExpression(expr, k, map[string]interface{}{exprEnvsVarName: envsI})
+	if err != nil {
+		return false, err
+	}
+	ok, isBool := result.(bool)
+	if !isBool {
+		return false, fmt.Errorf("the filter expression %s returned %+v of type %T instead of a bool", expr, result, result)
+	}
+	return ok, nil
+}
+
+// evalValueExpression evaluates the starlark expression which computes the value of a parameter for an env.
+// The expression gets the value of the parameter from the default, the question and the values of the env.
+func evalValueExpression(expr, env string, value interface{}, k parameterizertypes.K8sResourceT, matches map[string]string) (interface{}, error) {
+	matchesI := map[string]interface{}{}
+	for key, match := range matches {
+		matchesI[key] = match
+	}
+	return evalExpression(expr, k, map[string]interface{}{exprEnvVarName: env, exprValueVarName: value, exprMatchesVarName: matchesI})
+}
+
+// evalExpression evaluates a starlark expression with the resource, its kind, apiVersion and name and the given variables
+func evalExpression(expr string, k parameterizertypes.K8sResourceT, vars map[string]interface{}) (interface{}, error) {
+	kind, apiVersion, name, err := k8sschema.GetInfoFromK8sResource(k)
+	if err != nil {
+		return nil, err
+	}
+	vars[exprResourceVarName] = map[string]interface{}(k)
+	vars[exprKindVarName] = kind
+	vars[exprAPIVersionVarName] = apiVersion
+	vars[exprNameVarName] = name
+	globals := starlark.StringDict{
+		exprHasFnName: starlark.NewBuiltin(exprHasFnName, getHasFn(k)),
+		exprGetFnName: starlark.NewBuiltin(exprGetFnName, getGetFn(k)),
+	}
+	for varName, value := range vars {
+		starValue, err := starutil.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert the variable %s to starlark : %s", varName, err)
+		}
+		globals[varName] = starValue
+	}
+	thread := &starlark.Thread{Name: "parameterizer"}
+	thread.SetMaxExecutionSteps(exprMaxExecutionSteps)
+	result, err := starlark.Eval(thread, "expression", expr, globals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate the expression %s : %s", expr, err)
+	}
+	value, err := starutil.Unmarshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the result of the expression %s : %s", expr, err)
+	}
+	return value, nil
+}
+
+// getHasFn returns the function which checks if a key, in the syntax of the targets, exists in the resource
+func getHasFn(k parameterizertypes.K8sResourceT) func(*starlark.Thread, *starlark.Builtin, starlark.Tuple, []starlark.Tuple) (starlark.Value, error) {
+	return func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var key string
+		if err := starlark.UnpackArgs(b.Name(), args, kwargs, "key", &key); err != nil {
+			return starlark.None, err
+		}
+		results, err := GetAll(key, k)
+		return starlark.Bool(err == nil && len(results) != 0), nil
+	}
+}
+
+// getGetFn returns the function which gets the value of a key, in the syntax of the targets, or the default if the key does not exist
+func getGetFn(k parameterizertypes.K8sResourceT) func(*starlark.Thread, *starlark.Builtin, starlark.Tuple, []starlark.Tuple) (starlark.Value, error) {
+	return func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var key string
+		var def starlark.Value = starlark.None
+		if err := starlark.UnpackArgs(b.Name(), args, kwargs, "key", &key, "default?", &def); err != nil {
+			return starlark.None, err
+		}
+		results, err := GetAll(key, k)
+		if err != nil || len(results) == 0 {
+			return def, nil
+		}
+		return starutil.Marshal(results[0].Value)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/parameterizer"
+	"github.com/konveyor/move2kube/qaengine"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+)
+
+func TestParameterizeExpressions(t *testing.T) {
+	qaengine.StartEngine(true, 0, true)
+	srcDir := t.TempDir()
+	writeFiles(t, srcDir, map[string]string{"orders-deployment.yaml": ordersYaml, "orders-config.yaml": ordersConfigYaml})
+	outDir := t.TempDir()
+	// The config map does not have replicas, so the filter skips it
+	withReplicas := []parameterizertypes.FilterT{{Expression: `has("spec.replicas")`}}
+	ps := []parameterizertypes.ParameterizerT{
+		{Target: "spec.replicas", Template: "${replicas}", Expression: `3 if env == "prod" else value`, Filters: withReplicas},
+		{Target: "spec.template.spec.containers.[name=orders].env.[name=LOG_LEVEL].value", Template: "${loglevel}", Filters: []parameterizertypes.FilterT{{Kind: common.DeploymentKind}},
+			Parameters: []parameterizertypes.ParameterT{{Name: "loglevel", Expression: `"warn" if env == "prod" else get("metadata.name") + "-" + value`}}},
+	}
+	packSpecPath := parameterizertypes.PackagingSpecPathT{Envs: []string{"dev", "prod"}}
+	if _, err := parameterizer.Parameterize(srcDir, outDir, packSpecPath, ps); err != nil {
+		t.Fatalf("Failed to parameterize. Error: %q", err)
+	}
+	readFile := func(path string) string {
+		data, err := ioutil.ReadFile(filepath.Join(outDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read the file %s. Error: %q", path, err)
+		}
+		return string(data)
+	}
+
+	t.Run("helm values", func(t *testing.T) {
+		if dev := readFile(filepath.Join("helm-chart", common.DefaultProjectName, "values-dev.yaml")); !strings.Contains(dev, "replicas: 1") || !strings.Contains(dev, "loglevel: orders-info") {
+			t.Fatalf("Expected the values of dev to be computed from the resource. Actual: %s", dev)
+		}
+		if prod := readFile(filepath.Join("helm-chart", common.DefaultProjectName, "values-prod.yaml")); !strings.Contains(prod, "replicas: 3") || !strings.Contains(prod, "loglevel: warn") {
+			t.Fatalf("Expected the values of prod to be computed for prod. Actual: %s", prod)
+		}
+	})
+
+	t.Run("kustomize patches", func(t *testing.T) {
+		if prod := readFile(filepath.Join("kustomize", "overlays", "prod", "apps-v1-deployment-orders.yaml")); !strings.Contains(prod, "value: 3") || !strings.Contains(prod, "value: warn") {
+			t.Fatalf("Expected the patches of prod to use the computed values. Actual: %s", prod)
+		}
+	})
+
+	t.Run("openshift template parameters", func(t *testing.T) {
+		if prod := readFile(filepath.Join("openshift-template", "parameters-prod.yaml")); !strings.Contains(prod, "REPLICAS=3") || !strings.Contains(prod, "LOGLEVEL=warn") {
+			t.Fatalf("Expected the parameters of prod to use the computed values. Actual: %s", prod)
+		}
+	})
+
+	t.Run("runaway expressions are stopped", func(t *testing.T) {
+		runaway := []parameterizertypes.ParameterizerT{{Target: "spec.replicas", Template: "${replicas}", Filters: []parameterizertypes.FilterT{{Expression: `len([x for x in range(100000) for y in range(100000) if False]) == 0`}}}}
+		done := make(chan error)
+		go func() {
+			_, err := parameterizer.Parameterize(srcDir, t.TempDir(), packSpecPath, runaway)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			if err == nil || !strings.Contains(err.Error(), "too many steps") {
+				t.Fatalf("Expected the expression to fail after too many steps. Actual: %v", err)
+			}
+		case <-time.After(time.Minute):
+			t.Fatalf("Expected the expression to be stopped after the maximum execution steps")
+		}
+	})
+}
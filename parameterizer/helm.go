@@ -0,0 +1,572 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/deepcopy"
+	"github.com/konveyor/move2kube/k8sschema"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/konveyor/move2kube/types"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	helmChartAPIVersion     = "v2"
+	defaultHelmChartVersion = "0.1.0"
+	bitnamiHelmRepository   = "https://charts.bitnami.com/bitnami"
+	jsonSchemaVersion       = "http://json-schema.org/draft-07/schema#"
+	// serviceLabel is the label move2kube adds to the resources of a service
+	serviceLabel = types.GroupName + "/service"
+)
+
+var (
+	workloadKinds = []string{common.DeploymentKind, "StatefulSet", "DeploymentConfig"}
+	// backingServiceCharts are the bitnami charts which replace the workloads of the backing services, keyed by image name
+	backingServiceCharts = map[string]backingServiceChartT{
+		"postgres":   {chart: "postgresql", version: "12.x.x"},
+		"postgresql": {chart: "postgresql", version: "12.x.x"},
+		"mysql":      {chart: "mysql", version: "9.x.x"},
+		"mariadb":    {chart: "mariadb", version: "11.x.x"},
+		"mongo":      {chart: "mongodb", version: "13.x.x"},
+		"mongodb":    {chart: "mongodb", version: "13.x.x"},
+		"redis":      {chart: "redis", version: "17.x.x", values: map[string]interface{}{"architecture": "standalone"}},
+		"rabbitmq":   {chart: "rabbitmq", version: "11.x.x"},
+	}
+)
+
+// helmChartT is the Chart.yaml of a helm chart
+type helmChartT struct {
+	APIVersion   string            `yaml:"apiVersion"`
+	Name         string            `yaml:"name"`
+	Version      string            `yaml:"version"`
+	AppVersion   string            `yaml:"appVersion,omitempty"`
+	Description  string            `yaml:"description"`
+	Keywords     []string          `yaml:"keywords"`
+	Dependencies []helmDependencyT `yaml:"dependencies,omitempty"`
+}
+
+// helmDependencyT is a dependency of a helm chart. The charts without a repository are in the charts directory.
+type helmDependencyT struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Repository string `yaml:"repository,omitempty"`
+	Alias      string `yaml:"alias,omitempty"`
+	Condition  string `yaml:"condition,omitempty"`
+}
+
+// backingServiceChartT is the chart which deploys a backing service
+type backingServiceChartT struct {
+	chart   string
+	version string
+	values  map[string]interface{}
+}
+
+// createHelmChart creates a helm chart, or an umbrella chart with a subchart for each service, with multiple values.yaml
+func createHelmChart(srcDir, helmDir string, pathedKs map[string][]parameterizertypes.K8sResourceT, packSpecPath parameterizertypes.PackagingSpecPathT, ps []parameterizertypes.ParameterizerT) ([]string, error) {
+	chartName := packSpecPath.HelmChartName
+	if chartName == "" {
+		chartName = common.DefaultProjectName
+	}
+	version, appVersion := getHelmChartVersions(srcDir, packSpecPath)
+	existingChartDir := ""
+	if packSpecPath.ExistingHelmChart != "" {
+		existingChartDir = getExistingPackagingPath(srcDir, packSpecPath.ExistingHelmChart)
+		existingChart := helmChartT{}
+		if err := common.ReadYaml(filepath.Join(existingChartDir, "Chart.yaml"), &existingChart); err != nil {
+			return nil, fmt.Errorf("failed to read the Chart.yaml of the existing helm chart at path %s : %s", existingChartDir, err)
+		}
+		chartName = existingChart.Name
+		// The existing chart is rendered with the chart name as the release name, so the names of its resources might start with it
+		pathedKs = removeExistingResources(pathedKs, renderHelmChart(existingChartDir, chartName), chartName+"-")
+	}
+	services := getServices(pathedKs)
+	defaultValues := parameterizertypes.HelmValuesT{}
+	dependencies := []helmDependencyT{}
+	backingServices := getBackingServices(pathedKs, services)
+	for _, sn := range services {
+		backingServiceChart, ok := backingServices[sn]
+		if !ok {
+			continue
+		}
+		dependencies = append(dependencies, helmDependencyT{
+			Name:       backingServiceChart.chart,
+			Version:    backingServiceChart.version,
+			Repository: bitnamiHelmRepository,
+			Alias:      sn,
+			Condition:  sn + ".enabled",
+		})
+		// The service keeps its name, so that the other services can connect to it
+		values := map[string]interface{}{"enabled": true, "fullnameOverride": sn}
+		for k, v := range backingServiceChart.values {
+			values[k] = v
+		}
+		defaultValues[sn] = values
+	}
+	groupedKs := groupResourcesByService(pathedKs, services)
+	for sn := range backingServices {
+		logrus.Infof("The service %s is deployed using the %s helm chart. Configure its credentials in the values of the chart.", sn, backingServices[sn].chart)
+		delete(groupedKs, sn)
+	}
+	chart := helmChartT{
+		APIVersion:  helmChartAPIVersion,
+		Name:        chartName,
+		Version:     version,
+		AppVersion:  appVersion,
+		Description: "A Helm Chart generated by Move2Kube for " + chartName,
+		Keywords:    []string{chartName},
+	}
+	chartDir := filepath.Join(helmDir, chartName)
+	filesWritten := []string{}
+	if existingChartDir != "" {
+		// The new resources are merged into a copy of the existing chart
+		copiedFiles, err := copyDir(chartDir, existingChartDir)
+		filesWritten = append(filesWritten, copiedFiles...)
+		if err != nil {
+			return filesWritten, fmt.Errorf("failed to copy the existing helm chart at path %s : %s", existingChartDir, err)
+		}
+	}
+	umbrella := false
+	// The resources which do not belong to any service are grouped under the empty name
+	if existingChartDir == "" && len(groupedKs)-1 > 1 {
+		hints := []string{"Each service gets its own chart, which can be installed and upgraded on its own"}
+		umbrella = qaengine.FetchBoolAnswer(common.ConfigHelmUmbrellaKey, "Should an umbrella helm chart with a subchart for each service be created?", hints, false)
+	}
+	if !umbrella {
+		allKs := map[string][]parameterizertypes.K8sResourceT{}
+		for _, serviceKs := range groupedKs {
+			for kPath, ks := range serviceKs {
+				allKs[kPath] = append(allKs[kPath], ks...)
+			}
+		}
+		chart.Dependencies = dependencies
+		_, chartFilesWritten, err := writeHelmChart(chartDir, chart, allKs, packSpecPath.Envs, ps, defaultValues, nil)
+		return common.MergeStringSlices(filesWritten, chartFilesWritten...), err
+	}
+	subchartsValues := map[string]parameterizertypes.HelmValuesT{}
+	for _, sn := range services {
+		if _, ok := groupedKs[sn]; !ok {
+			continue
+		}
+		subchart := chart
+		subchart.Name = sn
+		subchart.Description = "A Helm Chart generated by Move2Kube for the service " + sn
+		subchart.Keywords = []string{chartName, sn}
+		namedValues, subchartFilesWritten, err := writeHelmChart(filepath.Join(chartDir, "charts", sn), subchart, groupedKs[sn], packSpecPath.Envs, ps, nil, nil)
+		filesWritten = append(filesWritten, subchartFilesWritten...)
+		if err != nil {
+			return filesWritten, err
+		}
+		for env, values := range namedValues {
+			if _, ok := subchartsValues[env]; !ok {
+				subchartsValues[env] = parameterizertypes.HelmValuesT{}
+			}
+			subchartsValues[env][sn] = values
+		}
+		dependencies = append(dependencies, helmDependencyT{Name: sn, Version: version, Condition: sn + ".enabled"})
+		defaultValues[sn] = map[string]interface{}{"enabled": true}
+	}
+	chart.Dependencies = dependencies
+	_, umbrellaFilesWritten, err := writeHelmChart(chartDir, chart, groupedKs[""], packSpecPath.Envs, ps, defaultValues, subchartsValues)
+	return append(filesWritten, umbrellaFilesWritten...), err
+}
+
+// writeHelmChart parameterizes the resources and writes a helm chart with them.
+// The default values go in the values.yaml and the extra values are added to the values of each env.
+// The files already in the chart directory are kept, and the new values, helpers and dependencies are merged into them.
+func writeHelmChart(chartDir string, chart helmChartT, pathedKs map[string][]parameterizertypes.K8sResourceT, envs []string, ps []parameterizertypes.ParameterizerT, defaultValues parameterizertypes.HelmValuesT, extraNamedValues map[string]parameterizertypes.HelmValuesT) (map[string]parameterizertypes.HelmValuesT, []string, error) {
+	filesWritten := []string{}
+	namedValues := map[string]parameterizertypes.HelmValuesT{}
+	templatesDir := filepath.Join(chartDir, "templates")
+	if err := os.MkdirAll(templatesDir, common.DefaultDirectoryPermission); err != nil {
+		return namedValues, filesWritten, err
+	}
+	for kPath, ks := range pathedKs {
+		finalKPath := getNewFilePath(filepath.Join(templatesDir, kPath))
+		for _, k := range ks {
+			k = deepcopy.DeepCopy(k).(parameterizertypes.K8sResourceT)
+			if err := parameterize(parameterizertypes.TargetHelm, envs, k, ps, namedValues, nil, nil); err != nil {
+				return namedValues, filesWritten, err
+			}
+			addHelmLabels(k, chart.Name)
+			if err := writeResourceStripQuotesAndAppendToFile(k, finalKPath); err != nil {
+				return namedValues, filesWritten, err
+			}
+			filesWritten = common.MergeStringSlices(filesWritten, finalKPath)
+		}
+	}
+	for env, extraValues := range extraNamedValues {
+		if _, ok := namedValues[env]; !ok {
+			namedValues[env] = parameterizertypes.HelmValuesT{}
+		}
+		for k, v := range extraValues {
+			namedValues[env][k] = v
+		}
+	}
+	for env, values := range namedValues {
+		finalKPath := filepath.Join(chartDir, "values-"+env+".yaml")
+		if err := mergeYamlFile(finalKPath, values); err != nil {
+			return namedValues, filesWritten, err
+		}
+		filesWritten = append(filesWritten, finalKPath)
+	}
+	if len(defaultValues) != 0 {
+		finalKPath := filepath.Join(chartDir, "values.yaml")
+		if err := mergeYamlFile(finalKPath, defaultValues); err != nil {
+			return namedValues, filesWritten, err
+		}
+		filesWritten = append(filesWritten, finalKPath)
+	}
+	finalKPath := filepath.Join(chartDir, "values.schema.json")
+	schema := map[string]interface{}{}
+	if _, err := os.Stat(finalKPath); err == nil {
+		if err := common.ReadJSON(finalKPath, &schema); err != nil {
+			return namedValues, filesWritten, fmt.Errorf("failed to read the existing values schema at path %s : %s", finalKPath, err)
+		}
+	}
+	if err := common.WriteJSON(finalKPath, getHelmValuesSchema(schema, defaultValues, namedValues)); err != nil {
+		return namedValues, filesWritten, err
+	}
+	filesWritten = common.MergeStringSlices(filesWritten, finalKPath)
+	finalKPath, err := writeHelmHelpers(templatesDir, chart.Name)
+	if err != nil {
+		return namedValues, filesWritten, err
+	}
+	if finalKPath != "" {
+		filesWritten = common.MergeStringSlices(filesWritten, finalKPath)
+	}
+	finalKPath = filepath.Join(templatesDir, "NOTES.txt")
+	if _, err := os.Stat(finalKPath); os.IsNotExist(err) {
+		if err := ioutil.WriteFile(finalKPath, []byte(getHelmNotes(chart)), common.DefaultFilePermission); err != nil {
+			return namedValues, filesWritten, err
+		}
+		filesWritten = append(filesWritten, finalKPath)
+	}
+	finalKPath = filepath.Join(chartDir, "Chart.yaml")
+	if err := mergeHelmChartFile(finalKPath, chart); err != nil {
+		return namedValues, filesWritten, err
+	}
+	filesWritten = common.MergeStringSlices(filesWritten, finalKPath)
+	return namedValues, filesWritten, nil
+}
+
+// getHelmChartVersions returns the chart version and the app version from the packaging spec, or else from the latest git tag of the source
+func getHelmChartVersions(srcDir string, packSpecPath parameterizertypes.PackagingSpecPathT) (version, appVersion string) {
+	version, appVersion = packSpecPath.HelmChartVersion, packSpecPath.HelmAppVersion
+	if version != "" {
+		return version, appVersion
+	}
+	tagVersion, err := common.GetGitTagVersion(srcDir)
+	if err != nil {
+		logrus.Debugf("Unable to get the version of the helm chart from the git tags. Using the version %s : %s", defaultHelmChartVersion, err)
+		return defaultHelmChartVersion, appVersion
+	}
+	if appVersion == "" {
+		appVersion = tagVersion.Original()
+	}
+	return tagVersion.String(), appVersion
+}
+
+// getServices returns the names of the services of the resources
+func getServices(pathedKs map[string][]parameterizertypes.K8sResourceT) []string {
+	services := []string{}
+	for _, ks := range pathedKs {
+		for _, k := range ks {
+			kind, _, name, err := k8sschema.GetInfoFromK8sResource(k)
+			if err != nil {
+				continue
+			}
+			if sn := getLabel(k, serviceLabel); sn != "" {
+				services = common.MergeStringSlices(services, sn)
+			} else if common.IsStringPresent(workloadKinds, kind) {
+				services = common.MergeStringSlices(services, name)
+			}
+		}
+	}
+	sort.Strings(services)
+	return services
+}
+
+// groupResourcesByService groups the resources by the service they belong to.
+// The resources which do not belong to any service are in the group with the empty name.
+func groupResourcesByService(pathedKs map[string][]parameterizertypes.K8sResourceT, services []string) map[string]map[string][]parameterizertypes.K8sResourceT {
+	groupedKs := map[string]map[string][]parameterizertypes.K8sResourceT{"": {}}
+	for kPath, ks := range pathedKs {
+		for _, k := range ks {
+			sn := getResourceService(k, services)
+			if _, ok := groupedKs[sn]; !ok {
+				groupedKs[sn] = map[string][]parameterizertypes.K8sResourceT{}
+			}
+			groupedKs[sn][kPath] = append(groupedKs[sn][kPath], k)
+		}
+	}
+	return groupedKs
+}
+
+func getResourceService(k parameterizertypes.K8sResourceT, services []string) string {
+	if sn := getLabel(k, serviceLabel); common.IsStringPresent(services, sn) {
+		return sn
+	}
+	if _, _, name, err := k8sschema.GetInfoFromK8sResource(k); err == nil && common.IsStringPresent(services, name) {
+		return name
+	}
+	return ""
+}
+
+// getBackingServices returns the services which can be deployed using a bitnami chart, like databases and caches
+func getBackingServices(pathedKs map[string][]parameterizertypes.K8sResourceT, services []string) map[string]backingServiceChartT {
+	backingServices := map[string]backingServiceChartT{}
+	for _, ks := range pathedKs {
+		for _, k := range ks {
+			kind, _, name, err := k8sschema.GetInfoFromK8sResource(k)
+			if err != nil || !common.IsStringPresent(workloadKinds, kind) {
+				continue
+			}
+			images, err := GetAll("spec.template.spec.containers.[name].image", k)
+			if err != nil || len(images) != 1 {
+				continue
+			}
+			image, ok := images[0].Value.(string)
+			if !ok {
+				continue
+			}
+			imageName, _ := common.GetImageNameAndTag(image)
+			backingServiceChart, ok := backingServiceCharts[imageName[strings.LastIndex(imageName, "/")+1:]]
+			if !ok {
+				continue
+			}
+			sn := getResourceService(k, services)
+			if sn == "" {
+				sn = name
+			}
+			key := common.ConfigServicesKey + common.Delim + `"` + sn + `"` + common.Delim + "helmchart"
+			desc := fmt.Sprintf("Should the service %s be deployed using the bitnami %s helm chart instead of its own resources?", sn, backingServiceChart.chart)
+			if !qaengine.FetchBoolAnswer(key, desc, []string{"The chart is added as a dependency of the helm chart"}, false) {
+				continue
+			}
+			backingServices[sn] = backingServiceChart
+		}
+	}
+	return backingServices
+}
+
+// addHelmLabels adds the standard helm labels to the metadata of the resource
+func addHelmLabels(k parameterizertypes.K8sResourceT, chartName string) {
+	metadata, ok := k["metadata"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	labels, ok := metadata["labels"].(map[string]interface{})
+	if !ok {
+		labels = map[string]interface{}{}
+		metadata["labels"] = labels
+	}
+	helmLabels := map[string]string{
+		"helm.sh/chart":                `{{ include "` + chartName + `.chart" . }}`,
+		"app.kubernetes.io/managed-by": "{{ .Release.Service }}",
+		"app.kubernetes.io/instance":   "{{ .Release.Name }}",
+	}
+	for k, v := range helmLabels {
+		if _, ok := labels[k]; !ok {
+			labels[k] = v
+		}
+	}
+}
+
+func getLabel(k parameterizertypes.K8sResourceT, label string) string {
+	metadata, ok := k["metadata"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	labels, ok := metadata["labels"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	value, _ := labels[label].(string)
+	return value
+}
+
+// getHelmValuesSchema adds the values of all the envs to the json schema
+func getHelmValuesSchema(schema map[string]interface{}, defaultValues parameterizertypes.HelmValuesT, namedValues map[string]parameterizertypes.HelmValuesT) map[string]interface{} {
+	if _, ok := schema["type"]; !ok {
+		schema["type"] = "object"
+	}
+	addToJSONSchema(schema, map[string]interface{}(defaultValues))
+	envs := []string{}
+	for env := range namedValues {
+		envs = append(envs, env)
+	}
+	sort.Strings(envs)
+	for _, env := range envs {
+		addToJSONSchema(schema, map[string]interface{}(namedValues[env]))
+	}
+	if _, ok := schema["$schema"]; !ok {
+		schema["$schema"] = jsonSchemaVersion
+	}
+	return schema
+}
+
+// addToJSONSchema adds the type of the value to the json schema. The existing types take precedence.
+func addToJSONSchema(schema map[string]interface{}, value interface{}) {
+	if _, ok := schema["type"]; !ok {
+		if valueType := getJSONSchemaType(value); valueType != "" {
+			schema["type"] = valueType
+		}
+	}
+	switch v := value.(type) {
+	case parameterizertypes.HelmValuesT:
+		addToJSONSchema(schema, map[string]interface{}(v))
+	case map[string]interface{}:
+		if schema["type"] != "object" {
+			return
+		}
+		properties, ok := schema["properties"].(map[string]interface{})
+		if !ok {
+			properties = map[string]interface{}{}
+			schema["properties"] = properties
+		}
+		for key, subValue := range v {
+			property, ok := properties[key].(map[string]interface{})
+			if !ok {
+				property = map[string]interface{}{}
+				properties[key] = property
+			}
+			addToJSONSchema(property, subValue)
+		}
+	case []interface{}:
+		if schema["type"] != "array" || len(v) == 0 {
+			return
+		}
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok {
+			items = map[string]interface{}{}
+			schema["items"] = items
+		}
+		addToJSONSchema(items, v[0])
+	}
+}
+
+func getJSONSchemaType(value interface{}) string {
+	switch v := value.(type) {
+	case parameterizertypes.HelmValuesT, map[string]interface{}:
+		return "object"
+	case []interface{}, []string:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "integer"
+	case float32:
+		return getJSONSchemaType(float64(v))
+	case float64:
+		// The resources are decoded from json, so the integers are floats
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	}
+	return ""
+}
+
+// getHelmHelpers returns the standard name and label helpers of a chart
+func getHelmHelpers(chartName string) string {
+	return strings.ReplaceAll(`{{/*
+Expand the name of the chart.
+*/}}
+{{- define "CHART.name" -}}
+{{- default .Chart.Name .Values.nameOverride | trunc 63 | trimSuffix "-" }}
+{{- end }}
+
+{{/*
+Create a default fully qualified app name.
+If the release name contains the chart name, it is used as the full name.
+*/}}
+{{- define "CHART.fullname" -}}
+{{- if .Values.fullnameOverride }}
+{{- .Values.fullnameOverride | trunc 63 | trimSuffix "-" }}
+{{- else }}
+{{- $name := default .Chart.Name .Values.nameOverride }}
+{{- if contains $name .Release.Name }}
+{{- .Release.Name | trunc 63 | trimSuffix "-" }}
+{{- else }}
+{{- printf "%s-%s" .Release.Name $name | trunc 63 | trimSuffix "-" }}
+{{- end }}
+{{- end }}
+{{- end }}
+
+{{/*
+Create the chart name and version as used by the chart label.
+*/}}
+{{- define "CHART.chart" -}}
+{{- printf "%s-%s" .Chart.Name .Chart.Version | replace "+" "_" | trunc 63 | trimSuffix "-" }}
+{{- end }}
+
+{{/*
+Common labels
+*/}}
+{{- define "CHART.labels" -}}
+helm.sh/chart: {{ include "CHART.chart" . }}
+{{ include "CHART.selectorLabels" . }}
+{{- if .Chart.AppVersion }}
+app.kubernetes.io/version: {{ .Chart.AppVersion | quote }}
+{{- end }}
+app.kubernetes.io/managed-by: {{ .Release.Service }}
+{{- end }}
+
+{{/*
+Selector labels
+*/}}
+{{- define "CHART.selectorLabels" -}}
+app.kubernetes.io/name: {{ include "CHART.name" . }}
+app.kubernetes.io/instance: {{ .Release.Name }}
+{{- end }}
+`, "CHART", chartName)
+}
+
+// getHelmNotes returns the notes shown after the chart is installed
+func getHelmNotes(chart helmChartT) string {
+	notes := `{{ .Chart.Name }} {{ .Chart.Version }} has been installed as the release {{ .Release.Name }} in the namespace {{ .Release.Namespace }}.
+
+To see the resources of the release, run:
+
+  kubectl get all --namespace {{ .Release.Namespace }} --selector app.kubernetes.io/instance={{ .Release.Name }}
+`
+	if len(chart.Dependencies) == 0 {
+		return notes
+	}
+	notes += "\nThe chart depends on the charts:\n\n"
+	for _, dependency := range chart.Dependencies {
+		name := dependency.Name
+		if dependency.Alias != "" {
+			name = dependency.Alias + " (" + dependency.Name + ")"
+		}
+		notes += "  - " + name + "\n"
+	}
+	notes += "\nRun \"helm dependency update\" before installing the chart to fetch the dependencies which are not in the charts directory.\n"
+	return notes
+}
@@ -0,0 +1,158 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/parameterizer"
+	"github.com/konveyor/move2kube/qaengine"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+)
+
+const (
+	webYaml = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    move2kube.konveyor.io/service: web
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      move2kube.konveyor.io/service: web
+  template:
+    metadata:
+      labels:
+        move2kube.konveyor.io/service: web
+    spec:
+      containers:
+        - name: web
+          image: quay.io/shop/web:1.0
+`
+	dbYaml = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: db
+  labels:
+    move2kube.konveyor.io/service: db
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      move2kube.konveyor.io/service: db
+  template:
+    metadata:
+      labels:
+        move2kube.konveyor.io/service: db
+    spec:
+      containers:
+        - name: db
+          image: postgres:13
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  labels:
+    move2kube.konveyor.io/service: db
+spec:
+  ports:
+    - port: 5432
+`
+)
+
+func TestParameterizeHelm(t *testing.T) {
+	qaengine.StartEngine(true, 0, true)
+	qaengine.SetupConfigFile("", []string{`move2kube.services."db".helmchart=true`}, nil, nil)
+	srcDir := t.TempDir()
+	for name, content := range map[string]string{"web-deployment.yaml": webYaml, "db.yaml": dbYaml} {
+		if err := ioutil.WriteFile(filepath.Join(srcDir, name), []byte(content), common.DefaultFilePermission); err != nil {
+			t.Fatalf("Failed to write the file %s. Error: %q", name, err)
+		}
+	}
+	outDir := t.TempDir()
+	ps := []parameterizertypes.ParameterizerT{{Target: "spec.replicas", Template: "${replicas}", Filters: []parameterizertypes.FilterT{{Kind: common.DeploymentKind}}}}
+	packSpecPath := parameterizertypes.PackagingSpecPathT{HelmChartName: "shop", HelmChartVersion: "1.2.0", HelmAppVersion: "v1.2.0"}
+	if _, err := parameterizer.Parameterize(srcDir, outDir, packSpecPath, ps); err != nil {
+		t.Fatalf("Failed to parameterize. Error: %q", err)
+	}
+	chartDir := filepath.Join(outDir, "helm-chart", "shop")
+	readFile := func(path string) string {
+		data, err := ioutil.ReadFile(filepath.Join(chartDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read the file %s. Error: %q", path, err)
+		}
+		return string(data)
+	}
+
+	t.Run("chart with the versions and the backing service dependency", func(t *testing.T) {
+		chart := map[string]interface{}{}
+		if err := common.ReadYaml(filepath.Join(chartDir, "Chart.yaml"), &chart); err != nil {
+			t.Fatalf("Failed to read the Chart.yaml. Error: %q", err)
+		}
+		if chart["version"] != "1.2.0" || chart["appVersion"] != "v1.2.0" {
+			t.Fatalf("Expected the versions from the packaging spec. Actual: %+v", chart)
+		}
+		dependencies, ok := chart["dependencies"].([]interface{})
+		if !ok || len(dependencies) != 1 {
+			t.Fatalf("Expected a single dependency. Actual: %+v", chart["dependencies"])
+		}
+		dependency := dependencies[0].(map[string]interface{})
+		if dependency["name"] != "postgresql" || dependency["alias"] != "db" || dependency["repository"] != "https://charts.bitnami.com/bitnami" {
+			t.Fatalf("Expected the db service to be deployed using the postgresql chart. Actual: %+v", dependency)
+		}
+		if !strings.Contains(readFile("values.yaml"), "fullnameOverride: db") {
+			t.Fatalf("Expected the postgresql chart to keep the name of the db service. Actual: %s", readFile("values.yaml"))
+		}
+	})
+
+	t.Run("templates with the helpers and without the backing service", func(t *testing.T) {
+		if !strings.Contains(readFile(filepath.Join("templates", "_helpers.tpl")), `{{- define "shop.labels" -}}`) {
+			t.Fatalf("Expected the label helper of the shop chart")
+		}
+		if _, err := ioutil.ReadFile(filepath.Join(chartDir, "templates", "db.yaml")); err == nil {
+			t.Fatalf("Expected the resources of the db service to be replaced by the postgresql chart")
+		}
+		web := readFile(filepath.Join("templates", "web-deployment.yaml"))
+		if !strings.Contains(web, `helm.sh/chart: {{ include "shop.chart" . }}`) || !strings.Contains(web, "replicas: {{ index .Values \"replicas\" }}") {
+			t.Fatalf("Expected the web deployment to be labelled and parameterized. Actual: %s", web)
+		}
+		if _, err := ioutil.ReadFile(filepath.Join(chartDir, "templates", "NOTES.txt")); err != nil {
+			t.Fatalf("Failed to read the notes. Error: %q", err)
+		}
+	})
+
+	t.Run("schema of the values", func(t *testing.T) {
+		schema := map[string]interface{}{}
+		if err := common.ReadJSON(filepath.Join(chartDir, "values.schema.json"), &schema); err != nil {
+			t.Fatalf("Failed to read the values schema. Error: %q", err)
+		}
+		properties := schema["properties"].(map[string]interface{})
+		if replicas, ok := properties["replicas"].(map[string]interface{}); !ok || replicas["type"] != "integer" {
+			t.Fatalf("Expected the replicas to be an integer. Actual: %+v", properties["replicas"])
+		}
+		if db, ok := properties["db"].(map[string]interface{}); !ok || db["type"] != "object" {
+			t.Fatalf("Expected the values of the db chart to be an object. Actual: %+v", properties["db"])
+		}
+	})
+}
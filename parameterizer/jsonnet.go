@@ -0,0 +1,153 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/deepcopy"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+)
+
+const (
+	tankaAPIVersion       = "tanka.dev/v1alpha1"
+	tankaEnvironmentKind  = "Environment"
+	jsonnetMainTemplate   = "(import '../../lib/resources.libsonnet')(std.mergePatch(import '../../lib/params.libsonnet', import 'params.libsonnet'))\n"
+	jsonnetfileContents   = "{\n  \"version\": 1,\n  \"dependencies\": [],\n  \"legacyImports\": true\n}\n"
+	jsonnetResourcesStart = "function(params) "
+)
+
+// createJsonnet writes a Jsonnet library that is a function of the params and a Tanka environment for each env.
+// The params of the first env are the defaults and each environment overrides them with its own params.
+// The output can be rendered using `tk show environments/<env>` or `jsonnet environments/<env>/main.jsonnet`
+func createJsonnet(jsonnetDir string, pathedKs map[string][]parameterizertypes.K8sResourceT, envs []string, ps []parameterizertypes.ParameterizerT) ([]string, error) {
+	filesWritten := []string{}
+	libDir := filepath.Join(jsonnetDir, "lib")
+	if err := os.MkdirAll(libDir, common.DefaultDirectoryPermission); err != nil {
+		return filesWritten, err
+	}
+	namedValues := map[string]parameterizertypes.HelmValuesT{}
+	resources := map[string]interface{}{}
+	for _, ks := range pathedKs {
+		for _, k := range ks {
+			k = deepcopy.DeepCopy(k).(parameterizertypes.K8sResourceT)
+			if err := parameterize(parameterizertypes.TargetJsonnet, envs, k, ps, namedValues, nil, nil); err != nil {
+				return filesWritten, err
+			}
+			group, version, kind, metadataName, err := getGVKNFromK(k)
+			if err != nil {
+				return filesWritten, err
+			}
+			name := fmt.Sprintf("%s-%s-%s-%s", group, version, kind, metadataName)
+			if group == "" {
+				name = fmt.Sprintf("%s-%s-%s", version, kind, metadataName)
+			}
+			resources[strings.ToLower(common.MakeFileNameCompliant(name))] = map[string]interface{}(k)
+		}
+	}
+	files := map[string]string{
+		filepath.Join(jsonnetDir, "jsonnetfile.json"): jsonnetfileContents,
+		filepath.Join(libDir, "resources.libsonnet"):  jsonnetResourcesStart + writeJsonnetValue(resources, "") + "\n",
+		filepath.Join(libDir, "params.libsonnet"):     "{}\n",
+	}
+	if len(envs) > 0 {
+		files[filepath.Join(libDir, "params.libsonnet")] = writeJsonnetValue(map[string]interface{}(namedValues[envs[0]]), "") + "\n"
+	}
+	for _, env := range envs {
+		envDir := filepath.Join(jsonnetDir, "environments", env)
+		spec := map[string]interface{}{
+			"apiVersion": tankaAPIVersion,
+			"kind":       tankaEnvironmentKind,
+			"metadata":   map[string]interface{}{"name": "environments/" + env},
+			"spec":       map[string]interface{}{"namespace": "default"},
+		}
+		specBytes, err := json.MarshalIndent(spec, "", "  ")
+		if err != nil {
+			return filesWritten, fmt.Errorf("failed to marshal the tanka environment %+v to json. Error: %q", spec, err)
+		}
+		files[filepath.Join(envDir, "spec.json")] = string(specBytes) + "\n"
+		files[filepath.Join(envDir, "main.jsonnet")] = jsonnetMainTemplate
+		files[filepath.Join(envDir, "params.libsonnet")] = writeJsonnetValue(map[string]interface{}(namedValues[env]), "") + "\n"
+	}
+	paths := []string{}
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := os.MkdirAll(filepath.Dir(path), common.DefaultDirectoryPermission); err != nil {
+			return filesWritten, err
+		}
+		if err := ioutil.WriteFile(path, []byte(files[path]), common.DefaultFilePermission); err != nil {
+			return filesWritten, fmt.Errorf("failed to write the file at path %s . Error: %q", path, err)
+		}
+		filesWritten = append(filesWritten, path)
+	}
+	return filesWritten, nil
+}
+
+// writeJsonnetValue writes the value as Jsonnet, replacing the placeholders with their expressions
+func writeJsonnetValue(value interface{}, indent string) string {
+	if expression, ok := getValueExpression(parameterizertypes.TargetJsonnet, value); ok {
+		return expression
+	}
+	switch actualValue := value.(type) {
+	case map[string]interface{}:
+		if len(actualValue) == 0 {
+			return "{}"
+		}
+		keys := []string{}
+		for k := range actualValue {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := "{\n"
+		for _, k := range keys {
+			out += indent + "  " + marshalJSONString(k) + ": " + writeJsonnetValue(actualValue[k], indent+"  ") + ",\n"
+		}
+		return out + indent + "}"
+	case []interface{}:
+		if len(actualValue) == 0 {
+			return "[]"
+		}
+		out := "[\n"
+		for _, v := range actualValue {
+			out += indent + "  " + writeJsonnetValue(v, indent+"  ") + ",\n"
+		}
+		return out + indent + "]"
+	}
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return "null"
+	}
+	return string(jsonBytes)
+}
+
+func marshalJSONString(s string) string {
+	jsonBytes, err := json.Marshal(s)
+	if err != nil {
+		return `""`
+	}
+	return string(jsonBytes)
+}
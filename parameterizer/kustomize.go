@@ -0,0 +1,639 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/deepcopy"
+	"github.com/konveyor/move2kube/k8sschema"
+	"github.com/konveyor/move2kube/qaengine"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	kustomizeAPIVersion          = "kustomize.config.k8s.io/v1beta1"
+	kustomizeComponentAPIVersion = "kustomize.config.k8s.io/v1alpha1"
+	kustomizationKind            = "Kustomization"
+	kustomizeComponentKind       = "Component"
+	configMapKind                = "ConfigMap"
+	secretKind                   = "Secret"
+)
+
+var (
+	// replicaKinds are the kinds whose replicas can be set using the replicas field of a kustomization
+	replicaKinds = []string{common.DeploymentKind, "StatefulSet", "ReplicaSet", "ReplicationController"}
+	// strategicMergeKeys are the keys which identify the elements of the lists in the strategic merge patches
+	strategicMergeKeys = []string{"name", "mountPath", "containerPort", "port"}
+)
+
+// kustomizationT is a kustomization.yaml
+type kustomizationT struct {
+	APIVersion         string                `yaml:"apiVersion"`
+	Kind               string                `yaml:"kind"`
+	Resources          []string              `yaml:"resources,omitempty"`
+	Components         []string              `yaml:"components,omitempty"`
+	Patches            []kustomizePatchT     `yaml:"patches,omitempty"`
+	Images             []kustomizeImageT     `yaml:"images,omitempty"`
+	Replicas           []kustomizeReplicaT   `yaml:"replicas,omitempty"`
+	ConfigMapGenerator []kustomizeGeneratorT `yaml:"configMapGenerator,omitempty"`
+	SecretGenerator    []kustomizeGeneratorT `yaml:"secretGenerator,omitempty"`
+}
+
+// kustomizePatchT is a patch in a kustomization. The strategic merge patches do not need a target.
+type kustomizePatchT struct {
+	Path   string                                   `yaml:"path"`
+	Target *parameterizertypes.PatchMetadataTargetT `yaml:"target,omitempty"`
+}
+
+// kustomizeImageT changes the name and the tag of an image
+type kustomizeImageT struct {
+	Name    string `yaml:"name"`
+	NewName string `yaml:"newName,omitempty"`
+	NewTag  string `yaml:"newTag,omitempty"`
+	Digest  string `yaml:"digest,omitempty"`
+}
+
+// kustomizeReplicaT changes the replicas of a workload
+type kustomizeReplicaT struct {
+	Name  string `yaml:"name"`
+	Count int    `yaml:"count"`
+}
+
+// kustomizeGeneratorT generates a config map or a secret from files
+type kustomizeGeneratorT struct {
+	Name      string                      `yaml:"name"`
+	Namespace string                      `yaml:"namespace,omitempty"`
+	Type      string                      `yaml:"type,omitempty"`
+	Files     []string                    `yaml:"files,omitempty"`
+	Options   *kustomizeGeneratorOptionsT `yaml:"options,omitempty"`
+}
+
+// kustomizeGeneratorOptionsT has the labels and annotations of the generated resources
+type kustomizeGeneratorOptionsT struct {
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// resourcePatchesT has the patches of a resource in an env
+type resourcePatchesT struct {
+	k        parameterizertypes.K8sResourceT
+	metadata parameterizertypes.PatchMetadataT
+	patches  []parameterizertypes.PatchT
+}
+
+// imageUseT is a patch which changes an image
+type imageUseT struct {
+	resourcePatches *resourcePatchesT
+	patch           parameterizertypes.PatchT
+}
+
+// getKustomizePatchType returns the type of the patches of the kustomize overlays
+func getKustomizePatchType(packSpecPath parameterizertypes.PackagingSpecPathT) parameterizertypes.KustomizePatchTypeT {
+	if packSpecPath.KustomizePatchType != "" {
+		return packSpecPath.KustomizePatchType
+	}
+	hints := []string{
+		"json6902 : the overlays contain json patches, which refer to the elements of the lists by their index",
+		"strategicmerge : the overlays contain strategic merge patches, the images and replicas fields, and components shared by the overlays. The config maps and secrets are generated",
+	}
+	options := []string{string(parameterizertypes.KustomizeJSON6902Patches), string(parameterizertypes.KustomizeStrategicMergePatches)}
+	return parameterizertypes.KustomizePatchTypeT(qaengine.FetchSelectAnswer(common.ConfigKustomizePatchTypeKey, "Which type of patches should the kustomize overlays use?", hints, string(parameterizertypes.KustomizeJSON6902Patches), options))
+}
+
+// createStrategicMergeKustomize creates a kustomize base and an overlay for each env with strategic merge patches
+func createStrategicMergeKustomize(kustDir string, pathedKs map[string][]parameterizertypes.K8sResourceT, envs []string, ps []parameterizertypes.ParameterizerT) ([]string, error) {
+	filesWritten := []string{}
+	baseDir := filepath.Join(kustDir, "base")
+	if err := os.MkdirAll(baseDir, common.DefaultDirectoryPermission); err != nil {
+		return filesWritten, err
+	}
+	base := kustomizationT{APIVersion: kustomizeAPIVersion, Kind: kustomizationKind}
+	overlays := map[string]*kustomizationT{}
+	envPatches := map[string][]*resourcePatchesT{}
+	envImageUses := map[string]map[string][]imageUseT{}
+	imageCounts := map[string]int{}
+	kPaths := []string{}
+	for kPath, ks := range pathedKs {
+		kPaths = append(kPaths, kPath)
+		for _, k := range ks {
+			countImages(map[string]interface{}(k), imageCounts)
+		}
+	}
+	sort.Strings(kPaths)
+	for _, kPath := range kPaths {
+		resourceWritten := false
+		for _, k := range pathedKs[kPath] {
+			group, version, kind, metadataName, err := getGVKNFromK(k)
+			if err != nil {
+				return filesWritten, err
+			}
+			if kind == configMapKind || kind == secretKind {
+				generator, generatorFilesWritten, err := createKustomizeGenerator(baseDir, kind, k)
+				filesWritten = append(filesWritten, generatorFilesWritten...)
+				if err != nil {
+					return filesWritten, err
+				}
+				if kind == configMapKind {
+					base.ConfigMapGenerator = append(base.ConfigMapGenerator, generator)
+				} else {
+					base.SecretGenerator = append(base.SecretGenerator, generator)
+				}
+			} else {
+				finalKPath := filepath.Join(baseDir, kPath)
+				if err := writeResourceAppendToFile(k, finalKPath); err != nil {
+					return filesWritten, err
+				}
+				filesWritten = common.MergeStringSlices(filesWritten, finalKPath)
+				resourceWritten = true
+			}
+			currKustPatches := map[string]map[string]parameterizertypes.PatchT{} // keyed by env and json pointer/path
+			if err := parameterize(parameterizertypes.TargetKustomize, envs, deepcopy.DeepCopy(k).(parameterizertypes.K8sResourceT), ps, nil, currKustPatches, nil); err != nil {
+				return filesWritten, err
+			}
+			patchFilename := fmt.Sprintf("%s-%s-%s-%s.yaml", group, version, kind, metadataName)
+			if group == "" {
+				patchFilename = fmt.Sprintf("%s-%s-%s.yaml", version, kind, metadataName)
+			}
+			metadata := parameterizertypes.PatchMetadataT{
+				Path:   strings.ToLower(common.MakeFileNameCompliant(patchFilename)),
+				Target: parameterizertypes.PatchMetadataTargetT{Kind: kind, Group: group, Version: version, Name: metadataName},
+			}
+			for env, patches := range currKustPatches {
+				if _, ok := overlays[env]; !ok {
+					overlays[env] = &kustomizationT{APIVersion: kustomizeAPIVersion, Kind: kustomizationKind, Resources: []string{"../../base"}}
+					envImageUses[env] = map[string][]imageUseT{}
+				}
+				resourcePatches := &resourcePatchesT{k: k, metadata: metadata}
+				pointers := []string{}
+				for pointer := range patches {
+					pointers = append(pointers, pointer)
+				}
+				sort.Strings(pointers)
+				for _, pointer := range pointers {
+					patch := patches[pointer]
+					subKeys := splitJSONPointer(pointer)
+					if common.IsStringPresent(replicaKinds, kind) && pointer == "/spec/replicas" {
+						if count, err := cast.ToIntE(patch.Value); err == nil {
+							overlays[env].Replicas = append(overlays[env].Replicas, kustomizeReplicaT{Name: metadataName, Count: count})
+							continue
+						}
+					}
+					if isImageKey(subKeys) {
+						if image, ok := getAtSubKeys(k, subKeys).(string); ok {
+							imageName, _, _ := splitImage(image)
+							envImageUses[env][imageName] = append(envImageUses[env][imageName], imageUseT{resourcePatches: resourcePatches, patch: patch})
+							continue
+						}
+					}
+					resourcePatches.patches = append(resourcePatches.patches, patch)
+				}
+				envPatches[env] = append(envPatches[env], resourcePatches)
+			}
+		}
+		if resourceWritten {
+			base.Resources = append(base.Resources, kPath)
+		}
+	}
+	finalKPath := filepath.Join(baseDir, "kustomization.yaml")
+	if err := common.WriteYaml(finalKPath, base); err != nil {
+		return filesWritten, err
+	}
+	filesWritten = append(filesWritten, finalKPath)
+	// the strategic merge patches keyed by the patch filename and env
+	envStrategicMergePatches := map[string]map[string]parameterizertypes.K8sResourceT{}
+	for env, overlay := range overlays {
+		overlay.Images = getKustomizeImages(envImageUses[env], imageCounts)
+		for _, resourcePatches := range envPatches[env] {
+			strategicMergePatch, jsonPatches := toStrategicMergePatch(resourcePatches.k, resourcePatches.patches)
+			if strategicMergePatch != nil {
+				if _, ok := envStrategicMergePatches[resourcePatches.metadata.Path]; !ok {
+					envStrategicMergePatches[resourcePatches.metadata.Path] = map[string]parameterizertypes.K8sResourceT{}
+				}
+				envStrategicMergePatches[resourcePatches.metadata.Path][env] = strategicMergePatch
+			}
+			if len(jsonPatches) == 0 {
+				continue
+			}
+			jsonPatchFilename := strings.TrimSuffix(resourcePatches.metadata.Path, ".yaml") + "-json6902.yaml"
+			finalKPath := filepath.Join(kustDir, "overlays", env, jsonPatchFilename)
+			if err := os.MkdirAll(filepath.Dir(finalKPath), common.DefaultDirectoryPermission); err != nil {
+				return filesWritten, err
+			}
+			if err := common.WriteYaml(finalKPath, jsonPatches); err != nil {
+				return filesWritten, err
+			}
+			filesWritten = append(filesWritten, finalKPath)
+			target := resourcePatches.metadata.Target
+			overlay.Patches = append(overlay.Patches, kustomizePatchT{Path: jsonPatchFilename, Target: &target})
+		}
+	}
+	// the patches which are the same in several envs are put in components
+	patchFilenames := []string{}
+	for patchFilename := range envStrategicMergePatches {
+		patchFilenames = append(patchFilenames, patchFilename)
+	}
+	sort.Strings(patchFilenames)
+	for _, patchFilename := range patchFilenames {
+		for _, sharedEnvs := range groupEnvsByPatch(envStrategicMergePatches[patchFilename]) {
+			strategicMergePatch := envStrategicMergePatches[patchFilename][sharedEnvs[0]]
+			if len(sharedEnvs) == 1 {
+				env := sharedEnvs[0]
+				finalKPath := filepath.Join(kustDir, "overlays", env, patchFilename)
+				if err := os.MkdirAll(filepath.Dir(finalKPath), common.DefaultDirectoryPermission); err != nil {
+					return filesWritten, err
+				}
+				if err := common.WriteYaml(finalKPath, strategicMergePatch); err != nil {
+					return filesWritten, err
+				}
+				filesWritten = append(filesWritten, finalKPath)
+				overlays[env].Patches = append(overlays[env].Patches, kustomizePatchT{Path: patchFilename})
+				continue
+			}
+			componentName := strings.TrimSuffix(patchFilename, ".yaml") + "-" + strings.Join(sharedEnvs, "-")
+			componentDir := filepath.Join(kustDir, "components", componentName)
+			if err := os.MkdirAll(componentDir, common.DefaultDirectoryPermission); err != nil {
+				return filesWritten, err
+			}
+			finalKPath := filepath.Join(componentDir, patchFilename)
+			if err := common.WriteYaml(finalKPath, strategicMergePatch); err != nil {
+				return filesWritten, err
+			}
+			filesWritten = append(filesWritten, finalKPath)
+			component := kustomizationT{APIVersion: kustomizeComponentAPIVersion, Kind: kustomizeComponentKind, Patches: []kustomizePatchT{{Path: patchFilename}}}
+			finalKPath = filepath.Join(componentDir, "kustomization.yaml")
+			if err := common.WriteYaml(finalKPath, component); err != nil {
+				return filesWritten, err
+			}
+			filesWritten = append(filesWritten, finalKPath)
+			for _, env := range sharedEnvs {
+				overlays[env].Components = append(overlays[env].Components, "../../components/"+componentName)
+			}
+		}
+	}
+	for env, overlay := range overlays {
+		envDir := filepath.Join(kustDir, "overlays", env)
+		if err := os.MkdirAll(envDir, common.DefaultDirectoryPermission); err != nil {
+			return filesWritten, err
+		}
+		finalKPath := filepath.Join(envDir, "kustomization.yaml")
+		if err := common.WriteYaml(finalKPath, overlay); err != nil {
+			return filesWritten, err
+		}
+		filesWritten = append(filesWritten, finalKPath)
+	}
+	return filesWritten, nil
+}
+
+// createKustomizeGenerator writes the data of a config map or a secret to files and returns the generator which creates it from the files
+func createKustomizeGenerator(baseDir, kind string, k parameterizertypes.K8sResourceT) (kustomizeGeneratorT, []string, error) {
+	filesWritten := []string{}
+	_, _, name, err := k8sschema.GetInfoFromK8sResource(k)
+	if err != nil {
+		return kustomizeGeneratorT{}, filesWritten, err
+	}
+	generator := kustomizeGeneratorT{Name: name}
+	if metadata, ok := k["metadata"].(map[string]interface{}); ok {
+		generator.Namespace = cast.ToString(metadata["namespace"])
+		labels, annotations := cast.ToStringMapString(metadata["labels"]), cast.ToStringMapString(metadata["annotations"])
+		if len(labels) != 0 || len(annotations) != 0 {
+			generator.Options = &kustomizeGeneratorOptionsT{Labels: labels, Annotations: annotations}
+		}
+	}
+	data := map[string][]byte{}
+	dataDir := "configmaps"
+	if kind == secretKind {
+		dataDir = "secrets"
+		generator.Type = cast.ToString(k["type"])
+		for key, value := range cast.ToStringMapString(k["data"]) {
+			decodedValue, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				logrus.Debugf("The value of the key %s in the secret %s is not base64 encoded : %s", key, name, err)
+				decodedValue = []byte(value)
+			}
+			data[key] = decodedValue
+		}
+		for key, value := range cast.ToStringMapString(k["stringData"]) {
+			data[key] = []byte(value)
+		}
+	} else {
+		for key, value := range cast.ToStringMapString(k["data"]) {
+			data[key] = []byte(value)
+		}
+		for key, value := range cast.ToStringMapString(k["binaryData"]) {
+			decodedValue, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return generator, filesWritten, fmt.Errorf("the binary data of the key %s in the config map %s is not base64 encoded : %s", key, name, err)
+			}
+			data[key] = decodedValue
+		}
+	}
+	keys := []string{}
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		relPath := filepath.Join(dataDir, name, key)
+		finalKPath := filepath.Join(baseDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(finalKPath), common.DefaultDirectoryPermission); err != nil {
+			return generator, filesWritten, err
+		}
+		if err := ioutil.WriteFile(finalKPath, data[key], common.DefaultFilePermission); err != nil {
+			return generator, filesWritten, err
+		}
+		filesWritten = append(filesWritten, finalKPath)
+		generator.Files = append(generator.Files, key+"="+filepath.ToSlash(relPath))
+	}
+	return generator, filesWritten, nil
+}
+
+// getKustomizeImages returns the images fields for the images which are changed to the same new image in all the containers using them.
+// The patches of the other images are added back to the patches of their resources.
+func getKustomizeImages(imageUses map[string][]imageUseT, imageCounts map[string]int) []kustomizeImageT {
+	images := []kustomizeImageT{}
+	imageNames := []string{}
+	for imageName := range imageUses {
+		imageNames = append(imageNames, imageName)
+	}
+	sort.Strings(imageNames)
+	for _, imageName := range imageNames {
+		uses := imageUses[imageName]
+		newImage, ok := uses[0].patch.Value.(string)
+		ok = ok && len(uses) == imageCounts[imageName]
+		for _, use := range uses {
+			if value, isString := use.patch.Value.(string); !isString || value != newImage {
+				ok = false
+			}
+		}
+		if !ok {
+			logrus.Debugf("The image %s is changed to different images. Using patches instead of the images field", imageName)
+			for _, use := range uses {
+				use.resourcePatches.patches = append(use.resourcePatches.patches, use.patch)
+			}
+			continue
+		}
+		image := kustomizeImageT{Name: imageName}
+		newName, newTag, digest := splitImage(newImage)
+		if newName != imageName {
+			image.NewName = newName
+		}
+		image.NewTag, image.Digest = newTag, digest
+		if newTag == "" && digest == "" {
+			// Otherwise the tag of the original image is kept
+			image.NewTag = "latest"
+		}
+		images = append(images, image)
+	}
+	return images
+}
+
+// toStrategicMergePatch converts the json patches to a strategic merge patch.
+// The patches which cannot be converted, like the ones changing the keys of the list elements, are returned as is.
+func toStrategicMergePatch(k parameterizertypes.K8sResourceT, patches []parameterizertypes.PatchT) (parameterizertypes.K8sResourceT, []parameterizertypes.PatchT) {
+	if len(patches) == 0 {
+		return nil, nil
+	}
+	strategicMergePatch := parameterizertypes.K8sResourceT{"apiVersion": k["apiVersion"], "kind": k["kind"]}
+	metadata := map[string]interface{}{}
+	if baseMetadata, ok := k["metadata"].(map[string]interface{}); ok {
+		metadata["name"] = baseMetadata["name"]
+		if namespace, ok := baseMetadata["namespace"]; ok {
+			metadata["namespace"] = namespace
+		}
+	}
+	strategicMergePatch["metadata"] = metadata
+	jsonPatches := []parameterizertypes.PatchT{}
+	converted := false
+	for _, patch := range patches {
+		if patch.Op == parameterizertypes.RemoveOp {
+			jsonPatches = append(jsonPatches, patch)
+			continue
+		}
+		candidate := deepcopy.DeepCopy(strategicMergePatch).(parameterizertypes.K8sResourceT)
+		if !addToStrategicMergePatch(candidate, k, splitJSONPointer(patch.Path), patch.Value) {
+			jsonPatches = append(jsonPatches, patch)
+			continue
+		}
+		strategicMergePatch = candidate
+		converted = true
+	}
+	if !converted {
+		return nil, jsonPatches
+	}
+	return strategicMergePatch, jsonPatches
+}
+
+// addToStrategicMergePatch sets the value at the sub keys in the patch. The list elements are identified by their merge keys.
+func addToStrategicMergePatch(patch, base map[string]interface{}, subKeys []string, value interface{}) bool {
+	key := subKeys[0]
+	if len(subKeys) == 1 {
+		patch[key] = value
+		return true
+	}
+	switch baseValue := base[key].(type) {
+	case map[string]interface{}:
+		child, ok := patch[key].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			patch[key] = child
+		}
+		return addToStrategicMergePatch(child, baseValue, subKeys[1:], value)
+	case []interface{}:
+		idx, err := strconv.Atoi(subKeys[1])
+		if err != nil || idx < 0 || idx >= len(baseValue) || len(subKeys) == 2 {
+			return false
+		}
+		element, ok := baseValue[idx].(map[string]interface{})
+		if !ok {
+			return false
+		}
+		identity := map[string]interface{}{}
+		for _, mergeKey := range strategicMergeKeys {
+			if mergeKeyValue, ok := element[mergeKey]; ok {
+				identity[mergeKey] = mergeKeyValue
+			}
+		}
+		if len(identity) == 0 {
+			return false
+		}
+		// The keys which identify the element cannot be changed by a strategic merge patch
+		if _, ok := identity[subKeys[2]]; ok && len(subKeys) == 3 {
+			return false
+		}
+		children, _ := patch[key].([]interface{})
+		var child map[string]interface{}
+		for _, childI := range children {
+			if c, ok := childI.(map[string]interface{}); ok && hasIdentity(c, identity) {
+				child = c
+				break
+			}
+		}
+		if child == nil {
+			child = identity
+			children = append(children, child)
+			patch[key] = children
+		}
+		return addToStrategicMergePatch(child, element, subKeys[2:], value)
+	case nil:
+		// The value is added under a new key
+		for _, subKey := range subKeys[1:] {
+			if _, err := strconv.Atoi(subKey); err == nil {
+				return false
+			}
+		}
+		child, ok := patch[key].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			patch[key] = child
+		}
+		return addToStrategicMergePatch(child, map[string]interface{}{}, subKeys[1:], value)
+	}
+	return false
+}
+
+func hasIdentity(element, identity map[string]interface{}) bool {
+	for k, v := range identity {
+		if element[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// groupEnvsByPatch groups the envs which have the same patch
+func groupEnvsByPatch(envPatches map[string]parameterizertypes.K8sResourceT) [][]string {
+	envs := []string{}
+	for env := range envPatches {
+		envs = append(envs, env)
+	}
+	sort.Strings(envs)
+	groups := [][]string{}
+	groupPatches := []string{}
+	for _, env := range envs {
+		patchBytes, err := yaml.Marshal(envPatches[env])
+		if err != nil {
+			logrus.Errorf("Failed to marshal the patch %+v : %s", envPatches[env], err)
+			groups = append(groups, []string{env})
+			groupPatches = append(groupPatches, "")
+			continue
+		}
+		found := false
+		for i, groupPatch := range groupPatches {
+			if groupPatch == string(patchBytes) {
+				groups[i] = append(groups[i], env)
+				found = true
+				break
+			}
+		}
+		if !found {
+			groups = append(groups, []string{env})
+			groupPatches = append(groupPatches, string(patchBytes))
+		}
+	}
+	return groups
+}
+
+// splitJSONPointer returns the unescaped sub keys of a json pointer
+func splitJSONPointer(pointer string) []string {
+	subKeys := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, subKey := range subKeys {
+		subKeys[i] = strings.ReplaceAll(strings.ReplaceAll(subKey, "~1", "/"), "~0", "~")
+	}
+	return subKeys
+}
+
+// isImageKey returns true if the sub keys point to the image of a container
+func isImageKey(subKeys []string) bool {
+	n := len(subKeys)
+	if n < 3 || subKeys[n-1] != "image" {
+		return false
+	}
+	if _, err := strconv.Atoi(subKeys[n-2]); err != nil {
+		return false
+	}
+	return subKeys[n-3] == "containers" || subKeys[n-3] == "initContainers"
+}
+
+// countImages counts the containers using each image in the value
+func countImages(value interface{}, imageCounts map[string]int) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, subValue := range v {
+			if containers, ok := subValue.([]interface{}); ok && (key == "containers" || key == "initContainers") {
+				for _, containerI := range containers {
+					container, ok := containerI.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					if image, ok := container["image"].(string); ok {
+						imageName, _, _ := splitImage(image)
+						imageCounts[imageName]++
+					}
+				}
+				continue
+			}
+			countImages(subValue, imageCounts)
+		}
+	case []interface{}:
+		for _, subValue := range v {
+			countImages(subValue, imageCounts)
+		}
+	}
+}
+
+// getAtSubKeys returns the value at the sub keys, or nil if there is no value
+func getAtSubKeys(value interface{}, subKeys []string) interface{} {
+	for _, subKey := range subKeys {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[subKey]
+		case []interface{}:
+			idx, err := strconv.Atoi(subKey)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil
+			}
+			value = v[idx]
+		default:
+			return nil
+		}
+	}
+	return value
+}
+
+// splitImage returns the name, including the registry, the tag and the digest of an image
+func splitImage(image string) (name, tag, digest string) {
+	name = image
+	if i := strings.Index(name, "@"); i != -1 {
+		name, digest = name[:i], name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i != -1 && !strings.Contains(name[i:], "/") {
+		name, tag = name[:i], name[i+1:]
+	}
+	return name, tag, digest
+}
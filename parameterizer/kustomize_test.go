@@ -0,0 +1,124 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/parameterizer"
+	"github.com/konveyor/move2kube/qaengine"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+)
+
+const (
+	ordersYaml = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: orders
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+        - name: orders
+          image: quay.io/shop/orders:1.0
+          env:
+            - name: LOG_LEVEL
+              value: info
+`
+	ordersConfigYaml = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: orders-config
+data:
+  application.properties: server.port=8080
+`
+)
+
+func TestParameterizeStrategicMergeKustomize(t *testing.T) {
+	qaengine.StartEngine(true, 0, true)
+	srcDir := t.TempDir()
+	for name, content := range map[string]string{"orders-deployment.yaml": ordersYaml, "orders-config.yaml": ordersConfigYaml} {
+		if err := ioutil.WriteFile(filepath.Join(srcDir, name), []byte(content), common.DefaultFilePermission); err != nil {
+			t.Fatalf("Failed to write the file %s. Error: %q", name, err)
+		}
+	}
+	outDir := t.TempDir()
+	envValue := func(name, dev, prod string) []parameterizertypes.ParameterT {
+		return []parameterizertypes.ParameterT{{Name: name, Values: []parameterizertypes.ParameterValueT{{Envs: []string{"dev"}, Value: dev}, {Envs: []string{"prod"}, Value: prod}}}}
+	}
+	deployments := []parameterizertypes.FilterT{{Kind: common.DeploymentKind}}
+	ps := []parameterizertypes.ParameterizerT{
+		{Target: "spec.replicas", Template: "${replicas}", Default: 3, Filters: deployments},
+		{Target: "spec.template.spec.containers.[name=orders].image", Template: "${image}", Filters: deployments, Parameters: envValue("image", "quay.io/shop/orders:dev", "quay.io/shop/orders:1.0.1")},
+		{Target: "spec.template.spec.containers.[name=orders].env.[name=LOG_LEVEL].value", Template: "${loglevel}", Default: "debug", Filters: deployments},
+	}
+	packSpecPath := parameterizertypes.PackagingSpecPathT{Envs: []string{"dev", "prod"}, KustomizePatchType: parameterizertypes.KustomizeStrategicMergePatches}
+	if _, err := parameterizer.Parameterize(srcDir, outDir, packSpecPath, ps); err != nil {
+		t.Fatalf("Failed to parameterize. Error: %q", err)
+	}
+	kustDir := filepath.Join(outDir, "kustomize")
+	readFile := func(path string) string {
+		data, err := ioutil.ReadFile(filepath.Join(kustDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read the file %s. Error: %q", path, err)
+		}
+		return string(data)
+	}
+
+	t.Run("config maps are generated in the base", func(t *testing.T) {
+		base := readFile(filepath.Join("base", "kustomization.yaml"))
+		if !strings.Contains(base, "configMapGenerator:") || strings.Contains(base, "orders-config.yaml") {
+			t.Fatalf("Expected the config map to be generated. Actual: %s", base)
+		}
+		if data := readFile(filepath.Join("base", "configmaps", "orders-config", "application.properties")); data != "server.port=8080" {
+			t.Fatalf("Expected the data of the config map in a file. Actual: %s", data)
+		}
+	})
+
+	t.Run("overlays use the images and replicas fields", func(t *testing.T) {
+		overlay := map[string]interface{}{}
+		if err := common.ReadYaml(filepath.Join(kustDir, "overlays", "prod", "kustomization.yaml"), &overlay); err != nil {
+			t.Fatalf("Failed to read the kustomization. Error: %q", err)
+		}
+		images, ok := overlay["images"].([]interface{})
+		if !ok || len(images) != 1 {
+			t.Fatalf("Expected a single image. Actual: %+v", overlay["images"])
+		}
+		if image := images[0].(map[string]interface{}); image["name"] != "quay.io/shop/orders" || image["newTag"] != "1.0.1" || image["newName"] != nil {
+			t.Fatalf("Expected the tag of the orders image to be changed. Actual: %+v", image)
+		}
+		replicas, ok := overlay["replicas"].([]interface{})
+		if !ok || len(replicas) != 1 || replicas[0].(map[string]interface{})["count"] != 3 {
+			t.Fatalf("Expected the replicas of the orders deployment to be changed. Actual: %+v", overlay["replicas"])
+		}
+	})
+
+	t.Run("patches shared by the overlays are in a component", func(t *testing.T) {
+		patch := readFile(filepath.Join("components", "apps-v1-deployment-orders-dev-prod", "apps-v1-deployment-orders.yaml"))
+		if !strings.Contains(patch, "name: LOG_LEVEL") || !strings.Contains(patch, "value: debug") || strings.Contains(patch, "/0/") {
+			t.Fatalf("Expected a strategic merge patch of the log level. Actual: %s", patch)
+		}
+		if overlay := readFile(filepath.Join("overlays", "dev", "kustomization.yaml")); !strings.Contains(overlay, "../../components/apps-v1-deployment-orders-dev-prod") {
+			t.Fatalf("Expected the dev overlay to use the component. Actual: %s", overlay)
+		}
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/konveyor/move2kube/common"
@@ -40,6 +41,12 @@ var (
 	invalidOCTemplateChars       = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
 	specialJSONPathChars         = regexp.MustCompile(`(~|\/)`)
 	templateInnerParametersRegex = regexp.MustCompile(`\$\([^)]+\)`)
+	defaultEnvs                  = []string{"dev", "staging", "prod"}
+)
+
+const (
+	valueExpressionPrefix = "(( "
+	valueExpressionSuffix = " ))"
 )
 
 // Parameterize does the parameterization based on a spec
@@ -63,7 +70,7 @@ func Parameterize(srcDir, outDir string, packSpecPath parameterizertypes.Packagi
 		packSpecPath.OCTemplates = filepath.Join(packSpecPath.Out, "openshift-template")
 	}
 	if len(packSpecPath.Envs) == 0 {
-		packSpecPath.Envs = []string{"dev", "staging", "prod"}
+		packSpecPath.Envs = defaultEnvs
 	}
 	pathedKs, err := k8sschema.GetK8sResourcesWithPaths(filepath.Join(cleanSrcDir, packSpecPath.Src))
 	if err != nil {
@@ -71,50 +78,27 @@ func Parameterize(srcDir, outDir string, packSpecPath parameterizertypes.Packagi
 	}
 	if packSpecPath.Helm != "" {
 		// helm chart with multiple values.yaml
-		helmChartName := packSpecPath.HelmChartName
-		if helmChartName == "" {
-			helmChartName = common.DefaultProjectName
-		}
-		namedValues := map[string]parameterizertypes.HelmValuesT{}
-		helmChartDir := filepath.Join(cleanOutDir, packSpecPath.Helm, helmChartName)
-		helmTemplatesDir := filepath.Join(helmChartDir, "templates")
-		if err := os.MkdirAll(helmTemplatesDir, common.DefaultDirectoryPermission); err != nil {
+		fw, err := createHelmChart(cleanSrcDir, filepath.Join(cleanOutDir, packSpecPath.Helm), pathedKs, packSpecPath, ps)
+		filesWritten = append(filesWritten, fw...)
+		if err != nil {
 			return filesWritten, err
 		}
-		for kPath, ks := range pathedKs {
-			for _, k := range ks {
-				k = deepcopy.DeepCopy(k).(parameterizertypes.K8sResourceT)
-				if err := parameterize(parameterizertypes.TargetHelm, packSpecPath.Envs, k, ps, namedValues, nil, nil); err != nil {
-					return filesWritten, err
-				}
-				finalKPath := filepath.Join(helmTemplatesDir, kPath)
-				if err := writeResourceStripQuotesAndAppendToFile(k, finalKPath); err != nil {
-					return filesWritten, err
-				}
-				filesWritten = append(filesWritten, finalKPath)
-			}
-		}
-		for env, values := range namedValues {
-			finalKPath := filepath.Join(helmChartDir, "values-"+env+".yaml")
-			if err := common.WriteYaml(finalKPath, values); err != nil {
-				return filesWritten, err
-			}
-			filesWritten = append(filesWritten, finalKPath)
-		}
-		helmChartYaml := map[string]interface{}{
-			"apiVersion":  "v2",
-			"name":        helmChartName,
-			"version":     "0.1.0",
-			"description": "A Helm Chart generated by Move2Kube for " + helmChartName,
-			"keywords":    []string{helmChartName},
+	}
+	if packSpecPath.Kustomize != "" && packSpecPath.ExistingKustomize != "" {
+		// new resources added to the base of the existing kustomization
+		fw, err := mergeIntoExistingKustomize(cleanSrcDir, filepath.Join(cleanOutDir, packSpecPath.Kustomize), pathedKs, packSpecPath)
+		filesWritten = append(filesWritten, fw...)
+		if err != nil {
+			return filesWritten, err
 		}
-		finalKPath := filepath.Join(helmChartDir, "Chart.yaml")
-		if err := common.WriteYaml(finalKPath, helmChartYaml); err != nil {
+	} else if packSpecPath.Kustomize != "" && getKustomizePatchType(packSpecPath) == parameterizertypes.KustomizeStrategicMergePatches {
+		// kustomize strategic merge patches with multiple overlays
+		fw, err := createStrategicMergeKustomize(filepath.Join(cleanOutDir, packSpecPath.Kustomize), pathedKs, packSpecPath.Envs, ps)
+		filesWritten = append(filesWritten, fw...)
+		if err != nil {
 			return filesWritten, err
 		}
-		filesWritten = append(filesWritten, finalKPath)
-	}
-	if packSpecPath.Kustomize != "" {
+	} else if packSpecPath.Kustomize != "" {
 		// kustomize json patches with multiple overlays
 		kustDir := filepath.Join(cleanOutDir, packSpecPath.Kustomize)
 		baseDir := filepath.Join(kustDir, "base")
@@ -240,6 +224,23 @@ func Parameterize(srcDir, outDir string, packSpecPath parameterizertypes.Packagi
 			filesWritten = append(filesWritten, finalKPath)
 		}
 	}
+	// ytt and Jsonnet are only generated when their output paths are given in the packaging spec
+	if packSpecPath.Ytt != "" {
+		// ytt overlays with data values for each env
+		fw, err := createYtt(filepath.Join(cleanOutDir, packSpecPath.Ytt), pathedKs, packSpecPath.Envs, ps)
+		filesWritten = append(filesWritten, fw...)
+		if err != nil {
+			return filesWritten, err
+		}
+	}
+	if packSpecPath.Jsonnet != "" {
+		// jsonnet library with a tanka environment for each env
+		fw, err := createJsonnet(filepath.Join(cleanOutDir, packSpecPath.Jsonnet), pathedKs, packSpecPath.Envs, ps)
+		filesWritten = append(filesWritten, fw...)
+		if err != nil {
+			return filesWritten, err
+		}
+	}
 	return filesWritten, nil
 }
 
@@ -347,6 +348,84 @@ func splitOnIdxs(s string, idxs []int) []parameterizertypes.ParamOrStringT {
 	return ss
 }
 
+// getValueKeyAndExpression returns the key of the parameter in the values and the expression that refers to it
+func getValueKeyAndExpression(target parameterizertypes.ParamTargetT, subKeys []string) (string, string) {
+	switch target {
+	case parameterizertypes.TargetYtt:
+		quotedKeys := []string{}
+		identifiers := []string{}
+		for _, subKey := range subKeys {
+			identifier := getYttIdentifier(strings.Trim(subKey, `"`))
+			quotedKeys = append(quotedKeys, `"`+identifier+`"`)
+			identifiers = append(identifiers, identifier)
+		}
+		return strings.Join(quotedKeys, "."), "data.values." + strings.Join(identifiers, ".")
+	case parameterizertypes.TargetJsonnet:
+		expression := "params"
+		for _, subKey := range subKeys {
+			expression += "[" + strconv.Quote(strings.Trim(subKey, `"`)) + "]"
+		}
+		return strings.Join(subKeys, "."), expression
+	}
+	return strings.Join(subKeys, "."), fmt.Sprintf(`{{ index .Values %s }}`, strings.Join(subKeys, " "))
+}
+
+// getValueTemplate returns the value that replaces the original value in the k8s resource.
+// For ytt and Jsonnet it is a placeholder that is turned back into the expression when the files are written.
+func getValueTemplate(target parameterizertypes.ParamTargetT, expression string) string {
+	if target == parameterizertypes.TargetHelm {
+		return expression
+	}
+	return valueExpressionPrefix + string(target) + ":" + expression + valueExpressionSuffix
+}
+
+// getValueExpression returns the expression if the value is a placeholder created by getValueTemplate
+func getValueExpression(target parameterizertypes.ParamTargetT, value interface{}) (string, bool) {
+	valueStr, ok := value.(string)
+	if !ok {
+		return "", false
+	}
+	prefix := valueExpressionPrefix + string(target) + ":"
+	if !strings.HasPrefix(valueStr, prefix) || !strings.HasSuffix(valueStr, valueExpressionSuffix) {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(valueStr, prefix), valueExpressionSuffix), true
+}
+
+// getMultiValueTemplate concatenates the strings and the expressions of the parameters in the template
+func getMultiValueTemplate(target parameterizertypes.ParamTargetT, paramsAndStrings []parameterizertypes.ParamOrStringT, expressions []string) string {
+	if target == parameterizertypes.TargetHelm {
+		fullTemplate := ""
+		expressionIdx := 0
+		for _, pOrS := range paramsAndStrings {
+			if !pOrS.IsParam {
+				fullTemplate += pOrS.Data
+				continue
+			}
+			fullTemplate += expressions[expressionIdx]
+			expressionIdx++
+		}
+		return fullTemplate
+	}
+	toString := "str(%s)"
+	if target == parameterizertypes.TargetJsonnet {
+		toString = "std.toString(%s)"
+	}
+	parts := []string{}
+	expressionIdx := 0
+	for _, pOrS := range paramsAndStrings {
+		if !pOrS.IsParam {
+			if pOrS.Data != "" {
+				parts = append(parts, strconv.Quote(pOrS.Data))
+			}
+			continue
+		}
+		parts = append(parts, fmt.Sprintf(toString, expressions[expressionIdx]))
+		expressionIdx++
+	}
+	return getValueTemplate(target, strings.Join(parts, " + "))
+}
+
 func subKeysToJSONPointer6901(subKeys []string) string {
 	for i, subKey := range subKeys {
 		if strings.HasPrefix(subKey, "[") && strings.HasSuffix(subKey, "]") {
@@ -420,6 +499,14 @@ func parameterize(target parameterizertypes.ParamTargetT, envs []string, k param
 			if err := parameterizeHelperOCTemplates(envs, k, p, namedValues, namedKustPatches, namedOCParams); err != nil {
 				return err
 			}
+		case parameterizertypes.TargetYtt:
+			if err := parameterizeHelperYtt(envs, k, p, namedValues, namedKustPatches, namedOCParams); err != nil {
+				return err
+			}
+		case parameterizertypes.TargetJsonnet:
+			if err := parameterizeHelperJsonnet(envs, k, p, namedValues, namedKustPatches, namedOCParams); err != nil {
+				return err
+			}
 		default:
 			return fmt.Errorf("unsupported parameterization type: %+v", target)
 		}
@@ -431,8 +518,7 @@ func parameterize(target parameterizertypes.ParamTargetT, envs []string, k param
 func parameterizeFilter(envs []string, k parameterizertypes.K8sResourceT, p parameterizertypes.ParameterizerT) (bool, error) {
 	log.Trace("start parameterizeFilter")
 	defer log.Trace("end parameterizeFilter")
-	kind, apiVersion, metadataName, err := k8sschema.GetInfoFromK8sResource(k)
-	if err != nil {
+	if _, _, _, err := k8sschema.GetInfoFromK8sResource(k); err != nil {
 		return false, err
 	}
 	if len(p.Filters) == 0 {
@@ -440,57 +526,98 @@ func parameterizeFilter(envs []string, k parameterizertypes.K8sResourceT, p para
 		return true, nil
 	}
 	for _, filter := range p.Filters {
-		// empty kind matches all kinds
-		if filter.Kind != "" {
-			re, err := regexp.Compile("^" + filter.Kind + "$")
-			if err != nil {
-				return false, err
-			}
-			if !re.MatchString(kind) {
-				continue
-			}
+		mismatch, err := getFilterMismatch(envs, k, filter)
+		if err != nil {
+			return false, err
 		}
-		// empty apiVersion matches all apiVersions
-		if filter.APIVersion != "" {
-			re, err := regexp.Compile("^" + filter.APIVersion + "$")
-			if err != nil {
-				return false, err
-			}
-			if !re.MatchString(apiVersion) {
-				continue
-			}
+		if mismatch == "" {
+			return true, nil
 		}
-		// empty name matches all names
-		if filter.Name != "" {
-			re, err := regexp.Compile("^" + filter.Name + "$")
-			if err != nil {
-				return false, err
-			}
-			if !re.MatchString(metadataName) {
-				continue
-			}
+	}
+	return false, nil
+}
+
+// getFilterMismatch returns the reason why the filter does not match the given k8s resource, or an empty string if it matches
+func getFilterMismatch(envs []string, k parameterizertypes.K8sResourceT, filter parameterizertypes.FilterT) (string, error) {
+	kind, apiVersion, metadataName, err := k8sschema.GetInfoFromK8sResource(k)
+	if err != nil {
+		return "", err
+	}
+	// empty kind matches all kinds
+	if filter.Kind != "" {
+		re, err := regexp.Compile("^" + filter.Kind + "$")
+		if err != nil {
+			return "", err
 		}
-		if filter.Envs != nil {
-			found := false
-			for _, env := range envs {
-				if common.IsStringPresent(filter.Envs, env) {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
+		if !re.MatchString(kind) {
+			return fmt.Sprintf("the kind %s does not match %s", kind, filter.Kind), nil
+		}
+	}
+	// empty apiVersion matches all apiVersions
+	if filter.APIVersion != "" {
+		re, err := regexp.Compile("^" + filter.APIVersion + "$")
+		if err != nil {
+			return "", err
+		}
+		if !re.MatchString(apiVersion) {
+			return fmt.Sprintf("the apiVersion %s does not match %s", apiVersion, filter.APIVersion), nil
+		}
+	}
+	// empty name matches all names
+	if filter.Name != "" {
+		re, err := regexp.Compile("^" + filter.Name + "$")
+		if err != nil {
+			return "", err
+		}
+		if !re.MatchString(metadataName) {
+			return fmt.Sprintf("the name %s does not match %s", metadataName, filter.Name), nil
+		}
+	}
+	if filter.Envs != nil {
+		found := false
+		for _, env := range envs {
+			if common.IsStringPresent(filter.Envs, env) {
+				found = true
+				break
 			}
 		}
-		return true, nil
+		if !found {
+			return fmt.Sprintf("none of the envs %+v are in %+v", envs, filter.Envs), nil
+		}
 	}
-	return false, nil
+	if filter.Expression != "" {
+		ok, err := evalFilterExpression(filter.Expression, envs, k)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return fmt.Sprintf("the expression %s is false", filter.Expression), nil
+		}
+	}
+	return "", nil
 }
 
 func parameterizeHelperHelm(envs []string, k parameterizertypes.K8sResourceT, p parameterizertypes.ParameterizerT, namedValues map[string]parameterizertypes.HelmValuesT, namedKustPatches map[string]map[string]parameterizertypes.PatchT, namedOCParams map[string]map[string]string) error {
 	log.Trace("start parameterizeHelperHelm")
 	defer log.Trace("end parameterizeHelperHelm")
+	return parameterizeHelperValues(parameterizertypes.TargetHelm, envs, k, p, namedValues)
+}
 
+func parameterizeHelperYtt(envs []string, k parameterizertypes.K8sResourceT, p parameterizertypes.ParameterizerT, namedValues map[string]parameterizertypes.HelmValuesT, namedKustPatches map[string]map[string]parameterizertypes.PatchT, namedOCParams map[string]map[string]string) error {
+	log.Trace("start parameterizeHelperYtt")
+	defer log.Trace("end parameterizeHelperYtt")
+	return parameterizeHelperValues(parameterizertypes.TargetYtt, envs, k, p, namedValues)
+}
+
+func parameterizeHelperJsonnet(envs []string, k parameterizertypes.K8sResourceT, p parameterizertypes.ParameterizerT, namedValues map[string]parameterizertypes.HelmValuesT, namedKustPatches map[string]map[string]parameterizertypes.PatchT, namedOCParams map[string]map[string]string) error {
+	log.Trace("start parameterizeHelperJsonnet")
+	defer log.Trace("end parameterizeHelperJsonnet")
+	return parameterizeHelperValues(parameterizertypes.TargetJsonnet, envs, k, p, namedValues)
+}
+
+// parameterizeHelperValues replaces the values in the k8s resource with expressions that refer to the values of each env.
+// Helm, ytt and Jsonnet only differ in the syntax of these expressions.
+func parameterizeHelperValues(target parameterizertypes.ParamTargetT, envs []string, k parameterizertypes.K8sResourceT, p parameterizertypes.ParameterizerT, namedValues map[string]parameterizertypes.HelmValuesT) error {
 	if len(p.Target) == 0 {
 		return fmt.Errorf("the target is empty")
 	}
@@ -573,8 +700,8 @@ func parameterizeHelperHelm(envs []string, k parameterizertypes.K8sResourceT, p
 					return fmt.Errorf("failed to find the sub key $(%s) in the any of the keys that matched: %+v", subKey, resultKV)
 				}
 			}
-			paramKey := strings.Join(subKeys, ".")
-			helmTemplate := fmt.Sprintf(`{{ index .Values %s }}`, strings.Join(subKeys, " "))
+			paramKey, valueExpression := getValueKeyAndExpression(target, subKeys)
+			valueTemplate := getValueTemplate(target, valueExpression)
 			if len(p.Parameters) > 0 {
 				if len(p.Parameters) != 1 {
 					return fmt.Errorf("the template only has a single parameter. Expected a single paramter definition. Actual length: %d Parameters: %+v", len(p.Parameters), p.Parameters)
@@ -583,16 +710,13 @@ func parameterizeHelperHelm(envs []string, k parameterizertypes.K8sResourceT, p
 				if param.Name != parameter {
 					return fmt.Errorf("the name in the paramter definition doesn't match the name in the template. Parameters: %+v", param)
 				}
-				if param.HelmTemplate != "" {
-					helmTemplate = param.HelmTemplate
+				if param.HelmTemplate != "" && target == parameterizertypes.TargetHelm {
+					valueTemplate = param.HelmTemplate
 				}
 				if param.Default != "" {
 					paramValue = param.Default
 				}
 			}
-			if err := set(key, helmTemplate, k); err != nil {
-				return fmt.Errorf("failed to set the key %s to the value %s in the k8s resource: %+v\nError: %q", key, helmTemplate, k, err)
-			}
 			for _, env := range envs {
 				origParamValue := paramValue
 				if len(p.Parameters) > 0 {
@@ -604,6 +728,9 @@ func parameterizeHelperHelm(envs []string, k parameterizertypes.K8sResourceT, p
 						}
 					}
 				}
+				if paramValue, err = evalParameterExpression(p.Expression, p.Parameters, parameter, env, paramValue, k, resultKV.Matches); err != nil {
+					return err
+				}
 				// set the key in the values.yaml
 				if _, ok := namedValues[env]; !ok {
 					namedValues[env] = parameterizertypes.HelmValuesT{}
@@ -613,6 +740,9 @@ func parameterizeHelperHelm(envs []string, k parameterizertypes.K8sResourceT, p
 				}
 				paramValue = origParamValue
 			}
+			if err := set(key, valueTemplate, k); err != nil {
+				return fmt.Errorf("failed to set the key %s to the value %s in the k8s resource: %+v\nError: %q", key, valueTemplate, k, err)
+			}
 			return nil
 		}
 		// multiple parameters only make sense when the original value is a string
@@ -636,7 +766,7 @@ Actual value is %+v of type %T`,
 		if err != nil {
 			return fmt.Errorf("failed to parse the multi parameter template: %s\nError: %q", templ, err)
 		}
-		helmTemplates := []string{}
+		valueExpressions := []string{}
 		paramKeys := []string{}
 		for _, parameter := range parameters {
 			subKeys := GetSubKeys(parameter)
@@ -662,46 +792,24 @@ Actual value is %+v of type %T`,
 					return fmt.Errorf("failed to find the sub key $(%s) in the any of the keys that matched: %+v", subKey, resultKV)
 				}
 			}
-			paramKeys = append(paramKeys, strings.Join(subKeys, "."))
-			helmTemplate := fmt.Sprintf(`{{ index .Values %s }}`, strings.Join(subKeys, " "))
+			paramKey, valueExpression := getValueKeyAndExpression(target, subKeys)
+			paramKeys = append(paramKeys, paramKey)
 			for _, stParameter := range p.Parameters {
 				if stParameter.Name != parameter {
 					continue
 				}
-				if stParameter.HelmTemplate != "" {
-					helmTemplate = stParameter.HelmTemplate
-				}
-				break
-			}
-			helmTemplates = append(helmTemplates, helmTemplate)
-		}
-		fullHelmTemplate := ""
-		helmTemplateIdx := 0
-		for _, pOrS := range paramsAndStrings {
-			if !pOrS.IsParam {
-				fullHelmTemplate += pOrS.Data
-				continue
-			}
-			currHelmTemplate := helmTemplates[helmTemplateIdx]
-			for _, param := range p.Parameters {
-				if "${"+param.Name+"}" != pOrS.Data {
-					continue
-				}
-				if param.HelmTemplate != "" {
-					currHelmTemplate = param.HelmTemplate
+				if stParameter.HelmTemplate != "" && target == parameterizertypes.TargetHelm {
+					valueExpression = stParameter.HelmTemplate
 				}
 				break
 			}
-			fullHelmTemplate += currHelmTemplate
-			helmTemplateIdx++
-		}
-		if err := set(key, fullHelmTemplate, k); err != nil {
-			return fmt.Errorf("failed to set the key %s to the value %s in the k8s resource: %+v\nError: %q", key, fullHelmTemplate, k, err)
+			valueExpressions = append(valueExpressions, valueExpression)
 		}
+		fullValueTemplate := getMultiValueTemplate(target, paramsAndStrings, valueExpressions)
 		// set all the keys in the values.yaml
 		for i, parameter := range parameters {
 			paramKey := paramKeys[i]
-			paramValue := originalValues[i]
+			var paramValue interface{} = originalValues[i]
 			for _, env := range envs {
 				origParamValue := paramValue
 				for _, param := range p.Parameters {
@@ -719,6 +827,9 @@ Actual value is %+v of type %T`,
 					}
 					break
 				}
+				if paramValue, err = evalParameterExpression("", p.Parameters, parameter, env, paramValue, k, resultKV.Matches); err != nil {
+					return err
+				}
 				// set the key in the values.yaml
 				if _, ok := namedValues[env]; !ok {
 					namedValues[env] = parameterizertypes.HelmValuesT{}
@@ -729,6 +840,9 @@ Actual value is %+v of type %T`,
 				paramValue = origParamValue
 			}
 		}
+		if err := set(key, fullValueTemplate, k); err != nil {
+			return fmt.Errorf("failed to set the key %s to the value %s in the k8s resource: %+v\nError: %q", key, fullValueTemplate, k, err)
+		}
 	}
 	return nil
 }
@@ -803,6 +917,13 @@ func parameterizeHelperKustomize(envs []string, k parameterizertypes.K8sResource
 					}
 				}
 			}
+			parameter := ""
+			if len(p.Parameters) == 1 {
+				parameter = p.Parameters[0].Name
+			}
+			if paramValue, err = evalParameterExpression(p.Expression, p.Parameters, parameter, env, paramValue, k, resultKV.Matches); err != nil {
+				return err
+			}
 			if _, ok := namedKustPatches[env]; !ok {
 				namedKustPatches[env] = map[string]parameterizertypes.PatchT{}
 			}
@@ -933,6 +1054,9 @@ func parameterizeHelperOCTemplates(envs []string, k parameterizertypes.K8sResour
 						}
 					}
 				}
+				if paramValue, err = evalParameterExpression(p.Expression, p.Parameters, parameter, env, paramValue, k, resultKV.Matches); err != nil {
+					return err
+				}
 				if _, ok := namedOCParams[env]; !ok {
 					namedOCParams[env] = map[string]string{}
 				}
@@ -1039,13 +1163,10 @@ Actual value is %+v of type %T`,
 			fullOCTemplate += currOCTemplate
 			ocTemplateIdx++
 		}
-		if err := set(key, fullOCTemplate, k); err != nil {
-			return fmt.Errorf("failed to set the key %s to the value %s in the k8s resource: %+v\nError: %q", key, fullOCTemplate, k, err)
-		}
 		// set all the keys in the values.yaml
 		for i, parameter := range parameters {
 			ocParamKey := paramKeys[i]
-			paramValue := originalValues[i]
+			var paramValue interface{} = originalValues[i]
 			for _, env := range envs {
 				origParamValue := paramValue
 				for _, param := range p.Parameters {
@@ -1063,14 +1184,24 @@ Actual value is %+v of type %T`,
 					}
 					break
 				}
+				if paramValue, err = evalParameterExpression("", p.Parameters, parameter, env, paramValue, k, resultKV.Matches); err != nil {
+					return err
+				}
 				// set the key in the values.yaml
 				if _, ok := namedOCParams[env]; !ok {
 					namedOCParams[env] = map[string]string{}
 				}
-				namedOCParams[env][ocParamKey] = paramValue
+				paramValueStr, err := cast.ToStringE(paramValue)
+				if err != nil {
+					return fmt.Errorf("openshift templates require parameter value to be string (or convertible to string)")
+				}
+				namedOCParams[env][ocParamKey] = paramValueStr
 				paramValue = origParamValue
 			}
 		}
+		if err := set(key, fullOCTemplate, k); err != nil {
+			return fmt.Errorf("failed to set the key %s to the value %s in the k8s resource: %+v\nError: %q", key, fullOCTemplate, k, err)
+		}
 	}
 	return nil
 }
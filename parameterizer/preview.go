@@ -0,0 +1,229 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/k8sschema"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+)
+
+// Preview returns what the parameterizers would do to the k8s resources, without writing any files or asking any questions
+func Preview(srcDir string, packSpecPath parameterizertypes.PackagingSpecPathT, ps []parameterizertypes.ParameterizerT) (parameterizertypes.ReportT, error) {
+	report := parameterizertypes.ReportT{Src: packSpecPath.Src, Resources: []parameterizertypes.ResourceReportT{}}
+	envs := packSpecPath.Envs
+	if len(envs) == 0 {
+		envs = defaultEnvs
+	}
+	pathedKs, err := k8sschema.GetK8sResourcesWithPaths(filepath.Join(srcDir, packSpecPath.Src))
+	if err != nil {
+		return report, err
+	}
+	kPaths := []string{}
+	for kPath := range pathedKs {
+		kPaths = append(kPaths, kPath)
+	}
+	sort.Strings(kPaths)
+	matched := make([]bool, len(ps))
+	for _, kPath := range kPaths {
+		for _, k := range pathedKs[kPath] {
+			kind, apiVersion, metadataName, err := k8sschema.GetInfoFromK8sResource(k)
+			if err != nil {
+				continue
+			}
+			resourceReport := parameterizertypes.ResourceReportT{Path: kPath, Kind: kind, APIVersion: apiVersion, Name: metadataName, Parameterizers: []parameterizertypes.ParameterizerResultT{}}
+			for i, p := range ps {
+				result := previewParameterizer(envs, k, p)
+				result.ParameterizerRefT = parameterizertypes.ParameterizerRefT{Index: i, Target: p.Target}
+				matched[i] = matched[i] || result.Matched
+				resourceReport.Parameterizers = append(resourceReport.Parameterizers, result)
+			}
+			report.Resources = append(report.Resources, resourceReport)
+		}
+	}
+	for i, p := range ps {
+		if !matched[i] {
+			report.UnmatchedParameterizers = append(report.UnmatchedParameterizers, parameterizertypes.ParameterizerRefT{Index: i, Target: p.Target})
+		}
+	}
+	return report, nil
+}
+
+// previewParameterizer returns the parameters the parameterizer creates for the k8s resource, or the reason why it does not match
+func previewParameterizer(envs []string, k parameterizertypes.K8sResourceT, p parameterizertypes.ParameterizerT) parameterizertypes.ParameterizerResultT {
+	result := parameterizertypes.ParameterizerResultT{}
+	mismatches := []string{}
+	for _, filter := range p.Filters {
+		mismatch, err := getFilterMismatch(envs, k, filter)
+		if err != nil {
+			result.Reason = "invalid filter: " + err.Error()
+			return result
+		}
+		if mismatch == "" {
+			mismatches = nil
+			break
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+	if len(mismatches) != 0 {
+		result.Reason = "filter mismatch: " + strings.Join(mismatches, "; ")
+		return result
+	}
+	resultKVs, err := GetAll(p.Target, k)
+	if err != nil || len(resultKVs) == 0 {
+		result.Reason = fmt.Sprintf("path absent: the target %s does not exist in the resource", p.Target)
+		return result
+	}
+	for _, resultKV := range resultKVs {
+		pathResult := previewPath(envs, k, p, resultKV)
+		result.Matched = result.Matched || pathResult.Reason == ""
+		result.Paths = append(result.Paths, pathResult)
+	}
+	if !result.Matched {
+		result.Reason = "none of the paths can be parameterized"
+	}
+	return result
+}
+
+// previewPath returns the parameters the parameterizer creates at a single path of the k8s resource
+func previewPath(envs []string, k parameterizertypes.K8sResourceT, p parameterizertypes.ParameterizerT, resultKV RT) parameterizertypes.PathResultT {
+	kind, apiVersion, metadataName, _ := k8sschema.GetInfoFromK8sResource(k)
+	quotedKeys := []string{}
+	for _, subKey := range resultKV.Key {
+		quotedKeys = append(quotedKeys, `"`+subKey+`"`)
+	}
+	key := strings.Join(quotedKeys, ".")
+	pathResult := parameterizertypes.PathResultT{Path: subKeysToJSONPointer6901(append([]string{}, resultKV.Key...))}
+	templ := p.Template
+	if templ == "" {
+		templ = fmt.Sprintf(`${"%s"."%s"."%s".%s}`, kind, apiVersion, metadataName, key)
+	}
+	parameters, err := getParameters(templ)
+	if err != nil {
+		pathResult.Reason = "invalid template: " + err.Error()
+		return pathResult
+	}
+	question := ""
+	if p.Question != nil {
+		question = p.Question.ID
+		if question == "" {
+			question = parameterizertypes.ParamQuesIDPrefix + common.Delim + apiVersion + common.Delim + kind + common.Delim + key
+		}
+	}
+	value := p.Default
+	if value == nil {
+		value = resultKV.Value
+	}
+	if len(parameters) == 1 {
+		var paramDef *parameterizertypes.ParameterT
+		if len(p.Parameters) == 1 {
+			paramDef = &p.Parameters[0]
+			if paramDef.Default != "" {
+				value = paramDef.Default
+			}
+		}
+		paramResult, err := previewParameter(envs, k, p, parameters[0], paramDef, value, resultKV)
+		if err != nil {
+			pathResult.Reason = "expression failed: " + err.Error()
+			return pathResult
+		}
+		paramResult.Question = question
+		pathResult.Parameters = []parameterizertypes.ParameterResultT{paramResult}
+		return pathResult
+	}
+	defaultStr, ok := value.(string)
+	if !ok {
+		pathResult.Reason = fmt.Sprintf("the template %s has multiple parameters, but the value %+v is not a string", templ, value)
+		return pathResult
+	}
+	originalValues, _, err := parseTemplate(templ, defaultStr, p.Regex)
+	if err != nil {
+		pathResult.Reason = "regex miss: " + err.Error()
+		return pathResult
+	}
+	// The expression of the parameterizer only applies to templates with a single parameter
+	p.Expression = ""
+	for i, parameter := range parameters {
+		var value interface{} = originalValues[i]
+		var paramDef *parameterizertypes.ParameterT
+		for j, param := range p.Parameters {
+			if param.Name != parameter {
+				continue
+			}
+			paramDef = &p.Parameters[j]
+			if param.Default != "" {
+				value = param.Default
+			}
+			break
+		}
+		paramResult, err := previewParameter(envs, k, p, parameter, paramDef, value, resultKV)
+		if err != nil {
+			pathResult.Reason = "expression failed: " + err.Error()
+			return pathResult
+		}
+		paramResult.Question = question
+		pathResult.Parameters = append(pathResult.Parameters, paramResult)
+	}
+	return pathResult
+}
+
+// previewParameter returns the default value of the parameter and its value in each env
+func previewParameter(envs []string, k parameterizertypes.K8sResourceT, p parameterizertypes.ParameterizerT, parameter string, paramDef *parameterizertypes.ParameterT, value interface{}, resultKV RT) (parameterizertypes.ParameterResultT, error) {
+	kind, apiVersion, metadataName, _ := k8sschema.GetInfoFromK8sResource(k)
+	subKeys := GetSubKeys(parameter)
+	for i, subKey := range subKeys {
+		if !strings.HasPrefix(subKey, "$(") || !strings.HasSuffix(subKey, ")") {
+			continue
+		}
+		subKey = strings.TrimSuffix(strings.TrimPrefix(subKey, "$("), ")")
+		if matchedSubKey, ok := resultKV.Matches[subKey]; ok {
+			subKeys[i] = matchedSubKey
+			continue
+		}
+		switch subKey {
+		case "kind":
+			subKeys[i] = kind
+		case "apiVersion":
+			subKeys[i] = apiVersion
+		case "metadataName":
+			subKeys[i] = metadataName
+		}
+	}
+	paramResult := parameterizertypes.ParameterResultT{Name: strings.Join(subKeys, "."), Default: value, Values: map[string]interface{}{}}
+	for _, env := range envs {
+		envValue := value
+		if paramDef != nil {
+			for _, pV := range paramDef.Values {
+				if doesMatchEnv(pV, env, kind, apiVersion, metadataName, resultKV.Matches) {
+					envValue = pV.Value
+					break
+				}
+			}
+		}
+		envValue, err := evalParameterExpression(p.Expression, p.Parameters, parameter, env, envValue, k, resultKV.Matches)
+		if err != nil {
+			return paramResult, err
+		}
+		paramResult.Values[env] = envValue
+	}
+	return paramResult, nil
+}
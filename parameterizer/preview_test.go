@@ -0,0 +1,79 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/parameterizer"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+)
+
+func TestPreview(t *testing.T) {
+	srcDir := t.TempDir()
+	writeFiles(t, srcDir, map[string]string{"orders-deployment.yaml": ordersYaml, "orders-config.yaml": ordersConfigYaml})
+	deployments := []parameterizertypes.FilterT{{Kind: common.DeploymentKind}}
+	ps := []parameterizertypes.ParameterizerT{
+		{Target: "spec.replicas", Template: "${replicas}", Filters: deployments, Parameters: []parameterizertypes.ParameterT{{Name: "replicas", Values: []parameterizertypes.ParameterValueT{{Envs: []string{"prod"}, Value: "3"}}}}},
+		{Target: "spec.template.spec.containers.[name=orders].image", Template: "${registry}/${name}:${tag}", Regex: `([^/]+)/(.+)@(.+)`, Filters: deployments},
+		{Target: "spec.strategy.type", Template: "${strategy}", Filters: deployments},
+		{Target: "spec.ports", Template: "${ports}", Filters: []parameterizertypes.FilterT{{Kind: "Service"}}},
+	}
+	report, err := parameterizer.Preview(srcDir, parameterizertypes.PackagingSpecPathT{Envs: []string{"dev", "prod"}}, ps)
+	if err != nil {
+		t.Fatalf("Failed to preview the parameterization. Error: %q", err)
+	}
+	if len(report.Resources) != 2 || report.Resources[1].Name != "orders" {
+		t.Fatalf("Expected a report for the config map and the deployment. Actual: %+v", report.Resources)
+	}
+	config, orders := report.Resources[0].Parameterizers, report.Resources[1].Parameterizers
+
+	t.Run("filter mismatch", func(t *testing.T) {
+		if config[0].Matched || !strings.HasPrefix(config[0].Reason, "filter mismatch: the kind ConfigMap") {
+			t.Fatalf("Expected the filter of the deployments not to match the config map. Actual: %+v", config[0])
+		}
+	})
+
+	t.Run("parameters with the values of each env", func(t *testing.T) {
+		if !orders[0].Matched || len(orders[0].Paths) != 1 || orders[0].Paths[0].Path != "/spec/replicas" {
+			t.Fatalf("Expected the replicas to be parameterized. Actual: %+v", orders[0])
+		}
+		want := parameterizertypes.ParameterResultT{Name: "replicas", Default: 1.0, Values: map[string]interface{}{"dev": 1.0, "prod": "3"}}
+		if actual := orders[0].Paths[0].Parameters; len(actual) != 1 || !cmp.Equal(actual[0], want) {
+			t.Fatalf("Unexpected parameter. Differences:\n%s", cmp.Diff(want, actual))
+		}
+	})
+
+	t.Run("regex miss and path absent", func(t *testing.T) {
+		if orders[1].Matched || len(orders[1].Paths) != 1 || !strings.HasPrefix(orders[1].Paths[0].Reason, "regex miss") {
+			t.Fatalf("Expected the image to not match the regex. Actual: %+v", orders[1])
+		}
+		if orders[2].Matched || !strings.HasPrefix(orders[2].Reason, "path absent") {
+			t.Fatalf("Expected the strategy to be absent. Actual: %+v", orders[2])
+		}
+	})
+
+	t.Run("parameterizers which match nothing", func(t *testing.T) {
+		want := []parameterizertypes.ParameterizerRefT{{Index: 1, Target: ps[1].Target}, {Index: 2, Target: ps[2].Target}, {Index: 3, Target: ps[3].Target}}
+		if !cmp.Equal(report.UnmatchedParameterizers, want) {
+			t.Fatalf("Unexpected unmatched parameterizers. Differences:\n%s", cmp.Diff(want, report.UnmatchedParameterizers))
+		}
+	})
+}
@@ -0,0 +1,126 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/parameterizer"
+	"github.com/konveyor/move2kube/qaengine"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+)
+
+func TestParameterizeYttAndJsonnet(t *testing.T) {
+	qaengine.StartEngine(true, 0, true)
+	srcDir := t.TempDir()
+	writeFiles(t, srcDir, map[string]string{"orders-deployment.yaml": ordersYaml, "orders-config.yaml": ordersConfigYaml})
+	outDir := t.TempDir()
+	deployments := []parameterizertypes.FilterT{{Kind: common.DeploymentKind}}
+	ps := []parameterizertypes.ParameterizerT{
+		{Target: "spec.replicas", Template: "${common.replicas}", Filters: deployments,
+			Parameters: []parameterizertypes.ParameterT{{Name: "common.replicas", Values: []parameterizertypes.ParameterValueT{{Envs: []string{"prod"}, Value: "3"}}}}},
+		{Target: "spec.template.spec.containers.[name=orders].image", Template: "${imageregistry}/shop/${imagename}", Filters: deployments},
+	}
+	packSpecPath := parameterizertypes.PackagingSpecPathT{Ytt: "ytt", Jsonnet: "jsonnet", Envs: []string{"dev", "prod"}}
+	if _, err := parameterizer.Parameterize(srcDir, outDir, packSpecPath, ps); err != nil {
+		t.Fatalf("Failed to parameterize. Error: %q", err)
+	}
+	readFile := func(path string) string {
+		data, err := ioutil.ReadFile(filepath.Join(outDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read the file %s. Error: %q", path, err)
+		}
+		return string(data)
+	}
+
+	t.Run("ytt overlays use the data values", func(t *testing.T) {
+		if config := readFile(filepath.Join("ytt", "config", "orders-deployment.yaml")); !strings.Contains(config, "replicas: 1") {
+			t.Fatalf("Expected the original resource in the config. Actual: %s", config)
+		}
+		overlays := readFile(filepath.Join("ytt", "config", "overlays.yaml"))
+		for _, expected := range []string{
+			`#@overlay/match by=overlay.subset({"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "orders"}})`,
+			"replicas: #@ data.values.common.replicas",
+			"#@overlay/match by=overlay.index(0)",
+			`image: #@ str(data.values.imageregistry) + "/shop/" + str(data.values.imagename)`,
+		} {
+			if !strings.Contains(overlays, expected) {
+				t.Fatalf("Expected the overlays to contain %s. Actual: %s", expected, overlays)
+			}
+		}
+		if strings.Contains(overlays, "orders-config") {
+			t.Fatalf("Expected no overlay for the config map. Actual: %s", overlays)
+		}
+		if prod := readFile(filepath.Join("ytt", "envs", "prod", "values.yaml")); !strings.HasPrefix(prod, "#@data/values\n---\n") || !strings.Contains(prod, `replicas: "3"`) || !strings.Contains(prod, "imageregistry: quay.io") {
+			t.Fatalf("Expected the data values of prod. Actual: %s", prod)
+		}
+		if defaults := readFile(filepath.Join("ytt", "config", "values.yaml")); !strings.Contains(defaults, "replicas: 1") {
+			t.Fatalf("Expected the data values of the first env to be the defaults. Actual: %s", defaults)
+		}
+	})
+
+	t.Run("jsonnet library is a function of the params", func(t *testing.T) {
+		resources := readFile(filepath.Join("jsonnet", "lib", "resources.libsonnet"))
+		for _, expected := range []string{
+			"function(params) {",
+			`"apps-v1-deployment-orders": {`,
+			`"replicas": params["common"]["replicas"],`,
+			`"image": std.toString(params["imageregistry"]) + "/shop/" + std.toString(params["imagename"]),`,
+		} {
+			if !strings.Contains(resources, expected) {
+				t.Fatalf("Expected the library to contain %s. Actual: %s", expected, resources)
+			}
+		}
+		if prod := readFile(filepath.Join("jsonnet", "environments", "prod", "params.libsonnet")); !strings.Contains(prod, `"replicas": "3",`) {
+			t.Fatalf("Expected the params of prod. Actual: %s", prod)
+		}
+		if main := readFile(filepath.Join("jsonnet", "environments", "dev", "main.jsonnet")); !strings.Contains(main, "import '../../lib/resources.libsonnet'") {
+			t.Fatalf("Expected the environment to import the library. Actual: %s", main)
+		}
+		if spec := readFile(filepath.Join("jsonnet", "environments", "dev", "spec.json")); !strings.Contains(spec, `"name": "environments/dev"`) {
+			t.Fatalf("Expected a tanka environment. Actual: %s", spec)
+		}
+	})
+}
+
+func TestParameterizeWithoutYttAndJsonnetPaths(t *testing.T) {
+	qaengine.StartEngine(true, 0, true)
+	srcDir := t.TempDir()
+	writeFiles(t, srcDir, map[string]string{"orders-deployment.yaml": ordersYaml})
+	outDir := t.TempDir()
+	ps := []parameterizertypes.ParameterizerT{{Target: "spec.replicas", Template: "${common.replicas}", Filters: []parameterizertypes.FilterT{{Kind: common.DeploymentKind}}}}
+	filesWritten, err := parameterizer.Parameterize(srcDir, outDir, parameterizertypes.PackagingSpecPathT{}, ps)
+	if err != nil {
+		t.Fatalf("Failed to parameterize. Error: %q", err)
+	}
+	for _, fileWritten := range filesWritten {
+		relFilePath, err := filepath.Rel(outDir, fileWritten)
+		if err != nil {
+			t.Fatalf("Failed to make the file path %s relative to the output path %s . Error: %q", fileWritten, outDir, err)
+		}
+		if strings.HasPrefix(relFilePath, "ytt"+string(filepath.Separator)) || strings.HasPrefix(relFilePath, "jsonnet"+string(filepath.Separator)) {
+			t.Fatalf("Expected ytt and Jsonnet to be generated only when their paths are given. Actual: %s", relFilePath)
+		}
+	}
+	if len(filesWritten) == 0 {
+		t.Fatal("Expected the helm chart, kustomize and openshift templates to be written")
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ingressKind = "Ingress"
+	routeKind   = "Route"
+	pvcKind     = "PersistentVolumeClaim"
+	// scalableKinds are the kinds of the workloads whose replicas can be parameterized
+	scalableKinds = "Deployment|StatefulSet|DeploymentConfig|ReplicationController"
+	// imageTagRegex splits an image into its repository and its tag
+	imageTagRegex = `(.+):([^:/]+)`
+)
+
+var (
+	// containersKeys are the keys of the containers in the workloads of each kind
+	containersKeys = []struct{ kinds, key string }{
+		{kinds: "Deployment|StatefulSet|DaemonSet|DeploymentConfig|ReplicationController|Job", key: "spec.template.spec.containers"},
+		{kinds: "CronJob", key: "spec.jobTemplate.spec.template.spec.containers"},
+		{kinds: "Pod", key: "spec.containers"},
+	}
+	taggedImageRegex = regexp.MustCompile("^" + imageTagRegex + "$")
+)
+
+// CollectTransformMetadataFromPath returns the metadata written by the transform, keyed by the directory containing the k8s resources
+func CollectTransformMetadataFromPath(dir string) (map[string]parameterizertypes.TransformMetadataFileT, error) {
+	metadataPaths, err := common.GetFilesByName(dir, []string{common.DefaultTransformMetadataFile}, nil)
+	if err != nil {
+		return nil, err
+	}
+	metadatas := map[string]parameterizertypes.TransformMetadataFileT{}
+	for _, metadataPath := range metadataPaths {
+		metadata := parameterizertypes.TransformMetadataFileT{}
+		if err := common.ReadMove2KubeYamlStrict(metadataPath, &metadata, parameterizertypes.TransformMetadataKind); err != nil {
+			logrus.Errorf("Unable to read the metadata of the transform at path %s : %s", metadataPath, err)
+			continue
+		}
+		metadatas[filepath.Dir(metadataPath)] = metadata
+	}
+	return metadatas, nil
+}
+
+// GetParameterizersFromTransformMetadata returns the parameterizers for the image tags, replicas, hosts, resources and storage sizes of the services
+func GetParameterizersFromTransformMetadata(metadata parameterizertypes.TransformMetadataSpecT) []parameterizertypes.ParameterizerT {
+	ps := []parameterizertypes.ParameterizerT{}
+	exposed := false
+	for _, service := range metadata.Services {
+		name := regexp.QuoteMeta(service.Name)
+		if service.Scalable {
+			ps = append(ps, parameterizertypes.ParameterizerT{
+				Target:   "spec.replicas",
+				Template: fmt.Sprintf("${%s.replicas}", service.Name),
+				Filters:  []parameterizertypes.FilterT{{Kind: scalableKinds, Name: name, Expression: `has("spec.replicas")`}},
+			})
+		}
+		for _, container := range service.Containers {
+			prefix := service.Name
+			if len(service.Containers) > 1 && container.Name != service.Name {
+				prefix += "." + container.Name
+			}
+			for _, containersKey := range containersKeys {
+				containerKey := fmt.Sprintf("%s.[name=%s]", containersKey.key, container.Name)
+				filters := []parameterizertypes.FilterT{{Kind: containersKey.kinds, Name: name}}
+				imageP := parameterizertypes.ParameterizerT{Target: containerKey + ".image", Template: fmt.Sprintf("${%s.image.repository}", prefix), Filters: filters}
+				if taggedImageRegex.MatchString(container.Image) {
+					imageP.Template = fmt.Sprintf("${%s.image.repository}:${%s.image.tag}", prefix, prefix)
+					imageP.Regex = imageTagRegex
+				}
+				ps = append(ps, imageP)
+				for _, resource := range container.Resources {
+					resourceKey := containerKey + ".resources." + resource
+					ps = append(ps, parameterizertypes.ParameterizerT{
+						Target:   resourceKey,
+						Template: fmt.Sprintf("${%s.resources.%s}", prefix, resource),
+						Filters:  []parameterizertypes.FilterT{{Kind: containersKey.kinds, Name: name, Expression: fmt.Sprintf("has(%q)", resourceKey)}},
+					})
+				}
+			}
+		}
+		if service.Exposed {
+			exposed = true
+			ps = append(ps, parameterizertypes.ParameterizerT{
+				Target:   "spec.host",
+				Template: fmt.Sprintf("${%s.host}", service.Name),
+				Filters:  []parameterizertypes.FilterT{{Kind: routeKind, Name: name, Expression: `has("spec.host")`}},
+			})
+		}
+	}
+	if exposed {
+		ps = append(ps, parameterizertypes.ParameterizerT{
+			Target:   "spec.rules.[host].host",
+			Template: "${ingress.hosts.$(host)}",
+			Filters:  []parameterizertypes.FilterT{{Kind: ingressKind}},
+		})
+	}
+	for _, storage := range metadata.Storages {
+		if storage.Kind != pvcKind || storage.Size == "" {
+			continue
+		}
+		ps = append(ps, parameterizertypes.ParameterizerT{
+			Target:   "spec.resources.requests.storage",
+			Template: fmt.Sprintf("${storages.%s.size}", strings.ReplaceAll(storage.Name, ".", "-")),
+			Filters:  []parameterizertypes.FilterT{{Kind: pvcKind, Name: regexp.QuoteMeta(storage.Name), Expression: `has("spec.resources.requests.storage")`}},
+		})
+	}
+	return ps
+}
@@ -0,0 +1,121 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/parameterizer"
+	"github.com/konveyor/move2kube/qaengine"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+)
+
+const (
+	ordersMetadata = `apiVersion: move2kube.konveyor.io/v1alpha1
+kind: TransformMetadata
+metadata:
+  name: shop
+spec:
+  services:
+    - name: orders
+      replicas: 1
+      scalable: true
+      exposed: true
+      containers:
+        - name: orders
+          image: quay.io/shop/orders:1.0
+          ports:
+            - 8080
+  storages:
+    - name: orders-data
+      kind: PersistentVolumeClaim
+      size: 1Gi
+`
+	ordersIngressYaml = `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: shop
+spec:
+  rules:
+    - host: shop.example.com
+      http:
+        paths:
+          - path: /orders
+            pathType: Prefix
+            backend:
+              service:
+                name: orders
+                port:
+                  number: 8080
+`
+	ordersPVCYaml = `apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: orders-data
+spec:
+  accessModes:
+    - ReadWriteOnce
+  resources:
+    requests:
+      storage: 1Gi
+`
+)
+
+func TestParameterizeFromTransformMetadata(t *testing.T) {
+	qaengine.StartEngine(true, 0, true)
+	srcDir := t.TempDir()
+	yamlsDir := filepath.Join("deploy", "yamls")
+	writeFiles(t, filepath.Join(srcDir, yamlsDir), map[string]string{
+		common.DefaultTransformMetadataFile:      ordersMetadata,
+		"orders-deployment.yaml":                 ordersYaml,
+		"shop-ingress.yaml":                      ordersIngressYaml,
+		"orders-data-persistentvolumeclaim.yaml": ordersPVCYaml,
+	})
+	metadatas, err := parameterizer.CollectTransformMetadataFromPath(srcDir)
+	if err != nil {
+		t.Fatalf("Failed to collect the metadata of the transform. Error: %q", err)
+	}
+	metadata, ok := metadatas[filepath.Join(srcDir, yamlsDir)]
+	if !ok || len(metadatas) != 1 {
+		t.Fatalf("Expected the metadata in the directory of the yamls. Actual: %+v", metadatas)
+	}
+	ps := parameterizer.GetParameterizersFromTransformMetadata(metadata.Spec)
+	outDir := t.TempDir()
+	packSpecPath := parameterizertypes.PackagingSpecPathT{Src: yamlsDir, Out: yamlsDir, Envs: []string{"dev"}}
+	if _, err := parameterizer.Parameterize(srcDir, outDir, packSpecPath, ps); err != nil {
+		t.Fatalf("Failed to parameterize. Error: %q", err)
+	}
+	values := map[string]interface{}{}
+	if err := common.ReadYaml(filepath.Join(outDir, yamlsDir, "helm-chart", common.DefaultProjectName, "values-dev.yaml"), &values); err != nil {
+		t.Fatalf("Failed to read the values. Error: %q", err)
+	}
+	orders, ok := values["orders"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected the values of the orders service. Actual: %+v", values)
+	}
+	if orders["replicas"] != 1 || orders["image"].(map[string]interface{})["tag"] != "1.0" || orders["image"].(map[string]interface{})["repository"] != "quay.io/shop/orders" {
+		t.Fatalf("Expected the replicas and the image of the orders service. Actual: %+v", orders)
+	}
+	if hosts := values["ingress"].(map[string]interface{})["hosts"].(map[string]interface{}); hosts["shop.example.com"] != "shop.example.com" {
+		t.Fatalf("Expected the host of the ingress. Actual: %+v", hosts)
+	}
+	if storage := values["storages"].(map[string]interface{})["orders-data"].(map[string]interface{}); storage["size"] != "1Gi" {
+		t.Fatalf("Expected the size of the storage. Actual: %+v", storage)
+	}
+}
@@ -0,0 +1,225 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package parameterizer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/deepcopy"
+	"github.com/konveyor/move2kube/k8sschema"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	yttLoadsHeader    = "#@ load(\"@ytt:overlay\", \"overlay\")\n#@ load(\"@ytt:data\", \"data\")\n"
+	yttDataValuesMark = "#@data/values\n---\n"
+)
+
+var validYttKeyRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.\-/]*$`)
+
+// yttExpressionT is a value in an overlay that is replaced by the result of the expression
+type yttExpressionT string
+
+// yttListItemT is an element of a list in an overlay that is matched by its index
+type yttListItemT struct {
+	Index int
+	Value interface{}
+}
+
+// createYtt writes the original resources along with ytt overlays that set the parameterized values from the data values.
+// The data values of the first env are the defaults and each env has its own data values.
+// The output can be rendered using `ytt -f config -f envs/<env>`
+func createYtt(yttDir string, pathedKs map[string][]parameterizertypes.K8sResourceT, envs []string, ps []parameterizertypes.ParameterizerT) ([]string, error) {
+	filesWritten := []string{}
+	configDir := filepath.Join(yttDir, "config")
+	if err := os.MkdirAll(configDir, common.DefaultDirectoryPermission); err != nil {
+		return filesWritten, err
+	}
+	namedValues := map[string]parameterizertypes.HelmValuesT{}
+	overlays := []string{}
+	kPaths := []string{}
+	for kPath := range pathedKs {
+		kPaths = append(kPaths, kPath)
+	}
+	sort.Strings(kPaths)
+	for _, kPath := range kPaths {
+		documents := []string{}
+		for _, k := range pathedKs[kPath] {
+			yamlBytes, err := yaml.Marshal(k)
+			if err != nil {
+				return filesWritten, fmt.Errorf("failed to marshal the k8s resource %+v to yaml. Error: %q", k, err)
+			}
+			documents = append(documents, "---\n"+string(yamlBytes))
+			k = deepcopy.DeepCopy(k).(parameterizertypes.K8sResourceT)
+			if err := parameterize(parameterizertypes.TargetYtt, envs, k, ps, namedValues, nil, nil); err != nil {
+				return filesWritten, err
+			}
+			overlay, ok := getYttOverlay(map[string]interface{}(k))
+			if !ok {
+				continue
+			}
+			kind, apiVersion, metadataName, err := k8sschema.GetInfoFromK8sResource(k)
+			if err != nil {
+				return filesWritten, err
+			}
+			overlays = append(overlays, fmt.Sprintf(
+				"#@overlay/match by=overlay.subset({\"apiVersion\": %s, \"kind\": %s, \"metadata\": {\"name\": %s}})\n---\n%s",
+				strconv.Quote(apiVersion), strconv.Quote(kind), strconv.Quote(metadataName), writeYttNode(overlay, "", false),
+			))
+		}
+		finalKPath := filepath.Join(configDir, kPath)
+		if err := os.MkdirAll(filepath.Dir(finalKPath), common.DefaultDirectoryPermission); err != nil {
+			return filesWritten, err
+		}
+		if err := ioutil.WriteFile(finalKPath, []byte(strings.Join(documents, "")), common.DefaultFilePermission); err != nil {
+			return filesWritten, fmt.Errorf("failed to write the resources to the file at path %s . Error: %q", finalKPath, err)
+		}
+		filesWritten = append(filesWritten, finalKPath)
+	}
+	if len(overlays) == 0 {
+		return filesWritten, nil
+	}
+	finalKPath := filepath.Join(configDir, "overlays.yaml")
+	if err := ioutil.WriteFile(finalKPath, []byte(yttLoadsHeader+"\n"+strings.Join(overlays, "\n")), common.DefaultFilePermission); err != nil {
+		return filesWritten, fmt.Errorf("failed to write the ytt overlays to the file at path %s . Error: %q", finalKPath, err)
+	}
+	filesWritten = append(filesWritten, finalKPath)
+	if len(envs) == 0 {
+		return filesWritten, nil
+	}
+	finalKPath = filepath.Join(configDir, "values.yaml")
+	if err := ioutil.WriteFile(finalKPath, []byte(yttDataValuesMark+writeYttNode(map[string]interface{}(namedValues[envs[0]]), "", false)), common.DefaultFilePermission); err != nil {
+		return filesWritten, fmt.Errorf("failed to write the ytt data values to the file at path %s . Error: %q", finalKPath, err)
+	}
+	filesWritten = append(filesWritten, finalKPath)
+	for _, env := range envs {
+		envDir := filepath.Join(yttDir, "envs", env)
+		if err := os.MkdirAll(envDir, common.DefaultDirectoryPermission); err != nil {
+			return filesWritten, err
+		}
+		finalKPath := filepath.Join(envDir, "values.yaml")
+		if err := ioutil.WriteFile(finalKPath, []byte(yttDataValuesMark+writeYttNode(map[string]interface{}(namedValues[env]), "", true)), common.DefaultFilePermission); err != nil {
+			return filesWritten, fmt.Errorf("failed to write the ytt data values to the file at path %s . Error: %q", finalKPath, err)
+		}
+		filesWritten = append(filesWritten, finalKPath)
+	}
+	return filesWritten, nil
+}
+
+// getYttOverlay returns the parts of the parameterized resource that contain expressions
+func getYttOverlay(value interface{}) (interface{}, bool) {
+	if expression, ok := getValueExpression(parameterizertypes.TargetYtt, value); ok {
+		return yttExpressionT(expression), true
+	}
+	switch actualValue := value.(type) {
+	case map[string]interface{}:
+		overlay := map[string]interface{}{}
+		for k, v := range actualValue {
+			if vOverlay, ok := getYttOverlay(v); ok {
+				overlay[k] = vOverlay
+			}
+		}
+		return overlay, len(overlay) > 0
+	case []interface{}:
+		overlay := []yttListItemT{}
+		for i, v := range actualValue {
+			if vOverlay, ok := getYttOverlay(v); ok {
+				overlay = append(overlay, yttListItemT{Index: i, Value: vOverlay})
+			}
+		}
+		return overlay, len(overlay) > 0
+	}
+	return nil, false
+}
+
+// writeYttNode writes the overlay or the data values as annotated yaml.
+// When replaceLists is true the lists are replaced instead of being appended to the lists of the earlier data values.
+func writeYttNode(value interface{}, indent string, replaceLists bool) string {
+	out := ""
+	switch actualValue := value.(type) {
+	case map[string]interface{}:
+		keys := []string{}
+		for k := range actualValue {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			key := getYttKey(k)
+			switch v := actualValue[k].(type) {
+			case yttExpressionT:
+				out += indent + key + ": #@ " + string(v) + "\n"
+			case map[string]interface{}, []yttListItemT:
+				out += indent + key + ":\n" + writeYttNode(v, indent+"  ", replaceLists)
+			case []interface{}:
+				if replaceLists {
+					out += indent + "#@overlay/replace\n"
+				}
+				out += indentYaml(map[string]interface{}{k: v}, indent)
+			default:
+				out += indentYaml(map[string]interface{}{k: v}, indent)
+			}
+		}
+	case []yttListItemT:
+		for _, item := range actualValue {
+			out += fmt.Sprintf("%s#@overlay/match by=overlay.index(%d)\n", indent, item.Index)
+			if expression, ok := item.Value.(yttExpressionT); ok {
+				out += indent + "- #@ " + string(expression) + "\n"
+				continue
+			}
+			out += indent + "-\n" + writeYttNode(item.Value, indent+"  ", replaceLists)
+		}
+	}
+	return out
+}
+
+// getYttKey quotes the key if it is not a plain yaml key
+func getYttKey(key string) string {
+	if validYttKeyRegex.MatchString(key) && key != "true" && key != "false" && key != "null" {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+// getYttIdentifier returns a key that can be used as an attribute of data.values
+func getYttIdentifier(key string) string {
+	identifier := invalidOCTemplateChars.ReplaceAllLiteralString(key, "_")
+	if identifier == "" || (identifier[0] >= '0' && identifier[0] <= '9') {
+		identifier = "_" + identifier
+	}
+	return identifier
+}
+
+func indentYaml(value interface{}, indent string) string {
+	yamlBytes, err := yaml.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	out := ""
+	for _, line := range strings.Split(strings.TrimSuffix(string(yamlBytes), "\n"), "\n") {
+		out += indent + line + "\n"
+	}
+	return out
+}
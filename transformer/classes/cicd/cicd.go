@@ -0,0 +1,252 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cicd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/environment"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/konveyor/move2kube/types/qaengine/commonqa"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// deployApply applies the k8s yamls to the cluster
+	deployApply = "Apply"
+	// deployGitOps commits the new image tags, so that the GitOps tools deploy them
+	deployGitOps = "GitOps"
+	// deployNone only builds and pushes the images
+	deployNone = "None"
+
+	defaultBranch     = "main"
+	defaultYamlsDir   = "yamls"
+	s2iBuildScript    = "s2ibuild.sh"
+	cnbBuildScript    = "cnbbuild.sh"
+	imageTagVariable  = "IMAGE_TAG"
+	registryURLVar    = "REGISTRY_URL"
+	registryNSVar     = "REGISTRY_NAMESPACE"
+	bumpCommitMessage = "Update the image tags to ${" + imageTagVariable + "} [skip ci]"
+	// installReleaseCommand installs the CLI in the latest linux release of a github repo
+	installReleaseCommand = `curl -sSL "$(curl -sSL https://api.github.com/repos/%s/releases/latest | grep -o 'https://[^"]*%s' | head -n 1)" | %star -xz -C /usr/local/bin`
+)
+
+// pipelineConfigT is the configuration of a pipeline which builds and pushes the images, and deploys the k8s yamls
+type pipelineConfigT struct {
+	Name              string
+	Branch            string
+	RegistryURL       string
+	RegistryNamespace string
+	BuildScript       string
+	PushScript        string
+	Images            []string
+	InstallS2I        bool
+	InstallPack       bool
+	Deploy            string
+	YamlsPath         string
+}
+
+// getPipelineConfig returns the configuration of the pipeline from the build scripts, push scripts, images and k8s yamls created so far.
+// The pipeline is created again whenever new artifacts are created, so that it contains all of them.
+func getPipelineConfig(env *environment.Environment, allArtifacts []transformertypes.Artifact) (pipelineConfigT, bool) {
+	pc := pipelineConfigT{Name: env.GetProjectName()}
+	yamlsPaths := []string{}
+	for _, a := range allArtifacts {
+		switch a.Artifact {
+		case artifacts.ContainerImagesBuildScriptArtifactType:
+			if paths := a.Paths[artifacts.ContainerImagesBuildShScriptPathType]; len(paths) > 0 {
+				pc.BuildScript = getRepoPath(env, paths[0])
+			}
+		case artifacts.ContainerImagesPushScriptArtifactType:
+			if paths := a.Paths[artifacts.ContainerImagesPushShScriptPathType]; len(paths) > 0 {
+				pc.PushScript = getRepoPath(env, paths[0])
+			}
+		case artifacts.ContainerImageBuildScriptArtifactType:
+			for _, path := range a.Paths[artifacts.ContainerImageBuildShScriptPathType] {
+				switch filepath.Base(path) {
+				case s2iBuildScript:
+					pc.InstallS2I = true
+				case cnbBuildScript:
+					pc.InstallPack = true
+				}
+			}
+		case artifacts.NewImagesArtifactType:
+			images := artifacts.NewImages{}
+			if err := a.GetConfig(artifacts.NewImagesConfigType, &images); err != nil {
+				logrus.Errorf("Unable to read Image config : %s", err)
+				continue
+			}
+			pc.Images = common.MergeStringSlices(pc.Images, images.ImageNames...)
+		case artifacts.KubernetesYamlsArtifactType:
+			for _, path := range a.Paths[artifacts.KubernetesYamlsPathType] {
+				yamlsPaths = common.MergeStringSlices(yamlsPaths, getRepoPath(env, path))
+			}
+		}
+	}
+	if pc.BuildScript == "" || pc.PushScript == "" || len(pc.Images) == 0 {
+		return pc, false
+	}
+	pc.RegistryURL = commonqa.ImageRegistry()
+	pc.RegistryNamespace = commonqa.ImageRegistryNamespace(env.ProjectName)
+	pc.Branch = defaultBranch
+	if _, _, _, _, repoBranch, err := common.GatherGitInfo(env.GetEnvironmentSource()); err != nil {
+		logrus.Debugf("Unable to get the git repo of the source : %s", err)
+	} else if repoBranch != "" {
+		pc.Branch = repoBranch
+	}
+	pc.Branch = qaengine.FetchStringAnswer(common.ConfigCICDBranchKey, "Provide the branch of the git repo on which the pipelines should deploy:", []string{"The images are built on every branch"}, pc.Branch)
+	hints := []string{
+		"Apply : the k8s yamls are applied to the cluster using kubectl",
+		"GitOps : the new image tags are committed to the k8s yamls, so that the GitOps tools deploy them",
+		"None : the pipelines only build and push the images",
+	}
+	pc.Deploy = qaengine.FetchSelectAnswer(common.ConfigCICDDeployKey, "How should the pipelines deploy the new images?", hints, deployApply, []string{deployApply, deployGitOps, deployNone})
+	if pc.Deploy == deployApply {
+		if len(yamlsPaths) == 0 {
+			pc.Deploy = deployNone
+		} else {
+			def := yamlsPaths[0]
+			for _, yamlsPath := range yamlsPaths {
+				if filepath.Base(yamlsPath) == defaultYamlsDir {
+					def = yamlsPath
+				}
+			}
+			pc.YamlsPath = def
+			if len(yamlsPaths) > 1 {
+				pc.YamlsPath = qaengine.FetchSelectAnswer(common.ConfigCICDYamlsKey, "Select the k8s yamls the pipelines should apply:", nil, def, yamlsPaths)
+			}
+		}
+	}
+	return pc, true
+}
+
+// getRepoPath returns the path in the git repo, assuming the output directory is at the root of the repo
+func getRepoPath(env *environment.Environment, path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(env.GetEnvironmentOutput(), path); err == nil {
+			path = rel
+		} else {
+			logrus.Errorf("Unable to make the path %s relative to the output directory : %s", path, err)
+		}
+	}
+	return common.GetUnixPath(path)
+}
+
+// getInstallCommands returns the commands installing the CLIs used by the build scripts
+func (pc pipelineConfigT) getInstallCommands(sudo bool) []string {
+	prefix := ""
+	if sudo {
+		prefix = "sudo "
+	}
+	commands := []string{}
+	if pc.InstallS2I {
+		commands = append(commands, fmt.Sprintf(installReleaseCommand, "openshift/source-to-image", `linux-amd64\.tar\.gz`, prefix))
+	}
+	if pc.InstallPack {
+		commands = append(commands, fmt.Sprintf(installReleaseCommand, "buildpacks/pack", `linux\.tgz`, prefix))
+	}
+	return commands
+}
+
+// getBuildCommands returns the commands building the images and pushing them with the latest tag and the tag of the commit
+func (pc pipelineConfigT) getBuildCommands() []string {
+	commands := []string{
+		"bash " + pc.BuildScript,
+		fmt.Sprintf(`bash %s "${%s}" "${%s}"`, pc.PushScript, registryURLVar, registryNSVar),
+	}
+	for _, image := range pc.Images {
+		taggedImage := fmt.Sprintf("${%s}/${%s}/%s:${%s}", registryURLVar, registryNSVar, getImageRepo(image), imageTagVariable)
+		commands = append(commands, fmt.Sprintf(`docker tag %s "%s"`, image, taggedImage), fmt.Sprintf(`docker push "%s"`, taggedImage))
+	}
+	return commands
+}
+
+// getBumpCommands returns the commands which change the tags of the images in the k8s yamls to the tag of the commit
+func (pc pipelineConfigT) getBumpCommands() []string {
+	commands := []string{}
+	for _, image := range pc.Images {
+		repo := fmt.Sprintf("${%s}/${%s}/%s", registryURLVar, registryNSVar, getImageRepo(image))
+		commands = append(commands, fmt.Sprintf(
+			`grep -rl "%s" %s | xargs -r sed -i -E "s#(%s)(:[A-Za-z0-9_.-]+)?([\"' ]|$)#\1:${%s}\3#g"`,
+			repo, common.DeployDir, repo, imageTagVariable,
+		))
+	}
+	return commands
+}
+
+// getDeployCommands returns the commands which deploy the new images, after the tags are bumped
+func (pc pipelineConfigT) getDeployCommands(pushRef string) []string {
+	switch pc.Deploy {
+	case deployApply:
+		return []string{"kubectl apply -f " + pc.YamlsPath}
+	case deployGitOps:
+		return []string{fmt.Sprintf(`git diff --quiet || (git commit -am "%s" && %s)`, bumpCommitMessage, strings.TrimSpace("git push "+pushRef))}
+	}
+	return nil
+}
+
+func getImageRepo(image string) string {
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i]
+	}
+	return image
+}
+
+// writePipeline writes the pipeline to a temp file and returns the path mapping to its destination
+func writePipeline(env *environment.Environment, pipeline interface{}, destPath string) ([]transformertypes.PathMapping, error) {
+	tempPath, err := ioutil.TempDir(env.TempPath, "*")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temp dir : %s", err)
+	}
+	srcPath := filepath.Join(tempPath, filepath.Base(destPath))
+	if err := writeYaml(srcPath, pipeline); err != nil {
+		return nil, err
+	}
+	return []transformertypes.PathMapping{{
+		Type:     transformertypes.DefaultPathMappingType,
+		SrcPath:  srcPath,
+		DestPath: destPath,
+	}}, nil
+}
+
+func writeYaml(path string, value interface{}) error {
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode the pipeline %+v : %s", value, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), common.DefaultDirectoryPermission); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, b.Bytes(), common.DefaultFilePermission); err != nil {
+		return fmt.Errorf("failed to write the pipeline to the file at path %s : %s", path, err)
+	}
+	return nil
+}
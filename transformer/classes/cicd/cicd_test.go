@@ -0,0 +1,104 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cicd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCICD(t *testing.T) {
+	pc := pipelineConfigT{
+		Name:              "shop",
+		Branch:            "main",
+		RegistryURL:       "quay.io",
+		RegistryNamespace: "shop",
+		BuildScript:       "scripts/buildimages.sh",
+		PushScript:        "scripts/pushimages.sh",
+		Images:            []string{"orders", "web:1.0"},
+		InstallPack:       true,
+		Deploy:            deployApply,
+		YamlsPath:         "deploy/yamls",
+	}
+
+	t.Run("images are built, pushed and tagged with the commit", func(t *testing.T) {
+		commands := pc.getBuildCommands()
+		expected := []string{
+			"bash scripts/buildimages.sh",
+			`bash scripts/pushimages.sh "${REGISTRY_URL}" "${REGISTRY_NAMESPACE}"`,
+			`docker tag orders "${REGISTRY_URL}/${REGISTRY_NAMESPACE}/orders:${IMAGE_TAG}"`,
+			`docker push "${REGISTRY_URL}/${REGISTRY_NAMESPACE}/orders:${IMAGE_TAG}"`,
+			`docker tag web:1.0 "${REGISTRY_URL}/${REGISTRY_NAMESPACE}/web:${IMAGE_TAG}"`,
+		}
+		for i, command := range expected {
+			if commands[i] != command {
+				t.Fatalf("Expected the command %s. Actual: %s", command, commands[i])
+			}
+		}
+		if installCommands := pc.getInstallCommands(false); len(installCommands) != 1 || !strings.Contains(installCommands[0], "buildpacks/pack") {
+			t.Fatalf("Expected only pack to be installed. Actual: %+v", installCommands)
+		}
+		if bumpCommands := pc.getBumpCommands(); len(bumpCommands) != 2 || !strings.Contains(bumpCommands[1], "${REGISTRY_URL}/${REGISTRY_NAMESPACE}/web") {
+			t.Fatalf("Expected the tags of both images to be bumped. Actual: %+v", bumpCommands)
+		}
+	})
+
+	t.Run("github actions workflow applies the k8s yamls on the branch", func(t *testing.T) {
+		workflow := getGitHubWorkflow(pc)
+		deployJob, ok := workflow.Jobs[gitHubDeployJobName]
+		if !ok || deployJob.Needs != gitHubBuildJobName || deployJob.If != "github.ref == 'refs/heads/main'" {
+			t.Fatalf("Expected a deploy job running on the branch after the build. Actual: %+v", workflow.Jobs)
+		}
+		if run := deployJob.Steps[len(deployJob.Steps)-1].Run; run != "kubectl apply -f deploy/yamls" {
+			t.Fatalf("Expected the k8s yamls to be applied. Actual: %s", run)
+		}
+		if workflow.Env[imageTagVariable] != "${{ github.sha }}" || workflow.Env[registryURLVar] != "quay.io" {
+			t.Fatalf("Expected the registry and the tag of the commit. Actual: %+v", workflow.Env)
+		}
+	})
+
+	t.Run("gitlab ci pipeline commits the image tags for gitops", func(t *testing.T) {
+		gitOpsPC := pc
+		gitOpsPC.Deploy = deployGitOps
+		path := filepath.Join(t.TempDir(), gitLabCIFileName)
+		if err := writeYaml(path, getGitLabCI(gitOpsPC)); err != nil {
+			t.Fatalf("Failed to write the pipeline. Error: %q", err)
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read the pipeline. Error: %q", err)
+		}
+		for _, expected := range []string{"stages:\n  - build\n  - deploy\n", "services:\n    - docker:24-dind", `$CI_COMMIT_BRANCH == "main"`, "git push \"https://oauth2:${GITOPS_TOKEN}@"} {
+			if !strings.Contains(string(data), expected) {
+				t.Fatalf("Expected the pipeline to contain %s. Actual: %s", expected, data)
+			}
+		}
+	})
+
+	t.Run("no deploy job when the pipelines only build", func(t *testing.T) {
+		buildPC := pc
+		buildPC.Deploy = deployNone
+		if _, ok := getGitHubWorkflow(buildPC).Jobs[gitHubDeployJobName]; ok {
+			t.Fatalf("Expected no deploy job in the workflow")
+		}
+		if pipeline := getGitLabCI(buildPC); len(pipeline.Stages) != 1 {
+			t.Fatalf("Expected only the build stage in the pipeline. Actual: %+v", pipeline.Stages)
+		}
+	})
+}
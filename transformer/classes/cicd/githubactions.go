@@ -0,0 +1,168 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cicd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/move2kube/environment"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	gitHubRunner        = "ubuntu-latest"
+	gitHubCheckout      = "actions/checkout@v3"
+	gitHubDockerLogin   = "docker/login-action@v2"
+	gitHubBotName       = "github-actions[bot]"
+	gitHubBotEmail      = "41898282+github-actions[bot]@users.noreply.github.com"
+	gitHubWorkflowsDir  = ".github/workflows"
+	gitHubBuildJobName  = "build"
+	gitHubDeployJobName = "deploy"
+)
+
+// GitHubActions implements Transformer interface
+type GitHubActions struct {
+	Config transformertypes.Transformer
+	Env    *environment.Environment
+}
+
+type gitHubWorkflowT struct {
+	Name string                `yaml:"name"`
+	On   gitHubTriggersT       `yaml:"on"`
+	Env  map[string]string     `yaml:"env"`
+	Jobs map[string]gitHubJobT `yaml:"jobs"`
+}
+
+type gitHubTriggersT struct {
+	Push             gitHubPushT `yaml:"push"`
+	WorkflowDispatch struct{}    `yaml:"workflow_dispatch"`
+}
+
+type gitHubPushT struct {
+	Branches []string `yaml:"branches,omitempty"`
+}
+
+type gitHubJobT struct {
+	RunsOn      string            `yaml:"runs-on"`
+	Needs       string            `yaml:"needs,omitempty"`
+	If          string            `yaml:"if,omitempty"`
+	Permissions map[string]string `yaml:"permissions,omitempty"`
+	Steps       []gitHubStepT     `yaml:"steps"`
+}
+
+type gitHubStepT struct {
+	Name string            `yaml:"name,omitempty"`
+	Uses string            `yaml:"uses,omitempty"`
+	With map[string]string `yaml:"with,omitempty"`
+	Env  map[string]string `yaml:"env,omitempty"`
+	Run  string            `yaml:"run,omitempty"`
+}
+
+// Init Initializes the transformer
+func (t *GitHubActions) Init(tc transformertypes.Transformer, env *environment.Environment) error {
+	t.Config = tc
+	t.Env = env
+	return nil
+}
+
+// GetConfig returns the transformer config
+func (t *GitHubActions) GetConfig() (transformertypes.Transformer, *environment.Environment) {
+	return t.Config, t.Env
+}
+
+// BaseDirectoryDetect runs detect in base directory
+func (t *GitHubActions) BaseDirectoryDetect(dir string) (namedServices map[string]transformertypes.ServicePlan, unnamedServices []transformertypes.TransformerPlan, err error) {
+	return nil, nil, nil
+}
+
+// DirectoryDetect runs detect in each sub directory
+func (t *GitHubActions) DirectoryDetect(dir string) (namedServices map[string]transformertypes.ServicePlan, unnamedServices []transformertypes.TransformerPlan, err error) {
+	return nil, nil, nil
+}
+
+// Transform generates a GitHub Actions workflow which builds and pushes the images, and deploys them
+func (t *GitHubActions) Transform(newArtifacts []transformertypes.Artifact, oldArtifacts []transformertypes.Artifact) ([]transformertypes.PathMapping, []transformertypes.Artifact, error) {
+	pc, ok := getPipelineConfig(t.Env, append(newArtifacts, oldArtifacts...))
+	if !ok {
+		return nil, nil, nil
+	}
+	pathMappings, err := writePipeline(t.Env, getGitHubWorkflow(pc), filepath.Join(gitHubWorkflowsDir, pc.Name+".yaml"))
+	if err != nil {
+		logrus.Errorf("Unable to write the GitHub Actions workflow : %s", err)
+		return nil, nil, err
+	}
+	secrets := "REGISTRY_USERNAME and REGISTRY_PASSWORD"
+	if pc.Deploy == deployApply {
+		secrets = "REGISTRY_USERNAME, REGISTRY_PASSWORD and KUBECONFIG"
+	}
+	logrus.Infof("The GitHub Actions workflow uses the repository secrets %s", secrets)
+	return pathMappings, nil, nil
+}
+
+// getGitHubWorkflow returns a workflow which builds the images on every push and deploys them on pushes to the branch
+func getGitHubWorkflow(pc pipelineConfigT) gitHubWorkflowT {
+	buildSteps := []gitHubStepT{{Uses: gitHubCheckout}}
+	if installCommands := pc.getInstallCommands(true); len(installCommands) > 0 {
+		buildSteps = append(buildSteps, gitHubStepT{Name: "Install the build tools", Run: strings.Join(installCommands, "\n")})
+	}
+	buildSteps = append(buildSteps,
+		gitHubStepT{Name: "Log in to the image registry", Uses: gitHubDockerLogin, With: map[string]string{
+			"registry": "${{ env." + registryURLVar + " }}",
+			"username": "${{ secrets.REGISTRY_USERNAME }}",
+			"password": "${{ secrets.REGISTRY_PASSWORD }}",
+		}},
+		gitHubStepT{Name: "Build and push the images", Run: strings.Join(pc.getBuildCommands(), "\n")},
+	)
+	workflow := gitHubWorkflowT{
+		Name: pc.Name,
+		Env: map[string]string{
+			registryURLVar:   pc.RegistryURL,
+			registryNSVar:    pc.RegistryNamespace,
+			imageTagVariable: "${{ github.sha }}",
+		},
+		Jobs: map[string]gitHubJobT{gitHubBuildJobName: {RunsOn: gitHubRunner, Steps: buildSteps}},
+	}
+	if pc.Deploy == deployNone {
+		return workflow
+	}
+	deployJob := gitHubJobT{
+		RunsOn: gitHubRunner,
+		Needs:  gitHubBuildJobName,
+		If:     "github.ref == 'refs/heads/" + pc.Branch + "'",
+		Steps: []gitHubStepT{
+			{Uses: gitHubCheckout},
+			{Name: "Update the image tags", Run: strings.Join(pc.getBumpCommands(), "\n")},
+		},
+	}
+	switch pc.Deploy {
+	case deployApply:
+		deployJob.Steps = append(deployJob.Steps,
+			gitHubStepT{Name: "Configure kubectl", Env: map[string]string{"KUBECONFIG_DATA": "${{ secrets.KUBECONFIG }}"}, Run: `mkdir -p ~/.kube && echo "${KUBECONFIG_DATA}" > ~/.kube/config`},
+			gitHubStepT{Name: "Deploy", Run: strings.Join(pc.getDeployCommands(""), "\n")},
+		)
+	case deployGitOps:
+		deployJob.Permissions = map[string]string{"contents": "write"}
+		deployJob.Steps = append(deployJob.Steps, gitHubStepT{Name: "Commit the image tags", Run: strings.Join(append([]string{
+			`git config user.name "` + gitHubBotName + `"`,
+			`git config user.email "` + gitHubBotEmail + `"`,
+		}, pc.getDeployCommands("")...), "\n")})
+	}
+	workflow.Jobs[gitHubDeployJobName] = deployJob
+	return workflow
+}
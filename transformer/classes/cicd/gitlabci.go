@@ -0,0 +1,154 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cicd
+
+import (
+	"github.com/konveyor/move2kube/environment"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	gitLabCIFileName     = ".gitlab-ci.yml"
+	gitLabBuildStage     = "build"
+	gitLabDeployStage    = "deploy"
+	gitLabDockerImage    = "docker:24"
+	gitLabDockerService  = "docker:24-dind"
+	gitLabKubectlImage   = "bitnami/kubectl:latest"
+	gitLabGitImage       = "alpine:3"
+	gitLabBotName        = "gitlab-ci"
+	gitLabBotEmail       = "gitlab-ci@${CI_SERVER_HOST}"
+	gitLabPushRef        = `"https://oauth2:${GITOPS_TOKEN}@${CI_SERVER_HOST}/${CI_PROJECT_PATH}.git" "HEAD:${CI_COMMIT_BRANCH}"`
+	gitLabRegistryLogin  = `echo "${REGISTRY_PASSWORD}" | docker login -u "${REGISTRY_USERNAME}" --password-stdin "${REGISTRY_URL}"`
+	gitLabImageTagSource = "${CI_COMMIT_SHORT_SHA}"
+)
+
+// GitLabCI implements Transformer interface
+type GitLabCI struct {
+	Config transformertypes.Transformer
+	Env    *environment.Environment
+}
+
+type gitLabCIT struct {
+	Stages    []string              `yaml:"stages"`
+	Variables map[string]string     `yaml:"variables"`
+	Jobs      map[string]gitLabJobT `yaml:",inline"`
+}
+
+type gitLabJobT struct {
+	Stage        string        `yaml:"stage"`
+	Image        interface{}   `yaml:"image"`
+	Services     []string      `yaml:"services,omitempty"`
+	Rules        []gitLabRuleT `yaml:"rules,omitempty"`
+	BeforeScript []string      `yaml:"before_script,omitempty"`
+	Script       []string      `yaml:"script"`
+}
+
+type gitLabImageT struct {
+	Name       string   `yaml:"name"`
+	Entrypoint []string `yaml:"entrypoint"`
+}
+
+type gitLabRuleT struct {
+	If string `yaml:"if"`
+}
+
+// Init Initializes the transformer
+func (t *GitLabCI) Init(tc transformertypes.Transformer, env *environment.Environment) error {
+	t.Config = tc
+	t.Env = env
+	return nil
+}
+
+// GetConfig returns the transformer config
+func (t *GitLabCI) GetConfig() (transformertypes.Transformer, *environment.Environment) {
+	return t.Config, t.Env
+}
+
+// BaseDirectoryDetect runs detect in base directory
+func (t *GitLabCI) BaseDirectoryDetect(dir string) (namedServices map[string]transformertypes.ServicePlan, unnamedServices []transformertypes.TransformerPlan, err error) {
+	return nil, nil, nil
+}
+
+// DirectoryDetect runs detect in each sub directory
+func (t *GitLabCI) DirectoryDetect(dir string) (namedServices map[string]transformertypes.ServicePlan, unnamedServices []transformertypes.TransformerPlan, err error) {
+	return nil, nil, nil
+}
+
+// Transform generates a GitLab CI pipeline which builds and pushes the images, and deploys them
+func (t *GitLabCI) Transform(newArtifacts []transformertypes.Artifact, oldArtifacts []transformertypes.Artifact) ([]transformertypes.PathMapping, []transformertypes.Artifact, error) {
+	pc, ok := getPipelineConfig(t.Env, append(newArtifacts, oldArtifacts...))
+	if !ok {
+		return nil, nil, nil
+	}
+	pathMappings, err := writePipeline(t.Env, getGitLabCI(pc), gitLabCIFileName)
+	if err != nil {
+		logrus.Errorf("Unable to write the GitLab CI pipeline : %s", err)
+		return nil, nil, err
+	}
+	variables := "REGISTRY_USERNAME and REGISTRY_PASSWORD"
+	switch pc.Deploy {
+	case deployApply:
+		variables = "REGISTRY_USERNAME, REGISTRY_PASSWORD and the file variable KUBECONFIG"
+	case deployGitOps:
+		variables = "REGISTRY_USERNAME, REGISTRY_PASSWORD and GITOPS_TOKEN, a token which can push to the repo"
+	}
+	logrus.Infof("The GitLab CI pipeline uses the CI/CD variables %s", variables)
+	return pathMappings, nil, nil
+}
+
+// getGitLabCI returns a pipeline which builds the images on every push and deploys them on pushes to the branch
+func getGitLabCI(pc pipelineConfigT) gitLabCIT {
+	pipeline := gitLabCIT{
+		Stages: []string{gitLabBuildStage},
+		Variables: map[string]string{
+			registryURLVar:       pc.RegistryURL,
+			registryNSVar:        pc.RegistryNamespace,
+			imageTagVariable:     gitLabImageTagSource,
+			"DOCKER_TLS_CERTDIR": "/certs",
+		},
+		Jobs: map[string]gitLabJobT{gitLabBuildStage: {
+			Stage:        gitLabBuildStage,
+			Image:        gitLabDockerImage,
+			Services:     []string{gitLabDockerService},
+			BeforeScript: append(append([]string{"apk add --no-cache bash curl"}, pc.getInstallCommands(false)...), gitLabRegistryLogin),
+			Script:       pc.getBuildCommands(),
+		}},
+	}
+	if pc.Deploy == deployNone {
+		return pipeline
+	}
+	deployJob := gitLabJobT{
+		Stage:  gitLabDeployStage,
+		Rules:  []gitLabRuleT{{If: `$CI_COMMIT_BRANCH == "` + pc.Branch + `"`}},
+		Script: append(pc.getBumpCommands(), pc.getDeployCommands(gitLabPushRef)...),
+	}
+	switch pc.Deploy {
+	case deployApply:
+		deployJob.Image = gitLabImageT{Name: gitLabKubectlImage, Entrypoint: []string{""}}
+	case deployGitOps:
+		deployJob.Image = gitLabGitImage
+		deployJob.BeforeScript = []string{
+			"apk add --no-cache git",
+			`git config user.name "` + gitLabBotName + `"`,
+			`git config user.email "` + gitLabBotEmail + `"`,
+		}
+	}
+	pipeline.Stages = append(pipeline.Stages, gitLabDeployStage)
+	pipeline.Jobs[gitLabDeployStage] = deployJob
+	return pipeline
+}
@@ -34,10 +34,15 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cast"
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	core "k8s.io/kubernetes/pkg/apis/core"
 	"k8s.io/kubernetes/pkg/apis/networking"
 )
 
+// cfHealthCheckPeriodSeconds is the interval at which the probes converted from the cf health checks are run
+const cfHealthCheckPeriodSeconds = 10
+
 // CloudFoundry implements Transformer interface
 type CloudFoundry struct {
 	Config transformertypes.Transformer
@@ -115,7 +120,10 @@ func (t *CloudFoundry) BaseDirectoryDetect(dir string) (namedServices map[string
 					artifacts.ProjectPathPathType: {fullbuilddirectory},
 				},
 			}
-			_, appinstance := getCfInstanceApp(cfInstanceApps, applicationName)
+			appinstancepath, appinstance := getCfInstanceApp(cfInstanceApps, applicationName)
+			if appinstancepath != "" {
+				ct.Paths[artifacts.CfRunningManifestPathType] = []string{appinstancepath}
+			}
 			if application.DockerImage != "" || appinstance.DockerImage != "" {
 				dockerImageName := application.DockerImage
 				if dockerImageName == "" {
@@ -189,7 +197,11 @@ func (t *CloudFoundry) Transform(newArtifacts []transformertypes.Artifact, oldAr
 			for varname, value := range application.EnvironmentVariables {
 				serviceContainer.Env = append(serviceContainer.Env, core.EnvVar{Name: varname, Value: value})
 			}
-			//TODO: Add support for services, health check, memory
+			//TODO: Add support for services
+			// The bound services are called by the application
+			for _, boundService := range application.Services {
+				serviceConfig.Dependencies = common.MergeStringSlices(serviceConfig.Dependencies, common.NormalizeForServiceName(boundService))
+			}
 			if application.Instances.IsSet {
 				serviceConfig.Replicas = application.Instances.Value
 			} else if cfinstanceapp.Instances != 0 {
@@ -220,8 +232,14 @@ func (t *CloudFoundry) Transform(newArtifacts []transformertypes.Artifact, oldAr
 				envvar := core.EnvVar{Name: "PORT", Value: cast.ToString(port)}
 				serviceContainer.Env = append(serviceContainer.Env, envvar)
 			}
+			serviceContainer.Resources.Limits = t.getResourceLimits(application, cfinstanceapp)
+			serviceContainer.LivenessProbe, serviceContainer.StartupProbe = t.getHealthCheckProbes(application, serviceContainer.Ports[0].ContainerPort)
 			serviceConfig.Containers = []core.Container{serviceContainer}
 			ir.Services[config.ServiceName] = serviceConfig
+			for _, task := range cfinstanceapp.Tasks {
+				taskService := t.getTaskService(config.ServiceName, serviceContainer, task)
+				ir.Services[taskService.Name] = taskService
+			}
 		}
 		artifactsCreated = append(artifactsCreated, transformertypes.Artifact{
 			Name:     t.Env.GetProjectName(),
@@ -234,6 +252,71 @@ func (t *CloudFoundry) Transform(newArtifacts []transformertypes.Artifact, oldAr
 	return nil, artifactsCreated, nil
 }
 
+// getResourceLimits converts the memory and disk quota of the application, which are in megabytes, to resource limits
+func (t *CloudFoundry) getResourceLimits(application manifest.Application, cfinstanceapp collecttypes.CfApplication) core.ResourceList {
+	limits := core.ResourceList{}
+	if application.Memory.IsSet {
+		limits[core.ResourceMemory] = *resource.NewQuantity(int64(application.Memory.Value)*1024*1024, resource.BinarySI)
+	} else if cfinstanceapp.Memory != 0 {
+		limits[core.ResourceMemory] = *resource.NewQuantity(cfinstanceapp.Memory*1024*1024, resource.BinarySI)
+	}
+	if application.DiskQuota.IsSet {
+		limits[core.ResourceEphemeralStorage] = *resource.NewQuantity(int64(application.DiskQuota.Value)*1024*1024, resource.BinarySI)
+	}
+	if len(limits) == 0 {
+		return nil
+	}
+	return limits
+}
+
+// getHealthCheckProbes converts the health check of the application to a liveness probe and, when the application has a health check timeout, a startup probe
+func (t *CloudFoundry) getHealthCheckProbes(application manifest.Application, port int32) (livenessProbe *core.Probe, startupProbe *core.Probe) {
+	handler := core.Handler{}
+	switch application.HealthCheckType {
+	case "http":
+		path := application.HealthCheckHTTPEndpoint
+		if path == "" {
+			path = "/"
+		}
+		handler.HTTPGet = &core.HTTPGetAction{Path: path, Port: intstr.FromInt(int(port)), Scheme: core.URISchemeHTTP}
+	case "", "port":
+		// Cloud Foundry checks the port by default
+		handler.TCPSocket = &core.TCPSocketAction{Port: intstr.FromInt(int(port))}
+	case "process", "none":
+		// Cloud Foundry only checks that the process is running, which is what Kubernetes does when there is no probe
+		return nil, nil
+	default:
+		logrus.Warnf("Unknown health check type %s in the application %s. Ignoring it.", application.HealthCheckType, application.Name)
+		return nil, nil
+	}
+	livenessProbe = &core.Probe{Handler: handler, PeriodSeconds: cfHealthCheckPeriodSeconds}
+	if application.HealthCheckTimeout != 0 {
+		failureThreshold := (int32(application.HealthCheckTimeout) + cfHealthCheckPeriodSeconds - 1) / cfHealthCheckPeriodSeconds
+		startupProbe = &core.Probe{Handler: *handler.DeepCopy(), PeriodSeconds: cfHealthCheckPeriodSeconds, FailureThreshold: failureThreshold}
+	}
+	return livenessProbe, startupProbe
+}
+
+// getTaskService returns a service which runs the task of the application using the application's image
+func (t *CloudFoundry) getTaskService(serviceName string, serviceContainer core.Container, task collecttypes.CfTask) irtypes.Service {
+	taskServiceName := serviceName + "-" + task.Name
+	taskService := irtypes.Service{Name: taskServiceName, Schedule: task.Schedule}
+	taskService.RestartPolicy = core.RestartPolicyOnFailure
+	taskContainer := core.Container{
+		Name:    taskServiceName,
+		Image:   serviceContainer.Image,
+		Command: []string{"/bin/sh", "-c", task.Command},
+	}
+	for _, env := range serviceContainer.Env {
+		if env.Name != "PORT" {
+			taskContainer.Env = append(taskContainer.Env, env)
+		}
+	}
+	taskService.Containers = []core.Container{taskContainer}
+	logrus.Debugf("Created the service %s for the task %s of %s", taskServiceName, task.Name, serviceName)
+	return taskService
+}
+
 // readApplicationManifest reads an application manifest
 func (t *CloudFoundry) readApplicationManifest(path string, serviceName string) ([]manifest.Application, []string, error) { // manifest, parameters
 	trimmedvariables, err := getMissingVariables(path)
@@ -209,5 +209,6 @@ func newContainerFromImageInfo(i collecttypes.ImageInfo) irtypes.ContainerImage
 	c.ExposedPorts = i.Spec.PortsToExpose
 	c.UserID = i.Spec.UserID
 	c.AccessedDirs = i.Spec.AccessedDirs
+	c.Env = i.Spec.Env
 	return c
 }
@@ -97,6 +97,20 @@ func makeVolumesFromTmpFS(serviceName string, tfsList []string) ([]core.VolumeMo
 	return vmList, vList
 }
 
+// getDependencies returns the names of the services a service depends on or links to
+func getDependencies(dependsOn []string, links []string) []string {
+	dependencies := []string{}
+	for _, dependency := range dependsOn {
+		dependencies = common.MergeStringSlices(dependencies, common.NormalizeForServiceName(dependency))
+	}
+	for _, link := range links {
+		// A link is of the form SERVICE:ALIAS
+		dependency := strings.SplitN(link, ":", 2)[0]
+		dependencies = common.MergeStringSlices(dependencies, common.NormalizeForServiceName(dependency))
+	}
+	return dependencies
+}
+
 func isPath(substring string) bool {
 	return strings.Contains(substring, "/") || substring == "."
 }
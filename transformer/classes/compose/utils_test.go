@@ -0,0 +1,45 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetDependencies(t *testing.T) {
+	testcases := []struct {
+		name      string
+		dependsOn []string
+		links     []string
+		want      []string
+	}{
+		{name: "no dependencies", want: []string{}},
+		{name: "depends_on", dependsOn: []string{"orders", "db"}, want: []string{"orders", "db"}},
+		{name: "links with and without an alias", links: []string{"db:database", "cache"}, want: []string{"db", "cache"}},
+		{name: "service in depends_on and links", dependsOn: []string{"db"}, links: []string{"db:database"}, want: []string{"db"}},
+		{name: "names are normalized", dependsOn: []string{"Orders_API"}, links: []string{"Payment.Gateway:payments"}, want: []string{"orders-api", "payment-gateway"}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := getDependencies(tc.dependsOn, tc.links); !cmp.Equal(actual, tc.want) {
+				t.Fatalf("Failed to get the dependencies properly. Differences:\n%s", cmp.Diff(tc.want, actual))
+			}
+		})
+	}
+}
@@ -242,6 +242,7 @@ func (c *v1v2Loader) convertToIR(filedir string, composeObject *project.Project,
 				}
 			}
 		}
+		serviceConfig.Dependencies = getDependencies(composeServiceConfig.DependsOn, composeServiceConfig.Links)
 
 		vml, vl := makeVolumesFromTmpFS(name, composeServiceConfig.Tmpfs)
 		for _, v := range vl {
@@ -222,6 +222,7 @@ func (c *v3Loader) convertToIR(filedir string, composeObject types.Config, servi
 		}
 
 		serviceConfig.Networks = c.getNetworks(composeServiceConfig, composeObject)
+		serviceConfig.Dependencies = getDependencies(composeServiceConfig.DependsOn, composeServiceConfig.Links)
 
 		if (composeServiceConfig.Deploy.Resources != types.Resources{}) {
 			if composeServiceConfig.Deploy.Resources.Limits != nil {
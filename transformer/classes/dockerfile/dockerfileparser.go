@@ -17,9 +17,11 @@
 package dockerfile
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/environment"
@@ -85,8 +87,12 @@ func (t *DockerfileParser) Transform(newArtifacts []transformertypes.Artifact, o
 			continue
 		}
 		processedImages[sImageName.ImageName] = true
+		healthCheckPath := ""
+		if len(a.Paths[artifacts.ProjectPathPathType]) != 0 {
+			healthCheckPath = getFrameworkHealthCheckPath(a.Paths[artifacts.ProjectPathPathType][0])
+		}
 		for _, path := range a.Paths[artifacts.DockerfilePathType] {
-			na := t.getIRFromDockerfile(path, sImageName.ImageName, sConfig.ServiceName)
+			na := t.getIRFromDockerfile(path, sImageName.ImageName, sConfig.ServiceName, healthCheckPath)
 			if na != nil {
 				nartifacts = append(nartifacts, *na)
 			}
@@ -95,7 +101,7 @@ func (t *DockerfileParser) Transform(newArtifacts []transformertypes.Artifact, o
 	return nil, nartifacts, nil
 }
 
-func (t *DockerfileParser) getIRFromDockerfile(dockerfilepath, imageName, serviceName string) *transformertypes.Artifact {
+func (t *DockerfileParser) getIRFromDockerfile(dockerfilepath, imageName, serviceName, healthCheckPath string) *transformertypes.Artifact {
 	df, err := t.getDockerFileAST(dockerfilepath)
 	if err != nil {
 		logrus.Errorf("Unable to parse dockerfile : %s", err)
@@ -104,7 +110,15 @@ func (t *DockerfileParser) getIRFromDockerfile(dockerfilepath, imageName, servic
 	ir := irtypes.NewIR()
 	ir.Name = t.Env.GetProjectName()
 	container := irtypes.NewContainer()
+	var healthCheck *core.Probe
 	for _, dfchild := range df.AST.Children {
+		if strings.EqualFold(dfchild.Value, "HEALTHCHECK") {
+			// The last HEALTHCHECK instruction overrides the previous ones
+			healthCheck, err = t.getHealthCheck(dfchild)
+			if err != nil {
+				logrus.Errorf("Unable to parse the HEALTHCHECK instruction in %s : %s", dockerfilepath, err)
+			}
+		}
 		if strings.EqualFold(dfchild.Value, "EXPOSE") {
 			for {
 				dfchild = dfchild.Next
@@ -141,6 +155,7 @@ func (t *DockerfileParser) getIRFromDockerfile(dockerfilepath, imageName, servic
 		irService.AddPortForwarding(servicePort, podPort, "")
 	}
 	serviceContainer.Ports = serviceContainerPorts
+	serviceContainer.LivenessProbe = healthCheck
 	irService.Containers = []core.Container{serviceContainer}
 	if t.isWindowsContainer(df) {
 		irService.Annotations = map[string]string{common.WindowsAnnotation: common.AnnotationLabelValue}
@@ -151,6 +166,12 @@ func (t *DockerfileParser) getIRFromDockerfile(dockerfilepath, imageName, servic
 			Value:  "Windows",
 		}}
 	}
+	if healthCheck == nil && healthCheckPath != "" {
+		if irService.Annotations == nil {
+			irService.Annotations = map[string]string{}
+		}
+		irService.Annotations[common.ProbePathAnnotation] = healthCheckPath
+	}
 	ir.Services[serviceName] = irService
 	return &transformertypes.Artifact{
 		Name:     t.Env.GetProjectName(),
@@ -160,6 +181,62 @@ func (t *DockerfileParser) getIRFromDockerfile(dockerfilepath, imageName, servic
 		}}
 }
 
+// getHealthCheck converts the HEALTHCHECK instruction to a probe. It returns nil for HEALTHCHECK NONE.
+func (t *DockerfileParser) getHealthCheck(node *dockerparser.Node) (*core.Probe, error) {
+	if node.Next == nil {
+		return nil, fmt.Errorf("missing the health check command")
+	}
+	if strings.EqualFold(node.Next.Value, "NONE") {
+		return nil, nil
+	}
+	if !strings.EqualFold(node.Next.Value, "CMD") {
+		return nil, fmt.Errorf("unknown health check type %s", node.Next.Value)
+	}
+	command := []string{}
+	for n := node.Next.Next; n != nil; n = n.Next {
+		command = append(command, n.Value)
+	}
+	if len(command) == 0 {
+		return nil, fmt.Errorf("missing the health check command")
+	}
+	if !node.Attributes["json"] {
+		command = []string{"/bin/sh", "-c", strings.Join(command, " ")}
+	}
+	probe := &core.Probe{Handler: core.Handler{Exec: &core.ExecAction{Command: command}}}
+	for _, flag := range node.Flags {
+		flag = strings.TrimPrefix(flag, "--")
+		name, value := flag, ""
+		if i := strings.Index(flag, "="); i != -1 {
+			name, value = flag[:i], flag[i+1:]
+		}
+		switch name {
+		case "retries":
+			retries, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid retries %s : %s", value, err)
+			}
+			probe.FailureThreshold = int32(retries)
+		case "interval", "timeout", "start-period":
+			duration, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %s : %s", name, value, err)
+			}
+			seconds := int32(duration.Seconds())
+			switch name {
+			case "interval":
+				probe.PeriodSeconds = seconds
+			case "timeout":
+				probe.TimeoutSeconds = seconds
+			default:
+				probe.InitialDelaySeconds = seconds
+			}
+		default:
+			logrus.Debugf("Ignoring the unsupported HEALTHCHECK flag %s", flag)
+		}
+	}
+	return probe, nil
+}
+
 func (t *DockerfileParser) getDockerFileAST(path string) (*dockerparser.Result, error) {
 	f, err := os.Open(path)
 	if err != nil {
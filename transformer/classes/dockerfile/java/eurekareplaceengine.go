@@ -285,8 +285,10 @@ func (t *EurekaReplaceEngine) DirectoryDetect(dir string) (namedServices map[str
 // Transform transforms the artifacts
 func (t *EurekaReplaceEngine) Transform(newArtifacts []transformertypes.Artifact, oldArtifacts []transformertypes.Artifact) ([]transformertypes.PathMapping, []transformertypes.Artifact, error) {
 	pathMappings := []transformertypes.PathMapping{}
+	artifactsCreated := []transformertypes.Artifact{}
 	for _, a := range newArtifacts {
 		var feignclients []string
+		var dependencies []string
 		if a.Artifact != artifacts.ServiceArtifactType {
 			continue
 		}
@@ -421,6 +423,9 @@ func (t *EurekaReplaceEngine) Transform(newArtifacts []transformertypes.Artifact
 							servicename = keyvalue[1][1 : len(keyvalue[1])-1]
 						}
 					}
+					if servicename != "default" {
+						dependencies = common.MergeStringSlices(dependencies, common.NormalizeForServiceName(servicename))
+					}
 					servicename = strings.ToUpper(servicename)
 					feignclients = append(feignclients, (servicename + "_URL"))
 					eachline = eachline + ", url = \"${" + servicename + "_URL" + "}\")"
@@ -501,6 +506,19 @@ func (t *EurekaReplaceEngine) Transform(newArtifacts []transformertypes.Artifact
 			_ = ioutil.WriteFile(path, out, 0644)
 		}
 
+		// the services called through feign clients are recorded, so that they can be reached without eureka
+		if len(dependencies) > 0 {
+			ir := irtypes.NewIR()
+			ir.Services[seConfig.ServiceName] = irtypes.Service{Name: seConfig.ServiceName, Dependencies: dependencies}
+			artifactsCreated = append(artifactsCreated, transformertypes.Artifact{
+				Name:     t.Env.GetProjectName(),
+				Artifact: irtypes.IRArtifactType,
+				Configs: map[transformertypes.ConfigType]interface{}{
+					irtypes.IRConfigType: ir,
+				},
+			})
+		}
+
 		// copy project code from source to destination
 		pathMappings = append(pathMappings, transformertypes.PathMapping{
 			Type:     transformertypes.SourcePathMappingType,
@@ -560,5 +578,5 @@ func (t *EurekaReplaceEngine) Transform(newArtifacts []transformertypes.Artifact
 			})
 		}
 	}
-	return pathMappings, nil, nil
+	return pathMappings, artifactsCreated, nil
 }
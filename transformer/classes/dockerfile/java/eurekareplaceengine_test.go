@@ -0,0 +1,112 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package java
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/environment"
+	environmenttypes "github.com/konveyor/move2kube/types/environment"
+	irtypes "github.com/konveyor/move2kube/types/ir"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+)
+
+func TestEurekaReplaceEngineTransform(t *testing.T) {
+	common.TempPath = t.TempDir()
+	env, err := environment.NewEnvironment(environment.EnvInfo{Name: "test", ProjectName: "myproject", Source: t.TempDir(), Context: t.TempDir()}, nil, environmenttypes.Container{})
+	if err != nil {
+		t.Fatalf("Unable to create the environment : %s", err)
+	}
+	defer env.Destroy()
+	engine := &EurekaReplaceEngine{Env: env}
+
+	testcases := []struct {
+		name             string
+		source           string
+		wantDependencies []string
+		wantURLs         []string
+	}{
+		{
+			name:   "feign client without a name",
+			source: "@FeignClient(url = \"http://localhost\")\npublic interface Client {}\n",
+		},
+		{
+			name:             "feign clients of other services",
+			source:           "@FeignClient(name = \"payments\")\npublic interface PaymentsClient {}\n@FeignClient(name = \"Customer_Service\", path = \"/customers\")\npublic interface CustomersClient {}\n",
+			wantDependencies: []string{"payments", "customer-service"},
+			wantURLs:         []string{"${PAYMENTS_URL}", "${CUSTOMER_SERVICE_URL}"},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			javaPath := filepath.Join(t.TempDir(), "Client.java")
+			if err := ioutil.WriteFile(javaPath, []byte(tc.source), 0644); err != nil {
+				t.Fatalf("Failed to write the java file. Error: %q", err)
+			}
+			a := transformertypes.Artifact{
+				Name:     "orders",
+				Artifact: artifacts.ServiceArtifactType,
+				Paths:    map[transformertypes.PathType][]string{JavaWithFeign: {javaPath}},
+				Configs: map[transformertypes.ConfigType]interface{}{
+					EurekaConfigType:            EurekaConfig{ServiceName: "orders"},
+					artifacts.ServiceConfigType: artifacts.ServiceConfig{ServiceName: "orders"},
+				},
+			}
+			_, createdArtifacts, err := engine.Transform([]transformertypes.Artifact{a}, nil)
+			if err != nil {
+				t.Fatalf("Failed to transform. Error: %q", err)
+			}
+			irArtifacts := []transformertypes.Artifact{}
+			for _, createdArtifact := range createdArtifacts {
+				if createdArtifact.Artifact == irtypes.IRArtifactType {
+					irArtifacts = append(irArtifacts, createdArtifact)
+				}
+			}
+			if len(tc.wantDependencies) == 0 {
+				if len(irArtifacts) != 0 {
+					t.Fatalf("Expected no IR artifact. Actual: %+v", irArtifacts)
+				}
+				return
+			}
+			if len(irArtifacts) != 1 {
+				t.Fatalf("Expected one IR artifact. Actual: %+v", createdArtifacts)
+			}
+			ir := irtypes.IR{}
+			if err := irArtifacts[0].GetConfig(irtypes.IRConfigType, &ir); err != nil {
+				t.Fatalf("Failed to get the IR from the artifact. Error: %q", err)
+			}
+			if actual := ir.Services["orders"].Dependencies; !cmp.Equal(actual, tc.wantDependencies) {
+				t.Fatalf("Failed to get the dependencies of the service properly. Differences:\n%s", cmp.Diff(tc.wantDependencies, actual))
+			}
+			source, err := ioutil.ReadFile(javaPath)
+			if err != nil {
+				t.Fatalf("Failed to read the java file. Error: %q", err)
+			}
+			for _, url := range tc.wantURLs {
+				if !strings.Contains(string(source), `url = "`+url+`"`) {
+					t.Fatalf("Expected the feign client to be called using the url %s. Actual:\n%s", url, source)
+				}
+			}
+		})
+	}
+}
@@ -31,6 +31,7 @@ import (
 	transformertypes "github.com/konveyor/move2kube/types/transformer"
 	"github.com/konveyor/move2kube/types/transformer/artifacts"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 const (
@@ -390,6 +391,57 @@ func (t *MavenAnalyser) Transform(newArtifacts []transformertypes.Artifact, oldA
 				},
 			})
 		}
+		annotations := map[string]string{}
+		if _, ok := a.Configs[artifacts.SpringBootConfigType]; ok && isActuatorPresent(pom) && len(a.Paths[artifacts.ProjectPathPathType]) != 0 {
+			springBootVersion := springbootConfig.SpringBootVersion
+			if springBootVersion == "" && pom.Parent != nil && pom.Parent.ArtifactID == "spring-boot-starter-parent" {
+				springBootVersion = pom.Parent.Version
+			}
+			annotations = getSpringBootActuatorProbeAnnotations(a.Paths[artifacts.ProjectPathPathType][0], springBootVersion)
+		}
+		if heapSize, ok := common.GetJavaMaxHeapSize(getJvmArguments(pom)); ok {
+			annotations[common.JavaMaxHeapAnnotation] = resource.NewQuantity(heapSize, resource.BinarySI).String()
+		}
+		if len(annotations) != 0 {
+			ir := irtypes.NewIR()
+			ir.Services[sConfig.ServiceName] = irtypes.Service{
+				Name:        sConfig.ServiceName,
+				Annotations: annotations,
+			}
+			createdArtifacts = append(createdArtifacts, transformertypes.Artifact{
+				Name:     t.Env.GetProjectName(),
+				Artifact: irtypes.IRArtifactType,
+				Configs: map[transformertypes.ConfigType]interface{}{
+					irtypes.IRConfigType: ir,
+				},
+			})
+		}
 	}
 	return pathMappings, createdArtifacts, nil
 }
+
+// getJvmArguments returns the arguments of the JVM which the spring boot maven plugin runs the app with
+func getJvmArguments(pom maven.Pom) string {
+	if pom.Build == nil || pom.Build.Plugins == nil {
+		return ""
+	}
+	for _, mavenPlugin := range *pom.Build.Plugins {
+		if mavenPlugin.ArtifactID == "spring-boot-maven-plugin" {
+			return mavenPlugin.Configuration.JvmArguments
+		}
+	}
+	return ""
+}
+
+// isActuatorPresent checks if the spring boot actuator, which serves the health endpoints, is a dependency
+func isActuatorPresent(pom maven.Pom) bool {
+	if pom.Dependencies == nil {
+		return false
+	}
+	for _, dependency := range *pom.Dependencies {
+		if dependency.ArtifactID == springbootActuatorArtifactID {
+			return true
+		}
+	}
+	return false
+}
@@ -21,25 +21,40 @@ import (
 	"bytes"
 	"io"
 	"io/ioutil"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/konveyor/move2kube/common"
 	"github.com/magiconair/properties"
 	"github.com/mikefarah/yq/v4/pkg/yqlib"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/mod/semver"
 	"gopkg.in/yaml.v3"
 )
 
 const (
 	springbootAppNameConfig  = "spring.application.name"
 	springbootProfilesConfig = "spring.profiles"
+
+	springbootContextPathConfig          = "server.servlet.context-path"
+	springbootManagementPortConfig       = "management.server.port"
+	springbootManagementBasePathConfig   = "management.server.base-path"
+	springbootActuatorBasePathConfig     = "management.endpoints.web.base-path"
+	springbootActuatorArtifactID         = "spring-boot-starter-actuator"
+	springbootDefaultActuatorBasePath    = "/actuator"
+	springbootHealthGroupsMinimumVersion = "v2.3"
 )
 
 const (
 	seperator = `---`
 )
 
+var (
+	springbootVersionRegex = regexp.MustCompile(`^[0-9]+(\.[0-9]+){0,2}`)
+)
+
 // SpringBootMetadataFiles defines the lists of configuration files from spring boot applications
 type SpringBootMetadataFiles struct {
 	bootstrapFiles     []string
@@ -134,6 +149,53 @@ func getSpringBootAppNameAndProfiles(springbootMetadataFiles SpringBootMetadataF
 	return appName, profiles
 }
 
+// getSpringBootActuatorProbeAnnotations returns the annotations of the actuator health endpoints used to probe the app.
+// The liveness and readiness health groups are available from spring boot 2.3.
+func getSpringBootActuatorProbeAnnotations(dir, springBootVersion string) map[string]string {
+	springbootMetadataFiles := getSpringBootMetadataFiles(dir)
+	propss := []*properties.Properties{}
+	for _, appPropFile := range springbootMetadataFiles.appPropFiles {
+		if filepath.Base(appPropFile) == "application.properties" {
+			propss = append(propss, getPropertiesFileSegmentsAsProperties(getSegmentsFromFiles([]string{appPropFile}))...)
+		}
+	}
+	for _, appYamlFile := range springbootMetadataFiles.appYamlFiles {
+		if base := filepath.Base(appYamlFile); base == "application.yaml" || base == "application.yml" {
+			propss = append(propss, getYamlSegmentsAsProperties(getSegmentsFromFiles([]string{appYamlFile}))...)
+		}
+	}
+	getProperty := func(key string) string {
+		for _, props := range propss {
+			if props == nil {
+				continue
+			}
+			if val := strings.TrimSpace(props.GetString(key, "")); val != "" {
+				return val
+			}
+		}
+		return ""
+	}
+	annotations := map[string]string{}
+	basePath := getProperty(springbootContextPathConfig)
+	if managementPort := getProperty(springbootManagementPortConfig); managementPort != "" {
+		annotations[common.ProbePortAnnotation] = managementPort
+		basePath = getProperty(springbootManagementBasePathConfig)
+	}
+	actuatorBasePath := getProperty(springbootActuatorBasePathConfig)
+	if actuatorBasePath == "" {
+		actuatorBasePath = springbootDefaultActuatorBasePath
+	}
+	healthPath := path.Join("/", basePath, actuatorBasePath, "health")
+	// Versions like 2.1.6.RELEASE are not semantic versions, so only their numeric prefix is compared
+	if version := "v" + springbootVersionRegex.FindString(springBootVersion); semver.IsValid(version) && semver.Compare(version, springbootHealthGroupsMinimumVersion) < 0 {
+		annotations[common.ProbePathAnnotation] = healthPath
+		return annotations
+	}
+	annotations[common.ProbePathAnnotation] = path.Join(healthPath, "liveness")
+	annotations[common.ReadinessProbePathAnnotation] = path.Join(healthPath, "readiness")
+	return annotations
+}
+
 func getYamlAsProperties(yamlStr string) (props *properties.Properties, err error) {
 	decoder := yaml.NewDecoder(strings.NewReader(yamlStr))
 	var dataBucket yaml.Node
@@ -17,7 +17,11 @@
 package dockerfile
 
 import (
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/environment"
@@ -85,3 +89,87 @@ func Transform(tc transformertypes.Transformer, env *environment.Environment, ne
 	}
 	return pathMappings, artifactsCreated, nil
 }
+
+var (
+	// nodejsWebFrameworks are the packages of the web frameworks whose health routes are looked for in the source
+	nodejsWebFrameworks = []string{"express", "fastify", "koa", "@hapi/hapi", "@nestjs/core", "@godaddy/terminus"}
+	// nodejsSourceExts are the extensions of the files in which the health routes are looked for
+	nodejsSourceExts = []string{".js", ".mjs", ".cjs", ".ts"}
+	// nodejsHealthRouteRegex matches the registration of a health route, like app.get('/health', ...), path: '/healthz',
+	// healthChecks: { '/health': ... } for terminus or @Controller('health') for nestjs
+	nodejsHealthRouteRegex = regexp.MustCompile(`(?:\.(?:get|all|route)\(\s*|(?:path|url)\s*:\s*)['"` + "`" + `](/[\w./-]*health[\w./-]*)['"` + "`" + `]|['"](/[\w./-]*health[\w./-]*)['"]\s*:|@Controller\(\s*['"]/?([\w./-]*health[\w./-]*)['"]`)
+	// djangoHealthCheckPackages are the packages which serve the health of django apps on /ht/
+	djangoHealthCheckPackages = []string{"django-health-check", "django_health_check"}
+)
+
+// getFrameworkHealthCheckPath returns the HTTP path on which the app serves its health.
+// It returns an empty string, so that only the port is checked, when no health route is detected.
+func getFrameworkHealthCheckPath(dir string) string {
+	var packageJSON PackageJSON
+	if err := common.ReadJSON(filepath.Join(dir, packageJSONFile), &packageJSON); err == nil {
+		for _, framework := range nodejsWebFrameworks {
+			if _, ok := packageJSON.Dependencies[framework]; ok {
+				return getNodejsHealthRoute(dir)
+			}
+		}
+		return ""
+	}
+	if _, err := os.Stat(filepath.Join(dir, "manage.py")); err != nil {
+		return ""
+	}
+	for _, requirementsFile := range []string{"requirements.txt", "Pipfile", "pyproject.toml"} {
+		requirements, err := ioutil.ReadFile(filepath.Join(dir, requirementsFile))
+		if err != nil {
+			continue
+		}
+		for _, healthCheckPackage := range djangoHealthCheckPackages {
+			if strings.Contains(string(requirements), healthCheckPackage) {
+				return "/ht/"
+			}
+		}
+	}
+	return ""
+}
+
+// getNodejsHealthRoute returns the first health route registered in the source of the nodejs project
+func getNodejsHealthRoute(dir string) string {
+	healthRoute := ""
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			logrus.Debugf("Skipping path %s due to error : %s", path, err)
+			return nil
+		}
+		if healthRoute != "" {
+			return filepath.SkipDir
+		}
+		if info.IsDir() {
+			if path != dir && (info.Name() == "node_modules" || strings.HasPrefix(info.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !common.IsStringPresent(nodejsSourceExts, filepath.Ext(path)) {
+			return nil
+		}
+		source, err := ioutil.ReadFile(path)
+		if err != nil {
+			logrus.Debugf("Unable to read the file %s : %s", path, err)
+			return nil
+		}
+		matches := nodejsHealthRouteRegex.FindSubmatch(source)
+		if matches == nil {
+			return nil
+		}
+		for _, match := range matches[1:] {
+			if len(match) != 0 {
+				healthRoute = "/" + strings.TrimPrefix(string(match), "/")
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Debugf("Unable to look for the health route in %s : %s", dir, err)
+	}
+	return healthRoute
+}
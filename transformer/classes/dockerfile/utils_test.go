@@ -0,0 +1,99 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package dockerfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetFrameworkHealthCheckPath(t *testing.T) {
+	testcases := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name: "express without a health route is probed on its port",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"express": "^4.17.1"}}`,
+				"index.js":     `app.get('/', (req, res) => res.send('hello'))`,
+			},
+			want: "",
+		},
+		{
+			name: "express health route",
+			files: map[string]string{
+				"package.json":      `{"dependencies": {"express": "^4.17.1"}}`,
+				"src/routes.js":     `router.get("/healthz", (req, res) => res.sendStatus(200))`,
+				"node_modules/x.js": `app.get('/health/dependency', handler)`,
+			},
+			want: "/healthz",
+		},
+		{
+			name: "fastify route option",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"fastify": "^3.0.0"}}`,
+				"server.ts":    `fastify.route({ method: 'GET', url: '/api/health', handler })`,
+			},
+			want: "/api/health",
+		},
+		{
+			name: "health route in node_modules is ignored",
+			files: map[string]string{
+				"package.json":      `{"dependencies": {"koa": "^2.13.1"}}`,
+				"node_modules/x.js": `router.get('/health', handler)`,
+			},
+			want: "",
+		},
+		{
+			name: "nestjs health controller",
+			files: map[string]string{
+				"package.json":             `{"dependencies": {"@nestjs/core": "^8.0.0"}}`,
+				"src/health.controller.ts": `@Controller('health')`,
+			},
+			want: "/health",
+		},
+		{
+			name: "django health check",
+			files: map[string]string{
+				"manage.py":        "",
+				"requirements.txt": "Django==3.2\ndjango-health-check==3.16.4\n",
+			},
+			want: "/ht/",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tc.files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+					t.Fatalf("Failed to create the directory for %s. Error: %q", name, err)
+				}
+				if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write %s. Error: %q", name, err)
+				}
+			}
+			if actual := getFrameworkHealthCheckPath(dir); actual != tc.want {
+				t.Fatalf("Expected the health check path to be %q. Actual: %q", tc.want, actual)
+			}
+		})
+	}
+}
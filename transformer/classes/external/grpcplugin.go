@@ -0,0 +1,262 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"runtime"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/environment"
+	"github.com/konveyor/move2kube/qaengine"
+	environmenttypes "github.com/konveyor/move2kube/types/environment"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+	"github.com/konveyor/move2kube/types/transformer/transformergrpc"
+	"github.com/phayes/freeport"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	defaultGRPCPluginStartupTimeout = 60
+	defaultGRPCPluginCallTimeout    = 600
+)
+
+// GRPCPlugin implements transformer interface and is used to talk to long running external transformers over grpc
+type GRPCPlugin struct {
+	Config       transformertypes.Transformer
+	PluginConfig GRPCPluginYamlConfig
+	Env          *environment.Environment
+
+	conn   *grpc.ClientConn
+	client transformergrpc.TransformerClient
+}
+
+// GRPCPluginYamlConfig is the format of grpc plugin yaml config
+type GRPCPluginYamlConfig struct {
+	EnableQA       bool                       `yaml:"enableQA"`
+	Platforms      []string                   `yaml:"platforms"`
+	ServerCMD      environmenttypes.Command   `yaml:"serverCMD"`
+	Port           int                        `yaml:"port"`           // Default : A free port is chosen and passed to the server in M2K_PLUGIN_PORT
+	StartupTimeout int                        `yaml:"startupTimeout"` // In seconds
+	CallTimeout    int                        `yaml:"callTimeout"`    // In seconds
+	Container      environmenttypes.Container `yaml:"container,omitempty"`
+}
+
+// Init starts the plugin server and waits for it to become healthy
+func (t *GRPCPlugin) Init(tc transformertypes.Transformer, env *environment.Environment) (err error) {
+	t.Config = tc
+	t.PluginConfig = GRPCPluginYamlConfig{}
+	err = common.GetObjFromInterface(t.Config.Spec.Config, &t.PluginConfig)
+	if err != nil {
+		logrus.Errorf("unable to load config for Transformer %+v into %T : %s", t.Config.Spec.Config, t.PluginConfig, err)
+		return err
+	}
+	if len(t.PluginConfig.ServerCMD) == 0 {
+		return fmt.Errorf("no serverCMD specified for transformer %s", tc.Name)
+	}
+	if t.PluginConfig.StartupTimeout <= 0 {
+		t.PluginConfig.StartupTimeout = defaultGRPCPluginStartupTimeout
+	}
+	if t.PluginConfig.CallTimeout <= 0 {
+		t.PluginConfig.CallTimeout = defaultGRPCPluginCallTimeout
+	}
+	var qaRPCReceiverAddr net.Addr = nil
+	if t.PluginConfig.EnableQA {
+		qaRPCReceiverAddr, err = qaengine.StartGRPCReceiver()
+		if err != nil {
+			logrus.Errorf("Unable to start QA RPC Receiver engine : %s", err)
+			logrus.Infof("Starting transformer that requires QA without QA.")
+		}
+	}
+	if !common.IsStringPresent(t.PluginConfig.Platforms, runtime.GOOS) && t.PluginConfig.Container.Image == "" {
+		return fmt.Errorf("platform %s not supported by transformer %s", runtime.GOOS, tc.Name)
+	}
+	t.Env, err = environment.NewEnvironment(env.EnvInfo, qaRPCReceiverAddr, t.PluginConfig.Container)
+	if err != nil {
+		logrus.Errorf("Unable to create Exec environment : %s", err)
+		return err
+	}
+	defer func() {
+		// Uninitialized transformers are not destroyed later, so the environment and the server have to be cleaned up here
+		if err != nil {
+			if derr := t.Env.Destroy(); derr != nil {
+				logrus.Errorf("Unable to destroy environment : %s", derr)
+			}
+		}
+	}()
+	port := t.PluginConfig.Port
+	if port == 0 {
+		port, err = freeport.GetFreePort()
+		if err != nil {
+			return fmt.Errorf("unable to find a free port : %s", err)
+		}
+	}
+	host, err := t.Env.Spawn(t.PluginConfig.ServerCMD, []string{environment.PluginPortEnvName + "=" + cast.ToString(port)})
+	if err != nil {
+		logrus.Errorf("Unable to start the plugin server for transformer %s : %s", tc.Name, err)
+		return err
+	}
+	addr := net.JoinHostPort(host, cast.ToString(port))
+	startupCtx, cancel := context.WithTimeout(context.Background(), time.Duration(t.PluginConfig.StartupTimeout)*time.Second)
+	defer cancel()
+	t.conn, err = grpc.DialContext(startupCtx, addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		logrus.Errorf("Unable to connect to the plugin server for transformer %s at %s : %s", tc.Name, addr, err)
+		return err
+	}
+	// The connection is closed before the plugin server is stopped, when the environment is destroyed
+	t.Env.AddCloser(t.conn)
+	if err = t.waitForHealthy(startupCtx); err != nil {
+		logrus.Errorf("Plugin server for transformer %s at %s is not healthy : %s", tc.Name, addr, err)
+		return err
+	}
+	t.client = transformergrpc.NewTransformerClient(t.conn)
+	tcmapobj, err := common.GetMapInterfaceFromObj(tc)
+	if err != nil {
+		logrus.Errorf("Unable to convert transformer config to map[string]interface{}")
+		return err
+	}
+	initInput := transformertypes.InitInput{
+		Config:      tcmapobj,
+		ProjectName: t.Env.GetProjectName(),
+		Source:      t.Env.GetEnvironmentSource(),
+		Context:     t.Env.GetEnvironmentContext(),
+	}
+	initInputBytes, err := json.Marshal(initInput)
+	if err != nil {
+		logrus.Errorf("Unable to marshal the init input %+v : %s", initInput, err)
+		return err
+	}
+	if _, err = t.client.Init(startupCtx, wrapperspb.String(string(initInputBytes))); err != nil {
+		logrus.Errorf("Unable to initialize the plugin for transformer %s : %s", tc.Name, err)
+		return err
+	}
+	logrus.Debugf("Plugin server for transformer %s started at %s", tc.Name, addr)
+	return nil
+}
+
+// GetConfig returns the transformer config
+func (t *GRPCPlugin) GetConfig() (transformertypes.Transformer, *environment.Environment) {
+	return t.Config, t.Env
+}
+
+// BaseDirectoryDetect runs detect in base directory
+func (t *GRPCPlugin) BaseDirectoryDetect(dir string) (namedServices map[string]transformertypes.ServicePlan, unnamedServices []transformertypes.TransformerPlan, err error) {
+	return t.executeDetect(t.client.BaseDirectoryDetect, dir)
+}
+
+// DirectoryDetect runs detect in each sub directory
+func (t *GRPCPlugin) DirectoryDetect(dir string) (namedServices map[string]transformertypes.ServicePlan, unnamedServices []transformertypes.TransformerPlan, err error) {
+	namedServices, unnamedServices, err = t.executeDetect(t.client.DirectoryDetect, dir)
+	if err != nil {
+		return namedServices, unnamedServices, err
+	}
+	for sn, ns := range namedServices {
+		for nsi, nst := range ns {
+			if len(nst.Paths) == 0 {
+				nst.Paths = map[string][]string{
+					artifacts.ProjectPathPathType: {dir},
+				}
+				ns[nsi] = nst
+			}
+		}
+		namedServices[sn] = ns
+	}
+	for unsi, unst := range unnamedServices {
+		if len(unst.Paths) == 0 {
+			unst.Paths = map[string][]string{
+				artifacts.ProjectPathPathType: {dir},
+			}
+		}
+		unnamedServices[unsi] = unst
+	}
+	return namedServices, unnamedServices, nil
+}
+
+// Transform transforms the artifacts
+func (t *GRPCPlugin) Transform(newArtifacts []transformertypes.Artifact, oldArtifacts []transformertypes.Artifact) (pathMappings []transformertypes.PathMapping, createdArtifacts []transformertypes.Artifact, err error) {
+	input, err := json.Marshal(transformertypes.TransformInput{NewArtifacts: newArtifacts, OldArtifacts: oldArtifacts})
+	if err != nil {
+		logrus.Errorf("Unable to marshal artifacts to json : %s", err)
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(t.PluginConfig.CallTimeout)*time.Second)
+	defer cancel()
+	resp, err := t.client.Transform(ctx, wrapperspb.String(string(input)))
+	if err != nil {
+		logrus.Errorf("Transform failed in transformer %s : %s", t.Config.Name, err)
+		return nil, nil, err
+	}
+	output := transformertypes.TransformOutput{}
+	if err := json.Unmarshal([]byte(resp.GetValue()), &output); err != nil {
+		logrus.Errorf("Error in unmarshalling json %s: %s.", resp.GetValue(), err)
+		return nil, nil, err
+	}
+	return output.PathMappings, output.CreatedArtifacts, nil
+}
+
+func (t *GRPCPlugin) executeDetect(detectFn func(context.Context, *wrapperspb.StringValue, ...grpc.CallOption) (*wrapperspb.StringValue, error), dir string) (namedServices map[string]transformertypes.ServicePlan, unnamedServices []transformertypes.TransformerPlan, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(t.PluginConfig.CallTimeout)*time.Second)
+	defer cancel()
+	resp, err := detectFn(ctx, wrapperspb.String(dir))
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return nil, nil, nil
+		}
+		logrus.Errorf("Detect failed in transformer %s for %s : %s", t.Config.Name, dir, err)
+		return nil, nil, err
+	}
+	if resp.GetValue() == "" {
+		return nil, nil, nil
+	}
+	output := transformertypes.DetectOutput{}
+	if err := json.Unmarshal([]byte(resp.GetValue()), &output); err != nil {
+		logrus.Errorf("Error in unmarshalling json %s: %s.", resp.GetValue(), err)
+		return nil, nil, err
+	}
+	return output.NamedServices, output.UnNamedServices, nil
+}
+
+func (t *GRPCPlugin) waitForHealthy(ctx context.Context) error {
+	healthClient := healthpb.NewHealthClient(t.conn)
+	for {
+		resp, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{})
+		if err == nil && resp.GetStatus() == healthpb.HealthCheckResponse_SERVING {
+			return nil
+		}
+		logrus.Debugf("Waiting for the plugin server of transformer %s to become healthy : %+v : %v", t.Config.Name, resp, err)
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("plugin server reported status %s", resp.GetStatus())
+		case <-time.After(time.Second):
+		}
+	}
+}
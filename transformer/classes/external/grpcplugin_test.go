@@ -0,0 +1,207 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/environment"
+	environmenttypes "github.com/konveyor/move2kube/types/environment"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+	"github.com/konveyor/move2kube/types/transformer/transformergrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type fakeTransformerServer struct {
+	transformergrpc.UnimplementedTransformerServer
+	initInput transformertypes.InitInput
+}
+
+func (s *fakeTransformerServer) Init(ctx context.Context, in *wrapperspb.StringValue) (*emptypb.Empty, error) {
+	if err := json.Unmarshal([]byte(in.GetValue()), &s.initInput); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *fakeTransformerServer) DirectoryDetect(ctx context.Context, in *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	output := transformertypes.DetectOutput{
+		NamedServices: map[string]transformertypes.ServicePlan{
+			"orders": {{Mode: transformertypes.ModeContainer, TransformerName: "test"}},
+		},
+	}
+	outputBytes, err := json.Marshal(output)
+	if err != nil {
+		return nil, err
+	}
+	return wrapperspb.String(string(outputBytes)), nil
+}
+
+func (s *fakeTransformerServer) Transform(ctx context.Context, in *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	input := transformertypes.TransformInput{}
+	if err := json.Unmarshal([]byte(in.GetValue()), &input); err != nil {
+		return nil, err
+	}
+	output := transformertypes.TransformOutput{}
+	for _, a := range input.NewArtifacts {
+		output.PathMappings = append(output.PathMappings, transformertypes.PathMapping{SrcPath: a.Name, DestPath: a.Name})
+	}
+	outputBytes, err := json.Marshal(output)
+	if err != nil {
+		return nil, err
+	}
+	return wrapperspb.String(string(outputBytes)), nil
+}
+
+func startFakePluginServer(t *testing.T, servingStatus healthpb.HealthCheckResponse_ServingStatus) (*fakeTransformerServer, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen : %s", err)
+	}
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", servingStatus)
+	healthpb.RegisterHealthServer(server, healthServer)
+	transformerServer := &fakeTransformerServer{}
+	transformergrpc.RegisterTransformerServer(server, transformerServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return transformerServer, listener.Addr().(*net.TCPAddr).Port
+}
+
+func newTestGRPCPlugin(t *testing.T, port int) (*GRPCPlugin, transformertypes.Transformer, *environment.Environment) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test plugin server command needs a unix shell")
+	}
+	common.TempPath = t.TempDir()
+	env, err := environment.NewEnvironment(environment.EnvInfo{Name: "test", ProjectName: "myproject", Source: t.TempDir(), Context: t.TempDir()}, nil, environmenttypes.Container{})
+	if err != nil {
+		t.Fatalf("Unable to create the environment : %s", err)
+	}
+	t.Cleanup(func() { env.Destroy() })
+	tc := transformertypes.Transformer{}
+	tc.Name = "test"
+	tc.Spec.Config = map[string]interface{}{
+		"serverCMD":      []string{"sleep", "60"},
+		"port":           port,
+		"platforms":      []string{runtime.GOOS},
+		"startupTimeout": 5,
+	}
+	return &GRPCPlugin{}, tc, env
+}
+
+func TestGRPCPlugin(t *testing.T) {
+	server, port := startFakePluginServer(t, healthpb.HealthCheckResponse_SERVING)
+	plugin, tc, env := newTestGRPCPlugin(t, port)
+	if err := plugin.Init(tc, env); err != nil {
+		t.Fatalf("Unable to initialize the plugin : %s", err)
+	}
+	defer plugin.Env.Destroy()
+
+	t.Run("init sends the project details to the plugin", func(t *testing.T) {
+		if server.initInput.ProjectName != "myproject" {
+			t.Fatalf("Expected the project name to be myproject. Actual: %s", server.initInput.ProjectName)
+		}
+		if server.initInput.Config == nil {
+			t.Fatal("Expected the transformer config to be sent to the plugin")
+		}
+	})
+	t.Run("directory detect fills in the directory of the services", func(t *testing.T) {
+		dir := t.TempDir()
+		namedServices, unnamedServices, err := plugin.DirectoryDetect(dir)
+		if err != nil {
+			t.Fatalf("Unable to detect : %s", err)
+		}
+		if len(unnamedServices) != 0 {
+			t.Fatalf("Expected no unnamed services. Actual: %+v", unnamedServices)
+		}
+		ns, ok := namedServices["orders"]
+		if !ok || len(ns) != 1 {
+			t.Fatalf("Expected the service orders to be detected. Actual: %+v", namedServices)
+		}
+		if paths := ns[0].Paths[artifacts.ProjectPathPathType]; len(paths) != 1 || paths[0] != dir {
+			t.Fatalf("Expected the project path to be %s. Actual: %+v", dir, ns[0].Paths)
+		}
+	})
+	t.Run("unimplemented base directory detect detects nothing", func(t *testing.T) {
+		namedServices, unnamedServices, err := plugin.BaseDirectoryDetect(t.TempDir())
+		if err != nil {
+			t.Fatalf("Expected no error. Actual: %s", err)
+		}
+		if len(namedServices) != 0 || len(unnamedServices) != 0 {
+			t.Fatalf("Expected no services. Actual: %+v %+v", namedServices, unnamedServices)
+		}
+	})
+	t.Run("transform returns the path mappings of the plugin", func(t *testing.T) {
+		newArtifacts := []transformertypes.Artifact{{Name: "orders"}}
+		pathMappings, _, err := plugin.Transform(newArtifacts, nil)
+		if err != nil {
+			t.Fatalf("Unable to transform : %s", err)
+		}
+		if len(pathMappings) != 1 || pathMappings[0].SrcPath != "orders" {
+			t.Fatalf("Expected a path mapping for orders. Actual: %+v", pathMappings)
+		}
+	})
+	t.Run("destroying the environment closes the connection", func(t *testing.T) {
+		if err := plugin.Env.Destroy(); err != nil {
+			t.Fatalf("Unable to destroy the environment : %s", err)
+		}
+		if state := plugin.conn.GetState(); state != connectivity.Shutdown {
+			t.Fatalf("Expected the connection to be shut down. Actual: %s", state)
+		}
+	})
+}
+
+func TestGRPCPluginNotServing(t *testing.T) {
+	_, port := startFakePluginServer(t, healthpb.HealthCheckResponse_NOT_SERVING)
+	plugin, tc, env := newTestGRPCPlugin(t, port)
+	tc.Spec.Config.(map[string]interface{})["startupTimeout"] = 1
+	if err := plugin.Init(tc, env); err == nil {
+		plugin.Env.Destroy()
+		t.Fatal("Expected the plugin initialization to fail when the server is not healthy")
+	}
+	if state := plugin.conn.GetState(); state != connectivity.Shutdown {
+		t.Fatalf("Expected the connection to be shut down. Actual: %s", state)
+	}
+}
+
+func TestGRPCPluginSpawnFailure(t *testing.T) {
+	plugin, tc, env := newTestGRPCPlugin(t, 0)
+	tc.Spec.Config.(map[string]interface{})["serverCMD"] = []string{"move2kube-test-plugin-server-which-does-not-exist"}
+	if err := plugin.Init(tc, env); err == nil {
+		plugin.Env.Destroy()
+		t.Fatal("Expected the plugin initialization to fail when the server cannot be started")
+	}
+	if plugin.Env == nil {
+		t.Fatal("Expected the plugin environment to be created")
+	}
+	if _, err := os.Stat(plugin.Env.GetEnvironmentContext()); !os.IsNotExist(err) {
+		t.Fatalf("Expected the environment context %s to be removed. Error: %v", plugin.Env.GetEnvironmentContext(), err)
+	}
+}
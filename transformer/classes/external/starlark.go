@@ -72,9 +72,19 @@ type Starlark struct {
 	transformFn  *starlark.Function
 }
 
+const defaultStarlarkMaxExecutionSteps = 100000000
+
 // StarYamlConfig defines yaml config for Starlark transformers
 type StarYamlConfig struct {
-	StarFile string `yaml:"starFile"`
+	StarFile          string          `yaml:"starFile"`
+	MaxExecutionSteps uint64          `yaml:"maxExecutionSteps"` // Per function call. Default : 100000000
+	Permissions       StarPermissions `yaml:"permissions"`
+}
+
+// StarPermissions defines the file system access allowed for Starlark transformers
+type StarPermissions struct {
+	WritableSource bool     `yaml:"writableSource"` // Default : The source directory is read only
+	WriteRoots     []string `yaml:"writeRoots"`     // Absolute or relative to the transformer yaml directory. The transformer temp and output directories are always writable
 }
 
 // Init Initializes the transformer
@@ -87,14 +97,17 @@ func (t *Starlark) Init(tc transformertypes.Transformer, env *environment.Enviro
 		logrus.Errorf("unable to load config for Transformer %+v into %T : %s", t.Config.Spec.Config, t.StarConfig, err)
 		return err
 	}
-	t.StarThread = &starlark.Thread{Name: tc.Name}
+	if t.StarConfig.MaxExecutionSteps == 0 {
+		t.StarConfig.MaxExecutionSteps = defaultStarlarkMaxExecutionSteps
+	}
+	t.StarThread = t.newThread()
 	t.setDefaultGlobals()
 	tcmapobj, err := common.GetMapInterfaceFromObj(tc)
 	if err != nil {
 		logrus.Errorf("Unable to conver transformer config to map[string]interface{}")
 		return err
 	}
-	t.StarGlobals[env.ProjectName], err = starutil.Marshal(env.ProjectName)
+	t.StarGlobals[projectVarName], err = starutil.Marshal(env.ProjectName)
 	if err != nil {
 		logrus.Errorf("Unable to load transformer config : %s", err)
 		return err
@@ -168,7 +181,7 @@ func (t *Starlark) Transform(newArtifacts []transformertypes.Artifact, oldArtifa
 		logrus.Errorf("Unable to convert %s to starlark value : %s", oldArtifacts, err)
 		return nil, nil, err
 	}
-	val, err := starlark.Call(t.StarThread, t.transformFn, starlark.Tuple{starNewArtifacts, starOldArtifacts}, nil)
+	val, err := starlark.Call(t.newThread(), t.transformFn, starlark.Tuple{starNewArtifacts, starOldArtifacts}, nil)
 	if err != nil {
 		logrus.Errorf("Unable to execute starlark function : %s", err)
 		return nil, nil, err
@@ -196,7 +209,7 @@ func (t *Starlark) executeDetect(fn *starlark.Function, dir string) (nameService
 		logrus.Errorf("Unable to convert %s to starlark value : %s", dir, err)
 		return nil, nil, err
 	}
-	val, err := starlark.Call(t.StarThread, fn, starlark.Tuple{starDir}, nil)
+	val, err := starlark.Call(t.newThread(), fn, starlark.Tuple{starDir}, nil)
 	if err != nil {
 		logrus.Errorf("Unable to execute starlark function : %s", err)
 		return nil, nil, err
@@ -215,6 +228,13 @@ func (t *Starlark) executeDetect(fn *starlark.Function, dir string) (nameService
 	return detectOutput.NamedServices, detectOutput.UnNamedServices, nil
 }
 
+// newThread returns a thread with a fresh execution step budget
+func (t *Starlark) newThread() *starlark.Thread {
+	thread := &starlark.Thread{Name: t.Config.Name}
+	thread.SetMaxExecutionSteps(t.StarConfig.MaxExecutionSteps)
+	return thread
+}
+
 func (t *Starlark) getStarlarkQuery() *starlark.Builtin {
 	return starlark.NewBuiltin(qaFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 		argDictValue := &starlark.Dict{}
@@ -267,8 +287,12 @@ func (t *Starlark) getStarlarkFSWrite() *starlark.Builtin {
 		if len(data) == 0 {
 			return starlark.None, fmt.Errorf("data is missing in write parameters")
 		}
+		filePath, err := t.getWritablePath(filePath)
+		if err != nil {
+			return starlark.None, err
+		}
 		numBytesWritten := len(data)
-		err := ioutil.WriteFile(filePath, []byte(data), fs.FileMode(permissions))
+		err = ioutil.WriteFile(filePath, []byte(data), fs.FileMode(permissions))
 		if err != nil {
 			return starlark.None, fmt.Errorf("could not write to file %s", filePath)
 		}
@@ -317,11 +341,13 @@ func (t *Starlark) addFSModules() {
 }
 
 func (t *Starlark) addAppModules() {
+	members, err := t.getAppModuleMembers()
+	if err != nil {
+		logrus.Errorf("Unable to load all the members of the %s module : %s", types.AppNameShort, err)
+	}
 	t.StarGlobals[types.AppNameShort] = &starlarkstruct.Module{
-		Name: types.AppNameShort,
-		Members: starlark.StringDict{
-			qaFnName: t.getStarlarkQuery(),
-		},
+		Name:    types.AppNameShort,
+		Members: members,
 	}
 }
 
@@ -481,3 +507,79 @@ func (t *Starlark) getStarlarkFSPathJoin() *starlark.Builtin {
 		return starutil.Marshal(path)
 	})
 }
+
+// getWritablePath resolves the path and checks that it is within the areas the transformer is allowed to write to
+func (t *Starlark) getWritablePath(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(t.Env.TempPath, path)
+	}
+	path, err := resolvePath(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve the path %s : %s", path, err)
+	}
+	if !t.StarConfig.Permissions.WritableSource {
+		for _, sourceDir := range []string{t.Env.Source, t.Env.GetEnvironmentSource()} {
+			if sourceDir == "" {
+				continue
+			}
+			if resolvedSourceDir, err := resolvePath(sourceDir); err == nil && isWithin(path, resolvedSourceDir) {
+				return "", fmt.Errorf("the path %s is in the source directory, which is read only for transformer %s", path, t.Config.Name)
+			}
+		}
+	}
+	writeRoots := []string{t.Env.TempPath, t.Env.GetEnvironmentOutput()}
+	for _, writeRoot := range t.StarConfig.Permissions.WriteRoots {
+		if !filepath.IsAbs(writeRoot) {
+			writeRoot = filepath.Join(t.Env.Context, writeRoot)
+		}
+		writeRoots = append(writeRoots, writeRoot)
+	}
+	for _, writeRoot := range writeRoots {
+		if writeRoot == "" {
+			continue
+		}
+		resolvedWriteRoot, err := resolvePath(writeRoot)
+		if err != nil {
+			logrus.Debugf("Unable to resolve the write root %s : %s", writeRoot, err)
+			continue
+		}
+		if isWithin(path, resolvedWriteRoot) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("transformer %s is not allowed to write to %s", t.Config.Name, path)
+}
+
+// resolvePath cleans the path and resolves the symbolic links in the longest existing prefix of the path,
+// so that neither ".." nor links can be used to escape a directory
+func resolvePath(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return path, err
+	}
+	existing, rest := path, ""
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return path, err
+	}
+	return filepath.Join(resolved, rest), nil
+}
+
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
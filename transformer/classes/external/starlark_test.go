@@ -0,0 +1,227 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/environment"
+	environmenttypes "github.com/konveyor/move2kube/types/environment"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	starutil "github.com/qri-io/starlib/util"
+	"go.starlark.net/starlark"
+)
+
+// newTestStarlark initializes a starlark transformer which runs the script
+func newTestStarlark(t *testing.T, script string, config map[string]interface{}) (*Starlark, error) {
+	common.TempPath = t.TempDir()
+	contextDir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(contextDir, "test.star"), []byte(script), common.DefaultFilePermission); err != nil {
+		t.Fatalf("Unable to create the starlark file : %s", err)
+	}
+	env, err := environment.NewEnvironment(environment.EnvInfo{Name: "test", ProjectName: "myproject", Source: t.TempDir(), Context: contextDir}, nil, environmenttypes.Container{})
+	if err != nil {
+		t.Fatalf("Unable to create the environment : %s", err)
+	}
+	t.Cleanup(func() { env.Destroy() })
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	config["starFile"] = "test.star"
+	tc := transformertypes.Transformer{}
+	tc.Name = "test"
+	tc.Spec.Config = config
+	st := &Starlark{}
+	return st, st.Init(tc, env)
+}
+
+func TestStarlarkInit(t *testing.T) {
+	st, err := newTestStarlark(t, "name = project\n\ndef transform(new_artifacts, old_artifacts):\n    return {}\n", nil)
+	if err != nil {
+		t.Fatalf("Unable to initialize the transformer : %s", err)
+	}
+	name, err := starutil.Unmarshal(st.StarGlobals["name"])
+	if err != nil {
+		t.Fatalf("Unable to unmarshal the project name : %s", err)
+	}
+	if name != "myproject" {
+		t.Fatalf("Expected the project global to be the project name myproject. Actual: %v", name)
+	}
+}
+
+func TestGetWritablePath(t *testing.T) {
+	common.TempPath = t.TempDir()
+	sourceDir := t.TempDir()
+	contextDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(contextDir, "out"), common.DefaultDirectoryPermission); err != nil {
+		t.Fatalf("Unable to create the test directory : %s", err)
+	}
+	env, err := environment.NewEnvironment(environment.EnvInfo{Name: "test", Source: sourceDir, Context: contextDir}, nil, environmenttypes.Container{})
+	if err != nil {
+		t.Fatalf("Unable to create the environment : %s", err)
+	}
+	defer env.Destroy()
+	st := &Starlark{Env: env, StarConfig: StarYamlConfig{Permissions: StarPermissions{WriteRoots: []string{"out"}}}}
+
+	t.Run("relative paths are resolved within the temp directory", func(t *testing.T) {
+		path, err := st.getWritablePath("foo/bar.yaml")
+		if err != nil {
+			t.Fatalf("Expected the path to be writable. Error: %s", err)
+		}
+		if !isWithin(path, env.TempPath) {
+			t.Fatalf("Expected %s to be within %s", path, env.TempPath)
+		}
+	})
+	t.Run("path traversal out of the temp directory is rejected", func(t *testing.T) {
+		if _, err := st.getWritablePath("../../foo.yaml"); err == nil {
+			t.Fatal("Expected the path to be rejected")
+		}
+	})
+	t.Run("the source directory and its copy are read only", func(t *testing.T) {
+		for _, dir := range []string{sourceDir, env.GetEnvironmentSource()} {
+			if _, err := st.getWritablePath(filepath.Join(dir, "Dockerfile")); err == nil {
+				t.Fatalf("Expected writes to %s to be rejected", dir)
+			}
+		}
+	})
+	t.Run("configured write roots are writable", func(t *testing.T) {
+		if _, err := st.getWritablePath(filepath.Join(contextDir, "out", "foo.yaml")); err != nil {
+			t.Fatalf("Expected the path to be writable. Error: %s", err)
+		}
+		if _, err := st.getWritablePath(filepath.Join(contextDir, "foo.yaml")); err == nil {
+			t.Fatal("Expected writes outside the write roots to be rejected")
+		}
+	})
+	t.Run("symbolic links can not be used to escape", func(t *testing.T) {
+		link := filepath.Join(env.TempPath, "link")
+		if err := os.Symlink(sourceDir, link); err != nil {
+			t.Skipf("Unable to create symbolic link : %s", err)
+		}
+		if _, err := st.getWritablePath(filepath.Join(link, "foo.yaml")); err == nil {
+			t.Fatal("Expected the path to be rejected")
+		}
+	})
+}
+
+func TestStarlarkMaxExecutionSteps(t *testing.T) {
+	script := `
+def directory_detect(dir):
+    for i in range(1000000000):
+        pass
+    return {}
+
+def transform(new_artifacts, old_artifacts):
+    return {}
+`
+	st, err := newTestStarlark(t, script, map[string]interface{}{"maxExecutionSteps": 10000})
+	if err != nil {
+		t.Fatalf("Unable to initialize the transformer : %s", err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := st.DirectoryDetect(t.TempDir())
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "too many steps") {
+			t.Fatalf("Expected the runaway loop to be stopped. Actual: %v", err)
+		}
+	case <-time.After(time.Minute):
+		t.Fatal("Expected the runaway loop to be stopped within a minute")
+	}
+	t.Run("every call gets a fresh step budget", func(t *testing.T) {
+		if _, _, err := st.Transform(nil, nil); err != nil {
+			t.Fatalf("Expected transform to run after a stopped call. Error: %s", err)
+		}
+	})
+}
+
+func TestStarlarkFSWrite(t *testing.T) {
+	script := `
+def write(path):
+    return fs.write(path, "data")
+
+def transform(new_artifacts, old_artifacts):
+    return {}
+`
+	st, err := newTestStarlark(t, script, map[string]interface{}{"permissions": map[string]interface{}{"writeRoots": []string{"out"}}})
+	if err != nil {
+		t.Fatalf("Unable to initialize the transformer : %s", err)
+	}
+	outDir := filepath.Join(st.Env.Context, "out")
+	if err := os.Mkdir(outDir, common.DefaultDirectoryPermission); err != nil {
+		t.Fatalf("Unable to create the test directory : %s", err)
+	}
+	outsideDir := t.TempDir()
+	write := func(path string) error {
+		_, err := starlark.Call(st.newThread(), st.StarGlobals["write"], starlark.Tuple{starlark.String(path)}, nil)
+		return err
+	}
+
+	t.Run("writes within the write roots succeed", func(t *testing.T) {
+		for _, path := range []string{filepath.Join(outDir, "foo.yaml"), "foo.yaml"} {
+			if err := write(path); err != nil {
+				t.Fatalf("Expected the write to %s to succeed. Error: %s", path, err)
+			}
+		}
+		if data, err := ioutil.ReadFile(filepath.Join(outDir, "foo.yaml")); err != nil || string(data) != "data" {
+			t.Fatalf("Expected the file to be written. Actual: %q %v", data, err)
+		}
+	})
+	t.Run("writes outside the write roots are rejected", func(t *testing.T) {
+		for _, path := range []string{filepath.Join(outsideDir, "foo.yaml"), filepath.Join(st.Env.Context, "foo.yaml"), filepath.Join(outDir, "..", "..", "foo.yaml")} {
+			if err := write(path); err == nil {
+				t.Fatalf("Expected the write to %s to be rejected", path)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(outsideDir, "foo.yaml")); !os.IsNotExist(err) {
+			t.Fatalf("Expected no file to be written outside the write roots. Error: %v", err)
+		}
+	})
+	t.Run("writes through symbolic links out of the write roots are rejected", func(t *testing.T) {
+		dirLink := filepath.Join(outDir, "dirlink")
+		if err := os.Symlink(outsideDir, dirLink); err != nil {
+			t.Skipf("Unable to create symbolic link : %s", err)
+		}
+		outsideFile := filepath.Join(outsideDir, "target.yaml")
+		if err := ioutil.WriteFile(outsideFile, []byte("original"), common.DefaultFilePermission); err != nil {
+			t.Fatalf("Unable to create the test file : %s", err)
+		}
+		fileLink := filepath.Join(outDir, "filelink.yaml")
+		if err := os.Symlink(outsideFile, fileLink); err != nil {
+			t.Fatalf("Unable to create symbolic link : %s", err)
+		}
+		for _, path := range []string{filepath.Join(dirLink, "foo.yaml"), filepath.Join(dirLink, "target.yaml"), fileLink} {
+			if err := write(path); err == nil {
+				t.Fatalf("Expected the write to %s to be rejected", path)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(outsideDir, "foo.yaml")); !os.IsNotExist(err) {
+			t.Fatalf("Expected no file to be written through the link. Error: %v", err)
+		}
+		if data, err := ioutil.ReadFile(outsideFile); err != nil || string(data) != "original" {
+			t.Fatalf("Expected the link target to be unchanged. Actual: %q %v", data, err)
+		}
+	})
+}
@@ -0,0 +1,289 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/konveyor/move2kube/common"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/mitchellh/mapstructure"
+	starutil "github.com/qri-io/starlib/util"
+	"github.com/sirupsen/logrus"
+	"go.starlark.net/starlark"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// m2k package
+	logDebugFnName          = "log_debug"
+	logInfoFnName           = "log_info"
+	logWarnFnName           = "log_warn"
+	logErrorFnName          = "log_error"
+	renderTemplateFnName    = "render_template"
+	artifactFnName          = "artifact"
+	pathMappingFnName       = "path_mapping"
+	transformerPlanFnName   = "transformer_plan"
+	globFnName              = "glob"
+	parseK8sYamlsFnName     = "parse_k8s_yamls"
+	dumpK8sYamlsFnName      = "dump_k8s_yamls"
+	patchK8sObjectFnName    = "patch_k8s_object"
+	projectNameMemberName   = "project_name"
+	targetClusterMemberName = "target_cluster"
+)
+
+func (t *Starlark) getStarlarkLog(fnName string, logFn func(format string, args ...interface{})) *starlark.Builtin {
+	return starlark.NewBuiltin(fnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var msg string
+		if err := starlark.UnpackPositionalArgs(fnName, args, kwargs, 1, &msg); err != nil {
+			return starlark.None, err
+		}
+		logFn("[%s] %s", t.Config.Name, msg)
+		return starlark.None, nil
+	})
+}
+
+func (t *Starlark) getStarlarkRenderTemplate() *starlark.Builtin {
+	return starlark.NewBuiltin(renderTemplateFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var tpl string
+		var data starlark.Value = starlark.None
+		if err := starlark.UnpackArgs(renderTemplateFnName, args, kwargs, "template", &tpl, "data?", &data); err != nil {
+			return starlark.None, err
+		}
+		dataI, err := starutil.Unmarshal(data)
+		if err != nil {
+			return starlark.None, fmt.Errorf("failed to unmarshal the template data : %s", err)
+		}
+		parsedTpl, err := template.New(t.Config.Name).Option("missingkey=error").Parse(tpl)
+		if err != nil {
+			return starlark.None, fmt.Errorf("unable to parse template : %s", err)
+		}
+		var out bytes.Buffer
+		if err := parsedTpl.Execute(&out, dataI); err != nil {
+			return starlark.None, fmt.Errorf("unable to render template : %s", err)
+		}
+		return starlark.String(out.String()), nil
+	})
+}
+
+// getStarlarkTypedConstructor returns a builtin which validates its keyword arguments against the go type
+// and returns the equivalent dict, so that scripts do not have to know the internal shapes
+func (t *Starlark) getStarlarkTypedConstructor(fnName string, newObj func() interface{}) *starlark.Builtin {
+	return starlark.NewBuiltin(fnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		if len(args) > 0 {
+			return starlark.None, fmt.Errorf("%s accepts only keyword arguments", fnName)
+		}
+		fields := map[string]interface{}{}
+		for _, kwarg := range kwargs {
+			key := string(kwarg[0].(starlark.String))
+			value, err := starutil.Unmarshal(kwarg[1])
+			if err != nil {
+				return starlark.None, fmt.Errorf("failed to unmarshal the argument %s of %s : %s", key, fnName, err)
+			}
+			fields[key] = value
+		}
+		obj := newObj()
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			Result:      obj,
+			TagName:     "yaml",
+			ErrorUnused: true,
+		})
+		if err != nil {
+			return starlark.None, err
+		}
+		if err := decoder.Decode(fields); err != nil {
+			return starlark.None, fmt.Errorf("invalid arguments to %s : %s", fnName, err)
+		}
+		mapObj, err := common.GetMapInterfaceFromObj(obj)
+		if err != nil {
+			return starlark.None, err
+		}
+		return starutil.Marshal(mapObj)
+	})
+}
+
+func (t *Starlark) getStarlarkGlob() *starlark.Builtin {
+	return starlark.NewBuiltin(globFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var dir, pattern string
+		if err := starlark.UnpackPositionalArgs(globFnName, args, kwargs, 2, &dir, &pattern); err != nil {
+			return starlark.None, err
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return starlark.None, fmt.Errorf("invalid pattern %s : %s", pattern, err)
+		}
+		matchBaseName := !strings.ContainsRune(pattern, '/')
+		result := []interface{}{}
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				logrus.Debugf("Skipping path %s : %s", path, err)
+				return nil
+			}
+			if path == dir {
+				return nil
+			}
+			relPath, err := filepath.Rel(dir, path)
+			if err != nil {
+				return nil
+			}
+			// Patterns without a path separator are matched against the names of files at any depth
+			matched, _ := filepath.Match(pattern, filepath.ToSlash(relPath))
+			if !matched && matchBaseName {
+				matched, _ = filepath.Match(pattern, info.Name())
+			}
+			if matched {
+				result = append(result, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return starlark.None, err
+		}
+		return starutil.Marshal(result)
+	})
+}
+
+func (t *Starlark) getStarlarkParseK8sYamls() *starlark.Builtin {
+	return starlark.NewBuiltin(parseK8sYamlsFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var data string
+		if err := starlark.UnpackPositionalArgs(parseK8sYamlsFnName, args, kwargs, 1, &data); err != nil {
+			return starlark.None, err
+		}
+		docs, err := common.SplitYAML([]byte(data))
+		if err != nil {
+			return starlark.None, fmt.Errorf("unable to split the yaml documents : %s", err)
+		}
+		objs := []interface{}{}
+		for _, doc := range docs {
+			obj := map[string]interface{}{}
+			if err := yaml.Unmarshal(doc, &obj); err != nil {
+				return starlark.None, fmt.Errorf("unable to parse the yaml document : %s", err)
+			}
+			if len(obj) == 0 {
+				continue
+			}
+			objs = append(objs, obj)
+		}
+		return starutil.Marshal(objs)
+	})
+}
+
+func (t *Starlark) getStarlarkDumpK8sYamls() *starlark.Builtin {
+	return starlark.NewBuiltin(dumpK8sYamlsFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var objsValue starlark.Value
+		if err := starlark.UnpackPositionalArgs(dumpK8sYamlsFnName, args, kwargs, 1, &objsValue); err != nil {
+			return starlark.None, err
+		}
+		objsI, err := starutil.Unmarshal(objsValue)
+		if err != nil {
+			return starlark.None, err
+		}
+		objs, ok := objsI.([]interface{})
+		if !ok {
+			objs = []interface{}{objsI}
+		}
+		docs := []string{}
+		for _, obj := range objs {
+			doc, err := common.ObjectToYamlBytes(obj)
+			if err != nil {
+				return starlark.None, fmt.Errorf("unable to marshal %+v to yaml : %s", obj, err)
+			}
+			docs = append(docs, string(doc))
+		}
+		return starlark.String(strings.Join(docs, "---\n")), nil
+	})
+}
+
+func (t *Starlark) getStarlarkPatchK8sObject() *starlark.Builtin {
+	return starlark.NewBuiltin(patchK8sObjectFnName, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var objValue, patchValue starlark.Value
+		if err := starlark.UnpackPositionalArgs(patchK8sObjectFnName, args, kwargs, 2, &objValue, &patchValue); err != nil {
+			return starlark.None, err
+		}
+		obj, err := starutil.Unmarshal(objValue)
+		if err != nil {
+			return starlark.None, err
+		}
+		patch, err := starutil.Unmarshal(patchValue)
+		if err != nil {
+			return starlark.None, err
+		}
+		return starutil.Marshal(mergePatch(obj, patch))
+	})
+}
+
+// mergePatch applies a json merge patch (RFC 7386). Keys set to None in the patch are removed.
+func mergePatch(obj, patch interface{}) interface{} {
+	patchMap, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	objMap, ok := obj.(map[string]interface{})
+	if !ok {
+		objMap = map[string]interface{}{}
+	}
+	for k, v := range patchMap {
+		if v == nil {
+			delete(objMap, k)
+			continue
+		}
+		objMap[k] = mergePatch(objMap[k], v)
+	}
+	return objMap
+}
+
+func (t *Starlark) getAppModuleMembers() (starlark.StringDict, error) {
+	members := starlark.StringDict{
+		qaFnName:             t.getStarlarkQuery(),
+		logDebugFnName:       t.getStarlarkLog(logDebugFnName, logrus.Debugf),
+		logInfoFnName:        t.getStarlarkLog(logInfoFnName, logrus.Infof),
+		logWarnFnName:        t.getStarlarkLog(logWarnFnName, logrus.Warnf),
+		logErrorFnName:       t.getStarlarkLog(logErrorFnName, logrus.Errorf),
+		renderTemplateFnName: t.getStarlarkRenderTemplate(),
+		artifactFnName: t.getStarlarkTypedConstructor(artifactFnName, func() interface{} {
+			return &transformertypes.Artifact{}
+		}),
+		pathMappingFnName: t.getStarlarkTypedConstructor(pathMappingFnName, func() interface{} {
+			return &transformertypes.PathMapping{}
+		}),
+		transformerPlanFnName: t.getStarlarkTypedConstructor(transformerPlanFnName, func() interface{} {
+			return &transformertypes.TransformerPlan{}
+		}),
+		globFnName:           t.getStarlarkGlob(),
+		parseK8sYamlsFnName:  t.getStarlarkParseK8sYamls(),
+		dumpK8sYamlsFnName:   t.getStarlarkDumpK8sYamls(),
+		patchK8sObjectFnName: t.getStarlarkPatchK8sObject(),
+	}
+	if t.Env == nil {
+		return members, nil
+	}
+	var err error
+	members[projectNameMemberName], err = starutil.Marshal(t.Env.GetProjectName())
+	if err != nil {
+		return members, err
+	}
+	targetCluster, err := common.GetMapInterfaceFromObj(t.Env.TargetCluster)
+	if err != nil {
+		return members, err
+	}
+	members[targetClusterMemberName], err = starutil.Marshal(targetCluster)
+	return members, err
+}
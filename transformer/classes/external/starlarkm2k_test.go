@@ -0,0 +1,241 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package external
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/types"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	starutil "github.com/qri-io/starlib/util"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// execStarlarkM2K runs the script with the m2k module and returns the value of its result variable
+func execStarlarkM2K(t *testing.T, st *Starlark, script string) (interface{}, error) {
+	members, err := st.getAppModuleMembers()
+	if err != nil {
+		t.Fatalf("Unable to get the members of the %s module : %s", types.AppNameShort, err)
+	}
+	globals := starlark.StringDict{types.AppNameShort: &starlarkstruct.Module{Name: types.AppNameShort, Members: members}}
+	globals, err = starlark.ExecFile(&starlark.Thread{Name: "test"}, "test.star", script, globals)
+	if err != nil {
+		return nil, err
+	}
+	return starutil.Unmarshal(globals["result"])
+}
+
+func TestStarlarkM2KBuiltins(t *testing.T) {
+	st := &Starlark{Config: transformertypes.Transformer{}}
+	st.Config.Name = "test"
+	testcases := []struct {
+		name    string
+		script  string
+		want    interface{}
+		wantErr string
+	}{
+		{
+			name:   "render_template renders the data",
+			script: `result = m2k.render_template("Hello {{ .name }}", {"name": "orders"})`,
+			want:   "Hello orders",
+		},
+		{
+			name:   "render_template without data",
+			script: `result = m2k.render_template(template="static")`,
+			want:   "static",
+		},
+		{
+			name:    "render_template fails on missing keys",
+			script:  `result = m2k.render_template("{{ .missing }}", {})`,
+			wantErr: "unable to render template",
+		},
+		{
+			name:    "render_template fails on invalid templates",
+			script:  `result = m2k.render_template("{{ .name ", {})`,
+			wantErr: "unable to parse template",
+		},
+		{
+			name:   "artifact builds the artifact dict",
+			script: `result = m2k.artifact(name="orders", artifact="Service", paths={"ProjectPath": ["/src/orders"]})`,
+			want: map[string]interface{}{
+				"name":     "orders",
+				"artifact": "Service",
+				"paths":    map[string]interface{}{"ProjectPath": []interface{}{"/src/orders"}},
+			},
+		},
+		{
+			name:    "artifact rejects unknown fields",
+			script:  `result = m2k.artifact(name="orders", unknown="value")`,
+			wantErr: "invalid arguments to artifact",
+		},
+		{
+			name:    "artifact rejects positional arguments",
+			script:  `result = m2k.artifact("orders")`,
+			wantErr: "accepts only keyword arguments",
+		},
+		{
+			name:   "path_mapping builds the path mapping dict",
+			script: `result = m2k.path_mapping(type="Template", sourcePath="tpl", destinationPath="out")`,
+			want: map[string]interface{}{
+				"type":            "Template",
+				"sourcePath":      "tpl",
+				"destinationPath": "out",
+				"templateConfig":  nil,
+			},
+		},
+		{
+			name:    "path_mapping rejects wrongly typed fields",
+			script:  `result = m2k.path_mapping(sourcePath=["tpl"])`,
+			wantErr: "invalid arguments to path_mapping",
+		},
+		{
+			name:   "transformer_plan builds the plan dict",
+			script: `result = m2k.transformer_plan(mode="Container", transformerName="test", generates=["Service"])`,
+			want: map[string]interface{}{
+				"mode":            "Container",
+				"transformerName": "test",
+				"generates":       []interface{}{"Service"},
+			},
+		},
+		{
+			name:   "parse_k8s_yamls parses all the documents and skips empty ones",
+			script: "result = m2k.parse_k8s_yamls(\"kind: Service\\n---\\n---\\nkind: Deployment\\n\")",
+			want: []interface{}{
+				map[string]interface{}{"kind": "Service"},
+				map[string]interface{}{"kind": "Deployment"},
+			},
+		},
+		{
+			name:    "parse_k8s_yamls fails on invalid yaml",
+			script:  `result = m2k.parse_k8s_yamls("kind: [")`,
+			wantErr: "yaml",
+		},
+		{
+			name:   "dump_k8s_yamls joins the documents",
+			script: `result = m2k.dump_k8s_yamls([{"kind": "Service"}, {"kind": "Deployment"}])`,
+			want:   "kind: Service\n---\nkind: Deployment\n",
+		},
+		{
+			name:   "dump_k8s_yamls accepts a single object",
+			script: `result = m2k.dump_k8s_yamls({"kind": "Service"})`,
+			want:   "kind: Service\n",
+		},
+		{
+			name:   "dump_k8s_yamls output can be parsed back",
+			script: `result = m2k.parse_k8s_yamls(m2k.dump_k8s_yamls([{"kind": "Service", "metadata": {"name": "orders"}}]))`,
+			want: []interface{}{
+				map[string]interface{}{"kind": "Service", "metadata": map[string]interface{}{"name": "orders"}},
+			},
+		},
+		{
+			name: "patch_k8s_object merges nested maps",
+			script: `result = m2k.patch_k8s_object(
+    {"metadata": {"name": "orders", "labels": {"app": "orders"}}, "spec": {"replicas": 1}},
+    {"metadata": {"labels": {"tier": "backend"}}, "spec": {"replicas": 2}})`,
+			want: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "orders", "labels": map[string]interface{}{"app": "orders", "tier": "backend"}},
+				"spec":     map[string]interface{}{"replicas": 2},
+			},
+		},
+		{
+			name:   "patch_k8s_object removes keys set to None and replaces lists",
+			script: `result = m2k.patch_k8s_object({"a": 1, "b": [1, 2], "c": {"d": 1}}, {"a": None, "b": [3], "c": None})`,
+			want:   map[string]interface{}{"b": []interface{}{3}},
+		},
+		{
+			name:    "patch_k8s_object needs two arguments",
+			script:  `result = m2k.patch_k8s_object({})`,
+			wantErr: "patch_k8s_object",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := execStarlarkM2K(t, st, tc.script)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("Expected an error containing %q. Actual: %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unable to run the script : %s", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Expected: %#v\nActual: %#v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestStarlarkM2KGlob(t *testing.T) {
+	st := &Starlark{}
+	dir := t.TempDir()
+	for _, path := range []string{"Dockerfile", "app/Dockerfile", "app/main.go", "app/nested/Dockerfile", "docs/readme.md"} {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Unable to create the test directory : %s", err)
+		}
+		if err := ioutil.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatalf("Unable to create the test file : %s", err)
+		}
+	}
+	testcases := []struct {
+		name    string
+		pattern string
+		want    []string
+		wantErr bool
+	}{
+		{name: "patterns without a separator match at any depth", pattern: "Dockerfile", want: []string{"Dockerfile", "app/Dockerfile", "app/nested/Dockerfile"}},
+		{name: "wildcards match the file names", pattern: "*.go", want: []string{"app/main.go"}},
+		{name: "patterns with a separator match the relative path", pattern: "app/*", want: []string{"app/Dockerfile", "app/main.go", "app/nested"}},
+		{name: "no matches", pattern: "*.java", want: []string{}},
+		{name: "invalid patterns are rejected", pattern: "[", wantErr: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := execStarlarkM2K(t, st, `result = m2k.glob(`+starlark.String(dir).String()+`, `+starlark.String(tc.pattern).String()+`)`)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unable to run the script : %s", err)
+			}
+			paths := []string{}
+			for _, p := range got.([]interface{}) {
+				relPath, err := filepath.Rel(dir, p.(string))
+				if err != nil {
+					t.Fatalf("Unable to make %s relative : %s", p, err)
+				}
+				paths = append(paths, filepath.ToSlash(relPath))
+			}
+			sort.Strings(paths)
+			if !reflect.DeepEqual(paths, tc.want) {
+				t.Fatalf("Expected: %v\nActual: %v", tc.want, paths)
+			}
+		})
+	}
+}
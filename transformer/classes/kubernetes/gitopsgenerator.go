@@ -0,0 +1,487 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package kubernetes
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/environment"
+	"github.com/konveyor/move2kube/qaengine"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	gitOpsArgoCD             = "ArgoCD"
+	gitOpsFlux               = "Flux"
+	gitOpsPackagingHelm      = "Helm"
+	gitOpsPackagingKustomize = "Kustomize"
+	gitOpsRepoURLPlaceholder = "<TODO: insert the url of the git repo>"
+	gitOpsDefaultBranch      = "main"
+	gitOpsSyncInterval       = "5m"
+
+	argoCDAPIVersion          = "argoproj.io/v1alpha1"
+	argoCDApplicationKind     = "Application"
+	argoCDApplicationSetKind  = "ApplicationSet"
+	argoCDNamespace           = "argocd"
+	argoCDProject             = "default"
+	argoCDInClusterServer     = "https://kubernetes.default.svc"
+	argoCDEnvPlaceholder      = "{{env}}"
+	fluxNamespace             = "flux-system"
+	fluxSourceAPIVersion      = "source.toolkit.fluxcd.io/v1beta2"
+	fluxKustomizeAPIVersion   = "kustomize.toolkit.fluxcd.io/v1beta2"
+	fluxHelmAPIVersion        = "helm.toolkit.fluxcd.io/v2beta1"
+	fluxGitRepositoryKind     = "GitRepository"
+	fluxKustomizationKind     = "Kustomization"
+	fluxHelmReleaseKind       = "HelmRelease"
+	fluxGitRepositoryFileName = "gitrepository.yaml"
+)
+
+// GitOps implements Transformer interface
+type GitOps struct {
+	Config transformertypes.Transformer
+	Env    *environment.Environment
+}
+
+// gitOpsRepoT is the git repo the GitOps tools sync from
+type gitOpsRepoT struct {
+	Name   string
+	URL    string
+	Branch string
+}
+
+// gitOpsAppT is a set of k8s yamls deployed by the GitOps tools.
+// The parameterized helm chart or kustomize overlays are deployed for each env, when they exist.
+type gitOpsAppT struct {
+	Name              string
+	Namespace         string
+	YamlsPath         string
+	HelmChartPath     string
+	KustomizeOverlays string
+	Envs              []string
+}
+
+type gitOpsMetadataT struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+type argoCDApplicationT struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Metadata   gitOpsMetadataT        `yaml:"metadata"`
+	Spec       argoCDApplicationSpecT `yaml:"spec"`
+}
+
+type argoCDApplicationSpecT struct {
+	Project     string             `yaml:"project"`
+	Source      argoCDSourceT      `yaml:"source"`
+	Destination argoCDDestinationT `yaml:"destination"`
+	SyncPolicy  argoCDSyncPolicyT  `yaml:"syncPolicy"`
+}
+
+type argoCDSourceT struct {
+	RepoURL        string       `yaml:"repoURL"`
+	TargetRevision string       `yaml:"targetRevision"`
+	Path           string       `yaml:"path"`
+	Helm           *argoCDHelmT `yaml:"helm,omitempty"`
+}
+
+type argoCDHelmT struct {
+	ValueFiles []string `yaml:"valueFiles"`
+}
+
+type argoCDDestinationT struct {
+	Server    string `yaml:"server"`
+	Namespace string `yaml:"namespace"`
+}
+
+type argoCDSyncPolicyT struct {
+	Automated   argoCDAutomatedT `yaml:"automated"`
+	SyncOptions []string         `yaml:"syncOptions,omitempty"`
+}
+
+type argoCDAutomatedT struct {
+	Prune    bool `yaml:"prune"`
+	SelfHeal bool `yaml:"selfHeal"`
+}
+
+type argoCDApplicationSetT struct {
+	APIVersion string                    `yaml:"apiVersion"`
+	Kind       string                    `yaml:"kind"`
+	Metadata   gitOpsMetadataT           `yaml:"metadata"`
+	Spec       argoCDApplicationSetSpecT `yaml:"spec"`
+}
+
+type argoCDApplicationSetSpecT struct {
+	Generators []argoCDGeneratorT         `yaml:"generators"`
+	Template   argoCDApplicationTemplateT `yaml:"template"`
+}
+
+type argoCDGeneratorT struct {
+	List argoCDListGeneratorT `yaml:"list"`
+}
+
+type argoCDListGeneratorT struct {
+	Elements []map[string]string `yaml:"elements"`
+}
+
+type argoCDApplicationTemplateT struct {
+	Metadata gitOpsMetadataT        `yaml:"metadata"`
+	Spec     argoCDApplicationSpecT `yaml:"spec"`
+}
+
+type fluxGitRepositoryT struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Metadata   gitOpsMetadataT        `yaml:"metadata"`
+	Spec       fluxGitRepositorySpecT `yaml:"spec"`
+}
+
+type fluxGitRepositorySpecT struct {
+	Interval string      `yaml:"interval"`
+	URL      string      `yaml:"url"`
+	Ref      fluxGitRefT `yaml:"ref"`
+}
+
+type fluxGitRefT struct {
+	Branch string `yaml:"branch"`
+}
+
+type fluxSourceRefT struct {
+	Kind      string `yaml:"kind"`
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+type fluxKustomizationT struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Metadata   gitOpsMetadataT        `yaml:"metadata"`
+	Spec       fluxKustomizationSpecT `yaml:"spec"`
+}
+
+type fluxKustomizationSpecT struct {
+	Interval        string         `yaml:"interval"`
+	SourceRef       fluxSourceRefT `yaml:"sourceRef"`
+	Path            string         `yaml:"path"`
+	Prune           bool           `yaml:"prune"`
+	TargetNamespace string         `yaml:"targetNamespace"`
+}
+
+type fluxHelmReleaseT struct {
+	APIVersion string               `yaml:"apiVersion"`
+	Kind       string               `yaml:"kind"`
+	Metadata   gitOpsMetadataT      `yaml:"metadata"`
+	Spec       fluxHelmReleaseSpecT `yaml:"spec"`
+}
+
+type fluxHelmReleaseSpecT struct {
+	Interval        string                  `yaml:"interval"`
+	TargetNamespace string                  `yaml:"targetNamespace"`
+	Install         fluxHelmReleaseInstallT `yaml:"install"`
+	Chart           fluxHelmChartTemplateT  `yaml:"chart"`
+}
+
+type fluxHelmReleaseInstallT struct {
+	CreateNamespace bool `yaml:"createNamespace"`
+}
+
+type fluxHelmChartTemplateT struct {
+	Spec fluxHelmChartSpecT `yaml:"spec"`
+}
+
+type fluxHelmChartSpecT struct {
+	Chart       string         `yaml:"chart"`
+	SourceRef   fluxSourceRefT `yaml:"sourceRef"`
+	ValuesFiles []string       `yaml:"valuesFiles"`
+}
+
+// Init Initializes the transformer
+func (t *GitOps) Init(tc transformertypes.Transformer, env *environment.Environment) error {
+	t.Config = tc
+	t.Env = env
+	return nil
+}
+
+// GetConfig returns the configuration
+func (t *GitOps) GetConfig() (transformertypes.Transformer, *environment.Environment) {
+	return t.Config, t.Env
+}
+
+// BaseDirectoryDetect runs detect in base input directory
+func (t *GitOps) BaseDirectoryDetect(dir string) (namedServices map[string]transformertypes.ServicePlan, unnamedServices []transformertypes.TransformerPlan, err error) {
+	return nil, nil, nil
+}
+
+// DirectoryDetect runs detect in each subdirectory
+func (t *GitOps) DirectoryDetect(dir string) (namedServices map[string]transformertypes.ServicePlan, unnamedServices []transformertypes.TransformerPlan, err error) {
+	return nil, nil, nil
+}
+
+// Transform generates the Argo CD and Flux configurations which deploy the k8s yamls from the git repo.
+// The parameterized yamls are created later than the k8s yamls, so their configurations replace the configurations of the k8s yamls.
+func (t *GitOps) Transform(newArtifacts []transformertypes.Artifact, oldArtifacts []transformertypes.Artifact) (pathMappings []transformertypes.PathMapping, createdArtifacts []transformertypes.Artifact, err error) {
+	pathMappings = []transformertypes.PathMapping{}
+	apps := []gitOpsAppT{}
+	for _, a := range newArtifacts {
+		if a.Artifact != artifacts.KubernetesYamlsArtifactType && a.Artifact != artifacts.ParameterizedYamlsArtifactType {
+			continue
+		}
+		if len(a.Paths[artifacts.KubernetesYamlsPathType]) == 0 {
+			continue
+		}
+		if app, ok := t.getGitOpsApp(a); ok {
+			apps = append(apps, app)
+		}
+	}
+	if len(apps) == 0 {
+		return pathMappings, nil, nil
+	}
+	hints := []string{"The applications are synced from the git repo the output is pushed to"}
+	tools := qaengine.FetchMultiSelectAnswer(common.ConfigGitOpsToolsKey, "Select the GitOps tools to deploy the applications using:", hints, []string{gitOpsArgoCD}, []string{gitOpsArgoCD, gitOpsFlux})
+	if len(tools) == 0 {
+		return pathMappings, nil, nil
+	}
+	repo := t.getGitOpsRepo()
+	tempPath, err := ioutil.TempDir(t.Env.TempPath, "*")
+	if err != nil {
+		logrus.Errorf("Unable to create temp dir : %s", err)
+		return pathMappings, nil, err
+	}
+	files := map[string][]interface{}{}
+	for _, tool := range tools {
+		switch tool {
+		case gitOpsArgoCD:
+			for _, app := range apps {
+				files[filepath.Join(common.DeployDir, common.GitOpsDir, "argocd", app.Name+".yaml")] = []interface{}{getArgoCDApplication(app, repo)}
+			}
+		case gitOpsFlux:
+			files[filepath.Join(common.DeployDir, common.GitOpsDir, "flux", fluxGitRepositoryFileName)] = []interface{}{getFluxGitRepository(repo)}
+			for _, app := range apps {
+				files[filepath.Join(common.DeployDir, common.GitOpsDir, "flux", app.Name+".yaml")] = getFluxResources(app, repo)
+			}
+		}
+	}
+	for destPath, documents := range files {
+		srcPath := filepath.Join(tempPath, destPath)
+		if err := writeYamlDocuments(srcPath, documents); err != nil {
+			logrus.Errorf("Unable to write the GitOps configuration to the file at path %s : %s", srcPath, err)
+			continue
+		}
+		pathMappings = append(pathMappings, transformertypes.PathMapping{
+			Type:     transformertypes.DefaultPathMappingType,
+			SrcPath:  srcPath,
+			DestPath: destPath,
+		})
+	}
+	return pathMappings, nil, nil
+}
+
+// getGitOpsApp returns the app deploying the k8s yamls or the parameterized yamls of the artifact
+func (t *GitOps) getGitOpsApp(a transformertypes.Artifact) (gitOpsAppT, bool) {
+	yamlsPath := t.getRepoPath(a.Paths[artifacts.KubernetesYamlsPathType][0])
+	name := t.Env.GetProjectName()
+	if base := filepath.Base(yamlsPath); base != "yamls" {
+		name += "-" + base
+	}
+	app := gitOpsAppT{
+		Name:      common.MakeStringDNSNameCompliant(name),
+		Namespace: qaengine.FetchStringAnswer(common.ConfigGitOpsNamespaceKey, "Provide the namespace the applications should be deployed to:", []string{"The parameterized applications are deployed to a namespace for each env, suffixed with the env"}, t.Env.GetProjectName()),
+		YamlsPath: yamlsPath,
+	}
+	if a.Artifact != artifacts.ParameterizedYamlsArtifactType {
+		return app, true
+	}
+	parameterizedYamls := artifacts.ParameterizedYamls{}
+	if err := a.GetConfig(artifacts.ParameterizedYamlsConfigType, &parameterizedYamls); err != nil {
+		logrus.Errorf("unable to load config for Transformer into %T : %s", parameterizedYamls, err)
+		return app, false
+	}
+	// The configs of the merged artifacts are appended
+	app.Envs = common.UniqueStrings(parameterizedYamls.Envs)
+	options := []string{}
+	if paths := a.Paths[artifacts.ParameterizedKustomizeOverlaysPathType]; len(paths) > 0 {
+		options = append(options, gitOpsPackagingKustomize)
+	}
+	if paths := a.Paths[artifacts.ParameterizedHelmChartPathType]; len(paths) > 0 {
+		options = append(options, gitOpsPackagingHelm)
+	}
+	if len(options) == 0 {
+		return app, false
+	}
+	packaging := options[0]
+	if len(options) > 1 {
+		packaging = qaengine.FetchSelectAnswer(common.ConfigGitOpsPackagingKey, "Select the parameterized packaging the GitOps tools should deploy for each env:", nil, options[0], options)
+	}
+	if packaging == gitOpsPackagingHelm {
+		app.HelmChartPath = t.getRepoPath(a.Paths[artifacts.ParameterizedHelmChartPathType][0])
+	} else {
+		app.KustomizeOverlays = t.getRepoPath(a.Paths[artifacts.ParameterizedKustomizeOverlaysPathType][0])
+	}
+	return app, true
+}
+
+// getGitOpsRepo returns the git repo the GitOps tools sync from. The repo of the source is the default.
+func (t *GitOps) getGitOpsRepo() gitOpsRepoT {
+	repo := gitOpsRepoT{Name: common.MakeStringDNSNameCompliant(t.Env.GetProjectName()), URL: gitOpsRepoURLPlaceholder, Branch: gitOpsDefaultBranch}
+	if _, _, _, repoURL, repoBranch, err := common.GatherGitInfo(t.Env.GetEnvironmentSource()); err != nil {
+		logrus.Debugf("Unable to get the git repo of the source : %s", err)
+	} else {
+		if repoURL != "" {
+			repo.URL = repoURL
+		}
+		if repoBranch != "" {
+			repo.Branch = repoBranch
+		}
+	}
+	repo.URL = qaengine.FetchStringAnswer(common.ConfigGitOpsRepoURLKey, "Provide the url of the git repo the output will be pushed to:", []string{"The GitOps tools sync the applications from this repo", "The output directory is expected to be at the root of the repo"}, repo.URL)
+	repo.Branch = qaengine.FetchStringAnswer(common.ConfigGitOpsRepoBranchKey, "Provide the branch of the git repo the output will be pushed to:", nil, repo.Branch)
+	if repo.URL == gitOpsRepoURLPlaceholder {
+		logrus.Warnf("The url of the git repo in the GitOps configurations is a placeholder. Replace it before applying them.")
+	}
+	return repo
+}
+
+// getRepoPath returns the path in the git repo, assuming the output directory is at the root of the repo
+func (t *GitOps) getRepoPath(path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(t.Env.GetEnvironmentOutput(), path); err == nil {
+			path = rel
+		} else {
+			logrus.Errorf("Unable to make the path %s relative to the output directory : %s", path, err)
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// getArgoCDApplication returns an Application for the k8s yamls or an ApplicationSet creating an Application for each env
+func getArgoCDApplication(app gitOpsAppT, repo gitOpsRepoT) interface{} {
+	spec := argoCDApplicationSpecT{
+		Project:     argoCDProject,
+		Source:      argoCDSourceT{RepoURL: repo.URL, TargetRevision: repo.Branch, Path: app.YamlsPath},
+		Destination: argoCDDestinationT{Server: argoCDInClusterServer, Namespace: app.Namespace},
+		SyncPolicy:  argoCDSyncPolicyT{Automated: argoCDAutomatedT{Prune: true, SelfHeal: true}, SyncOptions: []string{"CreateNamespace=true"}},
+	}
+	if app.HelmChartPath == "" && app.KustomizeOverlays == "" {
+		return argoCDApplicationT{
+			APIVersion: argoCDAPIVersion,
+			Kind:       argoCDApplicationKind,
+			Metadata:   gitOpsMetadataT{Name: app.Name, Namespace: argoCDNamespace},
+			Spec:       spec,
+		}
+	}
+	spec.Destination.Namespace = app.Namespace + "-" + argoCDEnvPlaceholder
+	if app.HelmChartPath != "" {
+		spec.Source.Path = app.HelmChartPath
+		spec.Source.Helm = &argoCDHelmT{ValueFiles: []string{"values-" + argoCDEnvPlaceholder + ".yaml"}}
+	} else {
+		spec.Source.Path = app.KustomizeOverlays + "/" + argoCDEnvPlaceholder
+	}
+	elements := []map[string]string{}
+	for _, env := range app.Envs {
+		elements = append(elements, map[string]string{"env": env})
+	}
+	return argoCDApplicationSetT{
+		APIVersion: argoCDAPIVersion,
+		Kind:       argoCDApplicationSetKind,
+		Metadata:   gitOpsMetadataT{Name: app.Name, Namespace: argoCDNamespace},
+		Spec: argoCDApplicationSetSpecT{
+			Generators: []argoCDGeneratorT{{List: argoCDListGeneratorT{Elements: elements}}},
+			Template: argoCDApplicationTemplateT{
+				Metadata: gitOpsMetadataT{Name: app.Name + "-" + argoCDEnvPlaceholder},
+				Spec:     spec,
+			},
+		},
+	}
+}
+
+func getFluxGitRepository(repo gitOpsRepoT) fluxGitRepositoryT {
+	return fluxGitRepositoryT{
+		APIVersion: fluxSourceAPIVersion,
+		Kind:       fluxGitRepositoryKind,
+		Metadata:   gitOpsMetadataT{Name: repo.Name, Namespace: fluxNamespace},
+		Spec:       fluxGitRepositorySpecT{Interval: gitOpsSyncInterval, URL: repo.URL, Ref: fluxGitRefT{Branch: repo.Branch}},
+	}
+}
+
+// getFluxResources returns a Kustomization for the k8s yamls, or a Kustomization or HelmRelease for each env
+func getFluxResources(app gitOpsAppT, repo gitOpsRepoT) []interface{} {
+	sourceRef := fluxSourceRefT{Kind: fluxGitRepositoryKind, Name: repo.Name}
+	getKustomization := func(name, path, namespace string) fluxKustomizationT {
+		return fluxKustomizationT{
+			APIVersion: fluxKustomizeAPIVersion,
+			Kind:       fluxKustomizationKind,
+			Metadata:   gitOpsMetadataT{Name: name, Namespace: fluxNamespace},
+			Spec:       fluxKustomizationSpecT{Interval: gitOpsSyncInterval, SourceRef: sourceRef, Path: "./" + path, Prune: true, TargetNamespace: namespace},
+		}
+	}
+	if app.HelmChartPath == "" && app.KustomizeOverlays == "" {
+		return []interface{}{getKustomization(app.Name, app.YamlsPath, app.Namespace)}
+	}
+	resources := []interface{}{}
+	for _, env := range app.Envs {
+		name := app.Name + "-" + env
+		namespace := app.Namespace + "-" + env
+		if app.KustomizeOverlays != "" {
+			resources = append(resources, getKustomization(name, app.KustomizeOverlays+"/"+env, namespace))
+			continue
+		}
+		resources = append(resources, fluxHelmReleaseT{
+			APIVersion: fluxHelmAPIVersion,
+			Kind:       fluxHelmReleaseKind,
+			Metadata:   gitOpsMetadataT{Name: name, Namespace: fluxNamespace},
+			Spec: fluxHelmReleaseSpecT{
+				Interval:        gitOpsSyncInterval,
+				TargetNamespace: namespace,
+				Install:         fluxHelmReleaseInstallT{CreateNamespace: true},
+				Chart: fluxHelmChartTemplateT{Spec: fluxHelmChartSpecT{
+					Chart:       "./" + app.HelmChartPath,
+					SourceRef:   sourceRef,
+					ValuesFiles: []string{app.HelmChartPath + "/values-" + env + ".yaml"},
+				}},
+			},
+		})
+	}
+	return resources
+}
+
+// writeYamlDocuments writes the documents as a multi document yaml
+func writeYamlDocuments(path string, documents []interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), common.DefaultDirectoryPermission); err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	for _, document := range documents {
+		if err := encoder.Encode(document); err != nil {
+			return fmt.Errorf("failed to encode the document %+v : %s", document, err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b.Bytes(), common.DefaultFilePermission)
+}
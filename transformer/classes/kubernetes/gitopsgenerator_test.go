@@ -0,0 +1,94 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package kubernetes
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+)
+
+func TestGitOps(t *testing.T) {
+	repo := gitOpsRepoT{Name: "shop", URL: "https://github.com/org/shop.git", Branch: "main"}
+	yamlsApp := gitOpsAppT{Name: "shop", Namespace: "shop", YamlsPath: "deploy/yamls"}
+
+	t.Run("parameterized paths and envs are added to the artifact", func(t *testing.T) {
+		pa := transformertypes.Artifact{Paths: map[transformertypes.PathType][]string{}}
+		parameterizedYamls := artifacts.ParameterizedYamls{}
+		for _, rel := range []string{"helm-chart/shop/Chart.yaml", "helm-chart/shop/values-dev.yaml", "helm-chart/shop/templates/orders-deployment.yaml", "kustomize/overlays/dev/kustomization.yaml", "kustomize/overlays/prod/kustomization.yaml"} {
+			addParameterizedPath(&pa, &parameterizedYamls, rel, filepath.Join("deploy", "yamls-parameterized", rel))
+		}
+		if pa.Paths[artifacts.ParameterizedHelmChartPathType][0] != filepath.Join("deploy", "yamls-parameterized", "helm-chart", "shop") || pa.Paths[artifacts.ParameterizedKustomizeOverlaysPathType][0] != filepath.Join("deploy", "yamls-parameterized", "kustomize", "overlays") {
+			t.Fatalf("Expected the paths of the helm chart and the kustomize overlays. Actual: %+v", pa.Paths)
+		}
+		if len(parameterizedYamls.Envs) != 2 || parameterizedYamls.Envs[0] != "dev" || parameterizedYamls.Envs[1] != "prod" {
+			t.Fatalf("Expected the envs dev and prod. Actual: %+v", parameterizedYamls.Envs)
+		}
+	})
+
+	t.Run("argo cd application for the k8s yamls", func(t *testing.T) {
+		application, ok := getArgoCDApplication(yamlsApp, repo).(argoCDApplicationT)
+		if !ok || application.Spec.Source.Path != "deploy/yamls" || application.Spec.Source.RepoURL != repo.URL || application.Spec.Destination.Namespace != "shop" {
+			t.Fatalf("Expected an application syncing the k8s yamls. Actual: %+v", application)
+		}
+	})
+
+	t.Run("argo cd application set for the envs of the helm chart", func(t *testing.T) {
+		app := yamlsApp
+		app.HelmChartPath, app.Envs = "deploy/yamls-parameterized/helm-chart/shop", []string{"dev", "prod"}
+		applicationSet, ok := getArgoCDApplication(app, repo).(argoCDApplicationSetT)
+		if !ok || len(applicationSet.Spec.Generators[0].List.Elements) != 2 {
+			t.Fatalf("Expected an application set with an element for each env. Actual: %+v", applicationSet)
+		}
+		spec := applicationSet.Spec.Template.Spec
+		if spec.Source.Path != app.HelmChartPath || spec.Source.Helm.ValueFiles[0] != "values-{{env}}.yaml" || spec.Destination.Namespace != "shop-{{env}}" {
+			t.Fatalf("Expected the values of each env to be used. Actual: %+v", spec)
+		}
+	})
+
+	t.Run("flux kustomizations for the envs of the kustomize overlays", func(t *testing.T) {
+		app := yamlsApp
+		app.KustomizeOverlays, app.Envs = "deploy/yamls-parameterized/kustomize/overlays", []string{"dev", "prod"}
+		path := filepath.Join(t.TempDir(), "shop.yaml")
+		if err := writeYamlDocuments(path, append([]interface{}{getFluxGitRepository(repo)}, getFluxResources(app, repo)...)); err != nil {
+			t.Fatalf("Failed to write the flux resources. Error: %q", err)
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read the flux resources. Error: %q", err)
+		}
+		for _, expected := range []string{"kind: GitRepository", "name: shop-prod", "path: ./deploy/yamls-parameterized/kustomize/overlays/prod", "targetNamespace: shop-dev", "---\n"} {
+			if !strings.Contains(string(data), expected) {
+				t.Fatalf("Expected the flux resources to contain %s. Actual: %s", expected, data)
+			}
+		}
+	})
+
+	t.Run("flux helm releases for the envs of the helm chart", func(t *testing.T) {
+		app := yamlsApp
+		app.HelmChartPath, app.Envs = "deploy/yamls-parameterized/helm-chart/shop", []string{"dev"}
+		resources := getFluxResources(app, repo)
+		helmRelease, ok := resources[0].(fluxHelmReleaseT)
+		if len(resources) != 1 || !ok || helmRelease.Spec.Chart.Spec.ValuesFiles[0] != "deploy/yamls-parameterized/helm-chart/shop/values-dev.yaml" {
+			t.Fatalf("Expected a helm release using the values of the env. Actual: %+v", resources)
+		}
+	})
+}
@@ -0,0 +1,167 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package kubernetes
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/environment"
+	"github.com/konveyor/move2kube/parameterizer"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/konveyor/move2kube/types/transformer/artifacts"
+	"github.com/sirupsen/logrus"
+)
+
+// HelmKustomizeAnalyser implements Transformer interface
+type HelmKustomizeAnalyser struct {
+	Config transformertypes.Transformer
+	Env    *environment.Environment
+}
+
+// Init Initializes the transformer
+func (t *HelmKustomizeAnalyser) Init(tc transformertypes.Transformer, env *environment.Environment) error {
+	t.Config = tc
+	t.Env = env
+	return nil
+}
+
+// GetConfig returns the transformer config
+func (t *HelmKustomizeAnalyser) GetConfig() (transformertypes.Transformer, *environment.Environment) {
+	return t.Config, t.Env
+}
+
+// BaseDirectoryDetect detects the existing helm charts and the bases of the existing kustomizations
+func (t *HelmKustomizeAnalyser) BaseDirectoryDetect(dir string) (namedServices map[string]transformertypes.ServicePlan, unnamedServices []transformertypes.TransformerPlan, err error) {
+	services := map[string]transformertypes.ServicePlan{}
+	kustomizations := map[string]map[string]interface{}{}
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			logrus.Debugf("Skipping path %s : %s", path, err)
+			return nil
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != dir {
+			for _, dirRegExp := range common.DefaultIgnoreDirRegexps {
+				if dirRegExp.MatchString(filepath.Base(path)) {
+					return filepath.SkipDir
+				}
+			}
+		}
+		chart := map[string]interface{}{}
+		if err := common.ReadYaml(filepath.Join(path, "Chart.yaml"), &chart); err == nil {
+			if name, ok := chart["name"].(string); ok && name != "" {
+				logrus.Debugf("Found the helm chart %s at path %s", name, path)
+				sn := common.NormalizeForServiceName(name)
+				services[sn] = append(services[sn], t.getTransformerPlan(artifacts.HelmChartArtifactType, artifacts.HelmChartPathType, path))
+				// The subcharts are a part of the chart
+				return filepath.SkipDir
+			}
+		}
+		for _, name := range parameterizer.KustomizationFileNames {
+			kustomization := map[string]interface{}{}
+			if err := common.ReadYaml(filepath.Join(path, name), &kustomization); err == nil {
+				kustomizations[path] = kustomization
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Errorf("Failed to walk the directory %s : %s", dir, err)
+		return nil, nil, err
+	}
+	kustDirs := []string{}
+	for kustDir := range kustomizations {
+		kustDirs = append(kustDirs, kustDir)
+	}
+	sort.Strings(kustDirs)
+	for _, kustDir := range kustDirs {
+		// The overlays and the components change the bases, so the new resources are added to the bases
+		if kind, _ := kustomizations[kustDir]["kind"].(string); kind == "Component" || isKustomizationOverlay(kustDir, kustomizations) {
+			continue
+		}
+		name := filepath.Base(kustDir)
+		if name == "base" {
+			name = filepath.Base(filepath.Dir(kustDir))
+		}
+		logrus.Debugf("Found the kustomization %s at path %s", name, kustDir)
+		sn := common.NormalizeForServiceName(name)
+		services[sn] = append(services[sn], t.getTransformerPlan(artifacts.KustomizeArtifactType, artifacts.KustomizePathType, kustDir))
+	}
+	return services, nil, nil
+}
+
+// DirectoryDetect runs detect in each sub directory
+func (t *HelmKustomizeAnalyser) DirectoryDetect(dir string) (namedServices map[string]transformertypes.ServicePlan, unnamedServices []transformertypes.TransformerPlan, err error) {
+	return nil, nil, nil
+}
+
+// Transform creates the artifacts of the existing helm charts and kustomizations, so that the new resources are merged into them
+func (t *HelmKustomizeAnalyser) Transform(newArtifacts []transformertypes.Artifact, oldArtifacts []transformertypes.Artifact) ([]transformertypes.PathMapping, []transformertypes.Artifact, error) {
+	artifactsCreated := []transformertypes.Artifact{}
+	for _, a := range newArtifacts {
+		if a.Artifact != artifacts.ServiceArtifactType {
+			continue
+		}
+		if paths := a.Paths[artifacts.HelmChartPathType]; len(paths) != 0 {
+			artifactsCreated = append(artifactsCreated, transformertypes.Artifact{
+				Name:     a.Name,
+				Artifact: artifacts.HelmChartArtifactType,
+				Paths:    map[transformertypes.PathType][]string{artifacts.HelmChartPathType: paths},
+			})
+		}
+		if paths := a.Paths[artifacts.KustomizePathType]; len(paths) != 0 {
+			artifactsCreated = append(artifactsCreated, transformertypes.Artifact{
+				Name:     a.Name,
+				Artifact: artifacts.KustomizeArtifactType,
+				Paths:    map[transformertypes.PathType][]string{artifacts.KustomizePathType: paths},
+			})
+		}
+	}
+	return nil, artifactsCreated, nil
+}
+
+func (t *HelmKustomizeAnalyser) getTransformerPlan(artifactType transformertypes.ArtifactType, pathType transformertypes.PathType, path string) transformertypes.TransformerPlan {
+	return transformertypes.TransformerPlan{
+		Mode:              t.Config.Spec.Mode,
+		ArtifactTypes:     []transformertypes.ArtifactType{artifactType},
+		BaseArtifactTypes: []transformertypes.ArtifactType{artifactType},
+		Paths:             map[transformertypes.PathType][]string{pathType: {path}},
+	}
+}
+
+// isKustomizationOverlay returns true if the kustomization uses other kustomizations as its resources, bases or components
+func isKustomizationOverlay(kustDir string, kustomizations map[string]map[string]interface{}) bool {
+	for _, field := range []string{"resources", "bases", "components"} {
+		refs, _ := kustomizations[kustDir][field].([]interface{})
+		for _, refI := range refs {
+			ref, ok := refI.(string)
+			if !ok {
+				continue
+			}
+			if _, ok := kustomizations[filepath.Clean(filepath.Join(kustDir, ref))]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
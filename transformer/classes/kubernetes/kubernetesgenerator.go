@@ -18,15 +18,19 @@ package kubernetes
 
 import (
 	"path/filepath"
+	"sort"
 
 	"github.com/konveyor/move2kube/apiresource"
 	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/environment"
 	"github.com/konveyor/move2kube/irpreprocessor"
+	"github.com/konveyor/move2kube/types"
 	irtypes "github.com/konveyor/move2kube/types/ir"
+	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
 	transformertypes "github.com/konveyor/move2kube/types/transformer"
 	"github.com/konveyor/move2kube/types/transformer/artifacts"
 	"github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
 )
 
 // Kubernetes implements Transformer interface
@@ -82,12 +86,18 @@ func (t *Kubernetes) Transform(newArtifacts []transformertypes.Artifact, oldArti
 		tempDest := filepath.Join(t.Env.TempPath, deployYamlsDir)
 		logrus.Debugf("Starting Kubernetes transform")
 		logrus.Debugf("Total services to be transformed : %d", len(ir.Services))
-		apis := []apiresource.IAPIResource{new(apiresource.Deployment), new(apiresource.Storage), new(apiresource.Service), new(apiresource.ImageStream), new(apiresource.NetworkPolicy)}
+		apis := []apiresource.IAPIResource{new(apiresource.Deployment), new(apiresource.Storage), new(apiresource.Service), new(apiresource.ImageStream), new(apiresource.NetworkPolicy), new(apiresource.ScalingPolicy)}
 		files, err := apiresource.TransformAndPersist(irtypes.NewEnhancedIRFromIR(ir), tempDest, apis, t.Env.TargetCluster)
 		if err != nil {
 			logrus.Errorf("Unable to transform and persist IR : %s", err)
 			return nil, nil, err
 		}
+		metadataPath := filepath.Join(tempDest, common.DefaultTransformMetadataFile)
+		if err := common.WriteYaml(metadataPath, getTransformMetadata(ir)); err != nil {
+			logrus.Errorf("Unable to write the metadata of the k8s resources : %s", err)
+		} else {
+			files = append(files, metadataPath)
+		}
 		for _, f := range files {
 			destPath, err := filepath.Rel(t.Env.TempPath, f)
 			if err != nil {
@@ -112,3 +122,53 @@ func (t *Kubernetes) Transform(newArtifacts []transformertypes.Artifact, oldArti
 	}
 	return pathMappings, createdArtifacts, nil
 }
+
+// getTransformMetadata returns the services and storages for which the k8s resources are created.
+// The parameterize command uses it to create the parameterizers for the transform output.
+func getTransformMetadata(ir irtypes.IR) parameterizertypes.TransformMetadataFileT {
+	metadata := parameterizertypes.TransformMetadataFileT{
+		TypeMeta:   types.TypeMeta{APIVersion: types.SchemeGroupVersion.String(), Kind: parameterizertypes.TransformMetadataKind},
+		ObjectMeta: types.ObjectMeta{Name: ir.Name},
+	}
+	serviceNames := []string{}
+	for serviceName := range ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		serviceMetadata := parameterizertypes.ServiceMetadataT{
+			Name:     service.Name,
+			Replicas: service.Replicas,
+			Scalable: !service.Daemon && service.Schedule == "" && service.RestartPolicy != core.RestartPolicyNever && service.RestartPolicy != core.RestartPolicyOnFailure,
+		}
+		for _, forwarding := range service.ServiceToPodPortForwardings {
+			if forwarding.ServiceRelPath != "" {
+				serviceMetadata.Exposed = true
+			}
+		}
+		for _, container := range service.Containers {
+			containerMetadata := parameterizertypes.ContainerMetadataT{Name: container.Name, Image: container.Image}
+			for _, port := range container.Ports {
+				containerMetadata.Ports = append(containerMetadata.Ports, port.ContainerPort)
+			}
+			for resourceName := range container.Resources.Requests {
+				containerMetadata.Resources = append(containerMetadata.Resources, "requests."+string(resourceName))
+			}
+			for resourceName := range container.Resources.Limits {
+				containerMetadata.Resources = append(containerMetadata.Resources, "limits."+string(resourceName))
+			}
+			sort.Strings(containerMetadata.Resources)
+			serviceMetadata.Containers = append(serviceMetadata.Containers, containerMetadata)
+		}
+		metadata.Spec.Services = append(metadata.Spec.Services, serviceMetadata)
+	}
+	for _, storage := range ir.Storages {
+		storageMetadata := parameterizertypes.StorageMetadataT{Name: storage.Name, Kind: string(storage.StorageType)}
+		if size, ok := storage.Resources.Requests[core.ResourceStorage]; ok {
+			storageMetadata.Size = size.String()
+		}
+		metadata.Spec.Storages = append(metadata.Spec.Storages, storageMetadata)
+	}
+	return metadata
+}
@@ -19,9 +19,12 @@ package kubernetes
 import (
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 
+	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/environment"
 	"github.com/konveyor/move2kube/parameterizer"
+	"github.com/konveyor/move2kube/qaengine"
 	parameterizertypes "github.com/konveyor/move2kube/types/parameterizer"
 	transformertypes "github.com/konveyor/move2kube/types/transformer"
 	"github.com/konveyor/move2kube/types/transformer/artifacts"
@@ -59,6 +62,7 @@ func (t *Parameterizer) DirectoryDetect(dir string) (namedServices map[string]tr
 // Transform transforms artifacts
 func (t *Parameterizer) Transform(newArtifacts []transformertypes.Artifact, oldArtifacts []transformertypes.Artifact) (pathMappings []transformertypes.PathMapping, createdArtifacts []transformertypes.Artifact, err error) {
 	pathMappings = []transformertypes.PathMapping{}
+	createdArtifacts = []transformertypes.Artifact{}
 	psmap, err := parameterizer.CollectParamsFromPath(t.Env.Context)
 	if err != nil {
 		logrus.Errorf("Error while parsing for params : %s", err)
@@ -68,6 +72,16 @@ func (t *Parameterizer) Transform(newArtifacts []transformertypes.Artifact, oldA
 	for _, p := range psmap {
 		ps = append(ps, p...)
 	}
+	packSpecPath := parameterizertypes.PackagingSpecPathT{}
+	if version, err := common.GetGitTagVersion(t.Env.GetEnvironmentSource()); err == nil {
+		packSpecPath.HelmChartVersion, packSpecPath.HelmAppVersion = version.String(), version.Original()
+	} else {
+		logrus.Debugf("Unable to get the version of the helm chart from the git tags of the source : %s", err)
+	}
+	// The new resources are merged into the existing helm chart and kustomization of the source
+	allArtifacts := append(newArtifacts, oldArtifacts...)
+	packSpecPath.ExistingHelmChart = t.getExistingPackagingPath(allArtifacts, artifacts.HelmChartArtifactType, artifacts.HelmChartPathType, common.ConfigHelmExistingChartKey, "helm chart")
+	packSpecPath.ExistingKustomize = t.getExistingPackagingPath(allArtifacts, artifacts.KustomizeArtifactType, artifacts.KustomizePathType, common.ConfigKustomizeExistingBaseKey, "kustomization")
 	for _, a := range newArtifacts {
 		yamlsPath := a.Paths[artifacts.KubernetesYamlsPathType][0]
 		tempPath, err := ioutil.TempDir(t.Env.TempPath, "*")
@@ -76,22 +90,109 @@ func (t *Parameterizer) Transform(newArtifacts []transformertypes.Artifact, oldA
 		}
 		baseDirName := filepath.Base(yamlsPath) + "-parameterized"
 		destPath := filepath.Join(tempPath, baseDirName)
-		filesWritten, err := parameterizer.Parameterize(yamlsPath, destPath, parameterizertypes.PackagingSpecPathT{}, ps)
+		filesWritten, err := parameterizer.Parameterize(yamlsPath, destPath, packSpecPath, ps)
 		if err != nil {
 			logrus.Errorf("Unable to parameterize : %s", err)
 		}
+		parameterizedYamls := artifacts.ParameterizedYamls{}
+		pa := transformertypes.Artifact{
+			Name:     a.Name,
+			Artifact: artifacts.ParameterizedYamlsArtifactType,
+			Paths:    map[transformertypes.PathType][]string{artifacts.KubernetesYamlsPathType: {yamlsPath}},
+		}
 		for _, f := range filesWritten {
 			rel, err := filepath.Rel(destPath, f)
 			if err != nil {
 				logrus.Errorf("Unable to make parameterized file path relative : %s", err)
 				continue
 			}
+			fileDestPath := t.getDestPath(rel, filepath.Join(filepath.Dir(yamlsPath), baseDirName), packSpecPath)
 			pathMappings = append(pathMappings, transformertypes.PathMapping{
 				Type:     transformertypes.DefaultPathMappingType,
 				SrcPath:  f,
-				DestPath: filepath.Join(filepath.Dir(yamlsPath), baseDirName, rel),
+				DestPath: fileDestPath,
 			})
+			addParameterizedPath(&pa, &parameterizedYamls, rel, fileDestPath)
 		}
+		if len(parameterizedYamls.Envs) == 0 {
+			continue
+		}
+		pa.Configs = map[transformertypes.ConfigType]interface{}{artifacts.ParameterizedYamlsConfigType: parameterizedYamls}
+		createdArtifacts = append(createdArtifacts, pa)
+	}
+	return pathMappings, createdArtifacts, nil
+}
+
+// addParameterizedPath adds the path of the helm chart or the kustomize overlays, and the env of the parameterized file to the artifact
+func addParameterizedPath(pa *transformertypes.Artifact, parameterizedYamls *artifacts.ParameterizedYamls, rel, destPath string) {
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	env := ""
+	switch {
+	case len(parts) == 3 && parts[0] == "helm-chart" && parts[2] == "Chart.yaml":
+		pa.Paths[artifacts.ParameterizedHelmChartPathType] = []string{filepath.Dir(destPath)}
+	case len(parts) == 3 && parts[0] == "helm-chart" && strings.HasPrefix(parts[2], "values-") && strings.HasSuffix(parts[2], ".yaml"):
+		env = strings.TrimSuffix(strings.TrimPrefix(parts[2], "values-"), ".yaml")
+	case len(parts) == 4 && parts[0] == "kustomize" && parts[1] == "overlays" && parts[3] == "kustomization.yaml":
+		pa.Paths[artifacts.ParameterizedKustomizeOverlaysPathType] = []string{filepath.Dir(filepath.Dir(destPath))}
+		env = parts[2]
+	}
+	if env != "" && !common.IsStringPresent(parameterizedYamls.Envs, env) {
+		parameterizedYamls.Envs = append(parameterizedYamls.Envs, env)
+	}
+}
+
+// getExistingPackagingPath returns the path of the existing helm chart or kustomization into which the new resources are merged
+func (t *Parameterizer) getExistingPackagingPath(allArtifacts []transformertypes.Artifact, artifactType transformertypes.ArtifactType, pathType transformertypes.PathType, key, packagingName string) string {
+	paths := map[string]string{}
+	options := []string{}
+	for _, a := range allArtifacts {
+		if a.Artifact != artifactType {
+			continue
+		}
+		for _, path := range a.Paths[pathType] {
+			relPath, err := filepath.Rel(t.Env.GetEnvironmentSource(), path)
+			if err != nil {
+				logrus.Errorf("Unable to make the path %s relative to the source directory : %s", path, err)
+				continue
+			}
+			if _, ok := paths[relPath]; !ok {
+				paths[relPath] = path
+				options = append(options, relPath)
+			}
+		}
+	}
+	if len(options) == 0 {
+		return ""
+	}
+	noneOption := "none"
+	desc := "Select the existing " + packagingName + " into which the new resources should be merged:"
+	hints := []string{"The resources which already exist in the " + packagingName + " are not generated again"}
+	relPath := qaengine.FetchSelectAnswer(key, desc, hints, options[0], append(options, noneOption))
+	if relPath == noneOption {
+		return ""
+	}
+	return paths[relPath]
+}
+
+// getDestPath returns the output path of a parameterized file. The files merged into an existing helm chart or kustomization replace the files in the source.
+func (t *Parameterizer) getDestPath(rel, parameterizedDir string, packSpecPath parameterizertypes.PackagingSpecPathT) string {
+	existingPaths := map[string]string{}
+	if packSpecPath.ExistingHelmChart != "" {
+		// The chart is in a directory named after the chart
+		existingPaths["helm-chart"] = packSpecPath.ExistingHelmChart
+	}
+	if packSpecPath.ExistingKustomize != "" {
+		existingPaths["kustomize"] = packSpecPath.ExistingKustomize
+	}
+	parts := strings.SplitN(filepath.ToSlash(rel), "/", 3)
+	existingPath, ok := existingPaths[parts[0]]
+	if !ok || len(parts) != 3 {
+		return filepath.Join(parameterizedDir, rel)
+	}
+	relExistingPath, err := filepath.Rel(t.Env.GetEnvironmentSource(), existingPath)
+	if err != nil {
+		logrus.Errorf("Unable to make the path %s relative to the source directory : %s", existingPath, err)
+		return filepath.Join(parameterizedDir, rel)
 	}
-	return pathMappings, nil, nil
+	return filepath.Join(common.DefaultSourceDir, relExistingPath, filepath.FromSlash(parts[2]))
 }
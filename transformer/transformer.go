@@ -17,6 +17,7 @@
 package transformer
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -26,6 +27,7 @@ import (
 	"github.com/konveyor/move2kube/filesystem"
 	"github.com/konveyor/move2kube/qaengine"
 	transformer "github.com/konveyor/move2kube/transformer/classes"
+	"github.com/konveyor/move2kube/transformer/classes/cicd"
 	"github.com/konveyor/move2kube/transformer/classes/cloudfoundry"
 	"github.com/konveyor/move2kube/transformer/classes/cnb"
 	"github.com/konveyor/move2kube/transformer/classes/compose"
@@ -46,7 +48,9 @@ import (
 var (
 	initialized                              = false
 	transformerTypes map[string]reflect.Type = map[string]reflect.Type{}
-	transformers     map[string]Transformer  = map[string]Transformer{}
+	// transformerConfigTypes are the types of the configs supported by the transformer classes
+	transformerConfigTypes map[string]reflect.Type = map[string]reflect.Type{}
+	transformers           map[string]Transformer  = map[string]Transformer{}
 )
 
 // Transformer interface defines transformer that transforms files and converts it to ir representation
@@ -65,6 +69,7 @@ func init() {
 	transformerObjs := []Transformer{
 		new(external.Starlark),
 		new(external.Executable),
+		new(external.GRPCPlugin),
 
 		new(dockerfile.DockerfileDetector),
 		new(dockerfile.DockerfileParser),
@@ -96,9 +101,14 @@ func init() {
 
 		new(kubernetes.Kubernetes),
 		new(kubernetes.Knative),
+		new(kubernetes.ServiceMesh),
 		new(kubernetes.Tekton),
 		new(kubernetes.BuildConfig),
+		new(kubernetes.HelmKustomizeAnalyser),
 		new(kubernetes.Parameterizer),
+		new(kubernetes.GitOps),
+		new(cicd.GitHubActions),
+		new(cicd.GitLabCI),
 
 		new(transformer.ReadMeGenerator),
 	}
@@ -111,29 +121,55 @@ func init() {
 		}
 		transformerTypes[tn] = t
 	}
+	transformerConfigObjs := map[Transformer]interface{}{
+		new(external.Starlark):                    external.StarYamlConfig{},
+		new(external.Executable):                  external.ExecutableYamlConfig{},
+		new(external.GRPCPlugin):                  external.GRPCPluginYamlConfig{},
+		new(dockerfile.NodejsDockerfileGenerator): dockerfile.NodejsDockerfileYamlConfig{},
+		new(dockerfile.GolangDockerfileGenerator): dockerfile.GolangDockerfileYamlConfig{},
+		new(java.JarAnalyser):                     java.JarYamlConfig{},
+		new(java.MavenAnalyser):                   java.MavenYamlConfig{},
+		new(cnb.CNBContainerizer):                 cnb.CNBContainerizerYamlConfig{},
+	}
+	for tt, c := range transformerConfigObjs {
+		transformerConfigTypes[reflect.TypeOf(tt).Elem().Name()] = reflect.TypeOf(c)
+	}
 }
 
 // Init initializes the transformers
 func Init(assetsPath, sourcePath string, targetCluster collectiontypes.ClusterMetadata, outputPath, projName string) (err error) {
-	filePaths, err := common.GetFilesByExt(assetsPath, []string{".yml", ".yaml"})
+	tcs, err := GetTransformerConfigs(assetsPath)
 	if err != nil {
-		logrus.Warnf("Unable to fetch yaml files and recognize cf manifest yamls at path %q Error: %q", assetsPath, err)
 		return err
 	}
 	transformerFiles := map[string]string{}
+	for tn, tc := range tcs {
+		transformerFiles[tn] = tc.Spec.FilePath
+	}
+	InitTransformers(transformerFiles, targetCluster, sourcePath, outputPath, projName, false)
+	return nil
+}
+
+// GetTransformerConfigs returns the transformer configs found in a directory, by transformer name
+func GetTransformerConfigs(dir string) (map[string]transformertypes.Transformer, error) {
+	filePaths, err := common.GetFilesByExt(dir, []string{".yml", ".yaml"})
+	if err != nil {
+		logrus.Warnf("Unable to fetch yaml files and recognize transformer yamls at path %q Error: %q", dir, err)
+		return nil, err
+	}
+	tcs := map[string]transformertypes.Transformer{}
 	for _, filePath := range filePaths {
 		tc, err := getTransformerConfig(filePath)
 		if err != nil {
 			logrus.Debugf("Unable to load %s as Transformer config : %s", filePath, err)
 			continue
 		}
-		if otc, ok := transformerFiles[tc.Name]; ok {
-			logrus.Warnf("Duplicate transformer configs with same name %s found. Ignoring %s in favor of %s", tc.Name, otc, filePath)
+		if otc, ok := tcs[tc.Name]; ok {
+			logrus.Warnf("Duplicate transformer configs with same name %s found. Ignoring %s in favor of %s", tc.Name, otc.Spec.FilePath, filePath)
 		}
-		transformerFiles[tc.Name] = filePath
+		tcs[tc.Name] = tc
 	}
-	InitTransformers(transformerFiles, targetCluster, sourcePath, outputPath, projName, false)
-	return nil
+	return tcs, nil
 }
 
 // InitTransformers initializes a subset of transformers
@@ -168,46 +204,64 @@ func InitTransformers(transformerToInit map[string]string, targetCluster collect
 	transformerNames := qaengine.FetchMultiSelectAnswer(common.ConfigTransformerTypesKey, "Select all transformer types that you are interested in:", []string{"Services that don't support any of the transformer types you are interested in will be ignored."}, tns, tns)
 	for _, tn := range transformerNames {
 		tc := transformerConfigs[tn]
-		if c, ok := transformerTypes[tc.Spec.Class]; !ok {
-			logrus.Errorf("Unable to find Transformer class %s in %+v", tc.Spec.Class, transformerTypes)
-		} else {
-			t := reflect.New(c).Interface().(Transformer)
-			transformerContextPath := filepath.Dir(tc.Spec.FilePath)
-			envInfo := environment.EnvInfo{
-				Name:            tc.Name,
-				ProjectName:     projName,
-				TargetCluster:   targetCluster,
-				Source:          sourcePath,
-				Output:          outputPath,
-				Context:         transformerContextPath,
-				RelTemplatesDir: tc.Spec.TemplatesDir,
-			}
-			for src, dest := range tc.Spec.ExternalFiles {
-				err := filesystem.Replicate(filepath.Join(transformerContextPath, src), filepath.Join(transformerContextPath, dest))
-				if err != nil {
-					logrus.Errorf("Error while copying external files in transformer %s (%s:%s) : %s", tc.Name, src, dest, err)
-				}
-			}
-			env, err := environment.NewEnvironment(envInfo, nil, environmenttypes.Container{})
-			if err != nil {
-				logrus.Errorf("Unable to create environment : %s", err)
-				return err
-			}
-			if err := t.Init(tc, env); err != nil {
-				if _, ok := err.(*transformertypes.TransformerDisabledError); ok {
-					logrus.Debugf("Unable to initialize transformer %s : %s", tc.Name, err)
-				} else {
-					logrus.Errorf("Unable to initialize transformer %s : %s", tc.Name, err)
-				}
+		t, err := initTransformer(tc, targetCluster, sourcePath, outputPath, projName)
+		if err != nil {
+			if _, ok := err.(*transformertypes.TransformerDisabledError); ok {
+				logrus.Debugf("Unable to initialize transformer %s : %s", tc.Name, err)
 			} else {
-				transformers[tn] = t
+				logrus.Errorf("Unable to initialize transformer %s : %s", tc.Name, err)
 			}
+			continue
 		}
+		transformers[tn] = t
 	}
 	initialized = true
 	return nil
 }
 
+// InitTransformer initializes a single transformer from its yaml, independent of the transformers initialized using Init
+func InitTransformer(transformerYamlPath string, targetCluster collectiontypes.ClusterMetadata, sourcePath string, outputPath, projName string) (Transformer, error) {
+	tc, err := getTransformerConfig(transformerYamlPath)
+	if err != nil {
+		logrus.Errorf("Unable to load %s as Transformer config : %s", transformerYamlPath, err)
+		return nil, err
+	}
+	return initTransformer(tc, targetCluster, sourcePath, outputPath, projName)
+}
+
+func initTransformer(tc transformertypes.Transformer, targetCluster collectiontypes.ClusterMetadata, sourcePath string, outputPath, projName string) (Transformer, error) {
+	c, ok := transformerTypes[tc.Spec.Class]
+	if !ok {
+		return nil, fmt.Errorf("unable to find Transformer class %s in %+v", tc.Spec.Class, transformerTypes)
+	}
+	t := reflect.New(c).Interface().(Transformer)
+	transformerContextPath := filepath.Dir(tc.Spec.FilePath)
+	envInfo := environment.EnvInfo{
+		Name:            tc.Name,
+		ProjectName:     projName,
+		TargetCluster:   targetCluster,
+		Source:          sourcePath,
+		Output:          outputPath,
+		Context:         transformerContextPath,
+		RelTemplatesDir: tc.Spec.TemplatesDir,
+	}
+	for src, dest := range tc.Spec.ExternalFiles {
+		err := filesystem.Replicate(filepath.Join(transformerContextPath, src), filepath.Join(transformerContextPath, dest))
+		if err != nil {
+			logrus.Errorf("Error while copying external files in transformer %s (%s:%s) : %s", tc.Name, src, dest, err)
+		}
+	}
+	env, err := environment.NewEnvironment(envInfo, nil, environmenttypes.Container{})
+	if err != nil {
+		logrus.Errorf("Unable to create environment : %s", err)
+		return nil, err
+	}
+	if err := t.Init(tc, env); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
 // Destroy destroys the transformers
 func Destroy() {
 	for _, t := range transformers {
@@ -225,11 +279,16 @@ func GetTransformers() map[string]Transformer {
 
 // GetServices returns the list of services detected in a directory
 func GetServices(prjName string, dir string) (services map[string]transformertypes.ServicePlan, err error) {
+	return DetectServices(prjName, dir, transformers)
+}
+
+// DetectServices returns the list of services detected in a directory by the given transformers
+func DetectServices(prjName string, dir string, ts map[string]Transformer) (services map[string]transformertypes.ServicePlan, err error) {
 	services = map[string]transformertypes.ServicePlan{}
 	unservices := []transformertypes.TransformerPlan{}
 	logrus.Infoln("Planning Transformation - Base Directory")
-	logrus.Debugf("Transformers : %+v", transformers)
-	for tn, t := range transformers {
+	logrus.Debugf("Transformers : %+v", ts)
+	for tn, t := range ts {
 		config, env := t.GetConfig()
 		env.Reset()
 		logrus.Infof("[%s] Planning transformation", tn)
@@ -251,7 +310,7 @@ func GetServices(prjName string, dir string) (services map[string]transformertyp
 	logrus.Infof("[Base Directory] Identified %d namedservices and %d unnamedservices", len(services), len(unservices))
 	logrus.Infoln("Transformation planning - Base Directory done")
 	logrus.Infoln("Planning Transformation - Directory Walk")
-	nservices, nunservices, err := walkForServices(dir, transformers, services)
+	nservices, nunservices, err := walkForServices(dir, ts, services)
 	if err != nil {
 		logrus.Errorf("Transformation planning - Directory Walk failed : %s", err)
 	} else {
@@ -275,21 +334,12 @@ func Transform(plan plantypes.Plan, outputPath string) (err error) {
 		for _, transformer := range service {
 			logrus.Infof("Transformer %s for service %s", transformer.TransformerName, serviceName)
 			t := transformers[transformer.TransformerName]
-			_, env := t.GetConfig()
-			env.Reset()
-			a := getArtifactForTransformerPlan(serviceName, transformer, plan)
-			newPathMappings, newArtifacts, err := t.Transform([]transformertypes.Artifact{*env.Encode(&a).(*transformertypes.Artifact)}, *env.Encode(&artifacts).(*[]transformertypes.Artifact))
+			a := GetArtifactForTransformerPlan(serviceName, transformer)
+			newPathMappings, newArtifacts, err := RunTransformer(t, []transformertypes.Artifact{a}, artifacts, plan.Spec.RootDir, outputPath)
 			if err != nil {
 				logrus.Errorf("Unable to transform service %s using %s : %s", serviceName, transformer.TransformerName, err)
 				continue
 			}
-			newPathMappings = env.ProcessPathMappings(newPathMappings)
-			newPathMappings = *env.DownloadAndDecode(&newPathMappings, true).(*[]transformertypes.PathMapping)
-			err = processPathMappings(newPathMappings, plan.Spec.RootDir, outputPath)
-			if err != nil {
-				logrus.Errorf("Unable to process path mappings")
-			}
-			newArtifacts = *env.DownloadAndDecode(&newArtifacts, false).(*[]transformertypes.Artifact)
 			pathMappings = append(pathMappings, newPathMappings...)
 			artifacts = mergeArtifacts(append(artifacts, newArtifacts...))
 			logrus.Infof("Created %d pathMappings and %d artifacts. Total Path Mappings : %d. Total Artifacts : %d.", len(newPathMappings), len(newArtifacts), len(pathMappings), len(artifacts))
@@ -306,8 +356,7 @@ func Transform(plan plantypes.Plan, outputPath string) (err error) {
 		newArtifactsCreated := []transformertypes.Artifact{}
 		logrus.Infof("Iteration %d", iteration)
 		for tn, t := range transformers {
-			config, env := t.GetConfig()
-			env.Reset()
+			config, _ := t.GetConfig()
 			artifactsToProcess := []transformertypes.Artifact{}
 			for _, na := range newArtifactsToProcess {
 				if common.IsStringPresent(config.Spec.ArtifactsToProcess, string(na.Artifact)) {
@@ -318,18 +367,11 @@ func Transform(plan plantypes.Plan, outputPath string) (err error) {
 				continue
 			}
 			logrus.Infof("Transformer %s processing %d artifacts", config.Name, len(artifactsToProcess))
-			newPathMappings, newArtifacts, err := t.Transform(*env.Encode(&artifactsToProcess).(*[]transformertypes.Artifact), *env.Encode(&artifacts).(*[]transformertypes.Artifact))
+			newPathMappings, newArtifacts, err := RunTransformer(t, artifactsToProcess, artifacts, plan.Spec.RootDir, outputPath)
 			if err != nil {
 				logrus.Errorf("Unable to transform artifacts using %s : %s", tn, err)
 				continue
 			}
-			newPathMappings = env.ProcessPathMappings(newPathMappings)
-			newPathMappings = *env.DownloadAndDecode(&newPathMappings, true).(*[]transformertypes.PathMapping)
-			err = processPathMappings(newPathMappings, plan.Spec.RootDir, outputPath)
-			if err != nil {
-				logrus.Errorf("Unable to process path mappings")
-			}
-			newArtifacts = *env.DownloadAndDecode(&newArtifacts, false).(*[]transformertypes.Artifact)
 			pathMappings = append(pathMappings, newPathMappings...)
 			newArtifactsCreated = append(newArtifactsCreated, newArtifacts...)
 			logrus.Infof("Created %d pathMappings and %d artifacts. Total Path Mappings : %d. Total Artifacts : %d.", len(newPathMappings), len(newArtifacts), len(pathMappings), len(artifacts))
@@ -351,6 +393,23 @@ func Transform(plan plantypes.Plan, outputPath string) (err error) {
 	return nil
 }
 
+// RunTransformer runs the transform of a transformer within its environment and processes the resulting path mappings into the output path
+func RunTransformer(t Transformer, newArtifacts, oldArtifacts []transformertypes.Artifact, rootDir, outputPath string) ([]transformertypes.PathMapping, []transformertypes.Artifact, error) {
+	_, env := t.GetConfig()
+	env.Reset()
+	newPathMappings, createdArtifacts, err := t.Transform(*env.Encode(&newArtifacts).(*[]transformertypes.Artifact), *env.Encode(&oldArtifacts).(*[]transformertypes.Artifact))
+	if err != nil {
+		return nil, nil, err
+	}
+	newPathMappings = env.ProcessPathMappings(newPathMappings)
+	newPathMappings = *env.DownloadAndDecode(&newPathMappings, true).(*[]transformertypes.PathMapping)
+	if err := processPathMappings(newPathMappings, rootDir, outputPath); err != nil {
+		logrus.Errorf("Unable to process path mappings")
+	}
+	createdArtifacts = *env.DownloadAndDecode(&createdArtifacts, false).(*[]transformertypes.Artifact)
+	return newPathMappings, createdArtifacts, nil
+}
+
 func walkForServices(inputPath string, ts map[string]Transformer, bservices map[string]transformertypes.ServicePlan) (services map[string]transformertypes.ServicePlan, unservices []transformertypes.TransformerPlan, err error) {
 	services = bservices
 	unservices = []transformertypes.TransformerPlan{}
@@ -382,7 +441,7 @@ func walkForServices(inputPath string, ts map[string]Transformer, bservices map[
 		common.PlanProgressNumDirectories++
 		logrus.Debugf("Planning dir transformation - %s", path)
 		found := false
-		for _, t := range transformers {
+		for _, t := range ts {
 			config, env := t.GetConfig()
 			logrus.Debugf("[%s] Planning transformation in %s", config.Name, path)
 			env.Reset()
@@ -0,0 +1,338 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package transformertest runs a single transformer against a test case directory and compares the results with golden files.
+//
+// A test case directory has the following layout:
+//
+//	source/                    the directory on which detect is run
+//	input.yaml                 (optional) the artifacts to transform, in the TransformInput format.
+//	                           By default the artifacts are created from the services detected in source/
+//	cluster.yaml               (optional) the target cluster metadata. By default the Kubernetes cluster is used
+//	expected/services.yaml     the services detected in source/
+//	expected/transform.yaml    the path mappings and artifacts returned by the transformer, in the TransformOutput format
+//	expected/output/           the files written to the output directory
+//
+// Absolute paths within the test case directory are written relative to it, and paths within the output directory
+// relative to the test case directory as if the output was written to output/.
+//
+// The caller is expected to have extracted the assets and started the QA engine, like the move2kube command does.
+// Go tests can use the transformertesting package, which does both.
+package transformertest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/common/pathconverters"
+	"github.com/konveyor/move2kube/configuration"
+	"github.com/konveyor/move2kube/filesystem"
+	"github.com/konveyor/move2kube/transformer"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// SourceDirName is the name of the directory within a test case, on which detect is run
+	SourceDirName = "source"
+	// InputFileName is the name of the file within a test case, containing the artifacts to transform
+	InputFileName = "input.yaml"
+	// ClusterFileName is the name of the file within a test case, containing the target cluster metadata
+	ClusterFileName = "cluster.yaml"
+	// ExpectedDirName is the name of the directory within a test case, containing the golden files
+	ExpectedDirName = "expected"
+	// ServicesFileName is the name of the golden file containing the detected services
+	ServicesFileName = "services.yaml"
+	// TransformFileName is the name of the golden file containing the transform output
+	TransformFileName = "transform.yaml"
+	// OutputDirName is the name of the golden directory containing the output files
+	OutputDirName = "output"
+	// TempPathPlaceholder replaces the paths of temporary copies made by the transformer environment, since they change on every run
+	TempPathPlaceholder = "<temp>"
+)
+
+// Result is the result of running a transformer on a test case
+type Result struct {
+	Services  map[string]transformertypes.ServicePlan
+	Transform transformertypes.TransformOutput
+	// OutputPath is the directory to which the transformer output was written
+	OutputPath string
+}
+
+// Run runs the transformer on the test case and returns the differences from the expected results.
+// If update is true, the expected results are overwritten with the actual results instead.
+func Run(transformerYamlPath, testCaseDir string, update bool) (diffs []string, err error) {
+	if transformerYamlPath, err = filepath.Abs(transformerYamlPath); err != nil {
+		return nil, err
+	}
+	if testCaseDir, err = filepath.Abs(testCaseDir); err != nil {
+		return nil, err
+	}
+	workDir, err := ioutil.TempDir(common.TempPath, "transformertest")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create a temporary directory : %s", err)
+	}
+	defer os.RemoveAll(workDir)
+	result, err := Execute(transformerYamlPath, testCaseDir, filepath.Join(workDir, OutputDirName))
+	if err != nil {
+		return nil, err
+	}
+	expectedDir := filepath.Join(testCaseDir, ExpectedDirName)
+	if update {
+		return nil, writeExpected(result, expectedDir)
+	}
+	return compare(result, expectedDir)
+}
+
+// Execute runs detect and transform of the transformer on the test case and writes the transformer output to outputPath
+func Execute(transformerYamlPath, testCaseDir, outputPath string) (result Result, err error) {
+	sourcePath := filepath.Join(testCaseDir, SourceDirName)
+	if _, err := os.Stat(sourcePath); err != nil {
+		return result, fmt.Errorf("the test case %s does not have a %s directory : %s", testCaseDir, SourceDirName, err)
+	}
+	if err := os.MkdirAll(outputPath, common.DefaultDirectoryPermission); err != nil {
+		return result, err
+	}
+	targetCluster, err := getTargetCluster(testCaseDir)
+	if err != nil {
+		return result, err
+	}
+	projName := filepath.Base(testCaseDir)
+	t, err := transformer.InitTransformer(transformerYamlPath, targetCluster, sourcePath, outputPath, projName)
+	if err != nil {
+		return result, fmt.Errorf("unable to initialize the transformer %s : %s", transformerYamlPath, err)
+	}
+	tc, env := t.GetConfig()
+	defer func() {
+		if err := env.Destroy(); err != nil {
+			logrus.Errorf("Unable to destroy environment : %s", err)
+		}
+	}()
+	result.OutputPath = outputPath
+	result.Services, err = transformer.DetectServices(projName, sourcePath, map[string]transformer.Transformer{tc.Name: t})
+	if err != nil {
+		return result, fmt.Errorf("detect failed : %s", err)
+	}
+	input, err := getInput(testCaseDir, result.Services)
+	if err != nil {
+		return result, err
+	}
+	if len(input.NewArtifacts) > 0 {
+		result.Transform.PathMappings, result.Transform.CreatedArtifacts, err = transformer.RunTransformer(t, input.NewArtifacts, input.OldArtifacts, sourcePath, outputPath)
+		if err != nil {
+			return result, fmt.Errorf("transform failed : %s", err)
+		}
+	}
+	relativize := func(path string) (string, error) {
+		for _, root := range []string{outputPath, testCaseDir} {
+			if filepath.IsAbs(path) && (path == root || common.IsParent(path, root)) {
+				rel, err := filepath.Rel(root, path)
+				if err != nil {
+					return path, err
+				}
+				if root == outputPath {
+					return filepath.Join(OutputDirName, rel), nil
+				}
+				return rel, nil
+			}
+		}
+		if filepath.IsAbs(path) && common.IsParent(path, common.TempPath) {
+			return TempPathPlaceholder, nil
+		}
+		return path, nil
+	}
+	if err := pathconverters.ProcessPaths(&result.Services, relativize); err != nil {
+		return result, err
+	}
+	if err := pathconverters.ProcessPaths(&result.Transform, relativize); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func getTargetCluster(testCaseDir string) (collecttypes.ClusterMetadata, error) {
+	loader := configuration.ClusterMDLoader{}
+	clusterPath := filepath.Join(testCaseDir, ClusterFileName)
+	if _, err := os.Stat(clusterPath); err == nil {
+		return loader.GetClusterMetadata(clusterPath)
+	}
+	p := plantypes.NewPlan()
+	if err := loader.UpdatePlan(&p); err != nil {
+		return collecttypes.ClusterMetadata{}, err
+	}
+	return loader.GetTargetClusterMetadataForPlan(p)
+}
+
+func getInput(testCaseDir string, services map[string]transformertypes.ServicePlan) (input transformertypes.TransformInput, err error) {
+	inputPath := filepath.Join(testCaseDir, InputFileName)
+	if _, err := os.Stat(inputPath); err == nil {
+		if err := common.ReadYaml(inputPath, &input); err != nil {
+			return input, fmt.Errorf("unable to read the input artifacts from %s : %s", inputPath, err)
+		}
+		err = pathconverters.ProcessPaths(&input, func(path string) (string, error) {
+			if path == "" || filepath.IsAbs(path) {
+				return path, nil
+			}
+			return filepath.Join(testCaseDir, path), nil
+		})
+		return input, err
+	}
+	serviceNames := []string{}
+	for sn := range services {
+		serviceNames = append(serviceNames, sn)
+	}
+	sort.Strings(serviceNames)
+	for _, sn := range serviceNames {
+		for _, tp := range services[sn] {
+			input.NewArtifacts = append(input.NewArtifacts, transformer.GetArtifactForTransformerPlan(sn, tp))
+		}
+	}
+	return input, nil
+}
+
+func writeExpected(result Result, expectedDir string) error {
+	if err := os.RemoveAll(expectedDir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(expectedDir, common.DefaultDirectoryPermission); err != nil {
+		return err
+	}
+	if err := common.WriteYaml(filepath.Join(expectedDir, ServicesFileName), result.Services); err != nil {
+		return err
+	}
+	if err := common.WriteYaml(filepath.Join(expectedDir, TransformFileName), result.Transform); err != nil {
+		return err
+	}
+	return filesystem.Replicate(result.OutputPath, filepath.Join(expectedDir, OutputDirName))
+}
+
+func compare(result Result, expectedDir string) (diffs []string, err error) {
+	if diff, err := compareYaml(filepath.Join(expectedDir, ServicesFileName), result.Services); err != nil {
+		return diffs, err
+	} else if diff != "" {
+		diffs = append(diffs, diff)
+	}
+	if diff, err := compareYaml(filepath.Join(expectedDir, TransformFileName), result.Transform); err != nil {
+		return diffs, err
+	} else if diff != "" {
+		diffs = append(diffs, diff)
+	}
+	outputDiffs, err := compareDirs(filepath.Join(expectedDir, OutputDirName), result.OutputPath)
+	return append(diffs, outputDiffs...), err
+}
+
+func compareYaml(expectedPath string, actual interface{}) (string, error) {
+	actualBytes, err := common.ObjectToYamlBytes(actual)
+	if err != nil {
+		return "", err
+	}
+	expectedBytes, err := ioutil.ReadFile(expectedPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Sprintf("The expected file %s does not exist. Actual:\n%s", expectedPath, string(actualBytes)), nil
+		}
+		return "", err
+	}
+	// Round trip the expected file through the same types, so that formatting differences are ignored
+	var expected interface{}
+	switch actual.(type) {
+	case map[string]transformertypes.ServicePlan:
+		services := map[string]transformertypes.ServicePlan{}
+		err = yaml.Unmarshal(expectedBytes, &services)
+		expected = services
+	case transformertypes.TransformOutput:
+		output := transformertypes.TransformOutput{}
+		err = yaml.Unmarshal(expectedBytes, &output)
+		expected = output
+	default:
+		err = yaml.Unmarshal(expectedBytes, &expected)
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to parse the expected file %s : %s", expectedPath, err)
+	}
+	if expectedBytes, err = common.ObjectToYamlBytes(expected); err != nil {
+		return "", err
+	}
+	if diff := cmp.Diff(string(expectedBytes), string(actualBytes)); diff != "" {
+		return fmt.Sprintf("%s is different from expected. Differences (-expected +actual):\n%s", filepath.Base(expectedPath), diff), nil
+	}
+	return "", nil
+}
+
+func compareDirs(expectedDir, actualDir string) (diffs []string, err error) {
+	expectedFiles, err := listFiles(expectedDir)
+	if err != nil {
+		return nil, err
+	}
+	actualFiles, err := listFiles(actualDir)
+	if err != nil {
+		return nil, err
+	}
+	for relPath := range expectedFiles {
+		if _, ok := actualFiles[relPath]; !ok {
+			diffs = append(diffs, fmt.Sprintf("The expected output file %s was not created", relPath))
+		}
+	}
+	for relPath := range actualFiles {
+		if _, ok := expectedFiles[relPath]; !ok {
+			diffs = append(diffs, fmt.Sprintf("The output file %s was not expected", relPath))
+			continue
+		}
+		expectedBytes, err := ioutil.ReadFile(filepath.Join(expectedDir, relPath))
+		if err != nil {
+			return diffs, err
+		}
+		actualBytes, err := ioutil.ReadFile(filepath.Join(actualDir, relPath))
+		if err != nil {
+			return diffs, err
+		}
+		if diff := cmp.Diff(string(expectedBytes), string(actualBytes)); diff != "" {
+			diffs = append(diffs, fmt.Sprintf("The output file %s is different from expected. Differences (-expected +actual):\n%s", relPath, diff))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs, nil
+}
+
+func listFiles(dir string) (map[string]bool, error) {
+	files := map[string]bool{}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return files, nil
+	}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(relPath)] = true
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,68 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package transformertest_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/move2kube/filesystem"
+	"github.com/konveyor/move2kube/transformer/transformertest"
+	"github.com/konveyor/move2kube/transformer/transformertest/transformertesting"
+)
+
+var update = flag.Bool("update", false, "update the expected results of the test cases")
+
+func TestAssertTestCase(t *testing.T) {
+	transformerPath := filepath.Join("testdata", "transformer", "transformer.yaml")
+	transformertesting.AssertTestCase(t, transformerPath, filepath.Join("testdata", "testcases", "simple"), *update)
+}
+
+func TestRunReportsDifferences(t *testing.T) {
+	transformertesting.Setup(t)
+	transformerPath := filepath.Join("testdata", "transformer", "transformer.yaml")
+	testCaseDir := filepath.Join(t.TempDir(), "simple")
+	if err := filesystem.Replicate(filepath.Join("testdata", "testcases", "simple"), testCaseDir); err != nil {
+		t.Fatalf("Failed to copy the test case. Error: %q", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(testCaseDir, "source", "app", "greeting.txt"), []byte("bye\n"), 0644); err != nil {
+		t.Fatalf("Failed to modify the test case. Error: %q", err)
+	}
+	diffs, err := transformertest.Run(transformerPath, testCaseDir, false)
+	if err != nil {
+		t.Fatalf("Failed to run the test case. Error: %q", err)
+	}
+	if len(diffs) != 1 {
+		t.Fatalf("Expected 1 difference. Actual: %d %+v", len(diffs), diffs)
+	}
+	if _, err := transformertest.Run(transformerPath, testCaseDir, true); err != nil {
+		t.Fatalf("Failed to update the test case. Error: %q", err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(testCaseDir, "expected", "output", "greeter", "greeting.txt"))
+	if err != nil {
+		t.Fatalf("Failed to read the updated expected output. Error: %q", err)
+	}
+	if string(data) != "bye\n" {
+		t.Fatalf("Expected the updated output to be %q. Actual: %q", "bye\n", string(data))
+	}
+	if _, err := os.Stat(filepath.Join(testCaseDir, "expected", "services.yaml")); err != nil {
+		t.Fatalf("Expected the services to be written. Error: %q", err)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package transformertesting provides the helpers to run the transformer test cases from go tests
+package transformertesting
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/konveyor/move2kube/assets"
+	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/qaengine"
+	"github.com/konveyor/move2kube/transformer/transformertest"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	setupOnce sync.Once
+	setupErr  error
+)
+
+// Setup extracts the assets and starts the QA engine, which answers the questions with their defaults.
+// It is done once for the test binary, since both are global.
+func Setup(t testing.TB) {
+	t.Helper()
+	setupOnce.Do(func() {
+		assetsFilePermissions := map[string]int{}
+		if setupErr = yaml.Unmarshal([]byte(assets.AssetFilePermissions), &assetsFilePermissions); setupErr != nil {
+			return
+		}
+		var assetsPath, tempPath string
+		if assetsPath, tempPath, setupErr = common.CreateAssetsData(assets.AssetsDir, assetsFilePermissions); setupErr != nil {
+			return
+		}
+		common.AssetsPath = assetsPath
+		common.TempPath = tempPath
+		qaengine.StartEngine(true, 0, true)
+	})
+	if setupErr != nil {
+		t.Fatalf("Failed to set up the transformer test environment. Error: %q", setupErr)
+	}
+}
+
+// AssertTestCase runs the transformer on the test case and fails the test if the results differ from the expected results
+func AssertTestCase(t testing.TB, transformerYamlPath, testCaseDir string, update bool) {
+	t.Helper()
+	Setup(t)
+	diffs, err := transformertest.Run(transformerYamlPath, testCaseDir, update)
+	if err != nil {
+		t.Fatalf("Failed to run the transformer %s on the test case %s . Error: %q", transformerYamlPath, testCaseDir, err)
+	}
+	for _, diff := range diffs {
+		t.Error(diff)
+	}
+}
@@ -21,11 +21,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 
 	"github.com/konveyor/move2kube/common"
 	"github.com/konveyor/move2kube/common/deepcopy"
-	plantypes "github.com/konveyor/move2kube/types/plan"
 	transformertypes "github.com/konveyor/move2kube/types/transformer"
 	"github.com/konveyor/move2kube/types/transformer/artifacts"
 	"github.com/sirupsen/logrus"
@@ -82,7 +82,8 @@ func getIgnorePaths(inputPath string) (ignoreDirectories []string, ignoreContent
 	return ignoreDirectories, ignoreContents
 }
 
-func getArtifactForTransformerPlan(serviceName string, t transformertypes.TransformerPlan, p plantypes.Plan) transformertypes.Artifact {
+// GetArtifactForTransformerPlan returns the service artifact which is passed to the transformer chosen for a service in the plan
+func GetArtifactForTransformerPlan(serviceName string, t transformertypes.TransformerPlan) transformertypes.Artifact {
 	serviceConfig := artifacts.ServiceConfig{
 		ServiceName: serviceName,
 	}
@@ -191,3 +192,55 @@ func setTransformerInfoForTransformers(transformers []transformertypes.Transform
 	}
 	return transformers
 }
+
+// GetConfigSchema returns the fields of the config supported by a transformer class along with their types.
+// It returns nil for the transformer classes which do not support a config.
+func GetConfigSchema(class string) (interface{}, error) {
+	if _, ok := transformerTypes[class]; !ok {
+		return nil, fmt.Errorf("unable to find Transformer class %s", class)
+	}
+	c, ok := transformerConfigTypes[class]
+	if !ok {
+		return nil, nil
+	}
+	return getTypeSchema(c, 0), nil
+}
+
+func getTypeSchema(t reflect.Type, depth int) interface{} {
+	const maxDepth = 5
+	switch t.Kind() {
+	case reflect.Ptr:
+		return getTypeSchema(t.Elem(), depth)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() != reflect.Struct || depth >= maxDepth {
+			return "[]" + fmt.Sprint(getTypeSchema(t.Elem(), maxDepth))
+		}
+		return []interface{}{getTypeSchema(t.Elem(), depth+1)}
+	case reflect.Map:
+		return "map[" + t.Key().Kind().String() + "]" + fmt.Sprint(getTypeSchema(t.Elem(), maxDepth))
+	case reflect.Struct:
+		if depth >= maxDepth {
+			return t.Name()
+		}
+		fields := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			fields[name] = getTypeSchema(f.Type, depth+1)
+		}
+		return fields
+	case reflect.Interface:
+		return "any"
+	default:
+		return t.Kind().String()
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package transformer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetConfigSchema(t *testing.T) {
+	t.Run("registered configs are the configs of their classes", func(t *testing.T) {
+		for class, configType := range transformerConfigTypes {
+			c, ok := transformerTypes[class]
+			if !ok {
+				t.Fatalf("Expected the class %s of the config %s to be registered", class, configType.Name())
+			}
+			found := false
+			for i := 0; i < c.NumField(); i++ {
+				ft := c.Field(i).Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if ft == configType {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Fatalf("Expected the class %s to have a field of the type %s", class, configType.Name())
+			}
+		}
+	})
+	t.Run("schema of the config of a class", func(t *testing.T) {
+		schema, err := GetConfigSchema("MavenAnalyser")
+		if err != nil {
+			t.Fatalf("Failed to get the config schema. Error: %q", err)
+		}
+		fields, ok := schema.(map[string]interface{})
+		if !ok || fields["defaultMavenVersion"] != "string" {
+			t.Fatalf("Expected the schema to contain the field defaultMavenVersion of type string. Actual: %+v", schema)
+		}
+	})
+	t.Run("class without a config", func(t *testing.T) {
+		schema, err := GetConfigSchema("DockerfileParser")
+		if err != nil || schema != nil {
+			t.Fatalf("Expected no schema. Actual: %+v Error: %v", schema, err)
+		}
+	})
+	t.Run("unknown class", func(t *testing.T) {
+		if _, err := GetConfigSchema("UnknownClass"); err == nil {
+			t.Fatal("Expected an error for an unknown class")
+		}
+	})
+}
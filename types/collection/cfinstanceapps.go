@@ -45,6 +45,14 @@ type CfApplication struct {
 	DockerImage       string            `yaml:"dockerImage,omitempty"`
 	Ports             []int32           `yaml:"ports"`
 	Env               map[string]string `yaml:"env,omitempty"`
+	Tasks             []CfTask          `yaml:"tasks,omitempty"`
+}
+
+// CfTask defines a task of a cf application, which is run once or, when it has a schedule, periodically by the cf scheduler
+type CfTask struct {
+	Name     string `yaml:"name"`
+	Command  string `yaml:"command"`
+	Schedule string `yaml:"schedule,omitempty"`
 }
 
 // NewCfInstanceApps creates a new instance of CfInstanceApps
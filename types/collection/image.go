@@ -46,6 +46,7 @@ type ImageInfoSpec struct {
 	PortsToExpose []int32  `yaml:"ports"`
 	AccessedDirs  []string `yaml:"accessedDirs"`
 	UserID        int      `yaml:"userID"`
+	Env           []string `yaml:"env,omitempty"`
 
 	Created string            `json:"created,omitempty" yaml:"created,omitempty"`
 	Params  map[string]string `json:"params,omitempty" yaml:"params,omitempty"`
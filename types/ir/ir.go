@@ -51,6 +51,35 @@ type IR struct {
 	ContainerImages map[string]ContainerImage // [imageName]
 	Services        map[string]Service
 	Storages        []Storage
+	TLS             map[string]TLSConfig // [hostPrefix]
+}
+
+// TLSTermination is where the TLS connections of an exposed host are terminated
+type TLSTermination string
+
+const (
+	// EdgeTLSTermination terminates TLS at the ingress, gateway or router
+	EdgeTLSTermination TLSTermination = "edge"
+	// ReencryptTLSTermination terminates TLS at the ingress, gateway or router and opens a new TLS connection to the service
+	ReencryptTLSTermination TLSTermination = "reencrypt"
+	// PassthroughTLSTermination passes the TLS connections through to the service
+	PassthroughTLSTermination TLSTermination = "passthrough"
+)
+
+const (
+	// IssuerKind is the kind of the cert-manager issuers which issue certificates only in their own namespace
+	IssuerKind = "Issuer"
+	// ClusterIssuerKind is the kind of the cert-manager issuers which issue certificates in all the namespaces
+	ClusterIssuerKind = "ClusterIssuer"
+)
+
+// TLSConfig is the TLS configuration of an exposed host
+type TLSConfig struct {
+	SecretName   string // Secret holding the certificate. It is created by cert-manager when an issuer is set.
+	Issuer       string // cert-manager issuer of the certificate
+	IssuerKind   string // Issuer or ClusterIssuer
+	Termination  TLSTermination
+	RedirectHTTP bool
 }
 
 // Service defines structure of an IR service
@@ -64,8 +93,21 @@ type Service struct {
 	ServiceToPodPortForwardings []ServiceToPodPortForwarding
 	Replicas                    int
 	Networks                    []string
+	Dependencies                []string //Names of the services this service calls
 	OnlyIngress                 bool
-	Daemon                      bool //Gets converted to DaemonSet
+	Daemon                      bool        //Gets converted to DaemonSet
+	StatefulSet                 bool        //Gets converted to StatefulSet
+	Schedule                    string      //Cron schedule on which the service is run. Gets converted to CronJob
+	Autoscaling                 Autoscaling //Gets converted to HorizontalPodAutoscaler
+	MaxUnavailable              int         //Gets converted to PodDisruptionBudget
+}
+
+// Autoscaling defines the horizontal autoscaling of a service
+type Autoscaling struct {
+	MinReplicas                       int
+	MaxReplicas                       int // Autoscaling is disabled when 0
+	TargetCPUUtilizationPercentage    int
+	TargetMemoryUtilizationPercentage int
 }
 
 // ServiceToPodPortForwarding forwards a k8s service port to a k8s pod port
@@ -75,6 +117,40 @@ type ServiceToPodPortForwarding struct {
 	ServiceRelPath string //Ingress fan-out path - If empty the service is not exposed
 }
 
+// ParseServiceRelPath returns the host prefix, the path and the service type encoded in the service relative path
+func (f ServiceToPodPortForwarding) ParseServiceRelPath() (hostPrefix, relPath string, serviceType core.ServiceType) {
+	serviceType = core.ServiceTypeClusterIP
+	relPath = f.ServiceRelPath
+	hostPrefix = ""
+	const nodeportSuffix = ":N"
+	const loadBalancerSuffix = ":L"
+	const noneSuffix = ":-"
+	if strings.HasSuffix(relPath, nodeportSuffix) {
+		serviceType = core.ServiceTypeNodePort
+		relPath = strings.TrimSuffix(relPath, nodeportSuffix)
+	}
+	if strings.HasSuffix(relPath, loadBalancerSuffix) {
+		serviceType = core.ServiceTypeLoadBalancer
+		relPath = strings.TrimSuffix(relPath, loadBalancerSuffix)
+	}
+	if strings.HasSuffix(relPath, noneSuffix) {
+		serviceType = ""
+		relPath = strings.TrimSuffix(relPath, noneSuffix)
+	}
+	if relPath != "" && !strings.HasPrefix(relPath, `/`) {
+		parts := []string{relPath}
+		if strings.Contains(relPath, `/`) {
+			parts = strings.SplitN(relPath, `/`, 2)
+		}
+		relPath = `/`
+		if len(parts) > 1 {
+			relPath += parts[1]
+		}
+		hostPrefix = parts[0]
+	}
+	return hostPrefix, relPath, serviceType
+}
+
 // ContainerBuildTypeValue stores the container build type
 type ContainerBuildTypeValue string
 
@@ -86,6 +162,7 @@ type ContainerImage struct {
 	ExposedPorts []int32  `yaml:"ports"`
 	UserID       int      `yaml:"userID"`
 	AccessedDirs []string `yaml:"accessedDirs"`
+	Env          []string `yaml:"env"`
 	Build        ContainerBuild
 }
 
@@ -146,27 +223,38 @@ func (service *Service) merge(nService Service) {
 	if nService.BackendServiceName != "" {
 		service.BackendServiceName = nService.BackendServiceName
 	}
-	podSpecJSON, err1 := json.Marshal(service.PodSpec)
-	if err1 != nil {
-		logrus.Errorf("Merge failed. Failed to marshal the first object %v to json. Error: %q", service.PodSpec, err1)
-	}
-	nPodSpecJSON, err2 := json.Marshal(nService.PodSpec)
-	if err2 != nil {
-		logrus.Errorf("Merge failed. Failed to marshal the second object %v to json. Error: %q", nService.PodSpec, err2)
-	}
-	if err1 != nil || err2 != nil {
-		podSpec := core.PodSpec{}
-		mergedJSON, err := strategicpatch.StrategicMergePatch(podSpecJSON, nPodSpecJSON, podSpec) // need to provide in reverse for proper ordering
-		if err != nil {
-			logrus.Errorf("Failed to merge the objects \n%s\n and \n%s\n Error: %q", podSpecJSON, nPodSpecJSON, err)
-		} else {
-			err := json.Unmarshal(mergedJSON, &podSpec)
+	switch {
+	case len(nService.Containers) == 0 && len(service.Containers) != 0:
+		// A service without containers, like the ones created only for the annotations or dependencies, keeps the pod spec
+	case len(service.Containers) == 0 && len(nService.Containers) != 0:
+		service.PodSpec = nService.PodSpec
+		service.OnlyIngress = nService.OnlyIngress
+		service.Daemon = nService.Daemon
+	default:
+		podSpecJSON, err1 := json.Marshal(service.PodSpec)
+		if err1 != nil {
+			logrus.Errorf("Merge failed. Failed to marshal the first object %v to json. Error: %q", service.PodSpec, err1)
+		}
+		nPodSpecJSON, err2 := json.Marshal(nService.PodSpec)
+		if err2 != nil {
+			logrus.Errorf("Merge failed. Failed to marshal the second object %v to json. Error: %q", nService.PodSpec, err2)
+		}
+		if err1 != nil || err2 != nil {
+			podSpec := core.PodSpec{}
+			mergedJSON, err := strategicpatch.StrategicMergePatch(podSpecJSON, nPodSpecJSON, podSpec) // need to provide in reverse for proper ordering
 			if err != nil {
-				logrus.Errorf("Failed to unmarshall object (%+v): %q", podSpec, err)
+				logrus.Errorf("Failed to merge the objects \n%s\n and \n%s\n Error: %q", podSpecJSON, nPodSpecJSON, err)
 			} else {
-				service.PodSpec = podSpec
+				err := json.Unmarshal(mergedJSON, &podSpec)
+				if err != nil {
+					logrus.Errorf("Failed to unmarshall object (%+v): %q", podSpec, err)
+				} else {
+					service.PodSpec = podSpec
+				}
 			}
 		}
+		service.OnlyIngress = service.OnlyIngress && nService.OnlyIngress
+		service.Daemon = service.Daemon && nService.Daemon
 	}
 	service.Annotations = common.MergeStringMaps(service.Annotations, nService.Annotations)
 	service.Labels = common.MergeStringMaps(service.Labels, nService.Labels)
@@ -174,8 +262,17 @@ func (service *Service) merge(nService Service) {
 		service.Replicas = nService.Replicas
 	}
 	service.Networks = common.MergeStringSlices(service.Networks, nService.Networks...)
-	service.OnlyIngress = service.OnlyIngress && nService.OnlyIngress
-	service.Daemon = service.Daemon && nService.Daemon
+	service.Dependencies = common.MergeStringSlices(service.Dependencies, nService.Dependencies...)
+	service.StatefulSet = service.StatefulSet || nService.StatefulSet
+	if nService.Schedule != "" {
+		service.Schedule = nService.Schedule
+	}
+	if nService.Autoscaling.MaxReplicas != 0 {
+		service.Autoscaling = nService.Autoscaling
+	}
+	if nService.MaxUnavailable != 0 {
+		service.MaxUnavailable = nService.MaxUnavailable
+	}
 	for _, pf := range nService.ServiceToPodPortForwardings {
 		service.AddPortForwarding(pf.ServicePort, pf.PodPort, pf.ServiceRelPath)
 	}
@@ -244,6 +341,7 @@ func (c *ContainerImage) Merge(newc ContainerImage) bool {
 	}
 	c.ExposedPorts = common.MergeInt32Slices(c.ExposedPorts, newc.ExposedPorts)
 	c.AccessedDirs = common.MergeStringSlices(c.AccessedDirs, newc.AccessedDirs...)
+	c.Env = common.MergeStringSlices(c.Env, newc.Env...)
 	c.Build.Merge(newc.Build)
 	return true
 }
@@ -287,6 +385,7 @@ func NewIR() IR {
 	ir.ContainerImages = map[string]ContainerImage{}
 	ir.Services = map[string]Service{}
 	ir.Storages = []Storage{}
+	ir.TLS = map[string]TLSConfig{}
 	return ir
 }
 
@@ -301,6 +400,12 @@ func (ir *IR) Merge(newir IR) {
 	for _, newst := range newir.Storages {
 		ir.AddStorage(newst)
 	}
+	for hostPrefix, tls := range newir.TLS {
+		if ir.TLS == nil {
+			ir.TLS = map[string]TLSConfig{}
+		}
+		ir.TLS[hostPrefix] = tls
+	}
 }
 
 // NewServiceWithName initializes a service with just the name.
@@ -0,0 +1,54 @@
+/*
+ *  Copyright IBM Corporation 2020, 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package ir
+
+import (
+	"testing"
+
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestMergeServiceWithoutContainers(t *testing.T) {
+	newService := func() Service {
+		service := NewServiceWithName("web")
+		service.Containers = []core.Container{{Name: "web", Image: "web:latest"}}
+		service.Volumes = []core.Volume{{Name: "data"}}
+		service.Daemon = true
+		return service
+	}
+	dependencies := Service{Name: "web", Dependencies: []string{"orders"}}
+	for name, services := range map[string][]Service{
+		"dependencies merged into the service": {newService(), dependencies},
+		"service merged into the dependencies": {dependencies, newService()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ir := NewIR()
+			for _, service := range services {
+				newIR := NewIR()
+				newIR.Services[service.Name] = service
+				ir.Merge(newIR)
+			}
+			service := ir.Services["web"]
+			if len(service.Containers) != 1 || len(service.Volumes) != 1 || !service.Daemon {
+				t.Fatalf("Expected the pod spec of the service to be kept. Actual: %+v", service)
+			}
+			if len(service.Dependencies) != 1 || service.Dependencies[0] != "orders" {
+				t.Fatalf("Expected the dependencies to be added. Actual: %+v", service.Dependencies)
+			}
+		})
+	}
+}
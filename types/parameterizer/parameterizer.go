@@ -18,6 +18,7 @@ package parameterizer
 
 import (
 	"github.com/konveyor/move2kube/common"
+	"github.com/konveyor/move2kube/types"
 	"github.com/konveyor/move2kube/types/qaengine"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -31,6 +32,9 @@ type PatchOpT string
 // ParamTargetT has Param Target
 type ParamTargetT string
 
+// KustomizePatchTypeT is the type of the patches in the kustomize overlays
+type KustomizePatchTypeT string
+
 // HelmValuesT has Helm Values
 type HelmValuesT map[string]interface{}
 
@@ -51,13 +55,20 @@ type PackagingSpecT struct {
 
 // PackagingSpecPathT is the set of source paths to be parameterized
 type PackagingSpecPathT struct {
-	Src           string   `yaml:"src" json:"src"`
-	Out           string   `yaml:"out,omitempty" json:"out,omitempty"`
-	Helm          string   `yaml:"helm,omitempty" json:"helm,omitempty"`
-	HelmChartName string   `yaml:"helmChartName,omitempty" json:"helmChartName,omitempty"`
-	Kustomize     string   `yaml:"kustomize,omitempty" json:"kustomize,omitempty"`
-	OCTemplates   string   `yaml:"openshiftTemplates,omitempty" json:"openshiftTemplates,omitempty"`
-	Envs          []string `yaml:"envs,omitempty" json:"envs,omitempty"`
+	Src                string              `yaml:"src" json:"src"`
+	Out                string              `yaml:"out,omitempty" json:"out,omitempty"`
+	Helm               string              `yaml:"helm,omitempty" json:"helm,omitempty"`
+	HelmChartName      string              `yaml:"helmChartName,omitempty" json:"helmChartName,omitempty"`
+	HelmChartVersion   string              `yaml:"helmChartVersion,omitempty" json:"helmChartVersion,omitempty"`
+	HelmAppVersion     string              `yaml:"helmAppVersion,omitempty" json:"helmAppVersion,omitempty"`
+	ExistingHelmChart  string              `yaml:"existingHelmChart,omitempty" json:"existingHelmChart,omitempty"`
+	Kustomize          string              `yaml:"kustomize,omitempty" json:"kustomize,omitempty"`
+	KustomizePatchType KustomizePatchTypeT `yaml:"kustomizePatchType,omitempty" json:"kustomizePatchType,omitempty"`
+	ExistingKustomize  string              `yaml:"existingKustomize,omitempty" json:"existingKustomize,omitempty"`
+	OCTemplates        string              `yaml:"openshiftTemplates,omitempty" json:"openshiftTemplates,omitempty"`
+	Ytt                string              `yaml:"ytt,omitempty" json:"ytt,omitempty"`         // ytt is generated only when its output path is given
+	Jsonnet            string              `yaml:"jsonnet,omitempty" json:"jsonnet,omitempty"` // Jsonnet is generated only when its output path is given
+	Envs               []string            `yaml:"envs,omitempty" json:"envs,omitempty"`
 }
 
 // ParameterizerFileT is the file format for the parameterizers
@@ -79,6 +90,7 @@ type ParameterizerT struct {
 	Regex      string            `yaml:"regex,omitempty" json:"regex,omitempty"`
 	Default    interface{}       `yaml:"default,omitempty" json:"default,omitempty"`
 	Question   *qaengine.Problem `yaml:"question,omitempty" json:"question,omitempty"`
+	Expression string            `yaml:"expression,omitempty" json:"expression,omitempty"`
 	Filters    []FilterT         `yaml:"filters,omitempty" json:"filters,omitempty"`
 	Parameters []ParameterT      `yaml:"parameters,omitempty" json:"parameters,omitempty"`
 }
@@ -89,6 +101,7 @@ type FilterT struct {
 	APIVersion string   `yaml:"apiVersion,omitempty" json:"apiVersion,omitempty"`
 	Name       string   `yaml:"name,omitempty" json:"name,omitempty"`
 	Envs       []string `yaml:"envs,omitempty" json:"envs,omitempty"`
+	Expression string   `yaml:"expression,omitempty" json:"expression,omitempty"`
 }
 
 // ParameterT is used to specify the environment specific defaults for the keys in the template
@@ -98,6 +111,7 @@ type ParameterT struct {
 	HelmTemplate      string            `yaml:"helmTemplate,omitempty" json:"helmTemplate,omitempty"`
 	OpenshiftTemplate string            `yaml:"openshiftTemplate,omitempty" json:"openshiftTemplate,omitempty"`
 	Values            []ParameterValueT `yaml:"values,omitempty" json:"values,omitempty"`
+	Expression        string            `yaml:"expression,omitempty" json:"expression,omitempty"`
 }
 
 // ParameterValueT is used to specify the value for a parameter in different contexts
@@ -138,6 +152,88 @@ type OCParamT struct {
 	Value string `yaml:"value"`
 }
 
+// ReportT is the report of a dry run of the parameterizers
+type ReportT struct {
+	Src                     string              `yaml:"src" json:"src"`
+	Resources               []ResourceReportT   `yaml:"resources" json:"resources"`
+	UnmatchedParameterizers []ParameterizerRefT `yaml:"unmatchedParameterizers,omitempty" json:"unmatchedParameterizers,omitempty"`
+}
+
+// ResourceReportT contains the results of all the parameterizers on a k8s resource
+type ResourceReportT struct {
+	Path           string                 `yaml:"path" json:"path"`
+	Kind           string                 `yaml:"kind" json:"kind"`
+	APIVersion     string                 `yaml:"apiVersion" json:"apiVersion"`
+	Name           string                 `yaml:"name" json:"name"`
+	Parameterizers []ParameterizerResultT `yaml:"parameterizers" json:"parameterizers"`
+}
+
+// ParameterizerRefT identifies a parameterizer using its position in the list of parameterizers and its target
+type ParameterizerRefT struct {
+	Index  int    `yaml:"index" json:"index"`
+	Target string `yaml:"target" json:"target"`
+}
+
+// ParameterizerResultT is the result of a parameterizer on a k8s resource. The reason explains why it did not match.
+type ParameterizerResultT struct {
+	ParameterizerRefT `yaml:",inline" json:",inline"`
+	Matched           bool          `yaml:"matched" json:"matched"`
+	Reason            string        `yaml:"reason,omitempty" json:"reason,omitempty"`
+	Paths             []PathResultT `yaml:"paths,omitempty" json:"paths,omitempty"`
+}
+
+// PathResultT is the result of a parameterizer at a json path of a k8s resource
+type PathResultT struct {
+	Path       string             `yaml:"path" json:"path"`
+	Reason     string             `yaml:"reason,omitempty" json:"reason,omitempty"`
+	Parameters []ParameterResultT `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+}
+
+// ParameterResultT is a parameter along with its default value and its value in each env
+type ParameterResultT struct {
+	Name     string                 `yaml:"name" json:"name"`
+	Default  interface{}            `yaml:"default" json:"default"`
+	Question string                 `yaml:"question,omitempty" json:"question,omitempty"`
+	Values   map[string]interface{} `yaml:"values,omitempty" json:"values,omitempty"`
+}
+
+// TransformMetadataFileT is the file format for the metadata of the k8s resources created by the transform
+type TransformMetadataFileT struct {
+	types.TypeMeta   `yaml:",inline" json:",inline"`
+	types.ObjectMeta `yaml:"metadata" json:"metadata"`
+	Spec             TransformMetadataSpecT `yaml:"spec" json:"spec"`
+}
+
+// TransformMetadataSpecT contains the services and storages for which the k8s resources were created
+type TransformMetadataSpecT struct {
+	Services []ServiceMetadataT `yaml:"services,omitempty" json:"services,omitempty"`
+	Storages []StorageMetadataT `yaml:"storages,omitempty" json:"storages,omitempty"`
+}
+
+// ServiceMetadataT is the metadata of a service
+type ServiceMetadataT struct {
+	Name       string               `yaml:"name" json:"name"`
+	Replicas   int                  `yaml:"replicas,omitempty" json:"replicas,omitempty"`
+	Scalable   bool                 `yaml:"scalable,omitempty" json:"scalable,omitempty"`
+	Exposed    bool                 `yaml:"exposed,omitempty" json:"exposed,omitempty"`
+	Containers []ContainerMetadataT `yaml:"containers,omitempty" json:"containers,omitempty"`
+}
+
+// ContainerMetadataT is the metadata of a container of a service. Resources contains the keys of the requests and limits that are set.
+type ContainerMetadataT struct {
+	Name      string   `yaml:"name" json:"name"`
+	Image     string   `yaml:"image" json:"image"`
+	Ports     []int32  `yaml:"ports,omitempty" json:"ports,omitempty"`
+	Resources []string `yaml:"resources,omitempty" json:"resources,omitempty"`
+}
+
+// StorageMetadataT is the metadata of a storage
+type StorageMetadataT struct {
+	Name string `yaml:"name" json:"name"`
+	Kind string `yaml:"kind" json:"kind"`
+	Size string `yaml:"size,omitempty" json:"size,omitempty"`
+}
+
 // ParamOrStringT is string along with a flag to indicate if it is a parameter
 type ParamOrStringT struct {
 	IsParam bool
@@ -149,6 +245,8 @@ const (
 	PackagingKind = "Packaging"
 	// ParameterizerKind is the kind for Parameterizer yamls
 	ParameterizerKind = "Parameterizer"
+	// TransformMetadataKind is the kind for the metadata of the k8s resources created by the transform
+	TransformMetadataKind = "TransformMetadata"
 	// ReplaceOp replaces the value at the key with a different value
 	ReplaceOp PatchOpT = "replace"
 	// AddOp inserts a value at the key
@@ -161,6 +259,14 @@ const (
 	TargetKustomize ParamTargetT = "kustomize"
 	// TargetOCTemplates is used when the target is the parameterization of Openshift Templates
 	TargetOCTemplates ParamTargetT = "openshifttemplates"
+	// TargetYtt is used when the target is the parameterization of Carvel ytt
+	TargetYtt ParamTargetT = "ytt"
+	// TargetJsonnet is used when the target is the parameterization of Jsonnet and Tanka
+	TargetJsonnet ParamTargetT = "jsonnet"
+	// KustomizeJSON6902Patches is used when the kustomize overlays contain json patches
+	KustomizeJSON6902Patches KustomizePatchTypeT = "json6902"
+	// KustomizeStrategicMergePatches is used when the kustomize overlays contain strategic merge patches, images, replicas and components
+	KustomizeStrategicMergePatches KustomizePatchTypeT = "strategicmerge"
 	// ParamQuesIDPrefix is used as a prefix when the key is not specified in the questions in a parameterizer
 	ParamQuesIDPrefix = common.BaseKey + common.Delim + "parameterization"
 )
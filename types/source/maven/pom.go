@@ -382,6 +382,7 @@ type Configuration struct {
 	Classifier            string    `xml:"classifier,omitempty"`
 	Source                string    `xml:"source,omitempty"`
 	Target                string    `xml:"target,omitempty"`
+	JvmArguments          string    `xml:"jvmArguments,omitempty"`
 	ConfigurationProfiles *[]string `xml:"profiles>profile,omitempty"`
 }
 
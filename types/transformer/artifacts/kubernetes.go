@@ -25,3 +25,19 @@ const KubernetesYamlsArtifactType transformertypes.ArtifactType = "KubernetesYam
 
 // KubernetesYamlsPathType is points to the kubernetes Yamls
 const KubernetesYamlsPathType transformertypes.PathType = "KubernetesYamls"
+
+const (
+	// ParameterizedYamlsArtifactType is the name of the artifact type of the parameterized Kubernetes Yamls
+	ParameterizedYamlsArtifactType transformertypes.ArtifactType = "ParameterizedYamls"
+	// ParameterizedHelmChartPathType points to the parameterized helm chart
+	ParameterizedHelmChartPathType transformertypes.PathType = "ParameterizedHelmChart"
+	// ParameterizedKustomizeOverlaysPathType points to the directory containing the kustomize overlay of each env
+	ParameterizedKustomizeOverlaysPathType transformertypes.PathType = "ParameterizedKustomizeOverlays"
+	// ParameterizedYamlsConfigType stores the envs of the parameterized Kubernetes Yamls
+	ParameterizedYamlsConfigType transformertypes.ConfigType = "ParameterizedYamls"
+)
+
+// ParameterizedYamls stores the envs for which the Kubernetes Yamls are parameterized
+type ParameterizedYamls struct {
+	Envs []string `yaml:"envs" json:"envs"`
+}
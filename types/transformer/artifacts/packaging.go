@@ -0,0 +1,32 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package artifacts
+
+import (
+	transformertypes "github.com/konveyor/move2kube/types/transformer"
+)
+
+const (
+	// HelmChartArtifactType is the name of the artifact type of an existing helm chart
+	HelmChartArtifactType transformertypes.ArtifactType = "HelmChart"
+	// HelmChartPathType points to the directory of an existing helm chart
+	HelmChartPathType transformertypes.PathType = "HelmChart"
+	// KustomizeArtifactType is the name of the artifact type of an existing kustomization
+	KustomizeArtifactType transformertypes.ArtifactType = "Kustomize"
+	// KustomizePathType points to the directory of the base of an existing kustomization
+	KustomizePathType transformertypes.PathType = "Kustomize"
+)
@@ -27,3 +27,17 @@ type TransformOutput struct {
 	PathMappings     []PathMapping `yaml:"pathMappings,omitempty" json:"pathMappings,omitempty"`
 	CreatedArtifacts []Artifact    `yaml:"artifacts,omitempty" json:"artifacts,omitempty"`
 }
+
+// TransformInput structure is the data format for sending data to external transform functions
+type TransformInput struct {
+	NewArtifacts []Artifact `yaml:"newArtifacts,omitempty" json:"newArtifacts,omitempty"`
+	OldArtifacts []Artifact `yaml:"oldArtifacts,omitempty" json:"oldArtifacts,omitempty"`
+}
+
+// InitInput structure is the data format for sending the transformer config to external long running transformers
+type InitInput struct {
+	Config      interface{} `yaml:"config" json:"config"`
+	ProjectName string      `yaml:"projectName,omitempty" json:"projectName,omitempty"`
+	Source      string      `yaml:"source,omitempty" json:"source,omitempty"`
+	Context     string      `yaml:"context,omitempty" json:"context,omitempty"`
+}
@@ -51,6 +51,7 @@ type TransformerSpec struct {
 	Class              string            `yaml:"class"`
 	ExternalFiles      map[string]string `yaml:"externalFiles"` // [source]destination
 	ArtifactsToProcess []string          `yaml:"consumes"`      //plantypes.ArtifactType
+	ArtifactsToProduce []string          `yaml:"produces"`      //plantypes.ArtifactType, used only for documentation
 	TemplatesDir       string            `yaml:"templates"`     //Relative to yaml directory or working directory in image
 	Config             interface{}       `yaml:"config"`
 }
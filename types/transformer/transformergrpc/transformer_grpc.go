@@ -0,0 +1,187 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package transformergrpc
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	serviceName = "transformergrpc.Transformer"
+
+	initMethod                = "Init"
+	baseDirectoryDetectMethod = "BaseDirectoryDetect"
+	directoryDetectMethod     = "DirectoryDetect"
+	transformMethod           = "Transform"
+)
+
+// TransformerClient is the client API for Transformer service.
+type TransformerClient interface {
+	Init(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	BaseDirectoryDetect(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*wrapperspb.StringValue, error)
+	DirectoryDetect(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*wrapperspb.StringValue, error)
+	Transform(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*wrapperspb.StringValue, error)
+}
+
+type transformerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewTransformerClient creates a client for the Transformer service
+func NewTransformerClient(cc grpc.ClientConnInterface) TransformerClient {
+	return &transformerClient{cc}
+}
+
+func (c *transformerClient) Init(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/"+initMethod, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transformerClient) BaseDirectoryDetect(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*wrapperspb.StringValue, error) {
+	return c.invokeString(ctx, baseDirectoryDetectMethod, in, opts...)
+}
+
+func (c *transformerClient) DirectoryDetect(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*wrapperspb.StringValue, error) {
+	return c.invokeString(ctx, directoryDetectMethod, in, opts...)
+}
+
+func (c *transformerClient) Transform(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*wrapperspb.StringValue, error) {
+	return c.invokeString(ctx, transformMethod, in, opts...)
+}
+
+func (c *transformerClient) invokeString(ctx context.Context, method string, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*wrapperspb.StringValue, error) {
+	out := new(wrapperspb.StringValue)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/"+method, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TransformerServer is the server API for Transformer service.
+// All implementations must embed UnimplementedTransformerServer for forward compatibility
+type TransformerServer interface {
+	Init(context.Context, *wrapperspb.StringValue) (*emptypb.Empty, error)
+	BaseDirectoryDetect(context.Context, *wrapperspb.StringValue) (*wrapperspb.StringValue, error)
+	DirectoryDetect(context.Context, *wrapperspb.StringValue) (*wrapperspb.StringValue, error)
+	Transform(context.Context, *wrapperspb.StringValue) (*wrapperspb.StringValue, error)
+	mustEmbedUnimplementedTransformerServer()
+}
+
+// UnimplementedTransformerServer must be embedded to have forward compatible implementations.
+type UnimplementedTransformerServer struct {
+}
+
+// Init accepts the configuration without doing anything
+func (UnimplementedTransformerServer) Init(context.Context, *wrapperspb.StringValue) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+// BaseDirectoryDetect is not implemented
+func (UnimplementedTransformerServer) BaseDirectoryDetect(context.Context, *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BaseDirectoryDetect not implemented")
+}
+
+// DirectoryDetect is not implemented
+func (UnimplementedTransformerServer) DirectoryDetect(context.Context, *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DirectoryDetect not implemented")
+}
+
+// Transform is not implemented
+func (UnimplementedTransformerServer) Transform(context.Context, *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transform not implemented")
+}
+func (UnimplementedTransformerServer) mustEmbedUnimplementedTransformerServer() {}
+
+// RegisterTransformerServer registers the transformer service implementation with a grpc server
+func RegisterTransformerServer(s grpc.ServiceRegistrar, srv TransformerServer) {
+	s.RegisterService(&Transformer_ServiceDesc, srv)
+}
+
+func _Transformer_Init_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(wrapperspb.StringValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransformerServer).Init(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + serviceName + "/" + initMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransformerServer).Init(ctx, req.(*wrapperspb.StringValue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func stringHandler(method string, fn func(TransformerServer, context.Context, *wrapperspb.StringValue) (*wrapperspb.StringValue, error)) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := new(wrapperspb.StringValue)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		if interceptor == nil {
+			return fn(srv.(TransformerServer), ctx, in)
+		}
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: "/" + serviceName + "/" + method,
+		}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return fn(srv.(TransformerServer), ctx, req.(*wrapperspb.StringValue))
+		}
+		return interceptor(ctx, in, info, handler)
+	}
+}
+
+// Transformer_ServiceDesc is the grpc.ServiceDesc for Transformer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Transformer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*TransformerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: initMethod,
+			Handler:    _Transformer_Init_Handler,
+		},
+		{
+			MethodName: baseDirectoryDetectMethod,
+			Handler:    stringHandler(baseDirectoryDetectMethod, TransformerServer.BaseDirectoryDetect),
+		},
+		{
+			MethodName: directoryDetectMethod,
+			Handler:    stringHandler(directoryDetectMethod, TransformerServer.DirectoryDetect),
+		},
+		{
+			MethodName: transformMethod,
+			Handler:    stringHandler(transformMethod, TransformerServer.Transform),
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "transformer.proto",
+}
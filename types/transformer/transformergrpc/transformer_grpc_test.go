@@ -0,0 +1,94 @@
+/*
+ *  Copyright IBM Corporation 2021
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package transformergrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type echoTransformerServer struct {
+	UnimplementedTransformerServer
+	initValue string
+}
+
+func (s *echoTransformerServer) Init(ctx context.Context, in *wrapperspb.StringValue) (*emptypb.Empty, error) {
+	s.initValue = in.GetValue()
+	return &emptypb.Empty{}, nil
+}
+
+func (s *echoTransformerServer) Transform(ctx context.Context, in *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	return wrapperspb.String("transformed " + in.GetValue()), nil
+}
+
+func newTestClient(t *testing.T, srv TransformerServer) TransformerClient {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	RegisterTransformerServer(server, srv)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	conn, err := grpc.DialContext(context.Background(), "bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return listener.Dial()
+	}))
+	if err != nil {
+		t.Fatalf("Unable to connect to the test server : %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewTransformerClient(conn)
+}
+
+func TestTransformerService(t *testing.T) {
+	srv := &echoTransformerServer{}
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+
+	t.Run("init is sent to the server", func(t *testing.T) {
+		if _, err := client.Init(ctx, wrapperspb.String(`{"projectName":"myproject"}`)); err != nil {
+			t.Fatalf("Unable to call init : %s", err)
+		}
+		if srv.initValue != `{"projectName":"myproject"}` {
+			t.Fatalf("Expected the init input to reach the server. Actual: %s", srv.initValue)
+		}
+	})
+	t.Run("transform returns the output of the server", func(t *testing.T) {
+		resp, err := client.Transform(ctx, wrapperspb.String("orders"))
+		if err != nil {
+			t.Fatalf("Unable to call transform : %s", err)
+		}
+		if resp.GetValue() != "transformed orders" {
+			t.Fatalf("Expected the output of the server. Actual: %s", resp.GetValue())
+		}
+	})
+	t.Run("methods which are not implemented return unimplemented", func(t *testing.T) {
+		for name, fn := range map[string]func(context.Context, *wrapperspb.StringValue, ...grpc.CallOption) (*wrapperspb.StringValue, error){
+			"BaseDirectoryDetect": client.BaseDirectoryDetect,
+			"DirectoryDetect":     client.DirectoryDetect,
+		} {
+			if _, err := fn(ctx, wrapperspb.String("dir")); status.Code(err) != codes.Unimplemented {
+				t.Fatalf("Expected %s to be unimplemented. Actual: %v", name, err)
+			}
+		}
+	})
+}